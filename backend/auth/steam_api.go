@@ -1,146 +1,15 @@
 package auth
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
-	"net/http"
 	"strings"
-	"time"
 )
 
 const (
-	steamAPIBaseURL = "https://api.steampowered.com"
-
 	// Steam default avatar hash (the blue question mark image)
 	steamDefaultAvatarHash = "fef49e7fa7e1997310d705b2a6158ff8dc1cdfeb"
 )
 
-// SteamPlayer represents a Steam player's profile data
-type SteamPlayer struct {
-	SteamID         string `json:"steamid"`
-	PersonaName     string `json:"personaname"`
-	ProfileURL      string `json:"profileurl"`
-	Avatar          string `json:"avatar"`           // 32x32
-	AvatarMedium    string `json:"avatarmedium"`     // 64x64
-	AvatarFull      string `json:"avatarfull"`       // 184x184
-	PersonaState    int    `json:"personastate"`     // 0=Offline, 1=Online, etc.
-	CommunityVisibilityState int `json:"communityvisibilitystate"`
-	ProfileState    int    `json:"profilestate"`
-	LastLogoff      int64  `json:"lastlogoff"`
-	RealName        string `json:"realname,omitempty"`
-	TimeCreated     int64  `json:"timecreated,omitempty"`
-	LocCountryCode  string `json:"loccountrycode,omitempty"`
-}
-
-// steamAPIResponse represents the API response structure
-type steamAPIResponse struct {
-	Response struct {
-		Players []SteamPlayer `json:"players"`
-	} `json:"response"`
-}
-
-// SteamAPIClient handles communication with the Steam Web API
-type SteamAPIClient struct {
-	apiKey     string
-	httpClient *http.Client
-}
-
-// NewSteamAPIClient creates a new Steam API client
-func NewSteamAPIClient(apiKey string) *SteamAPIClient {
-	return &SteamAPIClient{
-		apiKey: apiKey,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
-}
-
-// GetPlayerSummary fetches a single player's profile data
-func (c *SteamAPIClient) GetPlayerSummary(steamID string) (*SteamPlayer, error) {
-	// Skip fake users (used for development/testing)
-	if strings.HasPrefix(steamID, "FAKE_") {
-		return nil, fmt.Errorf("fake user: %s", steamID)
-	}
-
-	players, err := c.GetPlayerSummaries([]string{steamID})
-	if err != nil {
-		return nil, err
-	}
-
-	if len(players) == 0 {
-		return nil, fmt.Errorf("player not found: %s", steamID)
-	}
-
-	return &players[0], nil
-}
-
-// GetPlayerSummaries fetches profile data for multiple players (max 100)
-func (c *SteamAPIClient) GetPlayerSummaries(steamIDs []string) ([]SteamPlayer, error) {
-	if len(steamIDs) == 0 {
-		return nil, fmt.Errorf("no Steam IDs provided")
-	}
-
-	// Filter out fake users (used for development/testing)
-	realSteamIDs := make([]string, 0, len(steamIDs))
-	for _, id := range steamIDs {
-		if !strings.HasPrefix(id, "FAKE_") {
-			realSteamIDs = append(realSteamIDs, id)
-		}
-	}
-
-	if len(realSteamIDs) == 0 {
-		return []SteamPlayer{}, nil
-	}
-
-	if len(realSteamIDs) > 100 {
-		return nil, fmt.Errorf("maximum 100 Steam IDs allowed per request")
-	}
-
-	if c.apiKey == "" {
-		return nil, fmt.Errorf("Steam API key not configured")
-	}
-
-	// Build the API URL
-	url := fmt.Sprintf(
-		"%s/ISteamUser/GetPlayerSummaries/v2/?key=%s&steamids=%s",
-		steamAPIBaseURL,
-		c.apiKey,
-		strings.Join(realSteamIDs, ","),
-	)
-
-	// Make the request
-	log.Printf("[STEAM API] GET /ISteamUser/GetPlayerSummaries/v2 - Fetching %d player(s): %s", len(realSteamIDs), strings.Join(realSteamIDs, ", "))
-	start := time.Now()
-	resp, err := c.httpClient.Get(url)
-	duration := time.Since(start)
-	if err != nil {
-		log.Printf("[STEAM API] ERROR - GetPlayerSummaries failed after %v: %v", duration, err)
-		return nil, fmt.Errorf("failed to call Steam API: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[STEAM API] ERROR - GetPlayerSummaries returned status %d after %v", resp.StatusCode, duration)
-		return nil, fmt.Errorf("Steam API returned status %d", resp.StatusCode)
-	}
-
-	// Parse the response
-	var apiResp steamAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		log.Printf("[STEAM API] ERROR - Failed to parse response after %v: %v", duration, err)
-		return nil, fmt.Errorf("failed to parse Steam API response: %w", err)
-	}
-
-	log.Printf("[STEAM API] OK - GetPlayerSummaries returned %d player(s) in %v", len(apiResp.Response.Players), duration)
-	return apiResp.Response.Players, nil
-}
-
-// IsConfigured returns true if the API client has a valid API key
-func (c *SteamAPIClient) IsConfigured() bool {
-	return c.apiKey != ""
-}
-
 // IsDefaultAvatar checks if the given avatar URL is the Steam default avatar (blue question mark)
 func IsDefaultAvatar(avatarURL string) bool {
 	return strings.Contains(avatarURL, steamDefaultAvatarHash)
@@ -159,56 +28,3 @@ func GetAvatarOrFallback(avatarURL, username string) string {
 	}
 	return avatarURL
 }
-
-// CheckConnectivity verifies that the Steam API endpoints are reachable
-// Returns nil if all checks pass, otherwise returns an error describing the issue
-func (c *SteamAPIClient) CheckConnectivity() error {
-	log.Printf("[STEAM API] Checking connectivity to Steam services...")
-
-	// Check Steam Community (OpenID endpoint)
-	steamCommunityURL := "https://steamcommunity.com/openid"
-	log.Printf("[STEAM API] HEAD %s", steamCommunityURL)
-	start := time.Now()
-	resp, err := c.httpClient.Head(steamCommunityURL)
-	duration := time.Since(start)
-	if err != nil {
-		log.Printf("[STEAM API] ERROR - Steam Community unreachable after %v: %v", duration, err)
-		return fmt.Errorf("cannot reach Steam Community (%s): %w", steamCommunityURL, err)
-	}
-	resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		log.Printf("[STEAM API] ERROR - Steam Community returned status %d after %v", resp.StatusCode, duration)
-		return fmt.Errorf("Steam Community returned status %d", resp.StatusCode)
-	}
-	log.Printf("[STEAM API] OK - Steam Community reachable (status %d, %v)", resp.StatusCode, duration)
-
-	// Check Steam Web API (only if API key is configured)
-	if c.apiKey != "" {
-		// Use a simple API call to verify connectivity and API key validity
-		// We use GetPlayerSummaries with Valve's test Steam ID
-		testURL := fmt.Sprintf("%s/ISteamUser/GetPlayerSummaries/v2/?key=%s&steamids=76561197960435530", steamAPIBaseURL, c.apiKey)
-		log.Printf("[STEAM API] GET /ISteamUser/GetPlayerSummaries/v2 - Testing API key validity")
-		start = time.Now()
-		resp, err := c.httpClient.Get(testURL)
-		duration = time.Since(start)
-		if err != nil {
-			log.Printf("[STEAM API] ERROR - Steam Web API unreachable after %v: %v", duration, err)
-			return fmt.Errorf("cannot reach Steam Web API (%s): %w", steamAPIBaseURL, err)
-		}
-		resp.Body.Close()
-		if resp.StatusCode == 401 || resp.StatusCode == 403 {
-			log.Printf("[STEAM API] ERROR - API key invalid/unauthorized (status %d, %v)", resp.StatusCode, duration)
-			return fmt.Errorf("Steam API key is invalid or unauthorized (status %d)", resp.StatusCode)
-		}
-		if resp.StatusCode >= 400 {
-			log.Printf("[STEAM API] ERROR - Steam Web API returned status %d after %v", resp.StatusCode, duration)
-			return fmt.Errorf("Steam Web API returned status %d", resp.StatusCode)
-		}
-		log.Printf("[STEAM API] OK - Steam Web API reachable, API key valid (status %d, %v)", resp.StatusCode, duration)
-	} else {
-		log.Printf("[STEAM API] WARN - No API key configured, skipping Web API check")
-	}
-
-	log.Printf("[STEAM API] Connectivity check completed successfully")
-	return nil
-}
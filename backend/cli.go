@@ -0,0 +1,488 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+)
+
+// cliUsage describes the available maintenance subcommands
+const cliUsage = `Usage: rate-your-mate [command] [flags]
+
+Without a command the HTTP server is started.
+
+Commands:
+  migrate up                                Apply all pending migrations
+  migrate down [-steps n] [-all -yes]       Roll back the last n migrations (default 1), -all drops all tables
+  migrate status                            Show the applied and latest schema version
+  migrate force <version>                   Set the schema version and clear the dirty flag (after a manual repair)
+  seed                                      Replace all FAKE_ players with 15 fake players and 300 vote points
+  export [-o file] [-direct-messages]       Write a JSON archive of all event data (default: stdout), optionally with DMs
+  import [-mode merge|replace] <file>       Restore a JSON archive created by export ("-" reads stdin)
+  user ban [-reason text] <steam_id>        Ban a player and delete their account
+  user unban <steam_id>                     Remove a player from the ban list
+  votes wipe -yes                           Delete all votes
+  games resync [-skip-libraries]            Invalidate the game cache, refresh all libraries and re-sync game data
+  media prefetch                            Download images of all multiplayer games and avatars of all players for offline use
+
+The database is selected via the usual environment variables (DB_TYPE, DB_PATH, MYSQL_*, POSTGRES_*).
+Commands operate on the database directly; connected clients of a running server are not notified.
+`
+
+// runCLI executes a maintenance subcommand and returns the process exit code
+func runCLI(args []string) int {
+	commands := map[string]func([]string) error{
+		"migrate": cmdMigrate,
+		"seed":    cmdSeed,
+		"export":  cmdExport,
+		"import":  cmdImport,
+		"user":    cmdUser,
+		"votes":   cmdVotes,
+		"games":   cmdGames,
+		"media":   cmdMedia,
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Print(cliUsage)
+		return 0
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", args[0], cliUsage)
+		return 2
+	}
+
+	if err := command(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// openDatabase initializes the configured database for a CLI command
+func openDatabase(skipMigrations bool) error {
+	dbCfg := newDatabaseConfig(cfg)
+	dbCfg.SkipMigrations = skipMigrations
+	if err := database.Init(dbCfg); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return nil
+}
+
+// subcommand splits "<group> <action> ..." arguments and validates the action
+func subcommand(group string, args []string, actions ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%s expects one of: %s", group, strings.Join(actions, ", "))
+	}
+	for _, action := range actions {
+		if args[0] == action {
+			return action, args[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("unknown %s action %q (expected one of: %s)", group, args[0], strings.Join(actions, ", "))
+}
+
+// cmdMigrate manages the database schema
+func cmdMigrate(args []string) error {
+	action, args, err := subcommand("migrate", args, "up", "down", "status", "force")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	all := fs.Bool("all", false, "roll back all migrations")
+	yes := fs.Bool("yes", false, "confirm rolling back all migrations")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if action == "down" && *all && !*yes {
+		return fmt.Errorf("migrate down -all drops all tables, pass -yes to confirm")
+	}
+
+	forceVersion := 0
+	if action == "force" {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("migrate force expects exactly one version")
+		}
+		if forceVersion, err = strconv.Atoi(fs.Arg(0)); err != nil {
+			return fmt.Errorf("invalid version %q", fs.Arg(0))
+		}
+	}
+
+	if err := openDatabase(true); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	switch action {
+	case "up":
+		if err := database.MigrateUp(); err != nil {
+			return err
+		}
+	case "down":
+		n := *steps
+		if *all {
+			n = 0
+		} else if n < 1 {
+			return fmt.Errorf("-steps must be at least 1")
+		}
+		if err := database.MigrateDown(n); err != nil {
+			return err
+		}
+	case "force":
+		if err := database.ForceMigrationVersion(forceVersion); err != nil {
+			return err
+		}
+	}
+
+	status, err := database.GetMigrationStatus()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database:        %s\n", database.GetDBType())
+	fmt.Printf("Current version: %d\n", status.Version)
+	fmt.Printf("Latest version:  %d\n", status.Latest)
+	fmt.Printf("Pending:         %d\n", status.Pending)
+	fmt.Printf("Dirty:           %t\n", status.Dirty)
+	return nil
+}
+
+// cmdSeed fills the database with fake players and votes
+func cmdSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := openDatabase(false); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	userRepo := repository.NewUserRepository()
+	voteRepo := repository.NewVoteRepository()
+	result, err := services.NewSeedService(userRepo, repository.NewExportRepository()).SeedFakeData()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %d fake players with %d votes\n\n", result.UsersCreated, result.VotesImported)
+
+	// Print the resulting ranking of the fake players
+	rankings, err := voteRepo.GetGlobalRanking()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-5s %-20s %6s %6s %6s\n", "Rank", "Player", "Score", "Net", "Bonus")
+	for _, ranking := range rankings {
+		if !strings.HasPrefix(ranking.User.SteamID, "FAKE_") {
+			continue
+		}
+		fmt.Printf("%-5d %-20s %6d %6d %6d\n", ranking.Rank, ranking.User.Username, ranking.TotalScore, ranking.NetVotes, ranking.BonusPoints)
+	}
+	return nil
+}
+
+// newCLIExportService wires the export service without the HTTP server dependencies
+func newCLIExportService() *services.ExportService {
+	return services.NewExportService(cfg, Version,
+		repository.NewUserRepository(),
+		repository.NewVoteRepository(),
+		repository.NewChatRepository(),
+		repository.NewDirectMessageRepository(),
+		repository.NewGameOwnerRepository(),
+		repository.NewCustomGameRepository(),
+		repository.NewGameMetadataRepository(),
+		repository.NewPollRepository(),
+		repository.NewGameSessionRepository(),
+		repository.NewInstallStatusRepository(),
+		repository.NewTournamentRepository(),
+		repository.NewRatingRepository(),
+		repository.NewExportRepository(),
+	)
+}
+
+// cmdExport writes an export archive to a file or stdout
+func cmdExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "-", "output file (- for stdout)")
+	directMessages := fs.Bool("direct-messages", false, "include the private messages between players")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := openDatabase(false); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	archive, err := newCLIExportService().Export(services.ExportOptions{DirectMessages: *directMessages})
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d users, %d votes, %d chat messages, %d direct messages, %d bans, %d game owners, %d polls, %d game sessions, %d tournaments, %d matches\n",
+		len(archive.Users), len(archive.Votes), len(archive.ChatMessages), len(archive.DirectMessages), len(archive.BannedUsers), len(archive.GameOwners),
+		len(archive.Polls), len(archive.GameSessions), len(archive.Tournaments), len(archive.GameMatches))
+	return nil
+}
+
+// cmdImport restores an export archive into the configured database
+func cmdImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	mode := fs.String("mode", string(models.ImportModeMerge), "import mode: merge or replace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("import expects exactly one archive file")
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var archive models.ExportArchive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return fmt.Errorf("failed to parse archive: %w", err)
+	}
+
+	if err := openDatabase(false); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	// Settings only live in memory of the running server, so they are not applied here
+	result, err := newCLIExportService().Import(&archive, services.ImportOptions{Mode: models.ImportMode(*mode)})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Import (%s) finished: %d users created, %d matched, %d votes (%d skipped), %d chat messages (%d skipped), %d bans, %d game owners, %d polls (%d skipped), %d game sessions (%d skipped), %d tournaments (%d skipped), %d matches (%d skipped)\n",
+		result.Mode, result.UsersCreated, result.UsersMatched, result.VotesImported, result.VotesSkipped,
+		result.ChatMessagesImported, result.ChatMessagesSkipped, result.BansImported, result.GameOwnersImported,
+		result.PollsImported, result.PollsSkipped, result.GameSessionsImported, result.GameSessionsSkipped,
+		result.TournamentsImported, result.TournamentsSkipped, result.GameMatchesImported, result.GameMatchesSkipped)
+	return nil
+}
+
+// cmdUser manages bans
+func cmdUser(args []string) error {
+	action, args, err := subcommand("user", args, "ban", "unban")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("user "+action, flag.ContinueOnError)
+	reason := fs.String("reason", "", "ban reason")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("user %s expects exactly one Steam ID", action)
+	}
+	steamID := fs.Arg(0)
+
+	if err := openDatabase(false); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	userRepo := repository.NewUserRepository()
+
+	switch action {
+	case "ban":
+		banned, err := userRepo.IsBanned(steamID)
+		if err != nil {
+			return err
+		}
+		if banned {
+			return fmt.Errorf("%s is already banned", steamID)
+		}
+
+		username := steamID
+		user, err := userRepo.GetBySteamID(steamID)
+		if err != nil {
+			return err
+		}
+		if user != nil {
+			username = user.Username
+		}
+
+		if err := userRepo.BanUser(steamID, username, *reason, "cli"); err != nil {
+			return err
+		}
+		// Same as the admin endpoint: banned players lose their account
+		if user != nil {
+			if err := userRepo.DeleteByID(user.ID); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Banned %s (%s)\n", username, steamID)
+
+	case "unban":
+		banned, err := userRepo.IsBanned(steamID)
+		if err != nil {
+			return err
+		}
+		if !banned {
+			return fmt.Errorf("%s is not banned", steamID)
+		}
+		if err := userRepo.UnbanUser(steamID); err != nil {
+			return err
+		}
+		fmt.Printf("Unbanned %s\n", steamID)
+	}
+
+	return nil
+}
+
+// cmdVotes manages votes
+func cmdVotes(args []string) error {
+	_, args, err := subcommand("votes", args, "wipe")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("votes wipe", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "confirm deleting all votes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*yes {
+		return fmt.Errorf("votes wipe deletes all votes, pass -yes to confirm")
+	}
+
+	if err := openDatabase(false); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	deleted, err := repository.NewVoteRepository().DeleteAll()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d votes\n", deleted)
+	return nil
+}
+
+// cmdGames manages the game cache
+func cmdGames(args []string) error {
+	_, args, err := subcommand("games", args, "resync")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("games resync", flag.ContinueOnError)
+	skipLibraries := fs.Bool("skip-libraries", false, "don't re-fetch the players' game libraries from Steam")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := openDatabase(false); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	steamClient, err := steam.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	userRepo := repository.NewUserRepository()
+	gameCacheRepo := repository.NewGameCacheRepository()
+	gameService := services.NewGameService(cfg, steamClient, userRepo, gameCacheRepo, repository.NewGameOwnerRepository(),
+		services.NewImageCacheService(cfg), services.NewGameMetadataService(cfg, repository.NewGameMetadataRepository()))
+
+	if err := gameCacheRepo.InvalidateAll(); err != nil {
+		return err
+	}
+	fmt.Println("Game cache invalidated")
+
+	if !*skipLibraries {
+		refreshed, newGames, err := gameService.RefreshAllLibraries()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Refreshed %d libraries, %d new games\n", refreshed, newGames)
+	}
+
+	synced := 0
+	scheduler := services.NewGameSyncScheduler(cfg, gameService, repository.NewSyncJobRepository(),
+		func(phase string, currentGame string, processed, total int) {
+			switch {
+			case phase == "complete":
+				synced = total
+			case phase == "paused":
+				fmt.Printf("Rate limited by Steam, waiting until %s\n", gameService.RateLimitedUntil().Format(time.RFC3339))
+			case currentGame != "":
+				fmt.Printf("[%d/%d] %s\n", processed, total, currentGame)
+			}
+		})
+	if err := scheduler.RunOnce(context.Background()); err != nil {
+		return err
+	}
+	fmt.Printf("Game sync complete (%d games)\n", synced)
+	return nil
+}
+
+// cmdMedia manages the image caches
+func cmdMedia(args []string) error {
+	if _, _, err := subcommand("media", args, "prefetch"); err != nil {
+		return err
+	}
+
+	if err := openDatabase(false); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	steamClient, err := steam.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	prefetchService := services.NewMediaPrefetchService(steamClient, repository.NewUserRepository(), repository.NewGameCacheRepository(),
+		repository.NewCustomGameRepository(), services.NewImageCacheService(cfg), services.NewAvatarCacheService(cfg), nil)
+	err = prefetchService.Run(context.Background())
+	status := prefetchService.Status()
+	fmt.Printf("Cached images of %d games and players (%d failed)\n", status.Processed-status.Failed, status.Failed)
+	return err
+}
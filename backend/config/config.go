@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -18,8 +19,15 @@ type Config struct {
 	BackendURL  string
 
 	// Database
-	DBType     string // "sqlite" or "mysql"
-	DBPath     string // SQLite database path
+	DBType            string // "sqlite", "mysql" or "postgres"
+	DBPath            string // SQLite database path
+	MigrateAllowDirty bool   // Start even if the schema was left dirty by a failed migration
+
+	// SQLite backups
+	BackupDir           string        // Directory for pre-migration backups and snapshots
+	BackupBeforeMigrate bool          // Back up the SQLite database before applying pending migrations
+	SnapshotInterval    time.Duration // Interval for scheduled snapshots (0 = disabled)
+	SnapshotRetention   int           // Number of scheduled snapshots to keep
 
 	// MySQL
 	MySQLHost            string
@@ -35,8 +43,24 @@ type Config struct {
 	MySQLConnMaxLifetime time.Duration
 	MySQLConnMaxIdleTime time.Duration
 
+	// PostgreSQL
+	PostgresHost            string
+	PostgresPort            int
+	PostgresUser            string
+	PostgresPassword        string
+	PostgresDatabase        string
+	PostgresSSLMode         string // disable, require, verify-ca or verify-full
+	PostgresMaxOpenConns    int
+	PostgresMaxIdleConns    int
+	PostgresConnMaxLifetime time.Duration
+	PostgresConnMaxIdleTime time.Duration
+
 	// Steam
-	SteamAPIKey string
+	SteamAPIKey           string
+	SteamAPIBaseURL       string // Base URL of the Steam Web API
+	SteamStoreBaseURL     string // Base URL of the Steam Store
+	SteamCommunityBaseURL string // Base URL of the Steam Community (OpenID check, group member lists)
+	SteamFakeDir          string // Directory with Steam fixtures, replaces all Steam API calls if set (tests, offline demos)
 
 	// JWT
 	JWTSecret         string
@@ -59,9 +83,30 @@ type Config struct {
 	AdminSteamIDs []string
 	AdminPassword string // Optional password for additional admin panel security
 
+	// Chat
+	ModeratorSteamIDs  []string      // Players allowed to moderate the chat (admins always are)
+	ChatEditWindow     time.Duration // How long authors can edit and delete their messages
+	ChatSlowMode       time.Duration // Minimum time between two messages of a player at startup (0 = off), moderators can change it
+	ChatRateLimit      int           // Maximum messages per player and minute (0 = unlimited)
+	ChatWordFilter     []string      // Words filtered from chat messages, matched case-insensitively as whole words
+	ChatWordFilterMode string        // "replace" masks filtered words, "block" rejects the message
+
 	// Games
 	PinnedGameIDs        []int  // App IDs of pinned/featured games
 	GameMetadataPath     string // Path to game_metadata.json (can be overridden via ConfigMap)
+	GameMetadataWatchInterval time.Duration // Interval for checking game_metadata.json for changes (0 = only at startup)
+
+	// Game library sync
+	GameSyncInterval   time.Duration // Interval for scheduled syncs of stale games (0 = only at login and on demand)
+	GameSyncWorkers    int           // Number of parallel Steam Store lookups
+	GameSyncBackoffMin time.Duration // First pause after Steam rate limits us, doubled on every further 429
+	GameSyncBackoffMax time.Duration // Longest pause, unless Steam asks for more via Retry-After
+
+	// Image caches
+	ImageCacheMaxMB  int           // Disk quota for cached game images in MB (0 = unlimited)
+	AvatarCacheMaxMB int           // Disk quota for cached avatars in MB (0 = unlimited)
+	ImageCacheMaxAge time.Duration // Remove cached images and avatars not requested for this long (0 = never)
+	OfflineMedia     bool          // Never send browsers to external CDNs, serve images only from the local caches
 
 	// Countdown
 	CountdownTarget time.Time // Target time for countdown (when it reaches zero, voting pause is lifted)
@@ -81,8 +126,15 @@ func Load() *Config {
 		BackendURL:  getEnv("BACKEND_URL", "http://localhost:8080"),
 
 		// Database
-		DBType: getEnv("DB_TYPE", "sqlite"),
-		DBPath: getEnv("DB_PATH", "data/rate-your-mate.db"),
+		DBType:            getEnv("DB_TYPE", "sqlite"),
+		DBPath:            getEnv("DB_PATH", "data/rate-your-mate.db"),
+		MigrateAllowDirty: getEnvAsBool("MIGRATE_ALLOW_DIRTY", false),
+
+		// SQLite backups
+		BackupDir:           getEnv("BACKUP_DIR", ""),
+		BackupBeforeMigrate: getEnvAsBool("BACKUP_BEFORE_MIGRATE", true),
+		SnapshotInterval:    getEnvAsDuration("SNAPSHOT_INTERVAL", 0),
+		SnapshotRetention:   getEnvAsInt("SNAPSHOT_RETENTION", 10),
 
 		// MySQL
 		MySQLHost:            getEnv("MYSQL_HOST", "localhost"),
@@ -98,8 +150,24 @@ func Load() *Config {
 		MySQLConnMaxLifetime: getEnvAsDuration("MYSQL_CONN_MAX_LIFETIME", 5*time.Minute),
 		MySQLConnMaxIdleTime: getEnvAsDuration("MYSQL_CONN_MAX_IDLE_TIME", 1*time.Minute),
 
+		// PostgreSQL
+		PostgresHost:            getEnv("POSTGRES_HOST", "localhost"),
+		PostgresPort:            getEnvAsInt("POSTGRES_PORT", 5432),
+		PostgresUser:            getEnv("POSTGRES_USER", ""),
+		PostgresPassword:        getEnv("POSTGRES_PASSWORD", ""),
+		PostgresDatabase:        getEnv("POSTGRES_DATABASE", ""),
+		PostgresSSLMode:         getEnv("POSTGRES_SSLMODE", "disable"),
+		PostgresMaxOpenConns:    getEnvAsInt("POSTGRES_MAX_OPEN_CONNS", 25),
+		PostgresMaxIdleConns:    getEnvAsInt("POSTGRES_MAX_IDLE_CONNS", 5),
+		PostgresConnMaxLifetime: getEnvAsDuration("POSTGRES_CONN_MAX_LIFETIME", 5*time.Minute),
+		PostgresConnMaxIdleTime: getEnvAsDuration("POSTGRES_CONN_MAX_IDLE_TIME", 1*time.Minute),
+
 		// Steam & Auth
-		SteamAPIKey:       getEnv("STEAM_API_KEY", ""),
+		SteamAPIKey:           getEnv("STEAM_API_KEY", ""),
+		SteamAPIBaseURL:       getEnv("STEAM_API_BASE_URL", "https://api.steampowered.com"),
+		SteamStoreBaseURL:     getEnv("STEAM_STORE_BASE_URL", "https://store.steampowered.com"),
+		SteamCommunityBaseURL: getEnv("STEAM_COMMUNITY_BASE_URL", "https://steamcommunity.com"),
+		SteamFakeDir:          getEnv("STEAM_FAKE_DIR", ""),
 		JWTSecret:         getEnv("JWT_SECRET", ""),
 		JWTExpirationDays: getEnvAsInt("JWT_EXPIRATION_DAYS", 7),
 
@@ -118,13 +186,39 @@ func Load() *Config {
 		AdminPassword: getEnv("ADMIN_PASSWORD", ""),
 		PinnedGameIDs: getEnvAsIntSlice("PINNED_GAME_IDS", []int{}),
 
+		// Chat
+		ModeratorSteamIDs:  getEnvAsStringSlice("MODERATOR_STEAM_IDS", []string{}),
+		ChatEditWindow:     getEnvAsDuration("CHAT_EDIT_WINDOW", 15*time.Minute),
+		ChatSlowMode:       getEnvAsDuration("CHAT_SLOW_MODE", 0),
+		ChatRateLimit:      getEnvAsInt("CHAT_RATE_LIMIT", 10),
+		ChatWordFilter:     getEnvAsStringSlice("CHAT_WORD_FILTER", []string{}),
+		ChatWordFilterMode: getEnv("CHAT_WORD_FILTER_MODE", "replace"),
+
 		// Game Metadata (default path, can be overridden via ConfigMap mount in K8s)
-		GameMetadataPath: getEnv("GAME_METADATA_PATH", "defaults/game_metadata.json"),
+		GameMetadataPath:          getEnv("GAME_METADATA_PATH", "defaults/game_metadata.json"),
+		GameMetadataWatchInterval: getEnvAsDuration("GAME_METADATA_WATCH_INTERVAL", 30*time.Second),
+
+		// Game library sync
+		GameSyncInterval:   getEnvAsDuration("GAME_SYNC_INTERVAL", 1*time.Hour),
+		GameSyncWorkers:    getEnvAsInt("GAME_SYNC_WORKERS", 2),
+		GameSyncBackoffMin: getEnvAsDuration("GAME_SYNC_BACKOFF_MIN", 1*time.Minute),
+		GameSyncBackoffMax: getEnvAsDuration("GAME_SYNC_BACKOFF_MAX", 1*time.Hour),
+
+		// Image caches
+		ImageCacheMaxMB:  getEnvAsInt("IMAGE_CACHE_MAX_MB", 500),
+		AvatarCacheMaxMB: getEnvAsInt("AVATAR_CACHE_MAX_MB", 50),
+		ImageCacheMaxAge: getEnvAsDuration("IMAGE_CACHE_MAX_AGE", 30*24*time.Hour),
+		OfflineMedia:     getEnvAsBool("OFFLINE_MEDIA", false),
 
 		// Countdown
 		CountdownTarget: getEnvAsTime("COUNTDOWN_TARGET", time.Time{}),
 	}
 
+	// Keep backups next to the database unless configured otherwise
+	if cfg.BackupDir == "" {
+		cfg.BackupDir = filepath.Join(filepath.Dir(cfg.DBPath), "backups")
+	}
+
 	// Validate required configuration
 	cfg.validate()
 
@@ -133,7 +227,7 @@ func Load() *Config {
 
 // validate checks that all required configuration is present
 func (c *Config) validate() {
-	if c.SteamAPIKey == "" {
+	if c.SteamAPIKey == "" && c.SteamFakeDir == "" {
 		log.Println("WARNING: STEAM_API_KEY is not set - Steam profile data will not be available")
 	}
 	if c.JWTSecret == "" {
@@ -235,3 +329,13 @@ func (c *Config) IsAdmin(steamID string) bool {
 	}
 	return false
 }
+
+// IsModerator checks if the given Steam ID may moderate the chat (moderators and admins)
+func (c *Config) IsModerator(steamID string) bool {
+	for _, moderatorID := range c.ModeratorSteamIDs {
+		if moderatorID == steamID {
+			return true
+		}
+	}
+	return c.IsAdmin(steamID)
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupFileTimeFormat is the timestamp format used in backup file names
+const BackupFileTimeFormat = "20060102-150405"
+
+// PreMigrationBackupPrefix is the file name prefix of backups taken before migrating
+const PreMigrationBackupPrefix = "pre-migrate-"
+
+// backupDir is the directory for pre-migration backups (empty = disabled)
+var backupDir string
+
+// BackupSQLite writes a consistent copy of the open SQLite database to destPath
+// VACUUM INTO works on a live database and produces a compacted, self-contained file
+func BackupSQLite(destPath string) error {
+	if !IsSQLite() {
+		return fmt.Errorf("backups are only supported for SQLite databases")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// VACUUM INTO refuses to overwrite existing files
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup file already exists: %s", destPath)
+	}
+
+	start := time.Now()
+	if _, err := DB.Exec(`VACUUM INTO ?`, destPath); err != nil {
+		_ = os.Remove(destPath)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	log.Printf("SQLite backup written to %s in %v", destPath, time.Since(start))
+	return nil
+}
+
+// backupBeforeMigration takes a backup if migrations are pending on an existing SQLite database
+func backupBeforeMigration(currentVersion uint, pending int) error {
+	if backupDir == "" || pending == 0 || currentVersion == 0 {
+		// Nothing to protect: backups disabled, up to date or a fresh database
+		return nil
+	}
+
+	name := fmt.Sprintf("%sv%d-%s.db", PreMigrationBackupPrefix, currentVersion, time.Now().Format(BackupFileTimeFormat))
+	log.Printf("%d pending migration(s), backing up database before migrating", pending)
+	return BackupSQLite(filepath.Join(backupDir, name))
+}
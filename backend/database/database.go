@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 )
 
 // DBType represents the type of database being used
@@ -14,8 +15,24 @@ const (
 	DBTypeSQLite DBType = "sqlite"
 	// DBTypeMySQL represents MySQL database
 	DBTypeMySQL DBType = "mysql"
+	// DBTypePostgres represents PostgreSQL database
+	DBTypePostgres DBType = "postgres"
 )
 
+// displayName returns the human readable name of a database type for log messages
+func (t DBType) displayName() string {
+	switch t {
+	case DBTypeSQLite:
+		return "SQLite"
+	case DBTypeMySQL:
+		return "MySQL"
+	case DBTypePostgres:
+		return "PostgreSQL"
+	default:
+		return string(t)
+	}
+}
+
 // DB holds the global database connection
 var DB *sql.DB
 
@@ -37,9 +54,14 @@ func IsMySQL() bool {
 	return dbType == DBTypeMySQL
 }
 
+// IsPostgres returns true if the current database is PostgreSQL
+func IsPostgres() bool {
+	return dbType == DBTypePostgres
+}
+
 // Config holds database configuration for initialization
 type Config struct {
-	// Type of database: "sqlite" or "mysql"
+	// Type of database: "sqlite", "mysql" or "postgres"
 	Type DBType
 
 	// SQLite configuration
@@ -47,6 +69,20 @@ type Config struct {
 
 	// MySQL configuration
 	MySQL MySQLConfig
+
+	// PostgreSQL configuration
+	Postgres PostgresConfig
+
+	// SkipMigrations opens the connection without applying pending migrations
+	// (used by the migrate CLI commands, which manage the schema themselves)
+	SkipMigrations bool
+
+	// AllowDirty starts even if a previous migration failed half-way and left the schema dirty
+	AllowDirty bool
+
+	// BackupDir is where SQLite backups are written before pending migrations are applied
+	// (empty disables pre-migration backups)
+	BackupDir string
 }
 
 // Init initializes the database connection based on configuration
@@ -59,7 +95,11 @@ func Init(cfg Config) error {
 		if err := initSQLite(cfg.SQLitePath); err != nil {
 			return err
 		}
-		return runMigrations(DBTypeSQLite)
+		backupDir = cfg.BackupDir
+		if cfg.SkipMigrations {
+			return nil
+		}
+		return runMigrations(DBTypeSQLite, cfg.AllowDirty)
 
 	case DBTypeMySQL:
 		if cfg.MySQL.Host == "" || cfg.MySQL.Database == "" {
@@ -68,7 +108,22 @@ func Init(cfg Config) error {
 		if err := initMySQL(cfg.MySQL); err != nil {
 			return err
 		}
-		return runMigrations(DBTypeMySQL)
+		if cfg.SkipMigrations {
+			return nil
+		}
+		return runMigrations(DBTypeMySQL, cfg.AllowDirty)
+
+	case DBTypePostgres:
+		if cfg.Postgres.Host == "" || cfg.Postgres.Database == "" {
+			return fmt.Errorf("PostgreSQL host and database are required")
+		}
+		if err := initPostgres(cfg.Postgres); err != nil {
+			return err
+		}
+		if cfg.SkipMigrations {
+			return nil
+		}
+		return runMigrations(DBTypePostgres, cfg.AllowDirty)
 
 	default:
 		return fmt.Errorf("unsupported database type: %s", cfg.Type)
@@ -92,6 +147,14 @@ func InitMySQL(cfg MySQLConfig) error {
 	})
 }
 
+// InitPostgres is a convenience function to initialize PostgreSQL database
+func InitPostgres(cfg PostgresConfig) error {
+	return Init(Config{
+		Type:     DBTypePostgres,
+		Postgres: cfg,
+	})
+}
+
 // Close closes the database connection
 func Close() error {
 	if DB != nil {
@@ -101,6 +164,68 @@ func Close() error {
 	return nil
 }
 
+// Execer is implemented by both *sql.DB and *sql.Tx
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// InsertReturningID executes an INSERT statement and returns the ID of the new row
+// PostgreSQL does not report LastInsertId, so the ID is read via RETURNING there
+func InsertReturningID(db Execer, query string, args ...interface{}) (int64, error) {
+	if IsPostgres() {
+		var id int64
+		if err := db.QueryRow(query+" RETURNING id", args...).Scan(&id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpsertSQL returns an INSERT statement for the current database that updates updateCols with the
+// inserted values when a row with the same conflictCols exists, or skips the row without updateCols.
+// values are the VALUES expressions, empty means one ? placeholder per column.
+func UpsertSQL(table string, cols []string, values string, conflictCols, updateCols []string) string {
+	return upsertSQL(dbType, table, cols, values, conflictCols, updateCols)
+}
+
+// upsertSQL builds the upsert statement for a database type
+func upsertSQL(t DBType, table string, cols []string, values string, conflictCols, updateCols []string) string {
+	if values == "" {
+		values = strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+	}
+	insert := "INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + values + ")"
+
+	if t == DBTypeMySQL {
+		// MySQL/MariaDB has no conflict target, it checks all unique keys
+		if len(updateCols) == 0 {
+			return "INSERT IGNORE" + strings.TrimPrefix(insert, "INSERT")
+		}
+		sets := make([]string, len(updateCols))
+		for i, col := range updateCols {
+			sets[i] = col + " = VALUES(" + col + ")"
+		}
+		return insert + " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	}
+
+	// SQLite and PostgreSQL share the ON CONFLICT syntax
+	conflict := " ON CONFLICT (" + strings.Join(conflictCols, ", ") + ")"
+	if len(updateCols) == 0 {
+		return insert + conflict + " DO NOTHING"
+	}
+	sets := make([]string, len(updateCols))
+	for i, col := range updateCols {
+		sets[i] = col + " = excluded." + col
+	}
+	return insert + conflict + " DO UPDATE SET " + strings.Join(sets, ", ")
+}
+
 // WithTransaction executes a function within a transaction with retry support (for SQLite)
 // If the function returns an error, the transaction is rolled back
 // If the function succeeds, the transaction is committed
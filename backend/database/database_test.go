@@ -0,0 +1,32 @@
+package database
+
+import "testing"
+
+func TestUpsertSQL(t *testing.T) {
+	cols := []string{"user_id", "app_id", "status", "updated_at"}
+	tests := []struct {
+		name       string
+		dbType     DBType
+		values     string
+		updateCols []string
+		want       string
+	}{
+		{"sqlite update", DBTypeSQLite, "", []string{"status", "updated_at"},
+			`INSERT INTO t (user_id, app_id, status, updated_at) VALUES (?, ?, ?, ?) ON CONFLICT (user_id, app_id) DO UPDATE SET status = excluded.status, updated_at = excluded.updated_at`},
+		{"postgres ignore", DBTypePostgres, "", nil,
+			`INSERT INTO t (user_id, app_id, status, updated_at) VALUES (?, ?, ?, ?) ON CONFLICT (user_id, app_id) DO NOTHING`},
+		{"mysql update", DBTypeMySQL, "?, ?, ?, CURRENT_TIMESTAMP", []string{"status", "updated_at"},
+			`INSERT INTO t (user_id, app_id, status, updated_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP) ON DUPLICATE KEY UPDATE status = VALUES(status), updated_at = VALUES(updated_at)`},
+		{"mysql ignore", DBTypeMySQL, "", nil,
+			`INSERT IGNORE INTO t (user_id, app_id, status, updated_at) VALUES (?, ?, ?, ?)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := upsertSQL(tt.dbType, "t", cols, tt.values, []string{"user_id", "app_id"}, tt.updateCols)
+			if got != tt.want {
+				t.Errorf("upsertSQL() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
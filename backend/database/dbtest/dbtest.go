@@ -0,0 +1,149 @@
+// Package dbtest provides a shared harness for tests that need a migrated database.
+//
+// Every test runs against an in-memory SQLite database. MySQL and PostgreSQL are
+// added when a local instance is configured via environment variables:
+//
+//	TEST_MYSQL_HOST, TEST_MYSQL_PORT, TEST_MYSQL_USER, TEST_MYSQL_PASSWORD, TEST_MYSQL_DATABASE
+//	TEST_POSTGRES_HOST, TEST_POSTGRES_PORT, TEST_POSTGRES_USER, TEST_POSTGRES_PASSWORD, TEST_POSTGRES_DATABASE
+//
+// The server databases are wiped before each test, so never point them at real event data.
+package dbtest
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+)
+
+// tables lists all data tables in deletion order (children before parents)
+var tables = []string{"custom_games", "game_metadata", "sync_jobs", "player_ratings", "game_match_players", "game_matches", "tournament_matches", "tournament_team_members", "tournament_teams", "tournament_registrations", "tournaments", "game_session_players", "game_sessions", "game_install_status", "poll_ballots", "poll_options", "polls", "chat_mutes", "chat_reactions", "chat_channel_members", "chat_messages", "chat_channels", "direct_messages", "votes", "game_owners", "game_cache", "banned_users", "users"}
+
+// resetWhere limits the reset of tables with rows seeded by the migrations to the other rows
+var resetWhere = map[string]string{
+	"chat_channels": "kind = 'game'",
+}
+
+// memoryDBCounter gives every in-memory SQLite database a unique name
+var memoryDBCounter atomic.Int64
+
+// Backend describes a database the tests can run against
+type Backend struct {
+	Name   string
+	Config database.Config
+}
+
+// Backends returns in-memory SQLite plus every server database configured in the environment
+func Backends() []Backend {
+	backends := []Backend{{Name: string(database.DBTypeSQLite), Config: database.Config{Type: database.DBTypeSQLite}}}
+
+	if host := os.Getenv("TEST_MYSQL_HOST"); host != "" {
+		cfg := database.DefaultMySQLConfig()
+		cfg.Host = host
+		cfg.Port = envAsInt("TEST_MYSQL_PORT", cfg.Port)
+		cfg.User = envOr("TEST_MYSQL_USER", "root")
+		cfg.Password = os.Getenv("TEST_MYSQL_PASSWORD")
+		cfg.Database = envOr("TEST_MYSQL_DATABASE", "rate_your_mate_test")
+		backends = append(backends, Backend{Name: string(database.DBTypeMySQL), Config: database.Config{Type: database.DBTypeMySQL, MySQL: cfg}})
+	}
+
+	if host := os.Getenv("TEST_POSTGRES_HOST"); host != "" {
+		cfg := database.DefaultPostgresConfig()
+		cfg.Host = host
+		cfg.Port = envAsInt("TEST_POSTGRES_PORT", cfg.Port)
+		cfg.User = envOr("TEST_POSTGRES_USER", "postgres")
+		cfg.Password = os.Getenv("TEST_POSTGRES_PASSWORD")
+		cfg.Database = envOr("TEST_POSTGRES_DATABASE", "rate_your_mate_test")
+		backends = append(backends, Backend{Name: string(database.DBTypePostgres), Config: database.Config{Type: database.DBTypePostgres, Postgres: cfg}})
+	}
+
+	return backends
+}
+
+// Run runs fn as a subtest once per backend, each time with a fresh, fully migrated database
+// The tests share the global database.DB connection and must not run in parallel.
+func Run(t *testing.T, fn func(t *testing.T)) {
+	t.Helper()
+
+	for _, backend := range Backends() {
+		t.Run(backend.Name, func(t *testing.T) {
+			Open(t, backend)
+			fn(t)
+		})
+	}
+}
+
+// Open connects database.DB to the backend and closes it again when the test ends
+func Open(t testing.TB, backend Backend) {
+	t.Helper()
+
+	cfg := backend.Config
+	if cfg.Type == database.DBTypeSQLite {
+		// Shared cache keeps the in-memory database alive across the pooled connections
+		cfg.SQLitePath = fmt.Sprintf("file:rym-test-%d?mode=memory&cache=shared", memoryDBCounter.Add(1))
+	}
+
+	if err := database.Init(cfg); err != nil {
+		t.Fatalf("failed to open %s test database: %v", backend.Name, err)
+	}
+	t.Cleanup(func() {
+		database.Close()
+		database.DB = nil
+	})
+
+	if cfg.Type != database.DBTypeSQLite {
+		Reset(t)
+	}
+}
+
+// Reset deletes all rows from the data tables
+func Reset(t testing.TB) {
+	t.Helper()
+
+	for _, table := range tables {
+		query := `DELETE FROM ` + table
+		if where, ok := resetWhere[table]; ok {
+			query += ` WHERE ` + where
+		}
+		if _, err := database.DB.Exec(query); err != nil {
+			t.Fatalf("failed to reset table %s: %v", table, err)
+		}
+	}
+}
+
+// Exec runs a statement and fails the test on error
+// Statements use "?" placeholders, which are rewritten for PostgreSQL.
+func Exec(t testing.TB, query string, args ...interface{}) {
+	t.Helper()
+
+	if _, err := database.DB.Exec(query, args...); err != nil {
+		t.Fatalf("failed to execute %q: %v", query, err)
+	}
+}
+
+// Timestamp returns a fixed point in time relative to a base, truncated to whole seconds
+// so it round-trips through every backend's timestamp column
+func Timestamp(offset time.Duration) time.Time {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	return base.Add(offset).Truncate(time.Second)
+}
+
+// envOr returns the environment variable or a default value
+func envOr(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// envAsInt returns the environment variable as int or a default value
+func envAsInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -2,12 +2,16 @@ package database
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
@@ -17,84 +21,227 @@ var mysqlMigrations embed.FS
 //go:embed migrations/sqlite/*.sql
 var sqliteMigrations embed.FS
 
-// runMigrations runs database migrations using golang-migrate
-func runMigrations(dbType DBType) error {
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// MigrationStatus describes the schema version of the connected database
+type MigrationStatus struct {
+	Version uint `json:"version"` // Currently applied version (0 = no migrations applied)
+	Dirty   bool `json:"dirty"`   // True if a migration failed half-way
+	Latest  uint `json:"latest"`  // Highest version available in the embedded migrations
+	Pending int  `json:"pending"` // Number of embedded migrations not yet applied
+}
+
+// newMigrationSource creates the embedded migration source for the given database type
+func newMigrationSource(dbType DBType) (source.Driver, error) {
+	var sourceDriver source.Driver
+	var err error
+
 	switch dbType {
 	case DBTypeMySQL:
-		return runMySQLMigrationsV2()
+		sourceDriver, err = iofs.New(mysqlMigrations, "migrations/mysql")
 	case DBTypeSQLite:
-		return runSQLiteMigrationsV2()
+		sourceDriver, err = iofs.New(sqliteMigrations, "migrations/sqlite")
+	case DBTypePostgres:
+		sourceDriver, err = iofs.New(postgresMigrations, "migrations/postgres")
 	default:
-		return fmt.Errorf("unsupported database type for migrations: %s", dbType)
+		return nil, fmt.Errorf("unsupported database type for migrations: %s", dbType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration source: %w", err)
 	}
+
+	return sourceDriver, nil
 }
 
-// runMySQLMigrationsV2 runs MySQL migrations using golang-migrate
-func runMySQLMigrationsV2() error {
-	// Create the source driver from embedded files
-	sourceDriver, err := iofs.New(mysqlMigrations, "migrations/mysql")
+// newMigrate creates a migrate instance for the current database connection
+// The instance must not be closed, as that would close the shared DB connection
+func newMigrate(dbType DBType) (*migrate.Migrate, error) {
+	sourceDriver, err := newMigrationSource(dbType)
 	if err != nil {
-		return fmt.Errorf("failed to create migration source: %w", err)
+		return nil, err
 	}
 
-	// Create the database driver
-	dbDriver, err := mysql.WithInstance(DB, &mysql.Config{})
-	if err != nil {
-		return fmt.Errorf("failed to create MySQL migration driver: %w", err)
+	switch dbType {
+	case DBTypeMySQL:
+		dbDriver, err := mysql.WithInstance(DB, &mysql.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create MySQL migration driver: %w", err)
+		}
+		m, err := migrate.NewWithInstance("iofs", sourceDriver, "mysql", dbDriver)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+		}
+		return m, nil
+
+	case DBTypeSQLite:
+		dbDriver, err := sqlite.WithInstance(DB, &sqlite.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SQLite migration driver: %w", err)
+		}
+		m, err := migrate.NewWithInstance("iofs", sourceDriver, "sqlite", dbDriver)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+		}
+		return m, nil
+
+	case DBTypePostgres:
+		dbDriver, err := postgres.WithInstance(DB, &postgres.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL migration driver: %w", err)
+		}
+		m, err := migrate.NewWithInstance("iofs", sourceDriver, "postgres", dbDriver)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+		}
+		return m, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported database type for migrations: %s", dbType)
 	}
+}
 
-	// Create the migrate instance
-	m, err := migrate.NewWithInstance("iofs", sourceDriver, "mysql", dbDriver)
+// ErrDirtySchema is returned when the schema is marked dirty by a previously failed migration
+var ErrDirtySchema = errors.New("database schema is dirty")
+
+// runMigrations runs database migrations using golang-migrate
+// A dirty schema (left behind by a failed migration) is refused unless allowDirty is set,
+// in which case pending migrations are skipped and the schema is used as is.
+func runMigrations(dbType DBType, allowDirty bool) error {
+	m, err := newMigrate(dbType)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+	if dirty {
+		if !allowDirty {
+			return fmt.Errorf("%w at version %d: fix the schema manually and run 'migrate force <version>', or set MIGRATE_ALLOW_DIRTY=true to start anyway", ErrDirtySchema, version)
+		}
+		log.Printf("Warning: %s schema is dirty at version %d, skipping migrations (MIGRATE_ALLOW_DIRTY is set)", dbType.displayName(), version)
+		return nil
+	}
+
+	// Back up SQLite databases before changing the schema
+	if dbType == DBTypeSQLite {
+		_, pending, err := pendingMigrations(dbType, version)
+		if err != nil {
+			return err
+		}
+		if err := backupBeforeMigration(version, pending); err != nil {
+			return fmt.Errorf("pre-migration backup failed, not migrating: %w", err)
+		}
 	}
 
 	// Run migrations
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("MySQL migration failed: %w", err)
+		return fmt.Errorf("%s migration failed: %w", dbType.displayName(), err)
 	}
 
-	version, dirty, _ := m.Version()
+	version, dirty, _ = m.Version()
 	if dirty {
-		log.Printf("Warning: MySQL migrations are in dirty state at version %d", version)
+		return fmt.Errorf("%w at version %d after migrating", ErrDirtySchema, version)
+	}
+	log.Printf("%s migrations completed (version: %d)", dbType.displayName(), version)
+
+	return nil
+}
+
+// MigrateUp applies all pending migrations
+func MigrateUp() error {
+	return runMigrations(dbType, false)
+}
+
+// MigrateDown rolls back the given number of migrations (steps <= 0 rolls back everything)
+func MigrateDown(steps int) error {
+	m, err := newMigrate(dbType)
+	if err != nil {
+		return err
+	}
+
+	if steps <= 0 {
+		err = m.Down()
 	} else {
-		log.Printf("MySQL migrations completed (version: %d)", version)
+		err = m.Steps(-steps)
+	}
+	if err != nil && err != migrate.ErrNoChange && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s migration rollback failed: %w", dbType.displayName(), err)
 	}
 
+	version, dirty, _ := m.Version()
+	log.Printf("%s migrations rolled back (version: %d, dirty: %t)", dbType.displayName(), version, dirty)
 	return nil
 }
 
-// runSQLiteMigrationsV2 runs SQLite migrations using golang-migrate
-func runSQLiteMigrationsV2() error {
-	// Create the source driver from embedded files
-	sourceDriver, err := iofs.New(sqliteMigrations, "migrations/sqlite")
+// pendingMigrations walks the embedded migrations and returns the latest available version
+// and the number of migrations newer than the given version
+func pendingMigrations(dbType DBType, version uint) (uint, int, error) {
+	sourceDriver, err := newMigrationSource(dbType)
 	if err != nil {
-		return fmt.Errorf("failed to create migration source: %w", err)
+		return 0, 0, err
 	}
+	defer sourceDriver.Close()
+
+	var latest uint
+	pending := 0
+	v, err := sourceDriver.First()
+	for err == nil {
+		latest = v
+		if v > version {
+			pending++
+		}
+		v, err = sourceDriver.Next(v)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, 0, fmt.Errorf("failed to read migration source: %w", err)
+	}
+
+	return latest, pending, nil
+}
 
-	// Create the database driver
-	dbDriver, err := sqlite.WithInstance(DB, &sqlite.Config{})
+// GetMigrationStatus returns the applied and available schema versions
+func GetMigrationStatus() (*MigrationStatus, error) {
+	m, err := newMigrate(dbType)
 	if err != nil {
-		return fmt.Errorf("failed to create SQLite migration driver: %w", err)
+		return nil, err
+	}
+
+	status := &MigrationStatus{}
+	version, dirty, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return nil, fmt.Errorf("failed to get migration version: %w", err)
 	}
+	status.Version = version
+	status.Dirty = dirty
 
-	// Create the migrate instance
-	m, err := migrate.NewWithInstance("iofs", sourceDriver, "sqlite", dbDriver)
+	status.Latest, status.Pending, err = pendingMigrations(dbType, status.Version)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, err
 	}
 
-	// Run migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("SQLite migration failed: %w", err)
+	return status, nil
+}
+
+// ForceMigrationVersion sets the schema version without running any migration and clears
+// the dirty flag. Use it after manually repairing a schema left behind by a failed migration.
+// A version of -1 marks the database as having no migrations applied.
+func ForceMigrationVersion(version int) error {
+	if version < -1 {
+		return fmt.Errorf("invalid migration version %d", version)
 	}
 
-	version, dirty, _ := m.Version()
-	if dirty {
-		log.Printf("Warning: SQLite migrations are in dirty state at version %d", version)
-	} else {
-		log.Printf("SQLite migrations completed (version: %d)", version)
+	m, err := newMigrate(dbType)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version: %w", err)
 	}
 
+	log.Printf("%s migration version forced to %d", dbType.displayName(), version)
 	return nil
 }
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// openTestSQLite opens a fresh SQLite database in a temporary directory
+func openTestSQLite(t *testing.T) {
+	t.Helper()
+
+	if err := initSQLite(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() {
+		Close()
+		DB = nil
+	})
+}
+
+// sqliteTables returns the names of all user tables
+func sqliteTables(t *testing.T) []string {
+	t.Helper()
+
+	rows, err := DB.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		t.Fatalf("failed to list tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("failed to scan table name: %v", err)
+		}
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// countRows returns the number of rows in a table
+func countRows(t *testing.T, table string) int {
+	t.Helper()
+
+	var count int
+	if err := DB.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&count); err != nil {
+		t.Fatalf("failed to count rows in %s: %v", table, err)
+	}
+	return count
+}
+
+func TestSQLiteMigrationsAreReversible(t *testing.T) {
+	openTestSQLite(t)
+
+	status, err := GetMigrationStatus()
+	if err != nil {
+		t.Fatalf("failed to get migration status: %v", err)
+	}
+	if status.Version != 0 || status.Pending == 0 {
+		t.Fatalf("expected empty database with pending migrations, got %+v", status)
+	}
+	total := status.Pending
+
+	m, err := newMigrate(DBTypeSQLite)
+	if err != nil {
+		t.Fatalf("failed to create migrate instance: %v", err)
+	}
+
+	// Apply every up migration one by one
+	var previous uint
+	for i := 0; i < total; i++ {
+		if err := m.Steps(1); err != nil {
+			t.Fatalf("up migration %d/%d failed: %v", i+1, total, err)
+		}
+		version, dirty, err := m.Version()
+		if err != nil || dirty || version <= previous {
+			t.Fatalf("unexpected state after up migration %d: version=%d dirty=%t err=%v", i+1, version, dirty, err)
+		}
+		previous = version
+	}
+	if previous != status.Latest {
+		t.Fatalf("expected latest version %d, got %d", status.Latest, previous)
+	}
+
+	// Existing data must survive every down migration except the one dropping its table
+	if _, err := DB.Exec(`INSERT INTO users (steam_id, username, avatar_url, avatar_small, profile_url) VALUES ('FAKE_1', 'one', '', '', ''), ('FAKE_2', 'two', '', '', '')`); err != nil {
+		t.Fatalf("failed to insert users: %v", err)
+	}
+	if _, err := DB.Exec(`INSERT INTO votes (from_user_id, to_user_id, achievement_id, points) VALUES (1, 2, 'pro-player', 1)`); err != nil {
+		t.Fatalf("failed to insert vote: %v", err)
+	}
+
+	// Apply every down migration one by one
+	for i := total; i > 0; i-- {
+		if err := m.Steps(-1); err != nil {
+			t.Fatalf("down migration from version %d failed: %v", previous, err)
+		}
+
+		version, dirty, err := m.Version()
+		if i > 1 {
+			if err != nil || dirty || version >= previous {
+				t.Fatalf("unexpected state after down migration: version=%d dirty=%t err=%v", version, dirty, err)
+			}
+			if countRows(t, "users") != 2 || countRows(t, "votes") != 1 {
+				t.Fatalf("data was lost rolling back to version %d", version)
+			}
+			previous = version
+		}
+	}
+
+	if tables := sqliteTables(t); len(tables) != 1 || tables[0] != "schema_migrations" {
+		t.Fatalf("expected only schema_migrations after rolling back everything, got %v", tables)
+	}
+
+	// The rolled back database must be migratable again
+	if err := runMigrations(DBTypeSQLite, false); err != nil {
+		t.Fatalf("re-applying migrations failed: %v", err)
+	}
+}
+
+func TestDirtySchemaIsRefused(t *testing.T) {
+	openTestSQLite(t)
+
+	if err := runMigrations(DBTypeSQLite, false); err != nil {
+		t.Fatalf("initial migration failed: %v", err)
+	}
+	status, err := GetMigrationStatus()
+	if err != nil {
+		t.Fatalf("failed to get migration status: %v", err)
+	}
+
+	// Simulate a migration that failed half-way
+	if _, err := DB.Exec(`UPDATE schema_migrations SET dirty = 1`); err != nil {
+		t.Fatalf("failed to mark schema dirty: %v", err)
+	}
+
+	if err := runMigrations(DBTypeSQLite, false); !errors.Is(err, ErrDirtySchema) {
+		t.Fatalf("expected ErrDirtySchema, got %v", err)
+	}
+	if err := runMigrations(DBTypeSQLite, true); err != nil {
+		t.Fatalf("expected dirty schema to be accepted when forced, got %v", err)
+	}
+
+	if err := ForceMigrationVersion(int(status.Version)); err != nil {
+		t.Fatalf("failed to force version: %v", err)
+	}
+	if err := runMigrations(DBTypeSQLite, false); err != nil {
+		t.Fatalf("expected clean schema after forcing the version, got %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+-- Remove game polls (MySQL)
+
+DROP TABLE IF EXISTS poll_ballots;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,5 @@
+-- Remove install/readiness tracking and planned game sessions (MySQL)
+
+DROP TABLE IF EXISTS game_session_players;
+DROP TABLE IF EXISTS game_sessions;
+DROP TABLE IF EXISTS game_install_status;
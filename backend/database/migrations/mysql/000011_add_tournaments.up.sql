@@ -0,0 +1,65 @@
+-- Add tournaments with teams and bracket matches (MySQL)
+
+CREATE TABLE IF NOT EXISTS tournaments (
+    id BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    app_id BIGINT UNSIGNED NOT NULL,
+    format VARCHAR(30) NOT NULL,
+    team_size INT NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'registration',
+    bonus_points INT NOT NULL DEFAULT 0,
+    created_by BIGINT UNSIGNED NOT NULL,
+    winner_team_id BIGINT UNSIGNED DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    started_at DATETIME DEFAULT NULL,
+    finished_at DATETIME DEFAULT NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS tournament_registrations (
+    tournament_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tournament_id, user_id),
+    FOREIGN KEY (tournament_id) REFERENCES tournaments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS tournament_teams (
+    id BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+    tournament_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    seed INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (tournament_id) REFERENCES tournaments(id) ON DELETE CASCADE,
+    INDEX idx_tournament_teams_tournament (tournament_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS tournament_team_members (
+    team_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (team_id, user_id),
+    FOREIGN KEY (team_id) REFERENCES tournament_teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS tournament_matches (
+    id BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+    tournament_id BIGINT UNSIGNED NOT NULL,
+    bracket VARCHAR(20) NOT NULL,
+    round INT NOT NULL,
+    position INT NOT NULL,
+    team1_id BIGINT UNSIGNED DEFAULT NULL,
+    team2_id BIGINT UNSIGNED DEFAULT NULL,
+    score1 INT DEFAULT NULL,
+    score2 INT DEFAULT NULL,
+    winner_team_id BIGINT UNSIGNED DEFAULT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    is_bye TINYINT(1) NOT NULL DEFAULT 0,
+    reported_by BIGINT UNSIGNED DEFAULT NULL,
+    winner_next_match_id BIGINT UNSIGNED DEFAULT NULL,
+    winner_next_slot INT NOT NULL DEFAULT 0,
+    loser_next_match_id BIGINT UNSIGNED DEFAULT NULL,
+    loser_next_slot INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (tournament_id) REFERENCES tournaments(id) ON DELETE CASCADE,
+    INDEX idx_tournament_matches_tournament (tournament_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,38 @@
+-- Add match history and per-game Elo ratings (MySQL)
+
+CREATE TABLE IF NOT EXISTS game_matches (
+    id BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+    app_id BIGINT UNSIGNED NOT NULL,
+    winner_team INT DEFAULT NULL,
+    recorded_by BIGINT UNSIGNED NOT NULL,
+    played_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (recorded_by) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_game_matches_app_id (app_id, played_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS game_match_players (
+    match_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    team INT NOT NULL,
+    rating_before DOUBLE NOT NULL,
+    rating_after DOUBLE NOT NULL,
+    PRIMARY KEY (match_id, user_id),
+    FOREIGN KEY (match_id) REFERENCES game_matches(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_game_match_players_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS player_ratings (
+    user_id BIGINT UNSIGNED NOT NULL,
+    app_id BIGINT UNSIGNED NOT NULL,
+    rating DOUBLE NOT NULL,
+    matches INT NOT NULL DEFAULT 0,
+    wins INT NOT NULL DEFAULT 0,
+    losses INT NOT NULL DEFAULT 0,
+    draws INT NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, app_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_player_ratings_app_id (app_id, rating)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
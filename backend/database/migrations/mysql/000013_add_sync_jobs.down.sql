@@ -0,0 +1,3 @@
+-- Remove persisted state of background sync jobs (MySQL)
+
+DROP TABLE IF EXISTS sync_jobs;
@@ -0,0 +1,14 @@
+-- Add persisted state of background sync jobs (MySQL)
+
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    name VARCHAR(50) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'idle',
+    processed INT NOT NULL DEFAULT 0,
+    total INT NOT NULL DEFAULT 0,
+    failures INT NOT NULL DEFAULT 0,
+    paused_until DATETIME DEFAULT NULL,
+    last_error TEXT NOT NULL,
+    started_at DATETIME DEFAULT NULL,
+    finished_at DATETIME DEFAULT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+-- Add curated game metadata (MySQL)
+
+CREATE TABLE IF NOT EXISTS game_metadata (
+    app_id INT PRIMARY KEY,
+    max_players INT NOT NULL DEFAULT 0,
+    min_players INT NOT NULL DEFAULT 0,
+    lan_capable TINYINT(1) NOT NULL DEFAULT 0,
+    dedicated_server_required TINYINT(1) NOT NULL DEFAULT 0,
+    install_size_mb INT NOT NULL DEFAULT 0,
+    tags TEXT NOT NULL,
+    notes TEXT NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'admin',
+    updated_by VARCHAR(50) NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,12 @@
+-- Add custom non-Steam games (MySQL)
+
+CREATE TABLE IF NOT EXISTS custom_games (
+    id BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    categories TEXT NOT NULL,
+    max_players INT NOT NULL DEFAULT 0,
+    has_image TINYINT(1) NOT NULL DEFAULT 0,
+    created_by VARCHAR(50) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
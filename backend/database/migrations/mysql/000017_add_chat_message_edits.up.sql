@@ -0,0 +1,4 @@
+-- Add edited_at column to chat_messages table (MySQL)
+-- Set when the author edited a message
+
+ALTER TABLE chat_messages ADD COLUMN edited_at DATETIME DEFAULT NULL;
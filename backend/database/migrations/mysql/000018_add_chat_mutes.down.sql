@@ -0,0 +1,3 @@
+-- Remove chat mutes (MySQL)
+
+DROP TABLE IF EXISTS chat_mutes;
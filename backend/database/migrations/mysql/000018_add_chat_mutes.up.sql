@@ -0,0 +1,11 @@
+-- Add chat mutes (MySQL)
+
+-- One active mute per player, expired mutes are ignored and replaced by the next one
+CREATE TABLE IF NOT EXISTS chat_mutes (
+    user_id BIGINT UNSIGNED PRIMARY KEY,
+    reason TEXT NOT NULL,
+    muted_by VARCHAR(20) NOT NULL,
+    muted_until DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
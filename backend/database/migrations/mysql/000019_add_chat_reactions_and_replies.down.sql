@@ -0,0 +1,7 @@
+-- Remove chat reactions and replies (MySQL)
+
+DROP TABLE IF EXISTS chat_reactions;
+ALTER TABLE chat_messages
+    DROP FOREIGN KEY fk_chat_messages_parent,
+    DROP INDEX idx_chat_messages_parent,
+    DROP COLUMN parent_id;
@@ -0,0 +1,18 @@
+-- Add chat reactions and replies (MySQL)
+
+-- Replies keep their text when the parent message is deleted
+ALTER TABLE chat_messages
+    ADD COLUMN parent_id BIGINT UNSIGNED DEFAULT NULL,
+    ADD INDEX idx_chat_messages_parent (parent_id),
+    ADD CONSTRAINT fk_chat_messages_parent FOREIGN KEY (parent_id) REFERENCES chat_messages(id) ON DELETE SET NULL;
+
+-- Each player can react to a message once per emoji
+CREATE TABLE IF NOT EXISTS chat_reactions (
+    message_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, user_id, emoji),
+    FOREIGN KEY (message_id) REFERENCES chat_messages(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- Remove direct messages between players (MySQL)
+
+DROP TABLE IF EXISTS direct_messages;
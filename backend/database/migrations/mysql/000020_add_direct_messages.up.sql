@@ -0,0 +1,14 @@
+-- Add direct messages between players (MySQL)
+
+CREATE TABLE IF NOT EXISTS direct_messages (
+    id BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+    sender_id BIGINT UNSIGNED NOT NULL,
+    recipient_id BIGINT UNSIGNED NOT NULL,
+    message TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    read_at DATETIME DEFAULT NULL,
+    FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (recipient_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_direct_messages_conversation (sender_id, recipient_id, id),
+    INDEX idx_direct_messages_unread (recipient_id, read_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
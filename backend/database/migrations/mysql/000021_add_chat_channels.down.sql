@@ -0,0 +1,8 @@
+-- Remove chat channels (MySQL)
+
+DROP TABLE IF EXISTS chat_channel_members;
+ALTER TABLE chat_messages
+    DROP FOREIGN KEY fk_chat_messages_channel,
+    DROP INDEX idx_chat_messages_channel,
+    DROP COLUMN channel_id;
+DROP TABLE IF EXISTS chat_channels;
@@ -0,0 +1,30 @@
+-- Add chat channels (MySQL)
+
+-- Channels are global, per game or admin announcements; the first two always exist
+CREATE TABLE IF NOT EXISTS chat_channels (
+    id BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    app_id INT DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO chat_channels (id, slug, name, kind) VALUES (1, 'general', 'General', 'global');
+INSERT INTO chat_channels (id, slug, name, kind) VALUES (2, 'announcements', 'Announcements', 'announcements');
+
+-- Existing messages belong to the general channel
+ALTER TABLE chat_messages
+    ADD COLUMN channel_id BIGINT UNSIGNED NOT NULL DEFAULT 1,
+    ADD INDEX idx_chat_messages_channel (channel_id, id),
+    ADD CONSTRAINT fk_chat_messages_channel FOREIGN KEY (channel_id) REFERENCES chat_channels(id) ON DELETE CASCADE;
+
+-- Players who joined a game channel
+CREATE TABLE IF NOT EXISTS chat_channel_members (
+    channel_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    joined_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, user_id),
+    FOREIGN KEY (channel_id) REFERENCES chat_channels(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+-- Rollback initial schema (PostgreSQL)
+
+DROP TABLE IF EXISTS game_cache;
+DROP TABLE IF EXISTS chat_messages;
+DROP TABLE IF EXISTS votes;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,59 @@
+-- Initial schema for rate-your-mate (PostgreSQL)
+-- Boolean flags are stored as SMALLINT (0/1) like on SQLite and MySQL, so the
+-- repositories can compare them against 0 and 1 on every backend.
+
+-- Users table
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    steam_id VARCHAR(20) UNIQUE NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    avatar_url TEXT,
+    avatar_small TEXT,
+    profile_url TEXT,
+    credits INTEGER DEFAULT 0,
+    last_credit_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Votes table
+CREATE TABLE IF NOT EXISTS votes (
+    id BIGSERIAL PRIMARY KEY,
+    from_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id VARCHAR(50) NOT NULL,
+    points INTEGER DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_no_self_vote CHECK (from_user_id != to_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_votes_achievement ON votes(achievement_id, to_user_id);
+CREATE INDEX IF NOT EXISTS idx_votes_timeline ON votes(created_at DESC);
+
+-- Chat messages table
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    achievements TEXT DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_timeline ON chat_messages(created_at DESC);
+
+-- Game cache table for Steam Store data
+CREATE TABLE IF NOT EXISTS game_cache (
+    app_id BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    categories TEXT DEFAULT '[]',
+    is_free SMALLINT DEFAULT 0,
+    price_cents INTEGER DEFAULT 0,
+    original_cents INTEGER DEFAULT 0,
+    discount_percent INTEGER DEFAULT 0,
+    price_formatted VARCHAR(50) DEFAULT '',
+    fetch_failed SMALLINT DEFAULT 0,
+    review_score INTEGER DEFAULT -1,
+    fetched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_cache_fetched ON game_cache(fetched_at);
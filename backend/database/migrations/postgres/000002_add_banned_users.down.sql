@@ -0,0 +1,3 @@
+-- Remove banned_users table
+
+DROP TABLE IF EXISTS banned_users;
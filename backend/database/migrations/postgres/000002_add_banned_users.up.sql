@@ -0,0 +1,10 @@
+-- Add banned_users table for banning players
+
+CREATE TABLE IF NOT EXISTS banned_users (
+    id BIGSERIAL PRIMARY KEY,
+    steam_id VARCHAR(20) UNIQUE NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    reason TEXT DEFAULT '',
+    banned_by VARCHAR(20) NOT NULL,
+    banned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Remove is_secret column from votes table
+ALTER TABLE votes DROP COLUMN is_secret;
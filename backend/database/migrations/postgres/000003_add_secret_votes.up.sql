@@ -0,0 +1,3 @@
+-- Add is_secret column to votes table
+-- Default is 0 (false/open) for existing votes
+ALTER TABLE votes ADD COLUMN is_secret SMALLINT DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Revert steam_id columns back to VARCHAR(20)
+
+ALTER TABLE users ALTER COLUMN steam_id TYPE VARCHAR(20);
+ALTER TABLE banned_users ALTER COLUMN steam_id TYPE VARCHAR(20);
+ALTER TABLE banned_users ALTER COLUMN banned_by TYPE VARCHAR(20);
@@ -0,0 +1,5 @@
+-- Extend steam_id columns from VARCHAR(20) to VARCHAR(50) to support FAKE_ prefixed IDs
+
+ALTER TABLE users ALTER COLUMN steam_id TYPE VARCHAR(50);
+ALTER TABLE banned_users ALTER COLUMN steam_id TYPE VARCHAR(50);
+ALTER TABLE banned_users ALTER COLUMN banned_by TYPE VARCHAR(50);
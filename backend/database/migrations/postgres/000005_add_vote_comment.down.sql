@@ -0,0 +1,2 @@
+-- Remove comment column from votes table
+ALTER TABLE votes DROP COLUMN comment;
@@ -0,0 +1,2 @@
+-- Remove is_invalidated column from votes table
+ALTER TABLE votes DROP COLUMN is_invalidated;
@@ -0,0 +1,2 @@
+-- Add is_invalidated column to votes table
+ALTER TABLE votes ADD COLUMN is_invalidated SMALLINT DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Remove game_owners table (PostgreSQL)
+
+DROP TABLE IF EXISTS game_owners;
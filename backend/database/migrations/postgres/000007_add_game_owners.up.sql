@@ -0,0 +1,15 @@
+-- Add game_owners table to track which users own which games (PostgreSQL)
+-- steam_id uses the extended length from migration 000004, as PostgreSQL
+-- rejects longer values instead of truncating them
+
+CREATE TABLE IF NOT EXISTS game_owners (
+    app_id BIGINT NOT NULL,
+    steam_id VARCHAR(50) NOT NULL,
+    playtime_forever INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (app_id, steam_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_owners_app_id ON game_owners(app_id);
+CREATE INDEX IF NOT EXISTS idx_game_owners_steam_id ON game_owners(steam_id);
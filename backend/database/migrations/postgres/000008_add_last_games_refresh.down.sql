@@ -0,0 +1,3 @@
+-- Remove last_games_refresh_at column from users table (PostgreSQL)
+
+ALTER TABLE users DROP COLUMN last_games_refresh_at;
@@ -0,0 +1,3 @@
+-- Add last_games_refresh_at column to users table (PostgreSQL)
+
+ALTER TABLE users ADD COLUMN last_games_refresh_at TIMESTAMP DEFAULT NULL;
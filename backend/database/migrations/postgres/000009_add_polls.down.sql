@@ -0,0 +1,5 @@
+-- Remove game polls (PostgreSQL)
+
+DROP TABLE IF EXISTS poll_ballots;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,31 @@
+-- Add game polls (PostgreSQL)
+
+CREATE TABLE IF NOT EXISTS polls (
+    id BIGSERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    voting_method VARCHAR(20) NOT NULL DEFAULT 'single',
+    created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    closes_at TIMESTAMP DEFAULT NULL,
+    closed_at TIMESTAMP DEFAULT NULL,
+    winner_app_id BIGINT DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_polls_open ON polls(closed_at, closes_at);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    poll_id BIGINT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    app_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (poll_id, app_id)
+);
+
+CREATE TABLE IF NOT EXISTS poll_ballots (
+    poll_id BIGINT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id BIGINT NOT NULL,
+    preference INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, user_id, app_id)
+);
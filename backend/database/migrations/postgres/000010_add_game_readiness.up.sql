@@ -0,0 +1,28 @@
+-- Add install/readiness tracking and planned game sessions (PostgreSQL)
+
+CREATE TABLE IF NOT EXISTS game_install_status (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, app_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_install_status_app_id ON game_install_status(app_id);
+
+CREATE TABLE IF NOT EXISTS game_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    app_id BIGINT NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP DEFAULT NULL,
+    ready_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS game_session_players (
+    session_id BIGINT NOT NULL REFERENCES game_sessions(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (session_id, user_id)
+);
@@ -0,0 +1,7 @@
+-- Remove tournaments (PostgreSQL)
+
+DROP TABLE IF EXISTS tournament_matches;
+DROP TABLE IF EXISTS tournament_team_members;
+DROP TABLE IF EXISTS tournament_teams;
+DROP TABLE IF EXISTS tournament_registrations;
+DROP TABLE IF EXISTS tournaments;
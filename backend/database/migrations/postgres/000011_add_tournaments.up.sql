@@ -0,0 +1,60 @@
+-- Add tournaments with teams and bracket matches (PostgreSQL)
+
+CREATE TABLE IF NOT EXISTS tournaments (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    app_id BIGINT NOT NULL,
+    format VARCHAR(30) NOT NULL,
+    team_size INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'registration',
+    bonus_points INTEGER NOT NULL DEFAULT 0,
+    created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    winner_team_id BIGINT DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP DEFAULT NULL,
+    finished_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tournament_registrations (
+    tournament_id BIGINT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tournament_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS tournament_teams (
+    id BIGSERIAL PRIMARY KEY,
+    tournament_id BIGINT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    seed INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_teams_tournament ON tournament_teams(tournament_id);
+
+CREATE TABLE IF NOT EXISTS tournament_team_members (
+    team_id BIGINT NOT NULL REFERENCES tournament_teams(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS tournament_matches (
+    id BIGSERIAL PRIMARY KEY,
+    tournament_id BIGINT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    bracket VARCHAR(20) NOT NULL,
+    round INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    team1_id BIGINT DEFAULT NULL,
+    team2_id BIGINT DEFAULT NULL,
+    score1 INTEGER DEFAULT NULL,
+    score2 INTEGER DEFAULT NULL,
+    winner_team_id BIGINT DEFAULT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    is_bye SMALLINT NOT NULL DEFAULT 0,
+    reported_by BIGINT DEFAULT NULL,
+    winner_next_match_id BIGINT DEFAULT NULL,
+    winner_next_slot INTEGER NOT NULL DEFAULT 0,
+    loser_next_match_id BIGINT DEFAULT NULL,
+    loser_next_slot INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_matches_tournament ON tournament_matches(tournament_id);
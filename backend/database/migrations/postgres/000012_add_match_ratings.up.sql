@@ -0,0 +1,37 @@
+-- Add match history and per-game Elo ratings (PostgreSQL)
+
+CREATE TABLE IF NOT EXISTS game_matches (
+    id BIGSERIAL PRIMARY KEY,
+    app_id BIGINT NOT NULL,
+    winner_team INTEGER DEFAULT NULL,
+    recorded_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    played_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_matches_app_id ON game_matches(app_id, played_at);
+
+CREATE TABLE IF NOT EXISTS game_match_players (
+    match_id BIGINT NOT NULL REFERENCES game_matches(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team INTEGER NOT NULL,
+    rating_before DOUBLE PRECISION NOT NULL,
+    rating_after DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (match_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_match_players_user_id ON game_match_players(user_id);
+
+CREATE TABLE IF NOT EXISTS player_ratings (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id BIGINT NOT NULL,
+    rating DOUBLE PRECISION NOT NULL,
+    matches INTEGER NOT NULL DEFAULT 0,
+    wins INTEGER NOT NULL DEFAULT 0,
+    losses INTEGER NOT NULL DEFAULT 0,
+    draws INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, app_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_ratings_app_id ON player_ratings(app_id, rating);
@@ -0,0 +1,3 @@
+-- Remove persisted state of background sync jobs (PostgreSQL)
+
+DROP TABLE IF EXISTS sync_jobs;
@@ -0,0 +1,14 @@
+-- Add persisted state of background sync jobs (PostgreSQL)
+
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    name VARCHAR(50) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'idle',
+    processed INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    failures INTEGER NOT NULL DEFAULT 0,
+    paused_until TIMESTAMP DEFAULT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP DEFAULT NULL,
+    finished_at TIMESTAMP DEFAULT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Remove invited_at column from users table (PostgreSQL)
+
+ALTER TABLE users DROP COLUMN invited_at;
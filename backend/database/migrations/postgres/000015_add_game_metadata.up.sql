@@ -0,0 +1,15 @@
+-- Add curated game metadata (PostgreSQL)
+
+CREATE TABLE IF NOT EXISTS game_metadata (
+    app_id INTEGER PRIMARY KEY,
+    max_players INTEGER NOT NULL DEFAULT 0,
+    min_players INTEGER NOT NULL DEFAULT 0,
+    lan_capable SMALLINT NOT NULL DEFAULT 0,
+    dedicated_server_required SMALLINT NOT NULL DEFAULT 0,
+    install_size_mb INTEGER NOT NULL DEFAULT 0,
+    tags TEXT NOT NULL DEFAULT '[]',
+    notes TEXT NOT NULL DEFAULT '',
+    source VARCHAR(20) NOT NULL DEFAULT 'admin',
+    updated_by VARCHAR(50) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Remove custom non-Steam games (PostgreSQL)
+
+DROP TABLE IF EXISTS custom_games;
@@ -0,0 +1,12 @@
+-- Add custom non-Steam games (PostgreSQL)
+
+CREATE TABLE IF NOT EXISTS custom_games (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    categories TEXT NOT NULL DEFAULT '[]',
+    max_players INTEGER NOT NULL DEFAULT 0,
+    has_image SMALLINT NOT NULL DEFAULT 0,
+    created_by VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
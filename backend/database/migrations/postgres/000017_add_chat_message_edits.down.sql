@@ -0,0 +1,3 @@
+-- Remove edited_at column from chat_messages table (PostgreSQL)
+
+ALTER TABLE chat_messages DROP COLUMN edited_at;
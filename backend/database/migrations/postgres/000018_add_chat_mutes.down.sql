@@ -0,0 +1,3 @@
+-- Remove chat mutes (PostgreSQL)
+
+DROP TABLE IF EXISTS chat_mutes;
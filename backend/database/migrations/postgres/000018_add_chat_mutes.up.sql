@@ -0,0 +1,10 @@
+-- Add chat mutes (PostgreSQL)
+
+-- One active mute per player, expired mutes are ignored and replaced by the next one
+CREATE TABLE IF NOT EXISTS chat_mutes (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    muted_by VARCHAR(20) NOT NULL,
+    muted_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
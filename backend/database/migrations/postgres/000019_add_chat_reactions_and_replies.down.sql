@@ -0,0 +1,5 @@
+-- Remove chat reactions and replies (PostgreSQL)
+
+DROP TABLE IF EXISTS chat_reactions;
+DROP INDEX IF EXISTS idx_chat_messages_parent;
+ALTER TABLE chat_messages DROP COLUMN parent_id;
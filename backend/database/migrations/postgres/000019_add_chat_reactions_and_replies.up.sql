@@ -0,0 +1,15 @@
+-- Add chat reactions and replies (PostgreSQL)
+
+-- Replies keep their text when the parent message is deleted
+ALTER TABLE chat_messages ADD COLUMN parent_id BIGINT DEFAULT NULL REFERENCES chat_messages(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_parent ON chat_messages(parent_id);
+
+-- Each player can react to a message once per emoji
+CREATE TABLE IF NOT EXISTS chat_reactions (
+    message_id BIGINT NOT NULL REFERENCES chat_messages(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, user_id, emoji)
+);
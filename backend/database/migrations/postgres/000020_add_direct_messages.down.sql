@@ -0,0 +1,3 @@
+-- Remove direct messages between players (PostgreSQL)
+
+DROP TABLE IF EXISTS direct_messages;
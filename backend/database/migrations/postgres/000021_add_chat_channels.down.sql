@@ -0,0 +1,6 @@
+-- Remove chat channels (PostgreSQL)
+
+DROP TABLE IF EXISTS chat_channel_members;
+DROP INDEX IF EXISTS idx_chat_messages_channel;
+ALTER TABLE chat_messages DROP COLUMN channel_id;
+DROP TABLE IF EXISTS chat_channels;
@@ -0,0 +1,28 @@
+-- Add chat channels (PostgreSQL)
+
+-- Channels are global, per game or admin announcements; the first two always exist
+CREATE TABLE IF NOT EXISTS chat_channels (
+    id BIGSERIAL PRIMARY KEY,
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    app_id INTEGER DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO chat_channels (id, slug, name, kind) VALUES (1, 'general', 'General', 'global');
+INSERT INTO chat_channels (id, slug, name, kind) VALUES (2, 'announcements', 'Announcements', 'announcements');
+SELECT setval('chat_channels_id_seq', 2);
+
+-- Existing messages belong to the general channel
+ALTER TABLE chat_messages ADD COLUMN channel_id BIGINT NOT NULL DEFAULT 1 REFERENCES chat_channels(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_channel ON chat_messages(channel_id, id);
+
+-- Players who joined a game channel
+CREATE TABLE IF NOT EXISTS chat_channel_members (
+    channel_id BIGINT NOT NULL REFERENCES chat_channels(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, user_id)
+);
@@ -0,0 +1,34 @@
+-- Add game polls (SQLite)
+
+CREATE TABLE IF NOT EXISTS polls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    voting_method TEXT NOT NULL DEFAULT 'single',
+    created_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    closes_at DATETIME DEFAULT NULL,
+    closed_at DATETIME DEFAULT NULL,
+    winner_app_id INTEGER DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for finding open polls that are due to close
+CREATE INDEX IF NOT EXISTS idx_polls_open ON polls(closed_at, closes_at);
+
+-- Candidate games of a poll, in display order
+CREATE TABLE IF NOT EXISTS poll_options (
+    poll_id INTEGER NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    app_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (poll_id, app_id)
+);
+
+-- One row per chosen game and voter; preference 1 is the first choice
+CREATE TABLE IF NOT EXISTS poll_ballots (
+    poll_id INTEGER NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id INTEGER NOT NULL,
+    preference INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, user_id, app_id)
+);
@@ -0,0 +1,31 @@
+-- Add install/readiness tracking and planned game sessions (SQLite)
+
+-- Install status of a game per player; no row = not installed
+CREATE TABLE IF NOT EXISTS game_install_status (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, app_id)
+);
+
+-- Index for the per-game readiness roster
+CREATE INDEX IF NOT EXISTS idx_game_install_status_app_id ON game_install_status(app_id);
+
+-- Planned sessions: a game and the players who want to play it together
+CREATE TABLE IF NOT EXISTS game_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    app_id INTEGER NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    created_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at DATETIME DEFAULT NULL,
+    ready_at DATETIME DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS game_session_players (
+    session_id INTEGER NOT NULL REFERENCES game_sessions(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (session_id, user_id)
+);
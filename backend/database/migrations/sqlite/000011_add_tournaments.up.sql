@@ -0,0 +1,61 @@
+-- Add tournaments with teams and bracket matches (SQLite)
+
+CREATE TABLE IF NOT EXISTS tournaments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    app_id INTEGER NOT NULL,
+    format TEXT NOT NULL,
+    team_size INTEGER NOT NULL DEFAULT 1,
+    status TEXT NOT NULL DEFAULT 'registration',
+    bonus_points INTEGER NOT NULL DEFAULT 0,
+    created_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    winner_team_id INTEGER DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    started_at DATETIME DEFAULT NULL,
+    finished_at DATETIME DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tournament_registrations (
+    tournament_id INTEGER NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tournament_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS tournament_teams (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tournament_id INTEGER NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    seed INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_teams_tournament ON tournament_teams(tournament_id);
+
+CREATE TABLE IF NOT EXISTS tournament_team_members (
+    team_id INTEGER NOT NULL REFERENCES tournament_teams(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, user_id)
+);
+
+-- Bracket matches; winners and losers move on to the linked next match and slot (1 or 2)
+CREATE TABLE IF NOT EXISTS tournament_matches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tournament_id INTEGER NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    bracket TEXT NOT NULL,
+    round INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    team1_id INTEGER DEFAULT NULL,
+    team2_id INTEGER DEFAULT NULL,
+    score1 INTEGER DEFAULT NULL,
+    score2 INTEGER DEFAULT NULL,
+    winner_team_id INTEGER DEFAULT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    is_bye INTEGER NOT NULL DEFAULT 0,
+    reported_by INTEGER DEFAULT NULL,
+    winner_next_match_id INTEGER DEFAULT NULL,
+    winner_next_slot INTEGER NOT NULL DEFAULT 0,
+    loser_next_match_id INTEGER DEFAULT NULL,
+    loser_next_slot INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_matches_tournament ON tournament_matches(tournament_id);
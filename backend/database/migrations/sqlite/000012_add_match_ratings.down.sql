@@ -0,0 +1,5 @@
+-- Remove match history and per-game Elo ratings (SQLite)
+
+DROP TABLE IF EXISTS player_ratings;
+DROP TABLE IF EXISTS game_match_players;
+DROP TABLE IF EXISTS game_matches;
@@ -0,0 +1,42 @@
+-- Add match history and per-game Elo ratings (SQLite)
+
+-- Recorded match outcomes; winner_team is the team number, NULL = draw
+CREATE TABLE IF NOT EXISTS game_matches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    app_id INTEGER NOT NULL,
+    winner_team INTEGER DEFAULT NULL,
+    recorded_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    played_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for the match history of a game
+CREATE INDEX IF NOT EXISTS idx_game_matches_app_id ON game_matches(app_id, played_at);
+
+-- Players of a match with their rating before and after it (the rating history)
+CREATE TABLE IF NOT EXISTS game_match_players (
+    match_id INTEGER NOT NULL REFERENCES game_matches(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team INTEGER NOT NULL,
+    rating_before REAL NOT NULL,
+    rating_after REAL NOT NULL,
+    PRIMARY KEY (match_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_match_players_user_id ON game_match_players(user_id);
+
+-- Current rating of a player per game
+CREATE TABLE IF NOT EXISTS player_ratings (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    app_id INTEGER NOT NULL,
+    rating REAL NOT NULL,
+    matches INTEGER NOT NULL DEFAULT 0,
+    wins INTEGER NOT NULL DEFAULT 0,
+    losses INTEGER NOT NULL DEFAULT 0,
+    draws INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, app_id)
+);
+
+-- Index for the per-game ladder
+CREATE INDEX IF NOT EXISTS idx_player_ratings_app_id ON player_ratings(app_id, rating);
@@ -0,0 +1,3 @@
+-- Remove persisted state of background sync jobs (SQLite)
+
+DROP TABLE IF EXISTS sync_jobs;
@@ -0,0 +1,15 @@
+-- Add persisted state of background sync jobs (SQLite)
+
+-- One row per job, so an interrupted sync resumes after a restart
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    name TEXT PRIMARY KEY,
+    status TEXT NOT NULL DEFAULT 'idle',
+    processed INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    failures INTEGER NOT NULL DEFAULT 0,
+    paused_until DATETIME DEFAULT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    started_at DATETIME DEFAULT NULL,
+    finished_at DATETIME DEFAULT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
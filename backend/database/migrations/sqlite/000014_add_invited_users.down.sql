@@ -0,0 +1,3 @@
+-- Remove invited_at column from users table (SQLite)
+-- Requires SQLite 3.35.0+ (bundled with modernc.org/sqlite)
+ALTER TABLE users DROP COLUMN invited_at;
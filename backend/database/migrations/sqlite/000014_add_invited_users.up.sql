@@ -0,0 +1,4 @@
+-- Add invited_at column to users table (SQLite)
+-- Set for placeholder users imported from Steam who have not logged in yet
+
+ALTER TABLE users ADD COLUMN invited_at DATETIME DEFAULT NULL;
@@ -0,0 +1,3 @@
+-- Remove curated game metadata (SQLite)
+
+DROP TABLE IF EXISTS game_metadata;
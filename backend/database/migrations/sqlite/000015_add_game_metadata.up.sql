@@ -0,0 +1,16 @@
+-- Add curated game metadata (SQLite)
+
+-- One row per game, seeded from game_metadata.json or edited by admins
+CREATE TABLE IF NOT EXISTS game_metadata (
+    app_id INTEGER PRIMARY KEY,
+    max_players INTEGER NOT NULL DEFAULT 0,
+    min_players INTEGER NOT NULL DEFAULT 0,
+    lan_capable INTEGER NOT NULL DEFAULT 0,
+    dedicated_server_required INTEGER NOT NULL DEFAULT 0,
+    install_size_mb INTEGER NOT NULL DEFAULT 0,
+    tags TEXT NOT NULL DEFAULT '[]',
+    notes TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT 'admin',
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,13 @@
+-- Add custom non-Steam games (SQLite)
+
+-- Games managed by admins, listed with app ID 2000000000 + id next to the Steam games
+CREATE TABLE IF NOT EXISTS custom_games (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    categories TEXT NOT NULL DEFAULT '[]',
+    max_players INTEGER NOT NULL DEFAULT 0,
+    has_image INTEGER NOT NULL DEFAULT 0,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Remove edited_at column from chat_messages table (SQLite)
+-- Requires SQLite 3.35.0+ (bundled with modernc.org/sqlite)
+ALTER TABLE chat_messages DROP COLUMN edited_at;
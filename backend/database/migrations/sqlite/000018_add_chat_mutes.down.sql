@@ -0,0 +1,3 @@
+-- Remove chat mutes (SQLite)
+
+DROP TABLE IF EXISTS chat_mutes;
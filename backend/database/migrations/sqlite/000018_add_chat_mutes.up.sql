@@ -0,0 +1,10 @@
+-- Add chat mutes (SQLite)
+
+-- One active mute per player, expired mutes are ignored and replaced by the next one
+CREATE TABLE IF NOT EXISTS chat_mutes (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    muted_by TEXT NOT NULL,
+    muted_until DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
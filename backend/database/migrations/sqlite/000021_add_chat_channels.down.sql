@@ -0,0 +1,7 @@
+-- Remove chat channels (SQLite)
+-- Requires SQLite 3.35.0+ (bundled with modernc.org/sqlite)
+
+DROP TABLE IF EXISTS chat_channel_members;
+DROP INDEX IF EXISTS idx_chat_messages_channel;
+ALTER TABLE chat_messages DROP COLUMN channel_id;
+DROP TABLE IF EXISTS chat_channels;
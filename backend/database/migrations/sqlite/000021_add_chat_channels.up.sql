@@ -0,0 +1,27 @@
+-- Add chat channels (SQLite)
+
+-- Channels are global, per game or admin announcements; the first two always exist
+CREATE TABLE IF NOT EXISTS chat_channels (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    app_id INTEGER DEFAULT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO chat_channels (id, slug, name, kind) VALUES (1, 'general', 'General', 'global');
+INSERT INTO chat_channels (id, slug, name, kind) VALUES (2, 'announcements', 'Announcements', 'announcements');
+
+-- Existing messages belong to the general channel
+ALTER TABLE chat_messages ADD COLUMN channel_id INTEGER NOT NULL DEFAULT 1 REFERENCES chat_channels(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_channel ON chat_messages(channel_id, id);
+
+-- Players who joined a game channel
+CREATE TABLE IF NOT EXISTS chat_channel_members (
+    channel_id INTEGER NOT NULL REFERENCES chat_channels(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, user_id)
+);
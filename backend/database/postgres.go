@@ -0,0 +1,226 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresConfig holds PostgreSQL connection configuration
+type PostgresConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	SSLMode  string // disable, require, verify-ca or verify-full
+
+	// Connection pool configuration
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// DefaultPostgresConfig returns a PostgresConfig with sensible defaults
+func DefaultPostgresConfig() PostgresConfig {
+	return PostgresConfig{
+		Host:            "localhost",
+		Port:            5432,
+		SSLMode:         "disable",
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: 1 * time.Minute,
+	}
+}
+
+// initPostgres initializes a PostgreSQL database connection
+func initPostgres(cfg PostgresConfig) error {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	// Build PostgreSQL DSN
+	// The session time zone is UTC, matching the MySQL connection (Loc=UTC)
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Path:     "/" + cfg.Database,
+		RawQuery: url.Values{"sslmode": {sslMode}, "timezone": {"UTC"}}.Encode(),
+	}
+
+	connector, err := pq.NewConnector(dsn.String())
+	if err != nil {
+		return fmt.Errorf("failed to configure PostgreSQL connection: %w", err)
+	}
+	DB = sql.OpenDB(&postgresConnector{connector: connector})
+
+	// Configure connection pool
+	DB.SetMaxOpenConns(cfg.MaxOpenConns)
+	DB.SetMaxIdleConns(cfg.MaxIdleConns)
+	DB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	DB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	// Test the connection
+	if err := DB.Ping(); err != nil {
+		return fmt.Errorf("failed to ping PostgreSQL database: %w", err)
+	}
+
+	// Set database type
+	dbType = DBTypePostgres
+
+	// Log connection info (without password)
+	log.Printf("PostgreSQL database initialized: %s@%s:%d/%s (SSL: %s)",
+		cfg.User, cfg.Host, cfg.Port, cfg.Database, sslMode)
+
+	return nil
+}
+
+// postgresConnector wraps the lib/pq connector so the repositories can keep using
+// the "?" placeholders and 0/1 flags they share with SQLite and MySQL
+type postgresConnector struct {
+	connector *pq.Connector
+}
+
+// Connect opens a new wrapped connection
+func (c *postgresConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresConn{conn: conn}, nil
+}
+
+// Driver returns the underlying lib/pq driver
+func (c *postgresConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// postgresConn rewrites queries and arguments before handing them to lib/pq
+type postgresConn struct {
+	conn driver.Conn
+}
+
+// Prepare prepares a statement with rewritten placeholders
+func (c *postgresConn) Prepare(query string) (driver.Stmt, error) {
+	return c.conn.Prepare(rebindPostgres(query))
+}
+
+// PrepareContext prepares a statement with rewritten placeholders
+func (c *postgresConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.conn.(driver.ConnPrepareContext).PrepareContext(ctx, rebindPostgres(query))
+}
+
+// Close closes the underlying connection
+func (c *postgresConn) Close() error {
+	return c.conn.Close()
+}
+
+// Begin starts a transaction
+func (c *postgresConn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+// BeginTx starts a transaction with options
+func (c *postgresConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+// ExecContext executes a query with rewritten placeholders
+func (c *postgresConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.conn.(driver.ExecerContext).ExecContext(ctx, rebindPostgres(query), args)
+}
+
+// QueryContext runs a query with rewritten placeholders
+func (c *postgresConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.conn.(driver.QueryerContext).QueryContext(ctx, rebindPostgres(query), args)
+}
+
+// Ping checks the connection
+func (c *postgresConn) Ping(ctx context.Context) error {
+	return c.conn.(driver.Pinger).Ping(ctx)
+}
+
+// ResetSession is called before a pooled connection is reused
+func (c *postgresConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid reports whether the connection can be reused
+func (c *postgresConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue converts arguments the way the other backends store them:
+// booleans become 0/1 for the SMALLINT flag columns and times are sent as UTC
+func (c *postgresConn) CheckNamedValue(nv *driver.NamedValue) error {
+	value, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		if v {
+			value = int64(1)
+		} else {
+			value = int64(0)
+		}
+	case time.Time:
+		value = v.UTC()
+	}
+
+	nv.Value = value
+	return nil
+}
+
+// rebindPostgres rewrites "?" placeholders to PostgreSQL's "$1", "$2", ...
+// Question marks inside quoted strings and identifiers are left untouched.
+func rebindPostgres(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			// Doubled quotes are escapes and simply toggle twice
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(ch)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package database
+
+import "testing"
+
+func TestRebindPostgres(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no placeholders", `SELECT 1`, `SELECT 1`},
+		{"placeholders", `SELECT * FROM users WHERE id = ? AND steam_id = ?`, `SELECT * FROM users WHERE id = $1 AND steam_id = $2`},
+		{"string literal", `SELECT '?' FROM votes WHERE id = ?`, `SELECT '?' FROM votes WHERE id = $1`},
+		{"escaped quote", `SELECT 'it''s ?' WHERE a = ? AND b = ?`, `SELECT 'it''s ?' WHERE a = $1 AND b = $2`},
+		{"quoted identifier", `SELECT "a?b" FROM t WHERE c = ?`, `SELECT "a?b" FROM t WHERE c = $1`},
+		{"existing numbered placeholders", `SELECT $1`, `SELECT $1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebindPostgres(tt.query); got != tt.want {
+				t.Errorf("rebindPostgres(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -19,10 +19,12 @@ var ErrBusy = errors.New("database is busy, please try again")
 
 // initSQLite initializes a SQLite database connection
 func initSQLite(dbPath string) error {
-	// Ensure the directory exists
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create database directory: %w", err)
+	// Ensure the directory exists (URIs like "file:name?mode=memory" have none)
+	if !strings.HasPrefix(dbPath, "file:") {
+		dir := filepath.Dir(dbPath)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create database directory: %w", err)
+		}
 	}
 
 	// Open database connection with optimized settings for concurrent access
@@ -32,7 +34,11 @@ func initSQLite(dbPath string) error {
 	// _cache_size=1000 increases the page cache size
 	// _foreign_keys=ON enables foreign key constraints
 	// _txlock=immediate ensures write transactions get the lock immediately
-	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_busy_timeout=10000&_synchronous=NORMAL&_cache_size=1000&_foreign_keys=ON&_txlock=immediate", dbPath)
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	dsn := fmt.Sprintf("%s%s_journal_mode=WAL&_busy_timeout=10000&_synchronous=NORMAL&_cache_size=1000&_foreign_keys=ON&_txlock=immediate", dbPath, separator)
 
 	var err error
 	DB, err = sql.Open("sqlite", dsn)
@@ -79,16 +85,16 @@ func isBusyError(err error) bool {
 
 // WithRetry executes a function with retry logic for SQLITE_BUSY errors
 // It will retry up to maxRetries times with exponential backoff
-// For MySQL, the function is executed without retry logic
+// For MySQL and PostgreSQL, the function is executed without retry logic
 func WithRetry(fn func() error) error {
 	return WithRetryContext(context.Background(), fn)
 }
 
 // WithRetryContext executes a function with retry logic and context support
-// For MySQL, the function is executed without retry logic
+// For MySQL and PostgreSQL, the function is executed without retry logic
 func WithRetryContext(ctx context.Context, fn func() error) error {
-	// For MySQL, no retry needed - just execute the function
-	if dbType == DBTypeMySQL {
+	// For MySQL and PostgreSQL, no retry needed - just execute the function
+	if dbType != DBTypeSQLite {
 		return fn()
 	}
 
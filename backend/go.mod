@@ -10,7 +10,10 @@ require (
 	github.com/golang-migrate/migrate/v4 v4.19.1
 	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.10.9
 	github.com/yohcop/openid-go v1.0.1
+	golang.org/x/image v0.25.0
+	golang.org/x/sync v0.19.0
 	modernc.org/sqlite v1.43.0
 )
 
@@ -11,6 +11,7 @@ import (
 	"github.com/guided-traffic/rate-your-mate/backend/middleware"
 	"github.com/guided-traffic/rate-your-mate/backend/repository"
 	"github.com/guided-traffic/rate-your-mate/backend/services"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
 	"github.com/guided-traffic/rate-your-mate/backend/websocket"
 )
 
@@ -18,7 +19,7 @@ import (
 type AuthHandler struct {
 	cfg                *config.Config
 	steamAuth          *auth.SteamAuth
-	steamAPI           *auth.SteamAPIClient
+	steamClient        steam.Client
 	jwtService         *auth.JWTService
 	userRepo           *repository.UserRepository
 	creditService      *services.CreditService
@@ -28,11 +29,11 @@ type AuthHandler struct {
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(cfg *config.Config, userRepo *repository.UserRepository, creditService *services.CreditService, gameService *services.GameService, avatarCacheService *services.AvatarCacheService, wsHub *websocket.Hub) *AuthHandler {
+func NewAuthHandler(cfg *config.Config, steamClient steam.Client, userRepo *repository.UserRepository, creditService *services.CreditService, gameService *services.GameService, avatarCacheService *services.AvatarCacheService, wsHub *websocket.Hub) *AuthHandler {
 	return &AuthHandler{
 		cfg:                cfg,
 		steamAuth:          auth.NewSteamAuth(cfg.BackendURL),
-		steamAPI:           auth.NewSteamAPIClient(cfg.SteamAPIKey),
+		steamClient:        steamClient,
 		jwtService:         auth.NewJWTService(cfg.JWTSecret, cfg.JWTExpirationDays),
 		userRepo:           userRepo,
 		creditService:      creditService,
@@ -95,8 +96,8 @@ func (h *AuthHandler) SteamCallback(c *gin.Context) {
 	// Fetch player profile from Steam API
 	var username, avatarURL, avatarSmall, profileURL string
 	var originalAvatarURL string // Keep original URL for caching
-	if h.steamAPI.IsConfigured() {
-		player, err := h.steamAPI.GetPlayerSummary(steamID)
+	if h.steamClient.IsConfigured() {
+		player, err := steam.GetPlayerSummary(h.steamClient, steamID)
 		if err != nil {
 			log.Printf("Failed to fetch Steam profile for %s: %v", steamID, err)
 			// Continue with default values - we still have the Steam ID
@@ -112,11 +113,10 @@ func (h *AuthHandler) SteamCallback(c *gin.Context) {
 				originalAvatarURL = auth.GenerateFallbackAvatar(username)
 			}
 
-			// Cache avatar locally and use the same URL for both full and small
-			// (browsers will scale the image as needed)
+			// Cache avatar locally, the small version is resized from the cached image on demand
 			if h.avatarCacheService != nil {
 				avatarURL = h.avatarCacheService.CacheAvatar(steamID, originalAvatarURL)
-				avatarSmall = avatarURL // Use the same cached image for both
+				avatarSmall = h.avatarCacheService.GetSmallAvatarURL(avatarURL)
 
 				// Cleanup old avatar files if avatar changed
 				if avatarURL != originalAvatarURL {
@@ -226,6 +226,7 @@ func (h *AuthHandler) Me(c *gin.Context) {
 			"credit_interval_seconds": h.cfg.CreditIntervalMinutes * 60,
 			"credit_max":             h.cfg.CreditMax,
 			"is_admin":               h.cfg.IsAdmin(user.SteamID),
+			"is_moderator":           h.cfg.IsModerator(user.SteamID),
 		},
 	})
 }
@@ -267,23 +268,6 @@ func (h *AuthHandler) triggerBackgroundSync(steamID string) {
 
 	log.Printf("AuthHandler: Registering games for new user %s", steamID)
 
-	// Register user's games and trigger sync with WebSocket progress updates
-	h.gameService.RegisterUserGames(steamID, func(phase string, currentGame string, processed, total int) {
-		percentage := 0
-		if total > 0 {
-			percentage = (processed * 100) / total
-		}
-
-		if phase == "complete" {
-			h.wsHub.BroadcastGamesSyncComplete(processed)
-		} else {
-			h.wsHub.BroadcastGamesSyncProgress(&websocket.GamesSyncProgressPayload{
-				Phase:          phase,
-				CurrentGame:    currentGame,
-				ProcessedCount: processed,
-				TotalCount:     total,
-				Percentage:     percentage,
-			})
-		}
-	})
+	// Register user's games and trigger a sync, progress is broadcast via WebSocket by the sync scheduler
+	h.gameService.RegisterUserGames(steamID)
 }
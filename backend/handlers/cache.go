@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// CacheHandler reports the state of the local image caches
+type CacheHandler struct {
+	imageCacheService    *services.ImageCacheService
+	avatarCacheService   *services.AvatarCacheService
+	mediaPrefetchService *services.MediaPrefetchService
+}
+
+// NewCacheHandler creates a new cache handler
+func NewCacheHandler(imageCacheService *services.ImageCacheService, avatarCacheService *services.AvatarCacheService, mediaPrefetchService *services.MediaPrefetchService) *CacheHandler {
+	return &CacheHandler{
+		imageCacheService:    imageCacheService,
+		avatarCacheService:   avatarCacheService,
+		mediaPrefetchService: mediaPrefetchService,
+	}
+}
+
+// GetStats returns size, quota and hit rate of the game image and avatar caches
+// GET /api/v1/admin/cache/stats
+func (h *CacheHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"game_images": h.imageCacheService.Stats(),
+		"avatars":     h.avatarCacheService.Stats(),
+	})
+}
+
+// StartPrefetch starts downloading images of all multiplayer games and avatars of all players
+// Progress is broadcast as media_prefetch_progress messages.
+// POST /api/v1/admin/cache/prefetch
+func (h *CacheHandler) StartPrefetch(c *gin.Context) {
+	status, err := h.mediaPrefetchService.Start()
+	if err != nil {
+		if errors.Is(err, services.ErrPrefetchInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Prefetch already in progress", "status": status})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start prefetch"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// GetPrefetchStatus returns the progress of the running or last prefetch
+// GET /api/v1/admin/cache/prefetch
+func (h *CacheHandler) GetPrefetchStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.mediaPrefetchService.Status())
+}
+
+// parseImageSize reads the optional "size" query parameter of an image request
+// Returns 0 without the parameter. Responds with 400 and false if the size is not supported.
+func parseImageSize(c *gin.Context, supported []int) (int, bool) {
+	value := c.Query("size")
+	if value == "" {
+		return 0, true
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || !slices.Contains(supported, size) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported image size", "sizes": supported})
+		return 0, false
+	}
+	return size, true
+}
+
+// serveCachedImage serves a cached image with validators for conditional requests
+// The ETag is derived from modification time and size, which change whenever the file is
+// replaced. Last-Modified, If-None-Match and If-Modified-Since are handled by http.ServeFile,
+// which answers with 304 Not Modified when the client's copy is current. A JPEG is served as
+// WebP instead if the client accepts it and a WebP version is cached next to it.
+func serveCachedImage(c *gin.Context, path, contentType string, maxAge int) {
+	// Caches have to keep the JPEG and the WebP response apart
+	c.Header("Vary", "Accept")
+
+	path = filepath.Clean(path)
+	if contentType == "image/jpeg" && acceptsWebP(c.GetHeader("Accept")) {
+		webpPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".webp"
+		if _, err := os.Stat(webpPath); err == nil {
+			path, contentType = webpPath, "image/webp"
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	etag := fmt.Sprintf(`%x-%x`, info.ModTime().UnixNano(), info.Size())
+	if contentType == "image/webp" {
+		// Both versions of an image may share modification time and size
+		etag += "-webp"
+	}
+	c.Header("ETag", `"`+etag+`"`)
+	c.File(path)
+}
+
+// acceptsWebP reports whether an Accept header lists image/webp without rejecting it with q=0
+func acceptsWebP(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		if strings.TrimSpace(mediaType) != "image/webp" {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if key, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok && key == "q" {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
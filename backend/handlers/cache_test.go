@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServeCachedImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	for name, data := range map[string]string{"730.jpg": "jpeg data", "730.webp": "webp data", "440.jpg": "other jpeg"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	router := gin.New()
+	router.GET("/images/:file", func(c *gin.Context) {
+		serveCachedImage(c, filepath.Join(dir, c.Param("file")), "image/jpeg", 60)
+	})
+	get := func(file, accept, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/images/"+file, nil)
+		req.Header.Set("Accept", accept)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		file        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"730.jpg", "image/avif,image/webp,*/*;q=0.8", "image/webp", "webp data"},
+		{"730.jpg", "image/webp;q=0, */*", "image/jpeg", "jpeg data"},
+		{"730.jpg", "*/*", "image/jpeg", "jpeg data"},
+		{"440.jpg", "image/webp,*/*", "image/jpeg", "other jpeg"}, // no WebP version cached
+	}
+	for _, tt := range tests {
+		rec := get(tt.file, tt.accept, "")
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != tt.contentType || rec.Body.String() != tt.body {
+			t.Errorf("%s with Accept %q: got %d %s %q", tt.file, tt.accept, rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+		}
+		if rec.Header().Get("Vary") != "Accept" || rec.Header().Get("Cache-Control") != "public, max-age=60" {
+			t.Errorf("%s with Accept %q: unexpected cache headers %v", tt.file, tt.accept, rec.Header())
+		}
+	}
+
+	// The client's copy is current as long as the ETag matches, each format has its own
+	jpegETag := get("730.jpg", "*/*", "").Header().Get("ETag")
+	webpETag := get("730.jpg", "image/webp", "").Header().Get("ETag")
+	if jpegETag == "" || jpegETag == webpETag {
+		t.Fatalf("expected different ETags per format, got %q and %q", jpegETag, webpETag)
+	}
+	if rec := get("730.jpg", "*/*", jpegETag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("expected 304 Not Modified, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := get("730.jpg", "image/webp", jpegETag); rec.Code != http.StatusOK || rec.Body.String() != "webp data" {
+		t.Errorf("expected the WebP for a JPEG ETag, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if rec := get("missing.jpg", "*/*", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing image, got %d", rec.Code)
+	}
+}
@@ -1,64 +1,100 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
+	"log"
+	"math"
 	"net/http"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/config"
 	"github.com/guided-traffic/rate-your-mate/backend/middleware"
 	"github.com/guided-traffic/rate-your-mate/backend/models"
-	"github.com/guided-traffic/rate-your-mate/backend/repository"
-	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
 )
 
 // ChatHandler handles chat-related requests
 type ChatHandler struct {
-	chatRepo *repository.ChatRepository
-	userRepo *repository.UserRepository
-	wsHub    *websocket.Hub
+	chatService *services.ChatService
+	cfg         *config.Config
 }
 
 // NewChatHandler creates a new chat handler
-func NewChatHandler(chatRepo *repository.ChatRepository, userRepo *repository.UserRepository, wsHub *websocket.Hub) *ChatHandler {
+func NewChatHandler(chatService *services.ChatService, cfg *config.Config) *ChatHandler {
 	return &ChatHandler{
-		chatRepo: chatRepo,
-		userRepo: userRepo,
-		wsHub:    wsHub,
+		chatService: chatService,
+		cfg:         cfg,
 	}
 }
 
-// GetMessages returns recent chat messages
-// GET /api/v1/chat
+// GetMessages returns a page of general channel messages, oldest first
+// Without "before" the most recent messages are returned; older pages are loaded by
+// passing the next_cursor of the previous page as "before".
+// GET /api/v1/chat?before=<id>&limit=<n>
 func (h *ChatHandler) GetMessages(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "50")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 50
-	}
+	h.getMessages(c, models.GeneralChatChannelID)
+}
 
-	messages, err := h.chatRepo.GetRecent(limit)
+// GetChannelMessages returns a page of a channel's messages, oldest first, paged like GetMessages
+// GET /api/v1/chat/channels/:id/messages?before=<id>&limit=<n>
+func (h *ChatHandler) GetChannelMessages(c *gin.Context) {
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get chat messages",
-		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid channel ID"})
 		return
 	}
 
-	// Reverse order so oldest is first (for display)
-	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
-		messages[i], messages[j] = messages[j], messages[i]
+	h.getMessages(c, channelID)
+}
+
+// getMessages responds with a page of a channel's messages
+func (h *ChatHandler) getMessages(c *gin.Context, channelID uint64) {
+	claims, _ := middleware.GetClaims(c)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 0
+	}
+
+	var before uint64
+	if value := c.Query("before"); value != "" {
+		if before, err = strconv.ParseUint(value, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"messages": messages,
-	})
+	history, err := h.chatService.ChannelHistory(channelID, claims.UserID, before, limit)
+	if err != nil {
+		respondChatError(c, err, "Failed to get chat messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
 }
 
-// Create creates a new chat message
+// Create creates a new message in the general channel
 // POST /api/v1/chat
 func (h *ChatHandler) Create(c *gin.Context) {
+	h.create(c, models.GeneralChatChannelID)
+}
+
+// CreateInChannel creates a new message in a channel, posting in a game channel joins it
+// POST /api/v1/chat/channels/:id/messages
+func (h *ChatHandler) CreateInChannel(c *gin.Context) {
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid channel ID"})
+		return
+	}
+
+	h.create(c, channelID)
+}
+
+// create creates a new chat message in a channel
+func (h *ChatHandler) create(c *gin.Context, channelID uint64) {
 	// Get user from context (set by auth middleware)
 	claims, ok := middleware.GetClaims(c)
 	if !ok {
@@ -68,10 +104,6 @@ func (h *ChatHandler) Create(c *gin.Context) {
 		return
 	}
 
-	userID := claims.UserID
-	username := claims.Username
-	steamID := claims.SteamID
-
 	// Parse request
 	var req models.CreateChatMessageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -81,73 +113,302 @@ func (h *ChatHandler) Create(c *gin.Context) {
 		return
 	}
 
-	// Sanitize message
-	message := strings.TrimSpace(req.Message)
-	if len(message) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Message cannot be empty",
-		})
+	msg, err := h.chatService.PostToChannel(channelID, claims.UserID, req.Message, req.ParentID, h.cfg.IsModerator(claims.SteamID))
+	if err != nil {
+		respondChatError(c, err, "Failed to create chat message")
 		return
 	}
-	if len(message) > 500 {
-		message = message[:500]
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": msg,
+	})
+}
+
+// Update edits the text of the current user's chat message
+// PUT /api/v1/chat/:id
+func (h *ChatHandler) Update(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
 	}
 
-	// Get user's current achievements
-	achievements, err := h.chatRepo.GetUserAchievementBadges(userID)
+	var req models.UpdateChatMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	msg, err := h.chatService.Edit(messageID, claims.UserID, req.Message)
 	if err != nil {
-		achievements = []models.AchievementBadge{}
+		respondChatError(c, err, "Failed to update chat message")
+		return
 	}
 
-	// Convert achievements to JSON for storage
-	achievementsJSON, err := json.Marshal(achievements)
+	c.JSON(http.StatusOK, gin.H{"message": msg})
+}
+
+// Delete removes a chat message, authors can delete their own, moderators any
+// DELETE /api/v1/chat/:id
+func (h *ChatHandler) Delete(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		achievementsJSON = []byte("[]")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
 	}
 
-	// Create chat message
-	chatMsg := &models.ChatMessage{
-		UserID:       userID,
-		Message:      message,
-		Achievements: string(achievementsJSON),
+	if err := h.chatService.Delete(messageID, claims.UserID, h.cfg.IsModerator(claims.SteamID)); err != nil {
+		respondChatError(c, err, "Failed to delete chat message")
+		return
 	}
 
-	if err := h.chatRepo.Create(chatMsg); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create chat message",
-		})
+	c.JSON(http.StatusOK, gin.H{"message": "Chat message deleted"})
+}
+
+// AddReaction reacts to a chat message with an emoji
+// POST /api/v1/chat/:id/reactions
+func (h *ChatHandler) AddReaction(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
 		return
 	}
 
-	// Get the full message with user info
-	fullMsg, err := h.chatRepo.GetByID(chatMsg.ID)
+	var req models.ChatReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	reactions, err := h.chatService.React(messageID, claims.UserID, req.Emoji)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve chat message",
-		})
+		respondChatError(c, err, "Failed to add reaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reactions": reactions})
+}
+
+// RemoveReaction removes the current user's reaction from a chat message
+// DELETE /api/v1/chat/:id/reactions/:emoji
+func (h *ChatHandler) RemoveReaction(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
 		return
 	}
 
-	// Get user avatar info for WebSocket broadcast
-	user, _ := h.userRepo.GetByID(userID)
-	avatarSmall := ""
-	if user != nil {
-		avatarSmall = user.AvatarSmall
+	reactions, err := h.chatService.Unreact(messageID, claims.UserID, c.Param("emoji"))
+	if err != nil {
+		respondChatError(c, err, "Failed to remove reaction")
+		return
 	}
 
-	// Broadcast to all connected clients
-	h.wsHub.BroadcastChatMessage(&websocket.ChatMessagePayload{
-		ID:           fullMsg.ID,
-		UserID:       userID,
-		Username:     username,
-		SteamID:      steamID,
-		AvatarSmall:  avatarSmall,
-		Message:      fullMsg.Message,
-		Achievements: achievements,
-		CreatedAt:    fullMsg.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	})
+	c.JSON(http.StatusOK, gin.H{"reactions": reactions})
+}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": fullMsg,
-	})
+// GetChannels returns all chat channels with the current user's membership
+// GET /api/v1/chat/channels
+func (h *ChatHandler) GetChannels(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	channels, err := h.chatService.Channels(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat channels"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"channels": channels})
+}
+
+// JoinChannel joins a game channel, so its messages are delivered live
+// POST /api/v1/chat/channels/:id/join
+func (h *ChatHandler) JoinChannel(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid channel ID"})
+		return
+	}
+
+	channel, err := h.chatService.Join(channelID, claims.UserID)
+	if err != nil {
+		respondChatError(c, err, "Failed to join chat channel")
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+// JoinGameChannel joins the channel of a game, creating it on first use
+// POST /api/v1/chat/channels/games/:app_id/join
+func (h *ChatHandler) JoinGameChannel(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil || appID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	channel, err := h.chatService.JoinGame(appID, claims.UserID)
+	if err != nil {
+		respondChatError(c, err, "Failed to join chat channel")
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+// LeaveChannel leaves a game channel
+// DELETE /api/v1/chat/channels/:id/join
+func (h *ChatHandler) LeaveChannel(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid channel ID"})
+		return
+	}
+
+	if err := h.chatService.Leave(channelID, claims.UserID); err != nil {
+		respondChatError(c, err, "Failed to leave chat channel")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left chat channel"})
+}
+
+// ModeratorMiddleware restricts routes to chat moderators and admins
+func (h *ChatHandler) ModeratorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := middleware.GetClaims(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Not authenticated",
+			})
+			c.Abort()
+			return
+		}
+
+		if !h.cfg.IsModerator(claims.SteamID) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Moderator access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetModeration returns the slow mode and all active mutes (moderators only)
+// GET /api/v1/chat/moderation
+func (h *ChatHandler) GetModeration(c *gin.Context) {
+	status, err := h.chatService.ModerationStatus()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat moderation status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// SetSlowMode changes the minimum time between two messages of a player, 0 turns it off (moderators only)
+// PUT /api/v1/chat/moderation/slow-mode
+func (h *ChatHandler) SetSlowMode(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	var req models.UpdateSlowModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	h.chatService.SetSlowMode(time.Duration(*req.Seconds) * time.Second)
+	log.Printf("Moderator %s set chat slow mode to %ds", claims.SteamID, *req.Seconds)
+
+	c.JSON(http.StatusOK, gin.H{"slow_mode_seconds": *req.Seconds})
+}
+
+// Mute silences a player in the chat for the given number of minutes (moderators only)
+// POST /api/v1/chat/moderation/mutes/:user_id
+func (h *ChatHandler) Mute(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.MuteChatUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	mute, err := h.chatService.Mute(userID, time.Duration(req.DurationMinutes)*time.Minute, req.Reason, claims.SteamID)
+	if err != nil {
+		respondChatError(c, err, "Failed to mute player")
+		return
+	}
+
+	c.JSON(http.StatusOK, mute)
+}
+
+// Unmute lifts a player's mute (moderators only)
+// DELETE /api/v1/chat/moderation/mutes/:user_id
+func (h *ChatHandler) Unmute(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.chatService.Unmute(userID); err != nil {
+		respondChatError(c, err, "Failed to unmute player")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Player unmuted"})
+}
+
+// respondChatError maps chat service errors to HTTP responses
+func respondChatError(c *gin.Context, err error, fallback string) {
+	var rateLimitErr *services.ChatRateLimitError
+	if errors.As(err, &rateLimitErr) {
+		retryAfter := int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       rateLimitErr.Err.Error(),
+			"retry_after": retryAfter,
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrChatMessageNotFound), errors.Is(err, services.ErrChatUserNotFound),
+		errors.Is(err, services.ErrChatMuteNotFound), errors.Is(err, services.ErrChatChannelNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrChatForbidden), errors.Is(err, services.ErrChatEditWindowExpired),
+		errors.Is(err, services.ErrChatMuted), errors.Is(err, services.ErrChatChannelReadOnly):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidChatMessage), errors.Is(err, services.ErrChatMessageBlocked),
+		errors.Is(err, services.ErrInvalidChatReaction), errors.Is(err, services.ErrDirectMessageToSelf),
+		errors.Is(err, services.ErrChatChannelPermanent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
 }
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// maxCoverImageSize is the maximum accepted size of an uploaded cover image (5 MB)
+const maxCoverImageSize = 5 << 20
+
+// CustomGameHandler handles custom non-Steam games
+type CustomGameHandler struct {
+	customGameService *services.CustomGameService
+}
+
+// NewCustomGameHandler creates a new custom game handler
+func NewCustomGameHandler(customGameService *services.CustomGameService) *CustomGameHandler {
+	return &CustomGameHandler{
+		customGameService: customGameService,
+	}
+}
+
+// List returns all custom games
+// GET /api/v1/admin/games/custom
+func (h *CustomGameHandler) List(c *gin.Context) {
+	games, err := h.customGameService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get custom games"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"games": games})
+}
+
+// Create adds a new custom game
+// POST /api/v1/admin/games/custom
+func (h *CustomGameHandler) Create(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	var req models.CustomGameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	game, err := h.customGameService.Create(&req, claims.SteamID)
+	if err != nil {
+		respondCustomGameError(c, err, "Failed to create custom game")
+		return
+	}
+
+	c.JSON(http.StatusCreated, game)
+}
+
+// Update changes a custom game
+// PUT /api/v1/admin/games/custom/:app_id
+func (h *CustomGameHandler) Update(c *gin.Context) {
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	var req models.CustomGameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	game, err := h.customGameService.Update(appID, &req)
+	if err != nil {
+		respondCustomGameError(c, err, "Failed to update custom game")
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// Delete removes a custom game
+// DELETE /api/v1/admin/games/custom/:app_id
+func (h *CustomGameHandler) Delete(c *gin.Context) {
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	if err := h.customGameService.Delete(appID); err != nil {
+		respondCustomGameError(c, err, "Failed to delete custom game")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom game deleted"})
+}
+
+// UploadImage stores the cover image of a custom game
+// The image is sent as multipart field "image" or as raw request body (PNG, GIF, JPEG or WebP).
+// PUT /api/v1/admin/games/custom/:app_id/image
+func (h *CustomGameHandler) UploadImage(c *gin.Context) {
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCoverImageSize)
+
+	var reader io.Reader = c.Request.Body
+	if file, err := c.FormFile("image"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	game, err := h.customGameService.SetImage(appID, reader)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Image is too large"})
+			return
+		}
+		respondCustomGameError(c, err, "Failed to save cover image")
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// SetOwned declares that the current player owns a custom game
+// PUT /api/v1/games/:app_id/owned
+func (h *CustomGameHandler) SetOwned(c *gin.Context) {
+	h.setOwned(c, true)
+}
+
+// RemoveOwned withdraws the current player's ownership of a custom game
+// DELETE /api/v1/games/:app_id/owned
+func (h *CustomGameHandler) RemoveOwned(c *gin.Context) {
+	h.setOwned(c, false)
+}
+
+// setOwned updates the current player's declared ownership of a custom game
+func (h *CustomGameHandler) setOwned(c *gin.Context, owned bool) {
+	claims, _ := middleware.GetClaims(c)
+
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	if err := h.customGameService.SetOwned(appID, claims.SteamID, owned); err != nil {
+		respondCustomGameError(c, err, "Failed to update ownership")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"app_id": appID, "owned": owned})
+}
+
+// respondCustomGameError maps custom game service errors to HTTP responses
+func respondCustomGameError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrCustomGameNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidCustomGame), errors.Is(err, services.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
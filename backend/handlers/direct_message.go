@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// DirectMessageHandler handles private conversations between players
+type DirectMessageHandler struct {
+	dmService *services.DirectMessageService
+}
+
+// NewDirectMessageHandler creates a new direct message handler
+func NewDirectMessageHandler(dmService *services.DirectMessageService) *DirectMessageHandler {
+	return &DirectMessageHandler{dmService: dmService}
+}
+
+// GetConversations returns the current user's conversations, most recent first
+// GET /api/v1/direct-messages
+func (h *DirectMessageHandler) GetConversations(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	list, err := h.dmService.Conversations(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get conversations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetUnreadCount returns the number of unread direct messages of the current user
+// GET /api/v1/direct-messages/unread
+func (h *DirectMessageHandler) GetUnreadCount(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	count, err := h.dmService.UnreadCount(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count unread messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// GetConversation returns a page of the conversation with another player, oldest first
+// GET /api/v1/direct-messages/:user_id?before=<id>&limit=<n>
+func (h *DirectMessageHandler) GetConversation(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	partnerID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 0
+	}
+	var before uint64
+	if value := c.Query("before"); value != "" {
+		if before, err = strconv.ParseUint(value, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+	}
+
+	history, err := h.dmService.History(claims.UserID, partnerID, before, limit)
+	if err != nil {
+		respondChatError(c, err, "Failed to get conversation")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// Send sends a direct message to another player
+// POST /api/v1/direct-messages/:user_id
+func (h *DirectMessageHandler) Send(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	recipientID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.SendDirectMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	msg, err := h.dmService.Send(claims.UserID, recipientID, req.Message)
+	if err != nil {
+		respondChatError(c, err, "Failed to send direct message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": msg})
+}
+
+// MarkRead marks the messages from another player as read and sends them a read receipt
+// POST /api/v1/direct-messages/:user_id/read
+func (h *DirectMessageHandler) MarkRead(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	partnerID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	lastReadID, err := h.dmService.MarkRead(claims.UserID, partnerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark messages as read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"last_read_id": lastReadID})
+}
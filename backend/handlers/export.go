@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// maxImportSize is the maximum accepted size of an uploaded archive (50 MB)
+const maxImportSize = 50 << 20
+
+// ExportHandler handles admin export/import endpoints
+type ExportHandler struct {
+	exportService *services.ExportService
+	cfg           *config.Config
+	wsHub         *websocket.Hub
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *services.ExportService, cfg *config.Config, wsHub *websocket.Hub) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		cfg:           cfg,
+		wsHub:         wsHub,
+	}
+}
+
+// Export returns a versioned JSON archive of all event data as a file download
+// Query parameters: direct_messages=true to include the private messages between players
+// GET /api/v1/admin/export
+func (h *ExportHandler) Export(c *gin.Context) {
+	archive, err := h.exportService.Export(services.ExportOptions{DirectMessages: c.Query("direct_messages") == "true"})
+	if err != nil {
+		log.Printf("Failed to export event data: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export data"})
+		return
+	}
+
+	filename := fmt.Sprintf("rate-your-mate-export-%s.json", archive.ExportedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.JSON(http.StatusOK, archive)
+}
+
+// Import restores an archive created by Export
+// Accepts either a multipart upload (field "file") or the raw JSON archive as request body.
+// Query parameters: mode=merge|replace (default merge), settings=true to apply archived settings
+// POST /api/v1/admin/import
+func (h *ExportHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	// Only parse multipart uploads as a form, parsing any other body as a form would consume it
+	var reader io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing file field in upload"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	var archive models.ExportArchive
+	if err := json.NewDecoder(reader).Decode(&archive); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid archive: " + err.Error()})
+		return
+	}
+
+	opts := services.ImportOptions{
+		Mode:          models.ImportMode(c.DefaultQuery("mode", string(models.ImportModeMerge))),
+		ApplySettings: c.Query("settings") == "true",
+	}
+
+	result, err := h.exportService.Import(&archive, opts)
+	if err != nil {
+		var validationErr *services.ImportValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":    "Archive validation failed",
+				"problems": validationErr.Problems,
+			})
+			return
+		}
+		log.Printf("Failed to import event data: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import data"})
+		return
+	}
+
+	if result.SettingsApplied {
+		var countdownTarget *string
+		if !h.cfg.CountdownTarget.IsZero() {
+			formatted := h.cfg.CountdownTarget.Format(time.RFC3339)
+			countdownTarget = &formatted
+		}
+		h.wsHub.BroadcastSettingsUpdate(&websocket.SettingsPayload{
+			CreditIntervalMinutes:  h.cfg.CreditIntervalMinutes,
+			CreditMax:              h.cfg.CreditMax,
+			VotingPaused:           h.cfg.VotingPaused,
+			VoteVisibilityMode:     h.cfg.VoteVisibilityMode,
+			NegativeVotingDisabled: h.cfg.NegativeVotingDisabled,
+			CountdownTarget:        countdownTarget,
+		})
+	}
+	h.wsHub.BroadcastDataImported()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Import erfolgreich",
+		"result":  result,
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+func TestImportReadsNonMultipartBodies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exportService := services.NewExportService(&config.Config{}, "test", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	handler := NewExportHandler(exportService, &config.Config{}, nil)
+	router := gin.New()
+	router.POST("/import", handler.Import)
+
+	// curl -d sends the archive as application/x-www-form-urlencoded
+	for _, contentType := range []string{"application/json", "application/x-www-form-urlencoded"} {
+		req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(`{"format_version": 99}`))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var body struct {
+			Error    string   `json:"error"`
+			Problems []string `json:"problems"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: failed to decode response %q: %v", contentType, rec.Body.String(), err)
+		}
+		// The archive has to reach validation instead of failing to decode an empty body
+		if rec.Code != http.StatusBadRequest || body.Error != "Archive validation failed" || len(body.Problems) == 0 {
+			t.Errorf("%s: unexpected response %d %s", contentType, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"io"
+	"log"
 	"net/http"
-	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/guided-traffic/rate-your-mate/backend/auth"
 	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
 	"github.com/guided-traffic/rate-your-mate/backend/repository"
 	"github.com/guided-traffic/rate-your-mate/backend/services"
 	"github.com/guided-traffic/rate-your-mate/backend/websocket"
@@ -82,25 +86,8 @@ func (h *GameHandler) StartBackgroundSync(c *gin.Context) {
 		return
 	}
 
-	// Start sync with WebSocket progress updates
-	h.gameService.SyncGames(func(phase string, currentGame string, processed, total int) {
-		percentage := 0
-		if total > 0 {
-			percentage = (processed * 100) / total
-		}
-
-		if phase == "complete" {
-			h.wsHub.BroadcastGamesSyncComplete(processed)
-		} else {
-			h.wsHub.BroadcastGamesSyncProgress(&websocket.GamesSyncProgressPayload{
-				Phase:          phase,
-				CurrentGame:    currentGame,
-				ProcessedCount: processed,
-				TotalCount:     total,
-				Percentage:     percentage,
-			})
-		}
-	})
+	// Progress is broadcast via WebSocket by the sync scheduler
+	h.gameService.TriggerSyncIfNeeded()
 
 	c.JSON(http.StatusAccepted, gin.H{
 		"message": "Background sync started",
@@ -117,13 +104,20 @@ func (h *GameHandler) GetSyncStatus(c *gin.Context) {
 		percentage = (processed * 100) / total
 	}
 
+	// Set while syncing is paused because Steam rate limited us
+	var rateLimitedUntil *time.Time
+	if until := h.gameService.RateLimitedUntil(); !until.IsZero() {
+		rateLimitedUntil = &until
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"is_syncing":   isSyncing,
-		"phase":        phase,
-		"current_game": currentGame,
-		"processed":    processed,
-		"total":        total,
-		"percentage":   percentage,
+		"is_syncing":         isSyncing,
+		"phase":              phase,
+		"current_game":       currentGame,
+		"processed":          processed,
+		"total":              total,
+		"percentage":         percentage,
+		"rate_limited_until": rateLimitedUntil,
 	})
 }
 
@@ -176,6 +170,7 @@ func (h *GameHandler) InvalidateDBCache(c *gin.Context) {
 }
 
 // ServeGameImage serves a cached game image
+// The optional query parameter "size" selects a thumbnail width from services.GameImageSizes.
 // GET /api/v1/games/images/:filename
 func (h *GameHandler) ServeGameImage(c *gin.Context) {
 	filename := c.Param("filename")
@@ -194,21 +189,35 @@ func (h *GameHandler) ServeGameImage(c *gin.Context) {
 		return
 	}
 
-	// Check if image exists locally
-	imagePath := h.imageCacheService.GetImagePath(appID)
+	size, ok := parseImageSize(c, services.GameImageSizes)
+	if !ok {
+		return
+	}
 
-	// If not cached, try to cache it now
-	if !h.imageCacheService.HasImage(appID) {
-		if !h.imageCacheService.CacheImage(appID) {
-			// Redirect to Steam CDN as fallback
-			c.Redirect(http.StatusTemporaryRedirect, h.imageCacheService.GetSteamImageURL(appID))
+	// Look up the cached image, downloading it first if needed
+	imagePath, ok := h.imageCacheService.GetImage(appID)
+	if !ok {
+		// Custom games only have the cover uploaded by an admin, there is nothing on Steam.
+		// In offline mode browsers are never sent to the Steam CDN.
+		if models.IsCustomAppID(appID) || h.cfg.OfflineMedia {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
 			return
 		}
+		// Redirect to Steam CDN as fallback
+		c.Redirect(http.StatusTemporaryRedirect, h.imageCacheService.GetSteamImageURL(appID))
+		return
+	}
+
+	// Fall back to the full size image if the thumbnail cannot be generated
+	if size > 0 {
+		if variantPath, err := h.imageCacheService.GetImageVariant(appID, size); err != nil {
+			log.Printf("Failed to resize image of game %d to %dpx: %v", appID, size, err)
+		} else {
+			imagePath = variantPath
+		}
 	}
 
-	// Serve the cached image
-	c.Header("Cache-Control", "public, max-age=86400") // Cache for 24 hours
-	c.File(filepath.Clean(imagePath))
+	serveCachedImage(c, imagePath, "image/jpeg", 86400) // Cache for 24 hours
 }
 
 // RefreshMyGames refreshes the current user's game library from Steam
@@ -269,3 +278,63 @@ func (h *GameHandler) RefreshMyGames(c *gin.Context) {
 		"next_refresh_at":   time.Now().Add(userGamesRefreshCooldown),
 	})
 }
+
+// SuggestGames ranks games by how well they fit a group of players
+// Without user_ids, all currently connected players are used.
+// POST /api/v1/games/suggest
+func (h *GameHandler) SuggestGames(c *gin.Context) {
+	var req models.GameSuggestRequest
+	// An empty body is fine and means "everyone online"
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userIDs := req.UserIDs
+	if len(userIDs) == 0 {
+		userIDs = h.wsHub.GetConnectedUserIDs()
+	}
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No players given and nobody is online"})
+		return
+	}
+
+	players := make([]models.User, 0, len(userIDs))
+	seen := make(map[uint64]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		user, err := h.userRepo.GetByID(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load players"})
+			return
+		}
+		if user == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown user ID: " + strconv.FormatUint(userID, 10)})
+			return
+		}
+		players = append(players, *user)
+	}
+
+	// Keep the player list stable for the client
+	sort.Slice(players, func(i, j int) bool { return players[i].Username < players[j].Username })
+
+	suggestions, err := h.gameService.SuggestGames(players, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suggest games"})
+		return
+	}
+
+	publicPlayers := make([]models.PublicUser, 0, len(players))
+	for i := range players {
+		publicPlayers = append(publicPlayers, players[i].ToPublic())
+	}
+
+	c.JSON(http.StatusOK, models.GameSuggestResponse{
+		Players:     publicPlayers,
+		Suggestions: suggestions,
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// GameMetadataHandler handles the admin endpoints for curated game metadata
+type GameMetadataHandler struct {
+	gameMetadataService *services.GameMetadataService
+}
+
+// NewGameMetadataHandler creates a new game metadata handler
+func NewGameMetadataHandler(gameMetadataService *services.GameMetadataService) *GameMetadataHandler {
+	return &GameMetadataHandler{
+		gameMetadataService: gameMetadataService,
+	}
+}
+
+// List returns the metadata of all games
+// GET /api/v1/admin/games/metadata
+func (h *GameMetadataHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"metadata": h.gameMetadataService.List()})
+}
+
+// Set creates or replaces the metadata of a game
+// PUT /api/v1/admin/games/:app_id/metadata
+func (h *GameMetadataHandler) Set(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil || appID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	var req models.GameMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	meta, err := h.gameMetadataService.Set(appID, &req, claims.SteamID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidGameMetadata) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save game metadata"})
+		return
+	}
+
+	c.JSON(http.StatusOK, meta)
+}
+
+// Delete removes the metadata of a game
+// DELETE /api/v1/admin/games/:app_id/metadata
+func (h *GameMetadataHandler) Delete(c *gin.Context) {
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil || appID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	if err := h.gameMetadataService.Delete(appID); err != nil {
+		if errors.Is(err, services.ErrGameMetadataNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete game metadata"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Game metadata deleted"})
+}
+
+// Reload applies game_metadata.json right away instead of waiting for the file watcher
+// POST /api/v1/admin/games/metadata/reload
+func (h *GameMetadataHandler) Reload(c *gin.Context) {
+	if err := h.gameMetadataService.Reload(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"metadata": h.gameMetadataService.List()})
+}
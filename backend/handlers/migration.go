@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+)
+
+// MigrationHandler handles admin endpoints for the database schema
+type MigrationHandler struct{}
+
+// NewMigrationHandler creates a new migration handler
+func NewMigrationHandler() *MigrationHandler {
+	return &MigrationHandler{}
+}
+
+// MigrateDownRequest represents the request body for POST /admin/migrations/down
+type MigrateDownRequest struct {
+	Steps int `json:"steps" binding:"required,min=1"`
+}
+
+// ForceMigrationRequest represents the request body for POST /admin/migrations/force
+type ForceMigrationRequest struct {
+	Version *int `json:"version" binding:"required"` // -1 = no migrations applied
+}
+
+// GetStatus returns the current schema version and whether it is dirty
+// GET /api/v1/admin/migrations
+func (h *MigrationHandler) GetStatus(c *gin.Context) {
+	status, err := database.GetMigrationStatus()
+	if err != nil {
+		log.Printf("Failed to get migration status: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get migration status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"database":   database.GetDBType(),
+		"migrations": status,
+	})
+}
+
+// StepDown rolls back the given number of migrations
+// The running server keeps using the rolled back schema, so this is meant for
+// preparing a downgrade right before replacing the backend with an older version.
+// POST /api/v1/admin/migrations/down
+func (h *MigrationHandler) StepDown(c *gin.Context) {
+	var req MigrateDownRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "steps must be at least 1"})
+		return
+	}
+
+	claims, _ := middleware.GetClaims(c)
+	log.Printf("Admin %s rolls back %d migration(s)", claims.SteamID, req.Steps)
+
+	if err := database.MigrateDown(req.Steps); err != nil {
+		log.Printf("Failed to roll back migrations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.GetStatus(c)
+}
+
+// ForceVersion sets the schema version and clears the dirty flag without running migrations
+// POST /api/v1/admin/migrations/force
+func (h *MigrationHandler) ForceVersion(c *gin.Context) {
+	var req ForceMigrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version is required"})
+		return
+	}
+
+	claims, _ := middleware.GetClaims(c)
+	log.Printf("Admin %s forces migration version %d", claims.SteamID, *req.Version)
+
+	if err := database.ForceMigrationVersion(*req.Version); err != nil {
+		log.Printf("Failed to force migration version: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.GetStatus(c)
+}
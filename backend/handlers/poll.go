@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// PollHandler handles game poll endpoints
+type PollHandler struct {
+	pollService *services.PollService
+	cfg         *config.Config
+}
+
+// NewPollHandler creates a new poll handler
+func NewPollHandler(pollService *services.PollService, cfg *config.Config) *PollHandler {
+	return &PollHandler{
+		pollService: pollService,
+		cfg:         cfg,
+	}
+}
+
+// GetPolls returns open polls and the most recently closed ones
+// GET /api/v1/polls
+func (h *PollHandler) GetPolls(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	polls, err := h.pollService.ListPolls(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch polls"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"polls": polls})
+}
+
+// GetPoll returns a single poll with its live results
+// GET /api/v1/polls/:id
+func (h *PollHandler) GetPoll(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	pollID, ok := parsePollID(c)
+	if !ok {
+		return
+	}
+
+	poll, err := h.pollService.GetPoll(pollID, claims.UserID)
+	if err != nil {
+		respondPollError(c, err, "Failed to fetch poll")
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
+
+// Create opens a new poll
+// POST /api/v1/polls
+func (h *PollHandler) Create(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	var req models.CreatePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	poll, err := h.pollService.CreatePoll(claims.UserID, &req)
+	if err != nil {
+		respondPollError(c, err, "Failed to create poll")
+		return
+	}
+
+	c.JSON(http.StatusCreated, poll)
+}
+
+// Vote casts or replaces the caller's ballot
+// POST /api/v1/polls/:id/vote
+func (h *PollHandler) Vote(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	pollID, ok := parsePollID(c)
+	if !ok {
+		return
+	}
+
+	var req models.PollVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	poll, err := h.pollService.Vote(pollID, claims.UserID, req.AppIDs)
+	if err != nil {
+		respondPollError(c, err, "Failed to save vote")
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
+
+// Close closes a poll before its timer runs out (creator or admin only)
+// POST /api/v1/polls/:id/close
+func (h *PollHandler) Close(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	pollID, ok := parsePollID(c)
+	if !ok {
+		return
+	}
+
+	poll, err := h.pollService.GetPoll(pollID, claims.UserID)
+	if err != nil {
+		respondPollError(c, err, "Failed to fetch poll")
+		return
+	}
+	if poll.CreatedBy.ID != claims.UserID && !h.cfg.IsAdmin(claims.SteamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator or an admin can close this poll"})
+		return
+	}
+
+	closed, winner, err := h.pollService.ClosePoll(pollID)
+	if err != nil {
+		respondPollError(c, err, "Failed to close poll")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"poll":   closed,
+		"winner": winner,
+	})
+}
+
+// parsePollID reads the poll ID from the URL and responds with 400 if it is invalid
+func parsePollID(c *gin.Context) (uint64, bool) {
+	pollID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid poll ID"})
+		return 0, false
+	}
+	return pollID, true
+}
+
+// respondPollError maps poll service errors to HTTP responses
+func respondPollError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrPollNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Poll not found"})
+	case errors.Is(err, services.ErrPollClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Poll is already closed"})
+	case errors.Is(err, services.ErrInvalidPoll), errors.Is(err, services.ErrInvalidBallot):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// RatingHandler handles match history and rating endpoints
+type RatingHandler struct {
+	ratingService *services.RatingService
+	cfg           *config.Config
+}
+
+// NewRatingHandler creates a new rating handler
+func NewRatingHandler(ratingService *services.RatingService, cfg *config.Config) *RatingHandler {
+	return &RatingHandler{
+		ratingService: ratingService,
+		cfg:           cfg,
+	}
+}
+
+// RecordMatch records a match outcome and updates the ratings of its players
+// POST /api/v1/matches
+func (h *RatingHandler) RecordMatch(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	var req models.RecordMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	match, err := h.ratingService.RecordMatch(claims.UserID, h.cfg.IsAdmin(claims.SteamID), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotMatchPlayer):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidMatch), errors.Is(err, services.ErrUnknownGame):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record match"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, match)
+}
+
+// GetMatches returns the most recent matches, optionally filtered by ?app_id=
+// GET /api/v1/matches
+func (h *RatingHandler) GetMatches(c *gin.Context) {
+	appID := 0
+	if value := c.Query("app_id"); value != "" {
+		var err error
+		if appID, err = strconv.Atoi(value); err != nil || appID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultMatchListLimit)))
+
+	matches, err := h.ratingService.GetMatches(appID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matches": matches})
+}
+
+// GetLadder returns the rating ladder of a game
+// GET /api/v1/games/:app_id/ladder
+func (h *RatingHandler) GetLadder(c *gin.Context) {
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil || appID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	ladder, err := h.ratingService.GetLadder(appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ladder"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"app_id":  appID,
+		"ratings": ladder,
+	})
+}
+
+// GetPlayerRatings returns the ratings of a player in all games
+// GET /api/v1/users/:id/ratings
+func (h *RatingHandler) GetPlayerRatings(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ratings, err := h.ratingService.GetPlayerRatings(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ratings": ratings})
+}
+
+// GetHistory returns the rating history of a player in a game
+// GET /api/v1/users/:id/ratings/:app_id
+func (h *RatingHandler) GetHistory(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil || appID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	history, err := h.ratingService.GetHistory(userID, appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rating history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": userID,
+		"app_id":  appID,
+		"history": history,
+	})
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// ReadinessHandler handles install status and game session endpoints
+type ReadinessHandler struct {
+	readinessService *services.ReadinessService
+	userRepo         *repository.UserRepository
+	cfg              *config.Config
+}
+
+// NewReadinessHandler creates a new readiness handler
+func NewReadinessHandler(readinessService *services.ReadinessService, userRepo *repository.UserRepository, cfg *config.Config) *ReadinessHandler {
+	return &ReadinessHandler{
+		readinessService: readinessService,
+		userRepo:         userRepo,
+		cfg:              cfg,
+	}
+}
+
+// GetMyStatuses returns all install statuses the current user reported
+// GET /api/v1/games/install-status
+func (h *ReadinessHandler) GetMyStatuses(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	statuses, err := h.readinessService.GetStatuses(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch install status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statuses": statuses})
+}
+
+// SetMyStatus marks a game as installed, downloading, ready or not installed ("none")
+// PUT /api/v1/games/:app_id/install-status
+func (h *ReadinessHandler) SetMyStatus(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil || appID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	var req models.SetInstallStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := h.userRepo.GetByID(claims.UserID)
+	if err != nil || user == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	if err := h.readinessService.SetStatus(user, appID, req.Status, req.Progress); err != nil {
+		respondReadinessError(c, err, "Failed to update install status")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"app_id": appID,
+		"status": req.Status,
+	})
+}
+
+// GetRoster returns who owns a game and how far everyone is with installing it
+// GET /api/v1/games/:app_id/roster
+func (h *ReadinessHandler) GetRoster(c *gin.Context) {
+	appID, err := strconv.Atoi(c.Param("app_id"))
+	if err != nil || appID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid app ID"})
+		return
+	}
+
+	roster, err := h.readinessService.GetRoster(appID)
+	if err != nil {
+		respondReadinessError(c, err, "Failed to fetch roster")
+		return
+	}
+
+	c.JSON(http.StatusOK, roster)
+}
+
+// GetSessions returns the most recently planned game sessions
+// GET /api/v1/sessions
+func (h *ReadinessHandler) GetSessions(c *gin.Context) {
+	sessions, err := h.readinessService.ListSessions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// GetSession returns a game session with the readiness of its players
+// GET /api/v1/sessions/:id
+func (h *ReadinessHandler) GetSession(c *gin.Context) {
+	sessionID, ok := parseSessionID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.readinessService.GetSession(sessionID)
+	if err != nil {
+		respondReadinessError(c, err, "Failed to fetch session")
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// CreateSession plans a game session
+// POST /api/v1/sessions
+func (h *ReadinessHandler) CreateSession(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	var req models.CreateGameSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := h.userRepo.GetByID(claims.UserID)
+	if err != nil || user == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	session, err := h.readinessService.CreateSession(user, &req)
+	if err != nil {
+		respondReadinessError(c, err, "Failed to create session")
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// DeleteSession deletes a game session (creator or admin only)
+// DELETE /api/v1/sessions/:id
+func (h *ReadinessHandler) DeleteSession(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	sessionID, ok := parseSessionID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.readinessService.GetSession(sessionID)
+	if err != nil {
+		respondReadinessError(c, err, "Failed to fetch session")
+		return
+	}
+	if session.CreatedBy.ID != claims.UserID && !h.cfg.IsAdmin(claims.SteamID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator or an admin can delete this session"})
+		return
+	}
+
+	if err := h.readinessService.DeleteSession(sessionID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session deleted"})
+}
+
+// parseSessionID reads the session ID from the URL and responds with 400 if it is invalid
+func parseSessionID(c *gin.Context) (uint64, bool) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return 0, false
+	}
+	return sessionID, true
+}
+
+// respondReadinessError maps readiness service errors to HTTP responses
+func respondReadinessError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+	case errors.Is(err, services.ErrUnknownGame):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	case errors.Is(err, services.ErrInvalidSession), errors.Is(err, services.ErrInvalidStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// SnapshotHandler handles admin endpoints for SQLite snapshots
+type SnapshotHandler struct {
+	snapshotService *services.SnapshotService
+}
+
+// NewSnapshotHandler creates a new snapshot handler
+func NewSnapshotHandler(snapshotService *services.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// ListSnapshots returns all snapshots and pre-migration backups
+// GET /api/v1/admin/snapshots
+func (h *SnapshotHandler) ListSnapshots(c *gin.Context) {
+	snapshots, err := h.snapshotService.ListSnapshots()
+	if err != nil {
+		log.Printf("Failed to list snapshots: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":   h.snapshotService.Enabled(),
+		"snapshots": snapshots,
+	})
+}
+
+// CreateSnapshot takes a snapshot immediately
+// POST /api/v1/admin/snapshots
+func (h *SnapshotHandler) CreateSnapshot(c *gin.Context) {
+	if !h.snapshotService.Enabled() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Snapshots are only supported for SQLite databases"})
+		return
+	}
+
+	claims, _ := middleware.GetClaims(c)
+	log.Printf("Admin %s creates a database snapshot", claims.SteamID)
+
+	snapshot, err := h.snapshotService.CreateSnapshot()
+	if err != nil {
+		log.Printf("Failed to create snapshot: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create snapshot"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// DownloadSnapshot sends a snapshot file
+// GET /api/v1/admin/snapshots/:name
+func (h *SnapshotHandler) DownloadSnapshot(c *gin.Context) {
+	name := c.Param("name")
+	path, ok := h.snapshotService.SnapshotPath(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Snapshot not found"})
+		return
+	}
+
+	c.FileAttachment(path, name)
+}
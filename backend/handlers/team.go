@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// TeamHandler handles team generation endpoints
+type TeamHandler struct {
+	teamService *services.TeamService
+}
+
+// NewTeamHandler creates a new team handler
+func NewTeamHandler(teamService *services.TeamService) *TeamHandler {
+	return &TeamHandler{
+		teamService: teamService,
+	}
+}
+
+// Generate splits the given players into balanced teams
+// POST /api/v1/teams/generate
+func (h *TeamHandler) Generate(c *gin.Context) {
+	var req models.TeamGenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	response, err := h.teamService.GenerateTeams(&req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTeams) || errors.Is(err, services.ErrUnknownGame) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate teams"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
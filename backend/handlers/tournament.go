@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/middleware"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/services"
+)
+
+// TournamentHandler handles tournament endpoints
+type TournamentHandler struct {
+	tournamentService *services.TournamentService
+	cfg               *config.Config
+}
+
+// NewTournamentHandler creates a new tournament handler
+func NewTournamentHandler(tournamentService *services.TournamentService, cfg *config.Config) *TournamentHandler {
+	return &TournamentHandler{
+		tournamentService: tournamentService,
+		cfg:               cfg,
+	}
+}
+
+// GetTournaments returns all tournaments with their registered players
+// GET /api/v1/tournaments
+func (h *TournamentHandler) GetTournaments(c *gin.Context) {
+	tournaments, err := h.tournamentService.ListTournaments()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tournaments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tournaments": tournaments})
+}
+
+// GetTournament returns a tournament with its teams, bracket and standings
+// GET /api/v1/tournaments/:id
+func (h *TournamentHandler) GetTournament(c *gin.Context) {
+	tournamentID, ok := parseTournamentID(c)
+	if !ok {
+		return
+	}
+
+	tournament, err := h.tournamentService.GetTournament(tournamentID)
+	if err != nil {
+		respondTournamentError(c, err, "Failed to fetch tournament")
+		return
+	}
+
+	c.JSON(http.StatusOK, tournament)
+}
+
+// Register signs the caller up for a tournament
+// POST /api/v1/tournaments/:id/register
+func (h *TournamentHandler) Register(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	tournamentID, ok := parseTournamentID(c)
+	if !ok {
+		return
+	}
+
+	tournament, err := h.tournamentService.Register(tournamentID, claims.UserID)
+	if err != nil {
+		respondTournamentError(c, err, "Failed to register for tournament")
+		return
+	}
+
+	c.JSON(http.StatusOK, tournament)
+}
+
+// Unregister withdraws the caller from a tournament
+// DELETE /api/v1/tournaments/:id/register
+func (h *TournamentHandler) Unregister(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	tournamentID, ok := parseTournamentID(c)
+	if !ok {
+		return
+	}
+
+	tournament, err := h.tournamentService.Unregister(tournamentID, claims.UserID)
+	if err != nil {
+		respondTournamentError(c, err, "Failed to unregister from tournament")
+		return
+	}
+
+	c.JSON(http.StatusOK, tournament)
+}
+
+// ReportResult reports the score of a match (players of the match or admin only)
+// POST /api/v1/tournaments/:id/matches/:match_id/report
+func (h *TournamentHandler) ReportResult(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	tournamentID, ok := parseTournamentID(c)
+	if !ok {
+		return
+	}
+	matchID, ok := parseMatchID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tournament, err := h.tournamentService.ReportResult(tournamentID, matchID, claims.UserID, h.cfg.IsAdmin(claims.SteamID), &req)
+	if err != nil {
+		respondTournamentError(c, err, "Failed to report result")
+		return
+	}
+
+	c.JSON(http.StatusOK, tournament)
+}
+
+// Create creates a new tournament (admin only)
+// POST /api/v1/admin/tournaments
+func (h *TournamentHandler) Create(c *gin.Context) {
+	claims, _ := middleware.GetClaims(c)
+
+	var req models.CreateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tournament, err := h.tournamentService.CreateTournament(claims.UserID, &req)
+	if err != nil {
+		respondTournamentError(c, err, "Failed to create tournament")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tournament)
+}
+
+// Start closes registration, forms teams and generates the bracket (admin only)
+// POST /api/v1/admin/tournaments/:id/start
+func (h *TournamentHandler) Start(c *gin.Context) {
+	tournamentID, ok := parseTournamentID(c)
+	if !ok {
+		return
+	}
+
+	tournament, err := h.tournamentService.StartTournament(tournamentID)
+	if err != nil {
+		respondTournamentError(c, err, "Failed to start tournament")
+		return
+	}
+
+	c.JSON(http.StatusOK, tournament)
+}
+
+// ConfirmResult confirms the reported result of a match, or sets it if a score is sent (admin only)
+// POST /api/v1/admin/tournaments/:id/matches/:match_id/confirm
+func (h *TournamentHandler) ConfirmResult(c *gin.Context) {
+	tournamentID, ok := parseTournamentID(c)
+	if !ok {
+		return
+	}
+	matchID, ok := parseMatchID(c)
+	if !ok {
+		return
+	}
+
+	// An empty body confirms the reported score
+	var req *models.ReportMatchRequest
+	var body models.ReportMatchRequest
+	if err := c.ShouldBindJSON(&body); err == nil {
+		req = &body
+	} else if !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tournament, err := h.tournamentService.ConfirmResult(tournamentID, matchID, req)
+	if err != nil {
+		respondTournamentError(c, err, "Failed to confirm result")
+		return
+	}
+
+	c.JSON(http.StatusOK, tournament)
+}
+
+// Delete deletes a tournament (admin only)
+// DELETE /api/v1/admin/tournaments/:id
+func (h *TournamentHandler) Delete(c *gin.Context) {
+	tournamentID, ok := parseTournamentID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tournamentService.DeleteTournament(tournamentID); err != nil {
+		respondTournamentError(c, err, "Failed to delete tournament")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tournament deleted"})
+}
+
+// parseTournamentID reads the tournament ID from the URL and responds with 400 if it is invalid
+func parseTournamentID(c *gin.Context) (uint64, bool) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return 0, false
+	}
+	return tournamentID, true
+}
+
+// parseMatchID reads the match ID from the URL and responds with 400 if it is invalid
+func parseMatchID(c *gin.Context) (uint64, bool) {
+	matchID, err := strconv.ParseUint(c.Param("match_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return 0, false
+	}
+	return matchID, true
+}
+
+// respondTournamentError maps tournament service errors to HTTP responses
+func respondTournamentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrTournamentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+	case errors.Is(err, services.ErrMatchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+	case errors.Is(err, services.ErrNotTeamMember):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRegistrationClosed), errors.Is(err, services.ErrTournamentNotRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTournament), errors.Is(err, services.ErrInvalidMatchResult),
+		errors.Is(err, services.ErrUnknownGame):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -1,8 +1,8 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
-	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -134,6 +134,7 @@ func (h *UserHandler) GetOthers(c *gin.Context) {
 }
 
 // ServeAvatar serves a cached avatar image
+// The optional query parameter "size" selects a thumbnail width from services.AvatarSizes.
 // GET /api/v1/avatars/:filename
 func (h *UserHandler) ServeAvatar(c *gin.Context) {
 	filename := c.Param("filename")
@@ -150,21 +151,29 @@ func (h *UserHandler) ServeAvatar(c *gin.Context) {
 		return
 	}
 
-	// Check if avatar exists locally
-	if !h.avatarCacheService.HasAvatarFile(filename) {
+	size, ok := parseImageSize(c, services.AvatarSizes)
+	if !ok {
+		return
+	}
+
+	// Check if avatar exists locally (evicted avatars are downloaded again if possible)
+	avatarPath, ok := h.avatarCacheService.GetAvatarFile(filename)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Avatar not found"})
 		return
 	}
 
-	// Determine content type
+	// Determine content type, SVG avatars scale without a resized variant
 	contentType := "image/jpeg"
 	if strings.HasSuffix(filename, ".svg") {
 		contentType = "image/svg+xml"
+	} else if size > 0 {
+		if variantPath, err := h.avatarCacheService.GetAvatarVariant(filename, size); err != nil {
+			log.Printf("Failed to resize avatar %s to %dpx: %v", filename, size, err)
+		} else {
+			avatarPath = variantPath
+		}
 	}
 
-	// Serve the cached avatar
-	avatarPath := h.avatarCacheService.GetAvatarByFilename(filename)
-	c.Header("Content-Type", contentType)
-	c.Header("Cache-Control", "public, max-age=604800") // Cache for 7 days
-	c.File(filepath.Clean(avatarPath))
+	serveCachedImage(c, avatarPath, contentType, 604800) // Cache for 7 days
 }
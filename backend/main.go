@@ -1,18 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/guided-traffic/rate-your-mate/backend/auth"
 	"github.com/guided-traffic/rate-your-mate/backend/config"
 	"github.com/guided-traffic/rate-your-mate/backend/database"
 	"github.com/guided-traffic/rate-your-mate/backend/handlers"
 	"github.com/guided-traffic/rate-your-mate/backend/middleware"
 	"github.com/guided-traffic/rate-your-mate/backend/repository"
 	"github.com/guided-traffic/rate-your-mate/backend/services"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
 	"github.com/guided-traffic/rate-your-mate/backend/websocket"
 )
 
@@ -31,32 +37,27 @@ func main() {
 	cfg = config.Load()
 	log.Printf("Configuration loaded - Frontend: %s, Backend: %s", cfg.FrontendURL, cfg.BackendURL)
 
+	// Run maintenance subcommands (export, import, ...) instead of the server if requested
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
+	// Cancelled on SIGINT/SIGTERM to shut down background jobs and the server gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Check Steam connectivity at startup
-	steamAPIClient := auth.NewSteamAPIClient(cfg.SteamAPIKey)
-	if err := steamAPIClient.CheckConnectivity(); err != nil {
+	steamClient, err := steam.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize Steam client: %v", err)
+	}
+	if err := steamClient.CheckConnectivity(); err != nil {
 		log.Fatalf("Steam connectivity check failed: %v", err)
 	}
 	log.Println("Steam endpoints are reachable")
 
 	// Initialize database based on configuration
-	dbCfg := database.Config{
-		Type:       database.DBType(cfg.DBType),
-		SQLitePath: cfg.DBPath,
-		MySQL: database.MySQLConfig{
-			Host:            cfg.MySQLHost,
-			Port:            cfg.MySQLPort,
-			User:            cfg.MySQLUser,
-			Password:        cfg.MySQLPassword,
-			Database:        cfg.MySQLDatabase,
-			TLSEnabled:      cfg.MySQLTLSEnabled,
-			TLSSkipVerify:   cfg.MySQLTLSSkipVerify,
-			TLSCACert:       cfg.MySQLTLSCACert,
-			MaxOpenConns:    cfg.MySQLMaxOpenConns,
-			MaxIdleConns:    cfg.MySQLMaxIdleConns,
-			ConnMaxLifetime: cfg.MySQLConnMaxLifetime,
-			ConnMaxIdleTime: cfg.MySQLConnMaxIdleTime,
-		},
-	}
+	dbCfg := newDatabaseConfig(cfg)
 	if err := database.Init(dbCfg); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
@@ -71,33 +72,86 @@ func main() {
 	userRepo := repository.NewUserRepository()
 	voteRepo := repository.NewVoteRepository()
 	chatRepo := repository.NewChatRepository()
+	dmRepo := repository.NewDirectMessageRepository()
 	gameCacheRepo := repository.NewGameCacheRepository()
 	gameOwnerRepo := repository.NewGameOwnerRepository()
+	exportRepo := repository.NewExportRepository()
+	pollRepo := repository.NewPollRepository()
+	installStatusRepo := repository.NewInstallStatusRepository()
+	gameSessionRepo := repository.NewGameSessionRepository()
+	tournamentRepo := repository.NewTournamentRepository()
+	ratingRepo := repository.NewRatingRepository()
+	syncJobRepo := repository.NewSyncJobRepository()
+	gameMetadataRepo := repository.NewGameMetadataRepository()
+	customGameRepo := repository.NewCustomGameRepository()
 
 	// Initialize services
 	creditService := services.NewCreditService(cfg, userRepo)
-	imageCacheService := services.NewImageCacheService()
-	avatarCacheService := services.NewAvatarCacheService(cfg.BackendURL)
-	gameMetadataService := services.NewGameMetadataService(cfg.GameMetadataPath)
-	gameService := services.NewGameService(cfg, userRepo, gameCacheRepo, gameOwnerRepo, imageCacheService, gameMetadataService)
+	imageCacheService := services.NewImageCacheService(cfg)
+	avatarCacheService := services.NewAvatarCacheService(cfg)
+	gameMetadataService := services.NewGameMetadataService(cfg, gameMetadataRepo)
+	gameService := services.NewGameService(cfg, steamClient, userRepo, gameCacheRepo, gameOwnerRepo, imageCacheService, gameMetadataService)
+	customGameService := services.NewCustomGameService(customGameRepo, gameOwnerRepo, imageCacheService, gameService, wsHub)
+	gameSyncScheduler := services.NewGameSyncScheduler(cfg, gameService, syncJobRepo, wsHub.BroadcastGamesSyncStatus)
 	countdownService := services.NewCountdownService(cfg, wsHub, userRepo)
+	exportService := services.NewExportService(cfg, Version, userRepo, voteRepo, chatRepo, dmRepo, gameOwnerRepo, customGameRepo, gameMetadataRepo, pollRepo, gameSessionRepo, installStatusRepo, tournamentRepo, ratingRepo, exportRepo)
+	snapshotService := services.NewSnapshotService(cfg)
+	pollService := services.NewPollService(pollRepo, userRepo, gameCacheRepo, gameOwnerRepo, wsHub)
+	readinessService := services.NewReadinessService(installStatusRepo, gameSessionRepo, userRepo, gameCacheRepo, gameOwnerRepo, wsHub)
+	tournamentService := services.NewTournamentService(tournamentRepo, gameCacheRepo, wsHub)
+	teamService := services.NewTeamService(userRepo, voteRepo, gameCacheRepo, gameOwnerRepo)
+	ratingService := services.NewRatingService(ratingRepo, userRepo, gameCacheRepo, wsHub)
+	invitationService := services.NewInvitationService(steamClient, userRepo, gameService, wsHub)
+	chatService := services.NewChatService(cfg, chatRepo, userRepo, gameCacheRepo, wsHub)
+	dmService := services.NewDirectMessageService(dmRepo, userRepo, chatService, wsHub)
+	mediaPrefetchService := services.NewMediaPrefetchService(steamClient, userRepo, gameCacheRepo, customGameRepo, imageCacheService, avatarCacheService, wsHub)
+	defer mediaPrefetchService.Stop()
 
 	// Start countdown watcher
 	countdownService.Start()
 	defer countdownService.Stop()
 
+	// Start closing game polls when their timer runs out
+	pollService.Start()
+	defer pollService.Stop()
+
+	// Start scheduled SQLite snapshots (if configured)
+	snapshotService.Start()
+	defer snapshotService.Stop()
+
+	// Start watching game_metadata.json for changes
+	gameMetadataService.Start()
+	defer gameMetadataService.Stop()
+
+	// Start syncing stale game data in the background (resumes an interrupted sync)
+	gameSyncScheduler.Start(ctx)
+	defer gameSyncScheduler.Stop()
+
 	// Prefetch pinned games in background at startup
 	gameService.PrefetchPinnedGames()
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(cfg, userRepo, creditService, gameService, avatarCacheService, wsHub)
+	authHandler := handlers.NewAuthHandler(cfg, steamClient, userRepo, creditService, gameService, avatarCacheService, wsHub)
 	userHandler := handlers.NewUserHandler(userRepo, avatarCacheService)
 	achievementHandler := handlers.NewAchievementHandler()
 	voteHandler := handlers.NewVoteHandler(voteRepo, userRepo, creditService, wsHub, cfg)
 	wsHandler := handlers.NewWebSocketHandler(wsHub, authHandler.GetJWTService())
 	settingsHandler := handlers.NewSettingsHandler(cfg, wsHub, userRepo, voteRepo)
-	chatHandler := handlers.NewChatHandler(chatRepo, userRepo, wsHub)
+	chatHandler := handlers.NewChatHandler(chatService, cfg)
+	dmHandler := handlers.NewDirectMessageHandler(dmService)
 	gameHandler := handlers.NewGameHandler(gameService, imageCacheService, gameCacheRepo, userRepo, cfg, wsHub)
+	exportHandler := handlers.NewExportHandler(exportService, cfg, wsHub)
+	migrationHandler := handlers.NewMigrationHandler()
+	snapshotHandler := handlers.NewSnapshotHandler(snapshotService)
+	pollHandler := handlers.NewPollHandler(pollService, cfg)
+	readinessHandler := handlers.NewReadinessHandler(readinessService, userRepo, cfg)
+	tournamentHandler := handlers.NewTournamentHandler(tournamentService, cfg)
+	teamHandler := handlers.NewTeamHandler(teamService)
+	ratingHandler := handlers.NewRatingHandler(ratingService, cfg)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	gameMetadataHandler := handlers.NewGameMetadataHandler(gameMetadataService)
+	customGameHandler := handlers.NewCustomGameHandler(customGameService)
+	cacheHandler := handlers.NewCacheHandler(imageCacheService, avatarCacheService, mediaPrefetchService)
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -182,6 +236,35 @@ func main() {
 			// Chat
 			protected.GET("/chat", chatHandler.GetMessages)
 			protected.POST("/chat", chatHandler.Create)
+			protected.PUT("/chat/:id", chatHandler.Update)
+			protected.DELETE("/chat/:id", chatHandler.Delete)
+			protected.POST("/chat/:id/reactions", chatHandler.AddReaction)
+			protected.DELETE("/chat/:id/reactions/:emoji", chatHandler.RemoveReaction)
+
+			// Chat channels (general, per game and announcements)
+			protected.GET("/chat/channels", chatHandler.GetChannels)
+			protected.GET("/chat/channels/:id/messages", chatHandler.GetChannelMessages)
+			protected.POST("/chat/channels/:id/messages", chatHandler.CreateInChannel)
+			protected.POST("/chat/channels/:id/join", chatHandler.JoinChannel)
+			protected.DELETE("/chat/channels/:id/join", chatHandler.LeaveChannel)
+			protected.POST("/chat/channels/games/:app_id/join", chatHandler.JoinGameChannel)
+
+			// Chat moderation (require moderator privileges)
+			moderation := protected.Group("/chat/moderation")
+			moderation.Use(chatHandler.ModeratorMiddleware())
+			{
+				moderation.GET("", chatHandler.GetModeration)
+				moderation.PUT("/slow-mode", chatHandler.SetSlowMode)
+				moderation.POST("/mutes/:user_id", chatHandler.Mute)
+				moderation.DELETE("/mutes/:user_id", chatHandler.Unmute)
+			}
+
+			// Direct messages
+			protected.GET("/direct-messages", dmHandler.GetConversations)
+			protected.GET("/direct-messages/unread", dmHandler.GetUnreadCount)
+			protected.GET("/direct-messages/:user_id", dmHandler.GetConversation)
+			protected.POST("/direct-messages/:user_id", dmHandler.Send)
+			protected.POST("/direct-messages/:user_id/read", dmHandler.MarkRead)
 
 			// Voting status (for authenticated users)
 			protected.GET("/voting-status", settingsHandler.GetVotingStatus)
@@ -198,8 +281,44 @@ func main() {
 			protected.GET("/games", gameHandler.GetMultiplayerGames)
 			protected.POST("/games/refresh", gameHandler.RefreshGames)
 			protected.POST("/games/refresh-my-games", gameHandler.RefreshMyGames)
+			protected.POST("/games/suggest", gameHandler.SuggestGames)
 			protected.POST("/games/sync", gameHandler.StartBackgroundSync)
 			protected.GET("/games/sync/status", gameHandler.GetSyncStatus)
+			protected.PUT("/games/:app_id/owned", customGameHandler.SetOwned)
+			protected.DELETE("/games/:app_id/owned", customGameHandler.RemoveOwned)
+
+			// Install status and readiness
+			protected.GET("/games/install-status", readinessHandler.GetMyStatuses)
+			protected.PUT("/games/:app_id/install-status", readinessHandler.SetMyStatus)
+			protected.GET("/games/:app_id/roster", readinessHandler.GetRoster)
+			protected.GET("/sessions", readinessHandler.GetSessions)
+			protected.POST("/sessions", readinessHandler.CreateSession)
+			protected.GET("/sessions/:id", readinessHandler.GetSession)
+			protected.DELETE("/sessions/:id", readinessHandler.DeleteSession)
+
+			// Game polls
+			protected.GET("/polls", pollHandler.GetPolls)
+			protected.POST("/polls", pollHandler.Create)
+			protected.GET("/polls/:id", pollHandler.GetPoll)
+			protected.POST("/polls/:id/vote", pollHandler.Vote)
+			protected.POST("/polls/:id/close", pollHandler.Close)
+
+			// Balanced teams
+			protected.POST("/teams/generate", teamHandler.Generate)
+
+			// Match history and per-game ratings
+			protected.GET("/matches", ratingHandler.GetMatches)
+			protected.POST("/matches", ratingHandler.RecordMatch)
+			protected.GET("/games/:app_id/ladder", ratingHandler.GetLadder)
+			protected.GET("/users/:id/ratings", ratingHandler.GetPlayerRatings)
+			protected.GET("/users/:id/ratings/:app_id", ratingHandler.GetHistory)
+
+			// Tournaments
+			protected.GET("/tournaments", tournamentHandler.GetTournaments)
+			protected.GET("/tournaments/:id", tournamentHandler.GetTournament)
+			protected.POST("/tournaments/:id/register", tournamentHandler.Register)
+			protected.DELETE("/tournaments/:id/register", tournamentHandler.Unregister)
+			protected.POST("/tournaments/:id/matches/:match_id/report", tournamentHandler.ReportResult)
 
 			// Admin routes (require admin privileges)
 			admin := protected.Group("/admin")
@@ -213,6 +332,21 @@ func main() {
 				admin.POST("/credits/give", settingsHandler.GiveEveryoneCredit)
 				admin.POST("/votes/delete-all", settingsHandler.DeleteAllVotes)
 				admin.POST("/games/invalidate-cache", gameHandler.InvalidateDBCache)
+				admin.GET("/cache/stats", cacheHandler.GetStats)
+				admin.GET("/cache/prefetch", cacheHandler.GetPrefetchStatus)
+				admin.POST("/cache/prefetch", cacheHandler.StartPrefetch)
+				// Game metadata
+				admin.GET("/games/metadata", gameMetadataHandler.List)
+				admin.POST("/games/metadata/reload", gameMetadataHandler.Reload)
+				admin.PUT("/games/:app_id/metadata", gameMetadataHandler.Set)
+				admin.DELETE("/games/:app_id/metadata", gameMetadataHandler.Delete)
+
+				// Custom non-Steam games
+				admin.GET("/games/custom", customGameHandler.List)
+				admin.POST("/games/custom", customGameHandler.Create)
+				admin.PUT("/games/custom/:app_id", customGameHandler.Update)
+				admin.DELETE("/games/custom/:app_id", customGameHandler.Delete)
+				admin.PUT("/games/custom/:app_id/image", customGameHandler.UploadImage)
 				// Vote management
 				admin.PUT("/votes/:id/invalidate", voteHandler.ToggleInvalidation)
 				// User management
@@ -221,12 +355,89 @@ func main() {
 				admin.POST("/users/:id/kick", settingsHandler.KickUser)
 				admin.POST("/users/:id/ban", settingsHandler.BanUser)
 				admin.POST("/users/unban/:steam_id", settingsHandler.UnbanUser)
+				// Steam invitations
+				admin.POST("/invitations/friends", invitationHandler.ImportFriends)
+				admin.POST("/invitations/group", invitationHandler.ImportGroup)
+				// Data export/import
+				admin.GET("/export", exportHandler.Export)
+				admin.POST("/import", exportHandler.Import)
+				// Database schema
+				admin.GET("/migrations", migrationHandler.GetStatus)
+				admin.POST("/migrations/down", migrationHandler.StepDown)
+				admin.POST("/migrations/force", migrationHandler.ForceVersion)
+				// SQLite snapshots
+				admin.GET("/snapshots", snapshotHandler.ListSnapshots)
+				admin.POST("/snapshots", snapshotHandler.CreateSnapshot)
+				admin.GET("/snapshots/:name", snapshotHandler.DownloadSnapshot)
+				// Tournaments
+				admin.POST("/tournaments", tournamentHandler.Create)
+				admin.POST("/tournaments/:id/start", tournamentHandler.Start)
+				admin.POST("/tournaments/:id/matches/:match_id/confirm", tournamentHandler.ConfirmResult)
+				admin.DELETE("/tournaments/:id", tournamentHandler.Delete)
 			}
 		}
 	}
 
-	log.Printf("Server starting on port %s", cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: r,
+	}
+	go func() {
+		log.Printf("Server starting on port %s", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown failed: %v", err)
+	}
+}
+
+// newDatabaseConfig builds the database configuration from the application config
+func newDatabaseConfig(cfg *config.Config) database.Config {
+	return database.Config{
+		Type:       database.DBType(cfg.DBType),
+		SQLitePath: cfg.DBPath,
+		AllowDirty: cfg.MigrateAllowDirty,
+		BackupDir:  backupDirBeforeMigrate(cfg),
+		MySQL: database.MySQLConfig{
+			Host:            cfg.MySQLHost,
+			Port:            cfg.MySQLPort,
+			User:            cfg.MySQLUser,
+			Password:        cfg.MySQLPassword,
+			Database:        cfg.MySQLDatabase,
+			TLSEnabled:      cfg.MySQLTLSEnabled,
+			TLSSkipVerify:   cfg.MySQLTLSSkipVerify,
+			TLSCACert:       cfg.MySQLTLSCACert,
+			MaxOpenConns:    cfg.MySQLMaxOpenConns,
+			MaxIdleConns:    cfg.MySQLMaxIdleConns,
+			ConnMaxLifetime: cfg.MySQLConnMaxLifetime,
+			ConnMaxIdleTime: cfg.MySQLConnMaxIdleTime,
+		},
+		Postgres: database.PostgresConfig{
+			Host:            cfg.PostgresHost,
+			Port:            cfg.PostgresPort,
+			User:            cfg.PostgresUser,
+			Password:        cfg.PostgresPassword,
+			Database:        cfg.PostgresDatabase,
+			SSLMode:         cfg.PostgresSSLMode,
+			MaxOpenConns:    cfg.PostgresMaxOpenConns,
+			MaxIdleConns:    cfg.PostgresMaxIdleConns,
+			ConnMaxLifetime: cfg.PostgresConnMaxLifetime,
+			ConnMaxIdleTime: cfg.PostgresConnMaxIdleTime,
+		},
+	}
+}
+
+// backupDirBeforeMigrate returns the pre-migration backup directory, or "" if disabled
+func backupDirBeforeMigrate(cfg *config.Config) string {
+	if !cfg.BackupBeforeMigrate {
+		return ""
 	}
+	return cfg.BackupDir
 }
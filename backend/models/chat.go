@@ -1,23 +1,105 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // ChatMessage represents a chat message in the system
 type ChatMessage struct {
-	ID           uint64    `json:"id"`
-	UserID       uint64    `json:"user_id"`
-	Message      string    `json:"message"`
-	Achievements string    `json:"achievements"` // JSON array of achievement IDs at time of message
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uint64     `json:"id"`
+	UserID       uint64     `json:"user_id"`
+	Message      string     `json:"message"`
+	Achievements string     `json:"achievements"` // JSON array of achievement IDs at time of message
+	CreatedAt    time.Time  `json:"created_at"`
+	EditedAt     *time.Time `json:"edited_at,omitempty"`
+	ParentID     *uint64    `json:"parent_id,omitempty"` // Message this one replies to
+	ChannelID    uint64     `json:"channel_id"`
+	Channel      string     `json:"channel,omitempty"` // Channel slug, so exports can be restored into another database
 }
 
 // ChatMessageWithUser includes user information for display
 type ChatMessageWithUser struct {
-	ID           uint64           `json:"id"`
-	User         PublicUser       `json:"user"`
-	Message      string           `json:"message"`
+	ID           uint64             `json:"id"`
+	ChannelID    uint64             `json:"channel_id"`
+	User         PublicUser         `json:"user"`
+	Message      string             `json:"message"`
 	Achievements []AchievementBadge `json:"achievements"` // Achievement badges at time of message
-	CreatedAt    time.Time        `json:"created_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	EditedAt     *time.Time         `json:"edited_at,omitempty"` // Set when the author edited the message
+	ParentID     *uint64            `json:"parent_id,omitempty"` // Message this one replies to, cleared when it is deleted
+	Parent       *ChatReplyPreview  `json:"parent,omitempty"`
+	Reactions    []ChatReaction     `json:"reactions"` // In order of the first reaction with each emoji
+}
+
+// Chat channel kinds
+const (
+	ChatChannelGlobal        = "global"        // Everybody reads and writes
+	ChatChannelGame          = "game"          // One per game, players join the games they care about
+	ChatChannelAnnouncements = "announcements" // Everybody reads, only admins write
+)
+
+// Chat channels created by the migrations, they always exist
+const (
+	GeneralChatChannelID       uint64 = 1
+	AnnouncementsChatChannelID uint64 = 2
+)
+
+// ChatChannel is a named chat room
+// ReadOnly and Joined depend on the player the channel is shown to.
+type ChatChannel struct {
+	ID        uint64    `json:"id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	AppID     *int      `json:"app_id,omitempty"` // Game of a game channel
+	ReadOnly  bool      `json:"read_only"`
+	Joined    bool      `json:"joined"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GameChatChannelSlug returns the slug of the channel of a game
+func GameChatChannelSlug(appID int) string {
+	return fmt.Sprintf("game-%d", appID)
+}
+
+// ChatReplyPreview shows the message a reply refers to
+type ChatReplyPreview struct {
+	ID       uint64 `json:"id"`
+	Username string `json:"username"`
+	Message  string `json:"message"`
+}
+
+// ChatReaction aggregates the reactions with one emoji on a message
+type ChatReaction struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []uint64 `json:"user_ids"` // Players who reacted, so clients can highlight their own reaction
+}
+
+// ChatHistory is a page of chat messages, oldest first
+// Older messages are loaded by passing NextCursor as "before".
+type ChatHistory struct {
+	Channel         *ChatChannel          `json:"channel"`
+	Messages        []ChatMessageWithUser `json:"messages"`
+	HasMore         bool                  `json:"has_more"`
+	NextCursor      uint64                `json:"next_cursor,omitempty"` // ID of the oldest message in this page
+	SlowModeSeconds int                   `json:"slow_mode_seconds"`     // Minimum seconds between two messages, 0 when off
+}
+
+// ChatMute silences a player in the chat until MutedUntil
+type ChatMute struct {
+	User       PublicUser `json:"user"`
+	Reason     string     `json:"reason"`
+	MutedBy    string     `json:"muted_by"` // Steam ID of the moderator
+	MutedUntil time.Time  `json:"muted_until"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ChatModerationStatus is the moderation state shown to moderators
+type ChatModerationStatus struct {
+	SlowModeSeconds int        `json:"slow_mode_seconds"` // 0 when slow mode is off
+	Mutes           []ChatMute `json:"mutes"`             // Active mutes, ending soonest first
 }
 
 // AchievementBadge represents a simplified achievement for display as badge
@@ -31,5 +113,27 @@ type AchievementBadge struct {
 
 // CreateChatMessageRequest is the request body for creating a chat message
 type CreateChatMessageRequest struct {
+	Message  string `json:"message" binding:"required,min=1,max=500"`
+	ParentID uint64 `json:"parent_id"` // Optional message to reply to
+}
+
+// UpdateChatMessageRequest is the request body for editing a chat message
+type UpdateChatMessageRequest struct {
 	Message string `json:"message" binding:"required,min=1,max=500"`
 }
+
+// MuteChatUserRequest is the request body for muting a player
+type MuteChatUserRequest struct {
+	DurationMinutes int    `json:"duration_minutes" binding:"required,min=1,max=10080"`
+	Reason          string `json:"reason" binding:"max=255"`
+}
+
+// UpdateSlowModeRequest is the request body for changing the chat slow mode
+type UpdateSlowModeRequest struct {
+	Seconds *int `json:"seconds" binding:"required,min=0,max=3600"`
+}
+
+// ChatReactionRequest is the request body for reacting to a chat message
+type ChatReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required,max=32"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// CustomGameAppIDOffset is added to the ID of a custom game to get its app ID
+// Steam app IDs stay far below it, so custom games share the app ID based tables
+// (owners, polls, sessions, ...) with Steam games.
+const CustomGameAppIDOffset = 2000000000
+
+// CustomGameAppID returns the app ID of the custom game with the given ID
+func CustomGameAppID(id int64) int {
+	return CustomGameAppIDOffset + int(id)
+}
+
+// IsCustomAppID reports whether an app ID belongs to a custom game
+func IsCustomAppID(appID int) bool {
+	return appID > CustomGameAppIDOffset
+}
+
+// CustomGame is a non-Steam game managed by admins
+// Players declare ownership themselves, as there is no library to read it from.
+type CustomGame struct {
+	ID         int64     `json:"id"`
+	AppID      int       `json:"app_id"`
+	Name       string    `json:"name"`
+	Categories []string  `json:"categories"`
+	MaxPlayers int       `json:"max_players"` // 0 if unknown
+	HasImage   bool      `json:"has_image"`   // True once a cover image was uploaded
+	CreatedBy  string    `json:"created_by"`  // Steam ID of the admin
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CustomGameRequest represents the request body for creating or updating a custom game
+type CustomGameRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	Categories []string `json:"categories"`
+	MaxPlayers int      `json:"max_players"`
+}
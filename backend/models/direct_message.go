@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// DirectMessage is a private message between two players
+type DirectMessage struct {
+	ID          uint64     `json:"id"`
+	SenderID    uint64     `json:"sender_id"`
+	RecipientID uint64     `json:"recipient_id"`
+	Message     string     `json:"message"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ReadAt      *time.Time `json:"read_at,omitempty"` // Set when the recipient opened the conversation
+}
+
+// Conversation summarizes the direct messages with one other player
+type Conversation struct {
+	Partner     PublicUser    `json:"partner"`
+	LastMessage DirectMessage `json:"last_message"`
+	UnreadCount int           `json:"unread_count"` // Messages from the partner not read yet
+}
+
+// ConversationList is the list of a player's conversations, most recent first
+type ConversationList struct {
+	Conversations []Conversation `json:"conversations"`
+	UnreadCount   int            `json:"unread_count"` // Unread messages over all conversations
+}
+
+// DirectMessageHistory is a page of one conversation, oldest first
+// Older messages are loaded by passing NextCursor as "before".
+type DirectMessageHistory struct {
+	Partner    PublicUser      `json:"partner"`
+	Messages   []DirectMessage `json:"messages"`
+	HasMore    bool            `json:"has_more"`
+	NextCursor uint64          `json:"next_cursor,omitempty"` // ID of the oldest message in this page
+}
+
+// SendDirectMessageRequest is the request body for sending a direct message
+type SendDirectMessageRequest struct {
+	Message string `json:"message" binding:"required,min=1,max=500"`
+}
@@ -0,0 +1,178 @@
+package models
+
+import "time"
+
+// ExportFormatVersion is the current version of the event export archive format
+// Bump this whenever the archive layout changes in a non-backwards-compatible way
+const ExportFormatVersion = 1
+
+// ExportArchive is a full snapshot of a LAN event that can be restored into any supported database
+type ExportArchive struct {
+	FormatVersion      int                       `json:"format_version"`
+	ExportedAt         time.Time                 `json:"exported_at"`
+	AppVersion         string                    `json:"app_version"`
+	SourceDB           string                    `json:"source_db"` // Database type the archive was exported from
+	Settings           ExportSettings            `json:"settings"`
+	Users              []User                    `json:"users"`
+	Votes              []Vote                    `json:"votes"`
+	ChatChannels       []ExportChatChannel       `json:"chat_channels"`
+	ChatChannelMembers []ExportChatChannelMember `json:"chat_channel_members"`
+	ChatMessages       []ChatMessage             `json:"chat_messages"`
+	ChatReactions      []ExportChatReaction      `json:"chat_reactions"`
+	ChatMutes          []ChatMute                `json:"chat_mutes"`
+	DirectMessages     []DirectMessage           `json:"direct_messages,omitempty"`
+	BannedUsers        []BannedUser              `json:"banned_users"`
+	GameOwners         []ExportGameOwner         `json:"game_owners"`
+	CustomGames        []CustomGame              `json:"custom_games"`
+	GameMetadata       []GameMetadata            `json:"game_metadata"`
+	Polls              []Poll                    `json:"polls"` // Including their options
+	PollBallots        []ExportPollBallot        `json:"poll_ballots"`
+	GameSessions       []ExportGameSession       `json:"game_sessions"`
+	GameInstallStatus  []GameInstallStatus       `json:"game_install_status"`
+	Tournaments        []ExportTournament        `json:"tournaments"`  // Including registrations, teams and matches
+	GameMatches        []GameMatch               `json:"game_matches"` // Including the players and their rating changes
+	PlayerRatings      []PlayerRating            `json:"player_ratings"`
+}
+
+// ExportSettings contains the runtime settings that admins can change via the settings endpoint
+type ExportSettings struct {
+	CreditIntervalMinutes  int        `json:"credit_interval_minutes"`
+	CreditMax              int        `json:"credit_max"`
+	VotingPaused           bool       `json:"voting_paused"`
+	VoteVisibilityMode     string     `json:"vote_visibility_mode"`
+	MinVotesForRanking     int        `json:"min_votes_for_ranking"`
+	NegativeVotingDisabled bool       `json:"negative_voting_disabled"`
+	CountdownTarget        *time.Time `json:"countdown_target,omitempty"`
+}
+
+// ExportGameOwner represents a game ownership entry in an export archive
+type ExportGameOwner struct {
+	AppID           int       `json:"app_id"`
+	SteamID         string    `json:"steam_id"`
+	PlaytimeForever int       `json:"playtime_forever"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ExportChatChannel represents a chat channel in an export archive, identified by its slug
+type ExportChatChannel struct {
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	AppID     *int      `json:"app_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportChatChannelMember represents a player's membership in a game channel in an export archive
+type ExportChatChannelMember struct {
+	Channel  string    `json:"channel"` // Slug of the channel
+	UserID   uint64    `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// ExportChatReaction represents a single player's reaction to a chat message in an export archive
+type ExportChatReaction struct {
+	MessageID uint64    `json:"message_id"`
+	UserID    uint64    `json:"user_id"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportPollBallot represents a single chosen game of a player's poll ballot in an export archive
+type ExportPollBallot struct {
+	PollID     uint64    `json:"poll_id"`
+	UserID     uint64    `json:"user_id"`
+	AppID      int       `json:"app_id"`
+	Preference int       `json:"preference"` // 1 is the first choice
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ExportGameSession represents a planned game session with its players in an export archive
+type ExportGameSession struct {
+	ID        uint64     `json:"id"`
+	AppID     int        `json:"app_id"`
+	Title     string     `json:"title"`
+	CreatedBy uint64     `json:"created_by"` // User ID
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	PlayerIDs []uint64   `json:"player_ids"`
+}
+
+// ExportTournament represents a tournament with its registrations, teams and bracket in an export archive
+// Team and match IDs are only meaningful within the archive and are remapped on import.
+type ExportTournament struct {
+	ID            uint64                         `json:"id"`
+	Name          string                         `json:"name"`
+	AppID         int                            `json:"app_id"`
+	Format        TournamentFormat               `json:"format"`
+	TeamSize      int                            `json:"team_size"`
+	Status        TournamentStatus               `json:"status"`
+	BonusPoints   int                            `json:"bonus_points"`
+	CreatedBy     uint64                         `json:"created_by"` // User ID
+	WinnerTeamID  *uint64                        `json:"winner_team_id,omitempty"`
+	CreatedAt     time.Time                      `json:"created_at"`
+	StartedAt     *time.Time                     `json:"started_at,omitempty"`
+	FinishedAt    *time.Time                     `json:"finished_at,omitempty"`
+	Registrations []ExportTournamentRegistration `json:"registrations"`
+	Teams         []ExportTournamentTeam         `json:"teams"`
+	Matches       []TournamentMatch              `json:"matches"`
+}
+
+// ExportTournamentRegistration represents a player's sign-up for a tournament in an export archive
+type ExportTournamentRegistration struct {
+	UserID    uint64    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportTournamentTeam represents a tournament team with the user IDs of its members in an export archive
+type ExportTournamentTeam struct {
+	ID        uint64   `json:"id"`
+	Name      string   `json:"name"`
+	Seed      int      `json:"seed"`
+	MemberIDs []uint64 `json:"member_ids"`
+}
+
+// ImportMode defines how an archive is applied to the database
+type ImportMode string
+
+const (
+	// ImportModeMerge keeps existing data and adds everything from the archive that is not present yet
+	ImportModeMerge ImportMode = "merge"
+	// ImportModeReplace wipes all event data before restoring the archive
+	ImportModeReplace ImportMode = "replace"
+)
+
+// ImportResult summarizes what an import changed
+type ImportResult struct {
+	Mode                       ImportMode `json:"mode"`
+	UsersCreated               int        `json:"users_created"`
+	UsersMatched               int        `json:"users_matched"` // Existing users matched by Steam ID (merge mode)
+	VotesImported              int        `json:"votes_imported"`
+	VotesSkipped               int        `json:"votes_skipped"` // Duplicates already present (merge mode)
+	ChatChannelsImported       int        `json:"chat_channels_imported"`
+	ChatChannelMembersImported int        `json:"chat_channel_members_imported"`
+	ChatMessagesImported       int        `json:"chat_messages_imported"`
+	ChatMessagesSkipped        int        `json:"chat_messages_skipped"`
+	ChatReactionsImported      int        `json:"chat_reactions_imported"`
+	ChatMutesImported          int        `json:"chat_mutes_imported"`
+	DirectMessagesImported     int        `json:"direct_messages_imported"`
+	DirectMessagesSkipped      int        `json:"direct_messages_skipped"`
+	BansImported               int        `json:"bans_imported"`
+	GameOwnersImported         int        `json:"game_owners_imported"`
+	CustomGamesCreated         int        `json:"custom_games_created"`
+	CustomGamesMatched         int        `json:"custom_games_matched"` // Existing custom games matched by name
+	GameMetadataImported       int        `json:"game_metadata_imported"`
+	PollsImported              int        `json:"polls_imported"`
+	PollsSkipped               int        `json:"polls_skipped"` // Duplicates already present (merge mode)
+	PollBallotsImported        int        `json:"poll_ballots_imported"`
+	GameSessionsImported       int        `json:"game_sessions_imported"`
+	GameSessionsSkipped        int        `json:"game_sessions_skipped"` // Duplicates already present (merge mode)
+	InstallStatusesImported    int        `json:"install_statuses_imported"`
+	TournamentsImported        int        `json:"tournaments_imported"`
+	TournamentsSkipped         int        `json:"tournaments_skipped"` // Duplicates already present (merge mode)
+	GameMatchesImported        int        `json:"game_matches_imported"`
+	GameMatchesSkipped         int        `json:"game_matches_skipped"` // Duplicates already present (merge mode)
+	PlayerRatingsImported      int        `json:"player_ratings_imported"`
+	SettingsApplied            bool       `json:"settings_applied"`
+}
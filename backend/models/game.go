@@ -11,6 +11,7 @@ type Game struct {
 	OwnerCount      int      `json:"owner_count"`       // Number of players who own this game
 	Owners          []string `json:"owners"`            // Steam IDs of owners
 	IsPinned        bool     `json:"is_pinned"`         // Whether this game is pinned/featured
+	IsCustom        bool     `json:"is_custom,omitempty"` // Non-Steam game managed by admins, owners are self-declared
 	// Price information
 	IsFree          bool   `json:"is_free"`           // True if free-to-play
 	PriceCents      int    `json:"price_cents"`       // Current price in cents (e.g., 5999 = 59.99€)
@@ -20,7 +21,13 @@ type Game struct {
 	// Review information
 	ReviewScore int `json:"review_score"` // Percentage of positive reviews (0-100), -1 if not enough reviews
 	// Custom metadata (manually curated)
-	MaxPlayers int `json:"max_players,omitempty"` // Maximum number of players, 0 if unknown
+	MaxPlayers              int      `json:"max_players,omitempty"`               // Maximum number of players, 0 if unknown
+	MinPlayers              int      `json:"min_players,omitempty"`               // Minimum number of players, 0 if unknown
+	LANCapable              bool     `json:"lan_capable,omitempty"`               // Playable in the local network without internet
+	DedicatedServerRequired bool     `json:"dedicated_server_required,omitempty"` // Somebody has to host a dedicated server
+	InstallSizeMB           int      `json:"install_size_mb,omitempty"`           // Install size in MB, 0 if unknown
+	Tags                    []string `json:"tags,omitempty"`
+	Notes                   string   `json:"notes,omitempty"`
 }
 
 // GameOwnership represents a player's ownership of a game
@@ -66,3 +73,26 @@ func (g *Game) HasMultiplayerCategory() bool {
 	}
 	return false
 }
+
+// GameSuggestion is a game ranked for a specific group of players
+type GameSuggestion struct {
+	Game
+	PlayerCount      int      `json:"player_count"`       // Number of players in the group
+	PresentOwners    []string `json:"present_owners"`     // Steam IDs of group members who own the game
+	MissingOwners    []string `json:"missing_owners"`     // Steam IDs of group members who would need to buy it
+	Coverage         float64  `json:"coverage"`           // Share of the group that can play right away (0-1), 1 for free games
+	FitsGroup        bool     `json:"fits_group"`         // False if MaxPlayers is known and smaller than the group
+	MissingCostCents int      `json:"missing_cost_cents"` // Total price for all missing owners, -1 if the price is unknown
+}
+
+// GameSuggestRequest represents the request body for POST /games/suggest
+type GameSuggestRequest struct {
+	UserIDs []uint64 `json:"user_ids"` // Empty = all currently connected players
+	Limit   int      `json:"limit"`    // Maximum number of suggestions, 0 = default
+}
+
+// GameSuggestResponse represents the API response for game suggestions
+type GameSuggestResponse struct {
+	Players     []PublicUser     `json:"players"`
+	Suggestions []GameSuggestion `json:"suggestions"`
+}
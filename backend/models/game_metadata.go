@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Sources of game metadata
+const (
+	// GameMetadataSourceSeed marks entries imported from game_metadata.json, updated when the file changes
+	GameMetadataSourceSeed = "seed"
+	// GameMetadataSourceAdmin marks entries edited via the admin API, never overwritten by the file
+	GameMetadataSourceAdmin = "admin"
+)
+
+// GameMetadata contains manually curated metadata for a game
+type GameMetadata struct {
+	AppID                   int       `json:"app_id"`
+	MaxPlayers              int       `json:"max_players"`               // 0 if unknown
+	MinPlayers              int       `json:"min_players"`               // 0 if unknown
+	LANCapable              bool      `json:"lan_capable"`               // Playable in the local network without internet
+	DedicatedServerRequired bool      `json:"dedicated_server_required"` // Somebody has to host a dedicated server
+	InstallSizeMB           int       `json:"install_size_mb"`           // 0 if unknown
+	Tags                    []string  `json:"tags"`
+	Notes                   string    `json:"notes"`
+	Source                  string    `json:"source"`
+	UpdatedBy               string    `json:"updated_by"` // Steam ID of the admin, empty for seeded entries
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+// GameMetadataRequest represents the editable metadata of a game
+// It is also the format of the entries in game_metadata.json.
+type GameMetadataRequest struct {
+	MaxPlayers              int      `json:"max_players"`
+	MinPlayers              int      `json:"min_players"`
+	LANCapable              bool     `json:"lan_capable"`
+	DedicatedServerRequired bool     `json:"dedicated_server_required"`
+	InstallSizeMB           int      `json:"install_size_mb"`
+	Tags                    []string `json:"tags"`
+	Notes                   string   `json:"notes"`
+}
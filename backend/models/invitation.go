@@ -0,0 +1,21 @@
+package models
+
+// InviteFriendsRequest is the request body for importing a Steam friend list
+// SteamID defaults to the admin's own Steam ID.
+type InviteFriendsRequest struct {
+	SteamID string `json:"steam_id"`
+}
+
+// InviteGroupRequest is the request body for importing the members of a Steam group
+// Group is the group's URL name (steamcommunity.com/groups/<name>) or its 64-bit ID.
+type InviteGroupRequest struct {
+	Group string `json:"group" binding:"required"`
+}
+
+// InviteResult summarizes an import of Steam players as invited users
+type InviteResult struct {
+	Invited           []PublicUser `json:"invited"`
+	AlreadyRegistered int          `json:"already_registered"` // Players that already have an account or invitation
+	Banned            int          `json:"banned"`             // Banned players, never invited
+	NotFound          int          `json:"not_found"`          // Steam IDs without a Steam profile
+}
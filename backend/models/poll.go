@@ -0,0 +1,105 @@
+package models
+
+import "time"
+
+// PollVotingMethod defines how ballots of a poll are counted
+type PollVotingMethod string
+
+const (
+	// PollVotingSingle lets every player pick exactly one game
+	PollVotingSingle PollVotingMethod = "single"
+	// PollVotingApproval lets every player pick any number of games they would play
+	PollVotingApproval PollVotingMethod = "approval"
+	// PollVotingRanked lets every player rank games; counted as instant runoff
+	PollVotingRanked PollVotingMethod = "ranked"
+)
+
+// IsValid returns true if the voting method is known
+func (m PollVotingMethod) IsValid() bool {
+	switch m {
+	case PollVotingSingle, PollVotingApproval, PollVotingRanked:
+		return true
+	}
+	return false
+}
+
+// Poll represents a vote on which game to play next
+type Poll struct {
+	ID           uint64           `json:"id"`
+	Title        string           `json:"title"`
+	VotingMethod PollVotingMethod `json:"voting_method"`
+	CreatedBy    PublicUser       `json:"created_by"`
+	ClosesAt     *time.Time       `json:"closes_at"`     // nil = closed manually
+	ClosedAt     *time.Time       `json:"closed_at"`     // nil = still open
+	WinnerAppID  *int             `json:"winner_app_id"` // Set when the poll is closed with at least one ballot
+	CreatedAt    time.Time        `json:"created_at"`
+	Options      []PollOption     `json:"options"`
+}
+
+// IsOpen returns true if the poll still accepts ballots
+func (p *Poll) IsOpen() bool {
+	return p.ClosedAt == nil
+}
+
+// PollOption is a candidate game of a poll
+type PollOption struct {
+	AppID    int    `json:"app_id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// PollBallot is the ballot of a single player
+type PollBallot struct {
+	UserID uint64 `json:"user_id"`
+	AppIDs []int  `json:"app_ids"` // In order of preference (only relevant for ranked polls)
+}
+
+// PollOptionResult is the number of votes of a candidate game
+type PollOptionResult struct {
+	AppID int    `json:"app_id"`
+	Name  string `json:"name"`
+	Votes int    `json:"votes"`
+}
+
+// PollRound is one counting round of a ranked-choice poll
+type PollRound struct {
+	Round      int                `json:"round"`
+	Results    []PollOptionResult `json:"results"`
+	Eliminated []int              `json:"eliminated"` // App IDs dropped after this round
+}
+
+// PollResults contains the current tally of a poll
+type PollResults struct {
+	TotalBallots int                `json:"total_ballots"`
+	Results      []PollOptionResult `json:"results"`          // Final round for ranked polls
+	Rounds       []PollRound        `json:"rounds,omitempty"` // Only for ranked polls
+	WinnerAppID  *int               `json:"winner_app_id"`    // Current leader, nil without ballots
+}
+
+// PollWithResults is a poll including the live tally and the caller's own ballot
+type PollWithResults struct {
+	Poll
+	Results  PollResults `json:"results"`
+	MyBallot []int       `json:"my_ballot,omitempty"` // App IDs of the caller's ballot, omitted in broadcasts
+}
+
+// PollWinner announces the winning game of a closed poll
+type PollWinner struct {
+	PollID       uint64       `json:"poll_id"`
+	AppID        int          `json:"app_id"`
+	Name         string       `json:"name"`
+	NeedsInstall []PublicUser `json:"needs_install"` // Voters and online players who don't own the game
+}
+
+// CreatePollRequest is the request body for creating a poll
+type CreatePollRequest struct {
+	Title           string           `json:"title" binding:"required,min=1,max=100"`
+	VotingMethod    PollVotingMethod `json:"voting_method"` // Defaults to single
+	AppIDs          []int            `json:"app_ids" binding:"required,min=2,max=20"`
+	DurationMinutes int              `json:"duration_minutes" binding:"min=0,max=1440"` // 0 = close manually
+}
+
+// PollVoteRequest is the request body for voting in a poll
+type PollVoteRequest struct {
+	AppIDs []int `json:"app_ids" binding:"required,min=1"` // In order of preference for ranked polls
+}
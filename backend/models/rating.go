@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// GameMatch is a recorded match outcome of a game
+type GameMatch struct {
+	ID         uint64        `json:"id"`
+	AppID      int           `json:"app_id"`
+	GameName   string        `json:"game_name"`
+	WinnerTeam *int          `json:"winner_team"` // Team number starting at 1, nil = draw
+	RecordedBy uint64        `json:"recorded_by"`
+	PlayedAt   time.Time     `json:"played_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Players    []MatchPlayer `json:"players"`
+}
+
+// MatchPlayer is a player of a match with the rating change the match caused
+type MatchPlayer struct {
+	User         PublicUser `json:"user"`
+	Team         int        `json:"team"`
+	RatingBefore float64    `json:"rating_before"`
+	RatingAfter  float64    `json:"rating_after"`
+}
+
+// PlayerRating is the current rating of a player in a game
+type PlayerRating struct {
+	User      PublicUser `json:"user"`
+	AppID     int        `json:"app_id"`
+	Rating    float64    `json:"rating"`
+	Matches   int        `json:"matches"`
+	Wins      int        `json:"wins"`
+	Losses    int        `json:"losses"`
+	Draws     int        `json:"draws"`
+	Rank      int        `json:"rank,omitempty"` // Only set on ladders
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// RatingHistoryEntry is the rating of a player after one match
+type RatingHistoryEntry struct {
+	MatchID      uint64    `json:"match_id"`
+	PlayedAt     time.Time `json:"played_at"`
+	Team         int       `json:"team"`
+	WinnerTeam   *int      `json:"winner_team"`
+	RatingBefore float64   `json:"rating_before"`
+	RatingAfter  float64   `json:"rating_after"`
+}
+
+// RecordMatchRequest is the request body for recording a match outcome
+type RecordMatchRequest struct {
+	AppID      int        `json:"app_id" binding:"required"`
+	Teams      [][]uint64 `json:"teams" binding:"required,min=2"` // User IDs per team
+	WinnerTeam *int       `json:"winner_team"`                    // Team number starting at 1, omit for a draw
+	PlayedAt   *time.Time `json:"played_at"`                      // Defaults to now
+}
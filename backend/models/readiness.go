@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// InstallStatus describes how far a player is from being able to play a game
+type InstallStatus string
+
+const (
+	// InstallStatusNone means the game is not installed (no row is stored)
+	InstallStatusNone InstallStatus = "none"
+	// InstallStatusDownloading means the game is being downloaded or updated
+	InstallStatusDownloading InstallStatus = "downloading"
+	// InstallStatusInstalled means the game is installed but not confirmed up to date
+	InstallStatusInstalled InstallStatus = "installed"
+	// InstallStatusReady means the game is installed, updated and the player is ready to play
+	InstallStatusReady InstallStatus = "ready"
+)
+
+// IsValid returns true if the install status is known
+func (s InstallStatus) IsValid() bool {
+	switch s {
+	case InstallStatusNone, InstallStatusDownloading, InstallStatusInstalled, InstallStatusReady:
+		return true
+	}
+	return false
+}
+
+// GameInstallStatus is the install status of a game for one player
+type GameInstallStatus struct {
+	UserID    uint64        `json:"user_id"`
+	AppID     int           `json:"app_id"`
+	Status    InstallStatus `json:"status"`
+	Progress  int           `json:"progress"` // Download progress in percent (0-100)
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// ReadinessEntry is one player in a readiness roster
+type ReadinessEntry struct {
+	User      PublicUser    `json:"user"`
+	Owns      bool          `json:"owns"`
+	Online    bool          `json:"online"`
+	Status    InstallStatus `json:"status"`
+	Progress  int           `json:"progress"`
+	UpdatedAt *time.Time    `json:"updated_at"` // nil if the player never reported a status
+}
+
+// ReadinessSummary counts the players of a roster per install status
+type ReadinessSummary struct {
+	Ready        int `json:"ready"`
+	Installed    int `json:"installed"`
+	Downloading  int `json:"downloading"`
+	NotInstalled int `json:"not_installed"`
+}
+
+// GameRoster lists everyone who owns a game or reported an install status for it
+type GameRoster struct {
+	AppID   int              `json:"app_id"`
+	Name    string           `json:"name"`
+	Players []ReadinessEntry `json:"players"`
+	Summary ReadinessSummary `json:"summary"`
+}
+
+// GameSession is a planned round of a game with a fixed group of players
+type GameSession struct {
+	ID        uint64           `json:"id"`
+	AppID     int              `json:"app_id"`
+	Name      string           `json:"name"` // Game name from the games cache
+	Title     string           `json:"title"`
+	CreatedBy PublicUser       `json:"created_by"`
+	StartsAt  *time.Time       `json:"starts_at"`
+	ReadyAt   *time.Time       `json:"ready_at"` // Set while all players are ready
+	CreatedAt time.Time        `json:"created_at"`
+	Players   []ReadinessEntry `json:"players"`
+	Summary   ReadinessSummary `json:"summary"`
+}
+
+// SetInstallStatusRequest is the request body for updating the own install status of a game
+type SetInstallStatusRequest struct {
+	Status   InstallStatus `json:"status" binding:"required"`
+	Progress int           `json:"progress" binding:"min=0,max=100"` // Only used while downloading
+}
+
+// CreateGameSessionRequest is the request body for planning a game session
+type CreateGameSessionRequest struct {
+	AppID    int        `json:"app_id" binding:"required"`
+	Title    string     `json:"title" binding:"max=100"`
+	UserIDs  []uint64   `json:"user_ids"` // Empty = all currently connected players
+	StartsAt *time.Time `json:"starts_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// SyncJobStatus is the state of a background sync job
+type SyncJobStatus string
+
+const (
+	// SyncJobIdle means the job finished or never ran
+	SyncJobIdle SyncJobStatus = "idle"
+	// SyncJobRunning means the job is working through its items
+	SyncJobRunning SyncJobStatus = "running"
+	// SyncJobPaused means the job waits for a rate limit to pass
+	SyncJobPaused SyncJobStatus = "paused"
+)
+
+// SyncJob is the persisted state of a background sync job
+// A job that is not idle at startup was interrupted and is resumed.
+type SyncJob struct {
+	Name        string        `json:"name"`
+	Status      SyncJobStatus `json:"status"`
+	Processed   int           `json:"processed"`
+	Total       int           `json:"total"`
+	Failures    int           `json:"failures"` // Rate limits in a row, drives the backoff
+	PausedUntil *time.Time    `json:"paused_until"`
+	LastError   string        `json:"last_error"`
+	StartedAt   *time.Time    `json:"started_at"`
+	FinishedAt  *time.Time    `json:"finished_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// TeamGenerateRequest is the request body for generating balanced teams
+type TeamGenerateRequest struct {
+	UserIDs      []uint64   `json:"user_ids" binding:"required,min=2"`
+	TeamCount    int        `json:"team_count" binding:"required,min=2,max=16"`
+	AppID        int        `json:"app_id"`                            // 0 = balance by ranking score only
+	KeepTogether [][]uint64 `json:"keep_together"`                     // Each group ends up in the same team
+	KeepApart    [][]uint64 `json:"keep_apart"`                        // Players of each group end up in different teams
+	Candidates   int        `json:"candidates" binding:"min=0,max=10"` // Number of splits to return, 0 = 3
+}
+
+// TeamPlayer is a participant with the values used for balancing
+type TeamPlayer struct {
+	User            PublicUser `json:"user"`
+	PlaytimeMinutes int        `json:"playtime_minutes"` // playtime_forever of the target game
+	RankingScore    int        `json:"ranking_score"`    // Total score from the global ranking
+	Skill           float64    `json:"skill"`            // 0-100, relative to the other participants
+}
+
+// GeneratedTeam is one team of a split
+type GeneratedTeam struct {
+	Players []TeamPlayer `json:"players"`
+	Skill   float64      `json:"skill"` // Sum of the players' skill
+}
+
+// TeamSplit is a candidate split of all participants into teams
+type TeamSplit struct {
+	Teams        []GeneratedTeam `json:"teams"`
+	SkillSpread  float64         `json:"skill_spread"`  // Difference between the strongest and the weakest team
+	BalanceScore float64         `json:"balance_score"` // 100 = perfectly even
+}
+
+// TeamGenerateResponse contains the candidate splits, best first
+type TeamGenerateResponse struct {
+	AppID    int         `json:"app_id"`
+	GameName string      `json:"game_name"`
+	Splits   []TeamSplit `json:"splits"`
+}
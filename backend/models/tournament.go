@@ -0,0 +1,151 @@
+package models
+
+import "time"
+
+// TournamentFormat defines how the matches of a tournament are paired
+type TournamentFormat string
+
+const (
+	// TournamentSingleElimination knocks a team out after its first loss
+	TournamentSingleElimination TournamentFormat = "single_elimination"
+	// TournamentDoubleElimination knocks a team out after its second loss
+	TournamentDoubleElimination TournamentFormat = "double_elimination"
+	// TournamentRoundRobin lets every team play every other team once
+	TournamentRoundRobin TournamentFormat = "round_robin"
+)
+
+// IsValid returns true if the tournament format is known
+func (f TournamentFormat) IsValid() bool {
+	switch f {
+	case TournamentSingleElimination, TournamentDoubleElimination, TournamentRoundRobin:
+		return true
+	}
+	return false
+}
+
+// TournamentStatus is the lifecycle state of a tournament
+type TournamentStatus string
+
+const (
+	// TournamentStatusRegistration means players can still sign up
+	TournamentStatusRegistration TournamentStatus = "registration"
+	// TournamentStatusRunning means teams are formed and matches are played
+	TournamentStatusRunning TournamentStatus = "running"
+	// TournamentStatusFinished means all matches are confirmed and the winner is known
+	TournamentStatusFinished TournamentStatus = "finished"
+)
+
+// IsValid returns true if the tournament status is known
+func (s TournamentStatus) IsValid() bool {
+	switch s {
+	case TournamentStatusRegistration, TournamentStatusRunning, TournamentStatusFinished:
+		return true
+	}
+	return false
+}
+
+// MatchStatus is the state of a single tournament match
+type MatchStatus string
+
+const (
+	// MatchStatusPending means the match is waiting for teams or a result
+	MatchStatusPending MatchStatus = "pending"
+	// MatchStatusReported means a result was reported and waits for admin confirmation
+	MatchStatusReported MatchStatus = "reported"
+	// MatchStatusConfirmed means the result is final
+	MatchStatusConfirmed MatchStatus = "confirmed"
+)
+
+// IsValid returns true if the match status is known
+func (s MatchStatus) IsValid() bool {
+	switch s {
+	case MatchStatusPending, MatchStatusReported, MatchStatusConfirmed:
+		return true
+	}
+	return false
+}
+
+// Bracket names of tournament matches
+const (
+	BracketWinners    = "winners"
+	BracketLosers     = "losers"
+	BracketGrandFinal = "grand_final"
+	BracketRoundRobin = "round_robin"
+)
+
+// Tournament represents a tournament of a game from the games cache
+type Tournament struct {
+	ID           uint64               `json:"id"`
+	Name         string               `json:"name"`
+	AppID        int                  `json:"app_id"`
+	GameName     string               `json:"game_name"`
+	Format       TournamentFormat     `json:"format"`
+	TeamSize     int                  `json:"team_size"`
+	Status       TournamentStatus     `json:"status"`
+	BonusPoints  int                  `json:"bonus_points"` // Ranking points for each member of the winning team, 0 = none
+	CreatedBy    uint64               `json:"created_by"`
+	WinnerTeamID *uint64              `json:"winner_team_id"`
+	CreatedAt    time.Time            `json:"created_at"`
+	StartedAt    *time.Time           `json:"started_at"`
+	FinishedAt   *time.Time           `json:"finished_at"`
+	Players      []PublicUser         `json:"players"` // Registered players
+	Teams        []TournamentTeam     `json:"teams"`
+	Matches      []TournamentMatch    `json:"matches"`
+	Standings    []TournamentStanding `json:"standings,omitempty"` // Only for round robin
+}
+
+// TournamentTeam is a team formed from registered players
+type TournamentTeam struct {
+	ID      uint64       `json:"id"`
+	Name    string       `json:"name"`
+	Seed    int          `json:"seed"`
+	Members []PublicUser `json:"members"`
+}
+
+// TournamentMatch is a match between two teams
+// Team slots are nil until the feeding match is decided; a bye has only one team.
+type TournamentMatch struct {
+	ID                uint64      `json:"id"`
+	Bracket           string      `json:"bracket"`
+	Round             int         `json:"round"`
+	Position          int         `json:"position"`
+	Team1ID           *uint64     `json:"team1_id"`
+	Team2ID           *uint64     `json:"team2_id"`
+	Score1            *int        `json:"score1"`
+	Score2            *int        `json:"score2"`
+	WinnerTeamID      *uint64     `json:"winner_team_id"`
+	Status            MatchStatus `json:"status"`
+	IsBye             bool        `json:"is_bye"`
+	ReportedBy        *uint64     `json:"reported_by"`
+	WinnerNextMatchID *uint64     `json:"winner_next_match_id"`
+	WinnerNextSlot    int         `json:"winner_next_slot"`
+	LoserNextMatchID  *uint64     `json:"loser_next_match_id"`
+	LoserNextSlot     int         `json:"loser_next_slot"`
+}
+
+// TournamentStanding is a row of the round robin table
+type TournamentStanding struct {
+	TeamID       uint64 `json:"team_id"`
+	Played       int    `json:"played"`
+	Wins         int    `json:"wins"`
+	Draws        int    `json:"draws"`
+	Losses       int    `json:"losses"`
+	Points       int    `json:"points"` // 3 per win, 1 per draw
+	ScoreFor     int    `json:"score_for"`
+	ScoreAgainst int    `json:"score_against"`
+}
+
+// CreateTournamentRequest is the request body for creating a tournament
+type CreateTournamentRequest struct {
+	Name        string           `json:"name" binding:"required,min=1,max=100"`
+	AppID       int              `json:"app_id" binding:"required"`
+	Format      TournamentFormat `json:"format" binding:"required"`
+	TeamSize    int              `json:"team_size" binding:"min=0,max=10"`    // 0 = 1 player per team
+	BonusPoints int              `json:"bonus_points" binding:"min=0,max=50"` // 0 = does not affect the ranking
+}
+
+// ReportMatchRequest is the request body for reporting a match result
+type ReportMatchRequest struct {
+	Score1 int `json:"score1" binding:"min=0"`
+	Score2 int `json:"score2" binding:"min=0"`
+}
@@ -13,6 +13,7 @@ type User struct {
 	Credits            int        `json:"credits"`
 	LastCreditAt       time.Time  `json:"last_credit_at"`
 	LastGamesRefreshAt *time.Time `json:"last_games_refresh_at"`
+	InvitedAt          *time.Time `json:"invited_at"` // Set while the user is an imported placeholder that has not logged in yet
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
@@ -25,6 +26,7 @@ type PublicUser struct {
 	AvatarURL   string `json:"avatar_url"`
 	AvatarSmall string `json:"avatar_small"`
 	ProfileURL  string `json:"profile_url"`
+	Invited     bool   `json:"invited,omitempty"`
 }
 
 // ToPublic converts a User to PublicUser
@@ -36,6 +38,7 @@ func (u *User) ToPublic() PublicUser {
 		AvatarURL:   u.AvatarURL,
 		AvatarSmall: u.AvatarSmall,
 		ProfileURL:  u.ProfileURL,
+		Invited:     u.InvitedAt != nil,
 	}
 }
 
@@ -54,6 +57,7 @@ type AdminUserInfo struct {
 	ID          uint64    `json:"id"`
 	SteamID     string    `json:"steam_id"`
 	Username    string    `json:"username"`
-	AvatarSmall string    `json:"avatar_small"`
-	CreatedAt   time.Time `json:"created_at"`
+	AvatarSmall string     `json:"avatar_small"`
+	InvitedAt   *time.Time `json:"invited_at"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
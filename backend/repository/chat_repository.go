@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/guided-traffic/rate-your-mate/backend/database"
 	"github.com/guided-traffic/rate-your-mate/backend/models"
@@ -17,100 +20,661 @@ func NewChatRepository() *ChatRepository {
 }
 
 // Create creates a new chat message with the user's current achievements (with retry for SQLITE_BUSY)
+// Messages without a channel go to the general channel.
 func (r *ChatRepository) Create(msg *models.ChatMessage) error {
+	if msg.ChannelID == 0 {
+		msg.ChannelID = models.GeneralChatChannelID
+	}
 	return database.WithRetry(func() error {
-		result, err := database.DB.Exec(`
-			INSERT INTO chat_messages (user_id, message, achievements)
-			VALUES (?, ?, ?)`,
-			msg.UserID, msg.Message, msg.Achievements,
+		id, err := database.InsertReturningID(database.DB, `
+			INSERT INTO chat_messages (channel_id, user_id, message, achievements, parent_id)
+			VALUES (?, ?, ?, ?, ?)`,
+			msg.ChannelID, msg.UserID, msg.Message, msg.Achievements, msg.ParentID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create chat message: %w", err)
 		}
 
-		id, err := result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("failed to get last insert id: %w", err)
-		}
-
 		msg.ID = uint64(id)
 		return nil
 	})
 }
 
-// GetRecent returns the most recent chat messages
-func (r *ChatRepository) GetRecent(limit int) ([]models.ChatMessageWithUser, error) {
+// GetAll returns all raw chat messages in insertion order (used for exports)
+func (r *ChatRepository) GetAll() ([]models.ChatMessage, error) {
 	rows, err := database.DB.Query(`
-		SELECT
-			cm.id, cm.message, cm.achievements, cm.created_at,
-			u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url
+		SELECT cm.id, cm.channel_id, COALESCE(ch.slug, ''), cm.user_id, cm.message, cm.achievements, cm.created_at, cm.edited_at, cm.parent_id
 		FROM chat_messages cm
-		JOIN users u ON cm.user_id = u.id
-		ORDER BY cm.created_at DESC
-		LIMIT ?`, limit)
+		LEFT JOIN chat_channels ch ON cm.channel_id = ch.id
+		ORDER BY cm.id`)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent chat messages: %w", err)
+		return nil, fmt.Errorf("failed to get all chat messages: %w", err)
 	}
 	defer rows.Close()
 
-	var messages []models.ChatMessageWithUser
+	var messages []models.ChatMessage
 	for rows.Next() {
-		var m models.ChatMessageWithUser
-		var achievementsJSON string
-		err := rows.Scan(
-			&m.ID, &m.Message, &achievementsJSON, &m.CreatedAt,
-			&m.User.ID, &m.User.SteamID, &m.User.Username, &m.User.AvatarURL, &m.User.AvatarSmall, &m.User.ProfileURL,
-		)
-		if err != nil {
+		var msg models.ChatMessage
+		if err := rows.Scan(&msg.ID, &msg.ChannelID, &msg.Channel, &msg.UserID, &msg.Message, &msg.Achievements, &msg.CreatedAt, &msg.EditedAt, &msg.ParentID); err != nil {
 			return nil, fmt.Errorf("failed to scan chat message row: %w", err)
 		}
+		messages = append(messages, msg)
+	}
 
-		// Parse achievements JSON
-		if achievementsJSON != "" && achievementsJSON != "[]" {
-			if err := json.Unmarshal([]byte(achievementsJSON), &m.Achievements); err != nil {
-				// If parsing fails, just leave it empty
-				m.Achievements = []models.AchievementBadge{}
-			}
-		} else {
+	return messages, nil
+}
+
+// chatMessageColumns are the columns selected by scanChatMessage from chatMessageTables
+const chatMessageColumns = `
+	cm.id, cm.channel_id, cm.message, cm.achievements, cm.created_at, cm.edited_at, cm.parent_id,
+	u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url,
+	p.message, pu.username`
+
+// chatMessageTables joins chat messages with their author and the message they reply to
+const chatMessageTables = `
+	FROM chat_messages cm
+	JOIN users u ON cm.user_id = u.id
+	LEFT JOIN chat_messages p ON cm.parent_id = p.id
+	LEFT JOIN users pu ON p.user_id = pu.id`
+
+// scanChatMessage scans a chat message row selected with chatMessageColumns
+// Reactions are loaded separately by attachReactions.
+func scanChatMessage(row rowScanner) (*models.ChatMessageWithUser, error) {
+	var m models.ChatMessageWithUser
+	var achievementsJSON string
+	var parentMessage, parentUsername sql.NullString
+	err := row.Scan(
+		&m.ID, &m.ChannelID, &m.Message, &achievementsJSON, &m.CreatedAt, &m.EditedAt, &m.ParentID,
+		&m.User.ID, &m.User.SteamID, &m.User.Username, &m.User.AvatarURL, &m.User.AvatarSmall, &m.User.ProfileURL,
+		&parentMessage, &parentUsername,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if m.ParentID != nil && parentMessage.Valid {
+		m.Parent = &models.ChatReplyPreview{ID: *m.ParentID, Username: parentUsername.String, Message: parentMessage.String}
+	}
+	m.Reactions = []models.ChatReaction{}
+
+	// Parse achievements JSON, if parsing fails just leave it empty
+	m.Achievements = []models.AchievementBadge{}
+	if achievementsJSON != "" && achievementsJSON != "[]" {
+		if err := json.Unmarshal([]byte(achievementsJSON), &m.Achievements); err != nil {
 			m.Achievements = []models.AchievementBadge{}
 		}
+	}
+	return &m, nil
+}
+
+// GetRecent returns the most recent messages of a channel, newest first
+func (r *ChatRepository) GetRecent(channelID uint64, limit int) ([]models.ChatMessageWithUser, error) {
+	return r.GetBefore(channelID, 0, limit)
+}
+
+// GetBefore returns the messages of a channel older than the given message ID, newest first
+// beforeID 0 returns the most recent messages. IDs grow with every message, so they serve as cursor.
+func (r *ChatRepository) GetBefore(channelID, beforeID uint64, limit int) ([]models.ChatMessageWithUser, error) {
+	query := `SELECT` + chatMessageColumns + chatMessageTables + `
+		WHERE cm.channel_id = ?`
+	args := []interface{}{channelID}
+	if beforeID > 0 {
+		query += ` AND cm.id < ?`
+		args = append(args, beforeID)
+	}
+	query += ` ORDER BY cm.id DESC LIMIT ?`
+	args = append(args, limit)
 
-		messages = append(messages, m)
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat messages: %w", err)
 	}
+	defer rows.Close()
 
+	var messages []models.ChatMessageWithUser
+	for rows.Next() {
+		m, err := scanChatMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chat message row: %w", err)
+		}
+		messages = append(messages, *m)
+	}
+
+	if err := r.attachReactions(messages); err != nil {
+		return nil, err
+	}
 	return messages, nil
 }
 
 // GetByID returns a chat message by ID with full details
 func (r *ChatRepository) GetByID(id uint64) (*models.ChatMessageWithUser, error) {
-	var m models.ChatMessageWithUser
-	var achievementsJSON string
-	err := database.DB.QueryRow(`
-		SELECT
-			cm.id, cm.message, cm.achievements, cm.created_at,
-			u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url
-		FROM chat_messages cm
-		JOIN users u ON cm.user_id = u.id
+	m, err := scanChatMessage(database.DB.QueryRow(`
+		SELECT`+chatMessageColumns+chatMessageTables+`
 		WHERE cm.id = ?`, id,
-	).Scan(
-		&m.ID, &m.Message, &achievementsJSON, &m.CreatedAt,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat message: %w", err)
+	}
+
+	messages := []models.ChatMessageWithUser{*m}
+	if err := r.attachReactions(messages); err != nil {
+		return nil, err
+	}
+	return &messages[0], nil
+}
+
+// UpdateMessage replaces the text of a chat message and marks it as edited (with retry for SQLITE_BUSY)
+func (r *ChatRepository) UpdateMessage(id uint64, message string, editedAt time.Time) error {
+	return database.WithRetry(func() error {
+		_, err := database.DB.Exec(`UPDATE chat_messages SET message = ?, edited_at = ? WHERE id = ?`, message, editedAt, id)
+		if err != nil {
+			return fmt.Errorf("failed to update chat message: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes a chat message with its reactions and reports whether it existed
+func (r *ChatRepository) Delete(id uint64) (bool, error) {
+	var affected int64
+	err := database.WithTransaction(func(tx *sql.Tx) error {
+		var err error
+		affected, err = deleteChatMessages(tx, `id = ?`, id)
+		return err
+	})
+	return affected > 0, err
+}
+
+// deleteChatMessages deletes the chat messages matching where with their reactions and returns how many were deleted
+// Replies stay and lose their parent; this is done explicitly because SQLite doesn't enforce foreign keys.
+func deleteChatMessages(tx *sql.Tx, where string, args ...interface{}) (int64, error) {
+	// MySQL can't update a table it selects from in a subquery, so the replies are looked up first
+	rows, err := tx.Query(`SELECT id FROM chat_messages WHERE parent_id IN (SELECT id FROM chat_messages WHERE `+where+`)`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chat replies: %w", err)
+	}
+	var replyIDs []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan chat reply row: %w", err)
+		}
+		replyIDs = append(replyIDs, id)
+	}
+	rows.Close()
+
+	for _, id := range replyIDs {
+		if _, err := tx.Exec(`UPDATE chat_messages SET parent_id = NULL WHERE id = ?`, id); err != nil {
+			return 0, fmt.Errorf("failed to detach chat reply: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM chat_reactions WHERE message_id IN (SELECT id FROM chat_messages WHERE `+where+`)`, args...); err != nil {
+		return 0, fmt.Errorf("failed to delete chat reactions: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM chat_messages WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete chat messages: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
+}
+
+// AddReaction records a player's reaction and reports whether it was new (with retry for SQLITE_BUSY)
+func (r *ChatRepository) AddReaction(messageID, userID uint64, emoji string) (bool, error) {
+	query := database.UpsertSQL("chat_reactions", []string{"message_id", "user_id", "emoji", "created_at"}, "",
+		[]string{"message_id", "user_id", "emoji"}, nil)
+
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(query, messageID, userID, emoji, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to add chat reaction: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	return affected > 0, err
+}
+
+// RemoveReaction deletes a player's reaction and reports whether it existed (with retry for SQLITE_BUSY)
+func (r *ChatRepository) RemoveReaction(messageID, userID uint64, emoji string) (bool, error) {
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(`
+			DELETE FROM chat_reactions WHERE message_id = ? AND user_id = ? AND emoji = ?`,
+			messageID, userID, emoji)
+		if err != nil {
+			return fmt.Errorf("failed to remove chat reaction: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	return affected > 0, err
+}
+
+// GetReactions returns the aggregated reactions of a message
+func (r *ChatRepository) GetReactions(messageID uint64) ([]models.ChatReaction, error) {
+	reactions, err := r.getReactions([]uint64{messageID})
+	if err != nil {
+		return nil, err
+	}
+	if reactions[messageID] == nil {
+		return []models.ChatReaction{}, nil
+	}
+	return reactions[messageID], nil
+}
+
+// attachReactions loads the aggregated reactions of all given messages
+func (r *ChatRepository) attachReactions(messages []models.ChatMessageWithUser) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	ids := make([]uint64, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+
+	reactions, err := r.getReactions(ids)
+	if err != nil {
+		return err
+	}
+	for i := range messages {
+		if list, ok := reactions[messages[i].ID]; ok {
+			messages[i].Reactions = list
+		}
+	}
+	return nil
+}
+
+// getReactions aggregates the reactions of the given messages per emoji, in order of the first reaction
+func (r *ChatRepository) getReactions(messageIDs []uint64) (map[uint64][]models.ChatReaction, error) {
+	args := make([]interface{}, len(messageIDs))
+	for i, id := range messageIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(messageIDs)), ", ")
+
+	rows, err := database.DB.Query(`
+		SELECT r.message_id, r.emoji, r.user_id
+		FROM chat_reactions r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.message_id IN (`+placeholders+`)
+		ORDER BY r.message_id, r.created_at, r.user_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat reactions: %w", err)
+	}
+	defer rows.Close()
+
+	reactions := make(map[uint64][]models.ChatReaction)
+	for rows.Next() {
+		var messageID, userID uint64
+		var emoji string
+		if err := rows.Scan(&messageID, &emoji, &userID); err != nil {
+			return nil, fmt.Errorf("failed to scan chat reaction row: %w", err)
+		}
+
+		list := reactions[messageID]
+		found := false
+		for i := range list {
+			if list[i].Emoji == emoji {
+				list[i].Count++
+				list[i].UserIDs = append(list[i].UserIDs, userID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, models.ChatReaction{Emoji: emoji, Count: 1, UserIDs: []uint64{userID}})
+		}
+		reactions[messageID] = list
+	}
+
+	return reactions, nil
+}
+
+// chatChannelColumns are the columns selected by scanChatChannel
+const chatChannelColumns = `c.id, c.slug, c.name, c.kind, c.app_id, c.created_at`
+
+// scanChatChannel scans a channel row selected with chatChannelColumns
+func scanChatChannel(row rowScanner) (*models.ChatChannel, error) {
+	var c models.ChatChannel
+	if err := row.Scan(&c.ID, &c.Slug, &c.Name, &c.Kind, &c.AppID, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetChannels returns all channels, game channels last and sorted by name
+// Joined is set for the game channels the player is a member of.
+func (r *ChatRepository) GetChannels(userID uint64) ([]models.ChatChannel, error) {
+	rows, err := database.DB.Query(`
+		SELECT `+chatChannelColumns+`, CASE WHEN m.user_id IS NULL THEN 0 ELSE 1 END
+		FROM chat_channels c
+		LEFT JOIN chat_channel_members m ON m.channel_id = c.id AND m.user_id = ?
+		ORDER BY CASE WHEN c.kind = ? THEN 1 ELSE 0 END, c.name, c.id`, userID, models.ChatChannelGame)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []models.ChatChannel{}
+	for rows.Next() {
+		var c models.ChatChannel
+		if err := rows.Scan(&c.ID, &c.Slug, &c.Name, &c.Kind, &c.AppID, &c.CreatedAt, &c.Joined); err != nil {
+			return nil, fmt.Errorf("failed to scan chat channel row: %w", err)
+		}
+		channels = append(channels, c)
+	}
+
+	return channels, nil
+}
+
+// GetAllChannels returns all channels ordered by ID, for exports
+func (r *ChatRepository) GetAllChannels() ([]models.ChatChannel, error) {
+	rows, err := database.DB.Query(`SELECT ` + chatChannelColumns + ` FROM chat_channels c ORDER BY c.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all chat channels: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []models.ChatChannel
+	for rows.Next() {
+		c, err := scanChatChannel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chat channel row: %w", err)
+		}
+		channels = append(channels, *c)
+	}
+
+	return channels, nil
+}
+
+// GetAllChannelMembers returns the members of all channels, for exports
+func (r *ChatRepository) GetAllChannelMembers() ([]models.ExportChatChannelMember, error) {
+	rows, err := database.DB.Query(`
+		SELECT c.slug, m.user_id, m.joined_at
+		FROM chat_channel_members m
+		JOIN chat_channels c ON m.channel_id = c.id
+		ORDER BY m.channel_id, m.user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all chat channel members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []models.ExportChatChannelMember
+	for rows.Next() {
+		var member models.ExportChatChannelMember
+		if err := rows.Scan(&member.Channel, &member.UserID, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat channel member row: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// GetChannel returns a channel by ID, nil if it does not exist
+func (r *ChatRepository) GetChannel(id uint64) (*models.ChatChannel, error) {
+	c, err := scanChatChannel(database.DB.QueryRow(`
+		SELECT `+chatChannelColumns+` FROM chat_channels c WHERE c.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat channel: %w", err)
+	}
+	return c, nil
+}
+
+// EnsureGameChannel returns the channel of a game, creating it on first use (with retry for SQLITE_BUSY)
+func (r *ChatRepository) EnsureGameChannel(appID int, name string) (*models.ChatChannel, error) {
+	query := database.UpsertSQL("chat_channels", []string{"slug", "name", "kind", "app_id", "created_at"}, "",
+		[]string{"slug"}, nil)
+
+	slug := models.GameChatChannelSlug(appID)
+	err := database.WithRetry(func() error {
+		if _, err := database.DB.Exec(query, slug, name, models.ChatChannelGame, appID, time.Now().UTC()); err != nil {
+			return fmt.Errorf("failed to create game chat channel: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := scanChatChannel(database.DB.QueryRow(`
+		SELECT `+chatChannelColumns+` FROM chat_channels c WHERE c.slug = ?`, slug))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game chat channel: %w", err)
+	}
+	return c, nil
+}
+
+// JoinChannel adds a player to a channel and reports whether they were new (with retry for SQLITE_BUSY)
+func (r *ChatRepository) JoinChannel(channelID, userID uint64) (bool, error) {
+	query := database.UpsertSQL("chat_channel_members", []string{"channel_id", "user_id", "joined_at"}, "",
+		[]string{"channel_id", "user_id"}, nil)
+
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(query, channelID, userID, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to join chat channel: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	return affected > 0, err
+}
+
+// LeaveChannel removes a player from a channel and reports whether they were a member (with retry for SQLITE_BUSY)
+func (r *ChatRepository) LeaveChannel(channelID, userID uint64) (bool, error) {
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(`
+			DELETE FROM chat_channel_members WHERE channel_id = ? AND user_id = ?`, channelID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to leave chat channel: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	return affected > 0, err
+}
+
+// IsChannelMember reports whether a player joined a channel
+func (r *ChatRepository) IsChannelMember(channelID, userID uint64) (bool, error) {
+	var count int
+	err := database.DB.QueryRow(`
+		SELECT COUNT(*) FROM chat_channel_members WHERE channel_id = ? AND user_id = ?`,
+		channelID, userID,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check chat channel membership: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetChannelMemberIDs returns the IDs of all players who joined a channel
+func (r *ChatRepository) GetChannelMemberIDs(channelID uint64) ([]uint64, error) {
+	rows, err := database.DB.Query(`
+		SELECT m.user_id
+		FROM chat_channel_members m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.channel_id = ?
+		ORDER BY m.user_id`, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat channel members: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := []uint64{}
+	for rows.Next() {
+		var userID uint64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan chat channel member row: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+// GetAllReactions returns all single reactions, for exports
+func (r *ChatRepository) GetAllReactions() ([]models.ExportChatReaction, error) {
+	rows, err := database.DB.Query(`
+		SELECT message_id, user_id, emoji, created_at
+		FROM chat_reactions
+		ORDER BY message_id, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all chat reactions: %w", err)
+	}
+	defer rows.Close()
+
+	var reactions []models.ExportChatReaction
+	for rows.Next() {
+		var reaction models.ExportChatReaction
+		if err := rows.Scan(&reaction.MessageID, &reaction.UserID, &reaction.Emoji, &reaction.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat reaction row: %w", err)
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	return reactions, nil
+}
+
+// chatMuteColumns are the columns selected by scanChatMute, joined with the muted player
+const chatMuteColumns = `
+	m.reason, m.muted_by, m.muted_until, m.created_at,
+	u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url`
+
+// scanChatMute scans a mute row selected with chatMuteColumns
+func scanChatMute(row rowScanner) (*models.ChatMute, error) {
+	var m models.ChatMute
+	err := row.Scan(
+		&m.Reason, &m.MutedBy, &m.MutedUntil, &m.CreatedAt,
 		&m.User.ID, &m.User.SteamID, &m.User.Username, &m.User.AvatarURL, &m.User.AvatarSmall, &m.User.ProfileURL,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Mute silences a player until mutedUntil, replacing an earlier mute (with retry for SQLITE_BUSY)
+func (r *ChatRepository) Mute(userID uint64, reason, mutedBy string, mutedUntil time.Time) error {
+	query := database.UpsertSQL("chat_mutes", []string{"user_id", "reason", "muted_by", "muted_until", "created_at"}, "",
+		[]string{"user_id"},
+		[]string{"reason", "muted_by", "muted_until", "created_at"})
+
+	return database.WithRetry(func() error {
+		_, err := database.DB.Exec(query, userID, reason, mutedBy, mutedUntil, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to mute user: %w", err)
+		}
+		return nil
+	})
+}
 
+// Unmute lifts a player's mute and reports whether there was one (with retry for SQLITE_BUSY)
+func (r *ChatRepository) Unmute(userID uint64) (bool, error) {
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(`DELETE FROM chat_mutes WHERE user_id = ?`, userID)
+		if err != nil {
+			return fmt.Errorf("failed to unmute user: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	return affected > 0, err
+}
+
+// GetActiveMute returns a player's mute if it lasts beyond now, nil otherwise
+func (r *ChatRepository) GetActiveMute(userID uint64, now time.Time) (*models.ChatMute, error) {
+	m, err := scanChatMute(database.DB.QueryRow(`
+		SELECT`+chatMuteColumns+`
+		FROM chat_mutes m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.user_id = ? AND m.muted_until > ?`, userID, now,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chat message: %w", err)
+		return nil, fmt.Errorf("failed to get chat mute: %w", err)
 	}
+	return m, nil
+}
 
-	// Parse achievements JSON
-	if achievementsJSON != "" && achievementsJSON != "[]" {
-		if err := json.Unmarshal([]byte(achievementsJSON), &m.Achievements); err != nil {
-			m.Achievements = []models.AchievementBadge{}
+// GetActiveMutes returns all mutes lasting beyond now, ending soonest first
+func (r *ChatRepository) GetActiveMutes(now time.Time) ([]models.ChatMute, error) {
+	rows, err := database.DB.Query(`
+		SELECT`+chatMuteColumns+`
+		FROM chat_mutes m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.muted_until > ?
+		ORDER BY m.muted_until`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat mutes: %w", err)
+	}
+	defer rows.Close()
+
+	mutes := []models.ChatMute{}
+	for rows.Next() {
+		m, err := scanChatMute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chat mute row: %w", err)
 		}
-	} else {
-		m.Achievements = []models.AchievementBadge{}
+		mutes = append(mutes, *m)
 	}
 
-	return &m, nil
+	return mutes, nil
+}
+
+// GetAllMutes returns all mutes including expired ones, for exports
+func (r *ChatRepository) GetAllMutes() ([]models.ChatMute, error) {
+	rows, err := database.DB.Query(`
+		SELECT` + chatMuteColumns + `
+		FROM chat_mutes m
+		JOIN users u ON m.user_id = u.id
+		ORDER BY m.user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all chat mutes: %w", err)
+	}
+	defer rows.Close()
+
+	var mutes []models.ChatMute
+	for rows.Next() {
+		m, err := scanChatMute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chat mute row: %w", err)
+		}
+		mutes = append(mutes, *m)
+	}
+
+	return mutes, nil
 }
 
 // GetUserAchievementBadges returns the current achievement badges for a user (aggregated votes received)
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestChatHistoryAndEdits(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewChatRepository()
+		users := createUsers(t, "alice", "bob")
+
+		var ids []uint64
+		for i, text := range []string{"one", "two", "three", "four", "five"} {
+			msg := &models.ChatMessage{UserID: users[[]string{"alice", "bob"}[i%2]].ID, Message: text, Achievements: "[]"}
+			if err := repo.Create(msg); err != nil {
+				t.Fatalf("failed to create message: %v", err)
+			}
+			ids = append(ids, msg.ID)
+		}
+
+		// Pages go backwards from the newest message
+		page, err := repo.GetBefore(models.GeneralChatChannelID, 0, 2)
+		if err != nil {
+			t.Fatalf("GetBefore failed: %v", err)
+		}
+		if len(page) != 2 || page[0].Message != "five" || page[1].Message != "four" {
+			t.Fatalf("unexpected first page: %+v", page)
+		}
+		page, err = repo.GetBefore(models.GeneralChatChannelID, page[1].ID, 10)
+		if err != nil {
+			t.Fatalf("GetBefore failed: %v", err)
+		}
+		if len(page) != 3 || page[0].Message != "three" || page[2].Message != "one" || page[2].User.Username != "alice" {
+			t.Fatalf("unexpected second page: %+v", page)
+		}
+
+		editedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+		if err := repo.UpdateMessage(ids[0], "one (edited)", editedAt); err != nil {
+			t.Fatalf("UpdateMessage failed: %v", err)
+		}
+		msg, err := repo.GetByID(ids[0])
+		if err != nil || msg == nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if msg.Message != "one (edited)" || msg.EditedAt == nil || !msg.EditedAt.Equal(editedAt) {
+			t.Errorf("expected the edited message, got %q edited at %v", msg.Message, msg.EditedAt)
+		}
+
+		deleted, err := repo.Delete(ids[1])
+		if err != nil || !deleted {
+			t.Fatalf("Delete failed: %v, %v", deleted, err)
+		}
+		if deleted, err := repo.Delete(ids[1]); err != nil || deleted {
+			t.Errorf("expected a second delete to find nothing, got %v, %v", deleted, err)
+		}
+		if msg, err := repo.GetByID(ids[1]); err != nil || msg != nil {
+			t.Errorf("expected the deleted message to be gone, got %+v, %v", msg, err)
+		}
+	})
+}
+
+func TestChatMutes(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewChatRepository()
+		users := createUsers(t, "alice", "bob")
+		now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+
+		if err := repo.Mute(users["alice"].ID, "spam", "76561190000000099", now.Add(time.Hour)); err != nil {
+			t.Fatalf("Mute failed: %v", err)
+		}
+		if err := repo.Mute(users["bob"].ID, "", "76561190000000099", now.Add(-time.Minute)); err != nil {
+			t.Fatalf("Mute failed: %v", err)
+		}
+
+		mute, err := repo.GetActiveMute(users["alice"].ID, now)
+		if err != nil || mute == nil {
+			t.Fatalf("GetActiveMute failed: %v", err)
+		}
+		if mute.User.Username != "alice" || mute.Reason != "spam" || !mute.MutedUntil.Equal(now.Add(time.Hour)) {
+			t.Errorf("unexpected mute: %+v", mute)
+		}
+		if mute, err := repo.GetActiveMute(users["bob"].ID, now); err != nil || mute != nil {
+			t.Errorf("expected the expired mute to be ignored, got %+v, %v", mute, err)
+		}
+		if all, err := repo.GetAllMutes(); err != nil || len(all) != 2 {
+			t.Errorf("expected exports to include the expired mute, got %+v, %v", all, err)
+		}
+
+		// A new mute replaces the old one
+		if err := repo.Mute(users["bob"].ID, "flaming", "76561190000000099", now.Add(2*time.Hour)); err != nil {
+			t.Fatalf("Mute failed: %v", err)
+		}
+		mutes, err := repo.GetActiveMutes(now)
+		if err != nil {
+			t.Fatalf("GetActiveMutes failed: %v", err)
+		}
+		if len(mutes) != 2 || mutes[0].User.Username != "alice" || mutes[1].Reason != "flaming" {
+			t.Errorf("unexpected active mutes: %+v", mutes)
+		}
+
+		if unmuted, err := repo.Unmute(users["alice"].ID); err != nil || !unmuted {
+			t.Fatalf("Unmute failed: %v, %v", unmuted, err)
+		}
+		if unmuted, err := repo.Unmute(users["alice"].ID); err != nil || unmuted {
+			t.Errorf("expected a second unmute to find nothing, got %v, %v", unmuted, err)
+		}
+	})
+}
+
+func TestChatRepliesAndReactions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewChatRepository()
+		users := createUsers(t, "alice", "bob")
+
+		parent := &models.ChatMessage{UserID: users["alice"].ID, Message: "who is in?", Achievements: "[]"}
+		if err := repo.Create(parent); err != nil {
+			t.Fatalf("failed to create message: %v", err)
+		}
+		reply := &models.ChatMessage{UserID: users["bob"].ID, Message: "me", Achievements: "[]", ParentID: &parent.ID}
+		if err := repo.Create(reply); err != nil {
+			t.Fatalf("failed to create reply: %v", err)
+		}
+
+		for _, r := range []struct {
+			user  string
+			emoji string
+		}{{"bob", "👍"}, {"alice", "🔥"}, {"alice", "👍"}} {
+			if added, err := repo.AddReaction(parent.ID, users[r.user].ID, r.emoji); err != nil || !added {
+				t.Fatalf("AddReaction failed: %v, %v", added, err)
+			}
+		}
+		if added, err := repo.AddReaction(parent.ID, users["bob"].ID, "👍"); err != nil || added {
+			t.Errorf("expected a duplicate reaction to be ignored, got %v, %v", added, err)
+		}
+		if all, err := repo.GetAllReactions(); err != nil || len(all) != 3 || all[0].MessageID != parent.ID {
+			t.Errorf("unexpected exported reactions: %+v, %v", all, err)
+		}
+
+		page, err := repo.GetBefore(models.GeneralChatChannelID, 0, 10)
+		if err != nil {
+			t.Fatalf("GetBefore failed: %v", err)
+		}
+		if page[0].Parent == nil || page[0].Parent.Username != "alice" || page[0].Parent.Message != "who is in?" {
+			t.Errorf("expected a preview of the parent message, got %+v", page[0].Parent)
+		}
+		reactions := page[1].Reactions
+		if len(reactions) != 2 || reactions[0].Emoji != "👍" || reactions[0].Count != 2 || reactions[1].Emoji != "🔥" {
+			t.Errorf("unexpected reactions: %+v", reactions)
+		}
+		if len(page[0].Reactions) != 0 || page[0].Reactions == nil {
+			t.Errorf("expected no reactions on the reply, got %+v", page[0].Reactions)
+		}
+
+		if removed, err := repo.RemoveReaction(parent.ID, users["alice"].ID, "🔥"); err != nil || !removed {
+			t.Fatalf("RemoveReaction failed: %v, %v", removed, err)
+		}
+
+		// Deleting the parent keeps the reply
+		if _, err := repo.Delete(parent.ID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		msg, err := repo.GetByID(reply.ID)
+		if err != nil || msg == nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if msg.ParentID != nil || msg.Parent != nil {
+			t.Errorf("expected the parent to be cleared, got %v", *msg.ParentID)
+		}
+	})
+}
+
+func TestChatChannels(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewChatRepository()
+		users := createUsers(t, "alice", "bob")
+		alice, bob := users["alice"], users["bob"]
+
+		channel, err := repo.EnsureGameChannel(730, "Counter-Strike 2")
+		if err != nil {
+			t.Fatalf("EnsureGameChannel failed: %v", err)
+		}
+		if again, err := repo.EnsureGameChannel(730, "Renamed"); err != nil || again.ID != channel.ID || again.Name != "Counter-Strike 2" {
+			t.Fatalf("expected the existing channel, got %+v, %v", again, err)
+		}
+		if channel.Slug != "game-730" || channel.Kind != models.ChatChannelGame || channel.AppID == nil || *channel.AppID != 730 {
+			t.Errorf("unexpected game channel: %+v", channel)
+		}
+
+		if joined, err := repo.JoinChannel(channel.ID, alice.ID); err != nil || !joined {
+			t.Fatalf("JoinChannel failed: %v, %v", joined, err)
+		}
+		if joined, err := repo.JoinChannel(channel.ID, alice.ID); err != nil || joined {
+			t.Errorf("expected a second join to have no effect, got %v, %v", joined, err)
+		}
+		if _, err := repo.JoinChannel(channel.ID, bob.ID); err != nil {
+			t.Fatalf("JoinChannel failed: %v", err)
+		}
+		if left, err := repo.LeaveChannel(channel.ID, bob.ID); err != nil || !left {
+			t.Fatalf("LeaveChannel failed: %v, %v", left, err)
+		}
+		members, err := repo.GetChannelMemberIDs(channel.ID)
+		if err != nil || len(members) != 1 || members[0] != alice.ID {
+			t.Errorf("expected alice to be the only member, got %v, %v", members, err)
+		}
+
+		// The general and the announcements channel come from the migration
+		channels, err := repo.GetChannels(alice.ID)
+		if err != nil {
+			t.Fatalf("GetChannels failed: %v", err)
+		}
+		if len(channels) != 3 || channels[2].ID != channel.ID || !channels[2].Joined {
+			t.Fatalf("unexpected channels: %+v", channels)
+		}
+		if channels, _ := repo.GetChannels(bob.ID); channels[2].Joined {
+			t.Errorf("expected bob not to be in the game channel")
+		}
+
+		for _, msg := range []*models.ChatMessage{
+			{UserID: alice.ID, Message: "hello everybody", Achievements: "[]"},
+			{ChannelID: channel.ID, UserID: alice.ID, Message: "anyone up for a match?", Achievements: "[]"},
+		} {
+			if err := repo.Create(msg); err != nil {
+				t.Fatalf("failed to create message: %v", err)
+			}
+		}
+		for channelID, want := range map[uint64]string{models.GeneralChatChannelID: "hello everybody", channel.ID: "anyone up for a match?"} {
+			page, err := repo.GetBefore(channelID, 0, 10)
+			if err != nil {
+				t.Fatalf("GetBefore failed: %v", err)
+			}
+			if len(page) != 1 || page[0].Message != want || page[0].ChannelID != channelID {
+				t.Errorf("unexpected messages in channel %d: %+v", channelID, page)
+			}
+		}
+
+		all, err := repo.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		if len(all) != 2 || all[0].Channel != "general" || all[1].Channel != "game-730" {
+			t.Errorf("expected exported messages with their channel slugs, got %+v", all)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// CustomGameRepository handles custom non-Steam games
+type CustomGameRepository struct{}
+
+// NewCustomGameRepository creates a new custom game repository
+func NewCustomGameRepository() *CustomGameRepository {
+	return &CustomGameRepository{}
+}
+
+const customGameColumns = `id, name, categories, max_players, has_image, created_by, created_at, updated_at`
+
+// scanCustomGame scans a custom_games row selected with customGameColumns
+func scanCustomGame(row rowScanner) (*models.CustomGame, error) {
+	var game models.CustomGame
+	var categories string
+	if err := row.Scan(&game.ID, &game.Name, &categories, &game.MaxPlayers, &game.HasImage,
+		&game.CreatedBy, &game.CreatedAt, &game.UpdatedAt); err != nil {
+		return nil, err
+	}
+	game.AppID = models.CustomGameAppID(game.ID)
+	game.Categories = []string{}
+	if categories != "" {
+		if err := json.Unmarshal([]byte(categories), &game.Categories); err != nil {
+			return nil, fmt.Errorf("failed to parse categories of custom game %d: %w", game.ID, err)
+		}
+	}
+	return &game, nil
+}
+
+// GetAll returns all custom games ordered by name
+func (r *CustomGameRepository) GetAll() ([]models.CustomGame, error) {
+	rows, err := database.DB.Query(`SELECT ` + customGameColumns + ` FROM custom_games ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom games: %w", err)
+	}
+	defer rows.Close()
+
+	games := []models.CustomGame{}
+	for rows.Next() {
+		game, err := scanCustomGame(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom game: %w", err)
+		}
+		games = append(games, *game)
+	}
+	return games, rows.Err()
+}
+
+// GetByAppID returns the custom game with the given app ID, or nil if it does not exist
+func (r *CustomGameRepository) GetByAppID(appID int) (*models.CustomGame, error) {
+	if !models.IsCustomAppID(appID) {
+		return nil, nil
+	}
+	game, err := scanCustomGame(database.DB.QueryRow(`SELECT `+customGameColumns+` FROM custom_games WHERE id = ?`,
+		appID-models.CustomGameAppIDOffset))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom game: %w", err)
+	}
+	return game, nil
+}
+
+// Create inserts a new custom game and sets its ID and app ID
+func (r *CustomGameRepository) Create(game *models.CustomGame) error {
+	categories, err := marshalCategories(game.Categories)
+	if err != nil {
+		return err
+	}
+
+	return database.WithRetry(func() error {
+		id, err := database.InsertReturningID(database.DB, `
+			INSERT INTO custom_games (name, categories, max_players, has_image, created_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			game.Name, categories, game.MaxPlayers, game.HasImage, game.CreatedBy, game.CreatedAt, game.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create custom game: %w", err)
+		}
+		game.ID = id
+		game.AppID = models.CustomGameAppID(id)
+		return nil
+	})
+}
+
+// Update saves the editable fields of a custom game
+func (r *CustomGameRepository) Update(game *models.CustomGame) error {
+	categories, err := marshalCategories(game.Categories)
+	if err != nil {
+		return err
+	}
+
+	return database.WithRetry(func() error {
+		_, err := database.DB.Exec(`
+			UPDATE custom_games SET name = ?, categories = ?, max_players = ?, has_image = ?, updated_at = ?
+			WHERE id = ?`,
+			game.Name, categories, game.MaxPlayers, game.HasImage, game.UpdatedAt, game.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update custom game: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes a custom game together with its declared owners and metadata
+func (r *CustomGameRepository) Delete(game *models.CustomGame) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM game_owners WHERE app_id = ?`, game.AppID); err != nil {
+			return fmt.Errorf("failed to delete owners of custom game: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM game_metadata WHERE app_id = ?`, game.AppID); err != nil {
+			return fmt.Errorf("failed to delete metadata of custom game: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM custom_games WHERE id = ?`, game.ID); err != nil {
+			return fmt.Errorf("failed to delete custom game: %w", err)
+		}
+		return nil
+	})
+}
+
+// marshalCategories encodes categories for the categories column
+func marshalCategories(categories []string) (string, error) {
+	if categories == nil {
+		categories = []string{}
+	}
+	data, err := json.Marshal(categories)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal categories: %w", err)
+	}
+	return string(data), nil
+}
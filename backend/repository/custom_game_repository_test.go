@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestCustomGameRepository(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewCustomGameRepository()
+		ownerRepo := NewGameOwnerRepository()
+
+		game := &models.CustomGame{
+			Name:       "Bomberman LAN",
+			Categories: []string{"Multi-player", "LAN PvP"},
+			MaxPlayers: 8,
+			CreatedBy:  "76561190000000001",
+			CreatedAt:  dbtest.Timestamp(0),
+			UpdatedAt:  dbtest.Timestamp(0),
+		}
+		if err := repo.Create(game); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if !models.IsCustomAppID(game.AppID) || game.AppID != models.CustomGameAppID(game.ID) {
+			t.Fatalf("expected a custom app ID, got %d for ID %d", game.AppID, game.ID)
+		}
+
+		// Custom games are found through the game cache, so polls and sessions accept them
+		cached, err := NewGameCacheRepository().GetByAppID(game.AppID)
+		if err != nil || cached == nil {
+			t.Fatalf("expected the custom game in the game cache, got %v, %v", cached, err)
+		}
+		if cached.Name != "Bomberman LAN" || len(cached.GetCategories()) != 2 {
+			t.Errorf("unexpected cache entry: %+v", cached)
+		}
+
+		game.Name = "Bomberman Deluxe"
+		game.HasImage = true
+		game.UpdatedAt = dbtest.Timestamp(time.Hour)
+		if err := repo.Update(game); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		got, err := repo.GetByAppID(game.AppID)
+		if err != nil || got == nil {
+			t.Fatalf("GetByAppID failed: %v, %v", got, err)
+		}
+		if got.Name != "Bomberman Deluxe" || !got.HasImage || got.MaxPlayers != 8 {
+			t.Errorf("unexpected custom game after update: %+v", got)
+		}
+
+		// Deleting the game removes the declared owners
+		if err := ownerRepo.Upsert(game.AppID, "76561190000000001", 0); err != nil {
+			t.Fatalf("failed to declare ownership: %v", err)
+		}
+		if err := repo.Delete(game); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if got, err := repo.GetByAppID(game.AppID); err != nil || got != nil {
+			t.Errorf("expected the custom game to be deleted, got %v, %v", got, err)
+		}
+		if owners, err := ownerRepo.GetSteamIDsByAppID(game.AppID); err != nil || len(owners) != 0 {
+			t.Errorf("expected no owners after delete, got %v, %v", owners, err)
+		}
+
+		// Steam app IDs are never looked up as custom games
+		if got, err := repo.GetByAppID(730); err != nil || got != nil {
+			t.Errorf("expected no custom game for a Steam app ID, got %v, %v", got, err)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// DirectMessageRepository handles private messages between players
+type DirectMessageRepository struct{}
+
+// NewDirectMessageRepository creates a new direct message repository
+func NewDirectMessageRepository() *DirectMessageRepository {
+	return &DirectMessageRepository{}
+}
+
+const directMessageColumns = `dm.id, dm.sender_id, dm.recipient_id, dm.message, dm.created_at, dm.read_at`
+
+// scanDirectMessage scans a direct message row selected with directMessageColumns
+func scanDirectMessage(row rowScanner) (*models.DirectMessage, error) {
+	var m models.DirectMessage
+	if err := row.Scan(&m.ID, &m.SenderID, &m.RecipientID, &m.Message, &m.CreatedAt, &m.ReadAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Create stores a direct message (with retry for SQLITE_BUSY)
+func (r *DirectMessageRepository) Create(msg *models.DirectMessage) error {
+	return database.WithRetry(func() error {
+		id, err := database.InsertReturningID(database.DB, `
+			INSERT INTO direct_messages (sender_id, recipient_id, message, created_at)
+			VALUES (?, ?, ?, ?)`,
+			msg.SenderID, msg.RecipientID, msg.Message, msg.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create direct message: %w", err)
+		}
+
+		msg.ID = uint64(id)
+		return nil
+	})
+}
+
+// GetAll returns all direct messages, for exports
+func (r *DirectMessageRepository) GetAll() ([]models.DirectMessage, error) {
+	rows, err := database.DB.Query(`SELECT ` + directMessageColumns + ` FROM direct_messages dm ORDER BY dm.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all direct messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []models.DirectMessage
+	for rows.Next() {
+		m, err := scanDirectMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan direct message row: %w", err)
+		}
+		messages = append(messages, *m)
+	}
+
+	return messages, nil
+}
+
+// GetByID returns a direct message, nil if it does not exist
+func (r *DirectMessageRepository) GetByID(id uint64) (*models.DirectMessage, error) {
+	m, err := scanDirectMessage(database.DB.QueryRow(`
+		SELECT `+directMessageColumns+` FROM direct_messages dm WHERE dm.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get direct message: %w", err)
+	}
+	return m, nil
+}
+
+// GetConversation returns the messages between two players older than beforeID, newest first
+// beforeID 0 returns the most recent messages.
+func (r *DirectMessageRepository) GetConversation(userID, partnerID, beforeID uint64, limit int) ([]models.DirectMessage, error) {
+	query := `SELECT ` + directMessageColumns + `
+		FROM direct_messages dm
+		WHERE ((dm.sender_id = ? AND dm.recipient_id = ?) OR (dm.sender_id = ? AND dm.recipient_id = ?))`
+	args := []interface{}{userID, partnerID, partnerID, userID}
+	if beforeID > 0 {
+		query += ` AND dm.id < ?`
+		args = append(args, beforeID)
+	}
+	query += ` ORDER BY dm.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.DirectMessage{}
+	for rows.Next() {
+		m, err := scanDirectMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan direct message row: %w", err)
+		}
+		messages = append(messages, *m)
+	}
+
+	return messages, nil
+}
+
+// GetConversations returns one entry per conversation partner with the last message and
+// the number of unread messages from them, most recent conversation first
+func (r *DirectMessageRepository) GetConversations(userID uint64) ([]models.Conversation, error) {
+	rows, err := database.DB.Query(`
+		SELECT c.unread, `+directMessageColumns+`,
+			u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url
+		FROM (
+			SELECT partner_id, MAX(id) AS last_id, SUM(unread) AS unread
+			FROM (
+				SELECT recipient_id AS partner_id, id, 0 AS unread
+				FROM direct_messages WHERE sender_id = ?
+				UNION ALL
+				SELECT sender_id AS partner_id, id, CASE WHEN read_at IS NULL THEN 1 ELSE 0 END AS unread
+				FROM direct_messages WHERE recipient_id = ?
+			) d
+			GROUP BY partner_id
+		) c
+		JOIN direct_messages dm ON dm.id = c.last_id
+		JOIN users u ON u.id = c.partner_id
+		ORDER BY dm.id DESC`, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := []models.Conversation{}
+	for rows.Next() {
+		var c models.Conversation
+		m := &c.LastMessage
+		p := &c.Partner
+		if err := rows.Scan(&c.UnreadCount, &m.ID, &m.SenderID, &m.RecipientID, &m.Message, &m.CreatedAt, &m.ReadAt,
+			&p.ID, &p.SteamID, &p.Username, &p.AvatarURL, &p.AvatarSmall, &p.ProfileURL); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation row: %w", err)
+		}
+		conversations = append(conversations, c)
+	}
+
+	return conversations, nil
+}
+
+// GetUnreadCount returns the number of unread messages a player received
+func (r *DirectMessageRepository) GetUnreadCount(userID uint64) (int, error) {
+	var count int
+	err := database.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM direct_messages dm
+		JOIN users u ON dm.sender_id = u.id
+		WHERE dm.recipient_id = ? AND dm.read_at IS NULL`, userID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread direct messages: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks all unread messages from sender to recipient as read
+// Returns the ID of the newest message marked, 0 if there were no unread messages.
+func (r *DirectMessageRepository) MarkRead(recipientID, senderID uint64, readAt time.Time) (uint64, error) {
+	var lastID uint64
+	err := database.WithTransaction(func(tx *sql.Tx) error {
+		var maxID sql.NullInt64
+		err := tx.QueryRow(`
+			SELECT MAX(id) FROM direct_messages
+			WHERE recipient_id = ? AND sender_id = ? AND read_at IS NULL`,
+			recipientID, senderID,
+		).Scan(&maxID)
+		if err != nil {
+			return fmt.Errorf("failed to find unread direct messages: %w", err)
+		}
+		if !maxID.Valid {
+			lastID = 0
+			return nil
+		}
+
+		// Messages arriving meanwhile stay unread
+		_, err = tx.Exec(`
+			UPDATE direct_messages SET read_at = ?
+			WHERE recipient_id = ? AND sender_id = ? AND read_at IS NULL AND id <= ?`,
+			readAt, recipientID, senderID, maxID.Int64)
+		if err != nil {
+			return fmt.Errorf("failed to mark direct messages as read: %w", err)
+		}
+		lastID = uint64(maxID.Int64)
+		return nil
+	})
+	return lastID, err
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestDirectMessages(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewDirectMessageRepository()
+		users := createUsers(t, "alice", "bob", "carol")
+		alice, bob, carol := users["alice"].ID, users["bob"].ID, users["carol"].ID
+
+		for i, m := range []struct {
+			from, to uint64
+			text     string
+		}{
+			{alice, bob, "hi bob"},
+			{bob, alice, "hi alice"},
+			{carol, alice, "psst"},
+			{bob, alice, "you there?"},
+			{alice, carol, "what?"},
+		} {
+			msg := &models.DirectMessage{SenderID: m.from, RecipientID: m.to, Message: m.text, CreatedAt: dbtest.Timestamp(time.Duration(i) * time.Minute)}
+			if err := repo.Create(msg); err != nil {
+				t.Fatalf("failed to create direct message: %v", err)
+			}
+		}
+
+		if all, err := repo.GetAll(); err != nil || len(all) != 5 || all[0].Message != "hi bob" {
+			t.Errorf("unexpected exported direct messages: %+v, %v", all, err)
+		}
+
+		// Pages of one conversation, newest first
+		page, err := repo.GetConversation(alice, bob, 0, 2)
+		if err != nil {
+			t.Fatalf("GetConversation failed: %v", err)
+		}
+		if len(page) != 2 || page[0].Message != "you there?" || page[1].Message != "hi alice" {
+			t.Fatalf("unexpected first page: %+v", page)
+		}
+		page, err = repo.GetConversation(bob, alice, page[1].ID, 10)
+		if err != nil {
+			t.Fatalf("GetConversation failed: %v", err)
+		}
+		if len(page) != 1 || page[0].Message != "hi bob" {
+			t.Errorf("unexpected second page: %+v", page)
+		}
+
+		conversations, err := repo.GetConversations(alice)
+		if err != nil {
+			t.Fatalf("GetConversations failed: %v", err)
+		}
+		if len(conversations) != 2 {
+			t.Fatalf("expected two conversations, got %+v", conversations)
+		}
+		if conversations[0].Partner.Username != "carol" || conversations[0].LastMessage.Message != "what?" || conversations[0].UnreadCount != 1 {
+			t.Errorf("unexpected conversation with carol: %+v", conversations[0])
+		}
+		if conversations[1].Partner.Username != "bob" || conversations[1].UnreadCount != 2 {
+			t.Errorf("unexpected conversation with bob: %+v", conversations[1])
+		}
+		if count, err := repo.GetUnreadCount(alice); err != nil || count != 3 {
+			t.Errorf("expected 3 unread messages, got %d, %v", count, err)
+		}
+
+		readAt := dbtest.Timestamp(time.Hour)
+		lastID, err := repo.MarkRead(alice, bob, readAt)
+		if err != nil {
+			t.Fatalf("MarkRead failed: %v", err)
+		}
+		if lastID != conversations[1].LastMessage.ID {
+			t.Errorf("expected the last message from bob to be read, got %d", lastID)
+		}
+		if lastID, err := repo.MarkRead(alice, bob, readAt); err != nil || lastID != 0 {
+			t.Errorf("expected nothing left to mark, got %d, %v", lastID, err)
+		}
+		msg, err := repo.GetByID(conversations[1].LastMessage.ID)
+		if err != nil || msg == nil || msg.ReadAt == nil || !msg.ReadAt.Equal(readAt) {
+			t.Errorf("expected a read receipt, got %+v, %v", msg, err)
+		}
+		if count, err := repo.GetUnreadCount(alice); err != nil || count != 1 {
+			t.Errorf("expected 1 unread message, got %d, %v", count, err)
+		}
+	})
+}
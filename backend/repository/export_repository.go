@@ -0,0 +1,998 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// ExportRepository handles restoring export archives into the database
+type ExportRepository struct{}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository() *ExportRepository {
+	return &ExportRepository{}
+}
+
+// Restore writes the contents of an archive into the database within a single transaction.
+// User IDs and custom game app IDs from the archive are remapped to the IDs assigned by the target
+// database; existing custom games are matched by name, and in merge mode existing users are
+// matched by Steam ID and duplicate votes/messages/polls/sessions/tournaments/matches are skipped.
+// The archive is expected to be validated by the caller.
+func (r *ExportRepository) Restore(archive *models.ExportArchive, mode models.ImportMode) (*models.ImportResult, error) {
+	var result *models.ImportResult
+
+	err := database.WithTransaction(func(tx *sql.Tx) error {
+		// Reset on every attempt, WithTransaction may retry on SQLITE_BUSY
+		result = &models.ImportResult{Mode: mode}
+
+		if mode == models.ImportModeReplace {
+			if err := wipeEventData(tx); err != nil {
+				return err
+			}
+		}
+
+		userIDs, err := restoreUsers(tx, archive.Users, result)
+		if err != nil {
+			return err
+		}
+		appIDs, err := restoreCustomGames(tx, archive.CustomGames, result)
+		if err != nil {
+			return err
+		}
+		if err := restoreGameMetadata(tx, archive.GameMetadata, appIDs, result); err != nil {
+			return err
+		}
+		if err := restoreVotes(tx, archive.Votes, userIDs, result); err != nil {
+			return err
+		}
+		pollIDs, err := restorePolls(tx, archive.Polls, userIDs, appIDs, result)
+		if err != nil {
+			return err
+		}
+		if err := restorePollBallots(tx, archive.PollBallots, pollIDs, userIDs, appIDs, result); err != nil {
+			return err
+		}
+		if err := restoreGameSessions(tx, archive.GameSessions, userIDs, appIDs, result); err != nil {
+			return err
+		}
+		if err := restoreInstallStatus(tx, archive.GameInstallStatus, userIDs, appIDs, result); err != nil {
+			return err
+		}
+		if err := restoreTournaments(tx, archive.Tournaments, userIDs, appIDs, result); err != nil {
+			return err
+		}
+		if err := restoreGameMatches(tx, archive.GameMatches, userIDs, appIDs, result); err != nil {
+			return err
+		}
+		if err := restorePlayerRatings(tx, archive.PlayerRatings, userIDs, appIDs, result); err != nil {
+			return err
+		}
+		if err := restoreChatChannels(tx, archive.ChatChannels, result); err != nil {
+			return err
+		}
+		if err := restoreChatChannelMembers(tx, archive.ChatChannelMembers, userIDs, result); err != nil {
+			return err
+		}
+		messageIDs, err := restoreChatMessages(tx, archive.ChatMessages, userIDs, result)
+		if err != nil {
+			return err
+		}
+		if err := restoreChatReactions(tx, archive.ChatReactions, messageIDs, userIDs, result); err != nil {
+			return err
+		}
+		if err := restoreChatMutes(tx, archive.ChatMutes, userIDs, result); err != nil {
+			return err
+		}
+		if err := restoreDirectMessages(tx, archive.DirectMessages, userIDs, result); err != nil {
+			return err
+		}
+		if err := restoreBans(tx, archive.BannedUsers, result); err != nil {
+			return err
+		}
+		return restoreGameOwners(tx, archive.GameOwners, appIDs, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// wipeEventData deletes all event data (children first, so foreign keys are never violated)
+func wipeEventData(tx *sql.Tx) error {
+	for _, table := range []string{"player_ratings", "game_match_players", "game_matches", "tournament_matches", "tournament_team_members", "tournament_teams", "tournament_registrations", "tournaments", "game_session_players", "game_sessions", "game_install_status", "poll_ballots", "poll_options", "polls", "chat_mutes", "chat_reactions", "chat_messages", "chat_channel_members", "direct_messages", "votes", "game_owners", "banned_users", "users"} {
+		if _, err := tx.Exec(`DELETE FROM ` + table); err != nil {
+			return fmt.Errorf("failed to wipe %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// restoreUsers inserts missing users and returns a map of archive user ID -> database user ID
+func restoreUsers(tx *sql.Tx, users []models.User, result *models.ImportResult) (map[uint64]uint64, error) {
+	userIDs := make(map[uint64]uint64, len(users))
+
+	for _, user := range users {
+		var existingID uint64
+		err := tx.QueryRow(`SELECT id FROM users WHERE steam_id = ?`, user.SteamID).Scan(&existingID)
+		if err == nil {
+			userIDs[user.ID] = existingID
+			result.UsersMatched++
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to look up user %s: %w", user.SteamID, err)
+		}
+
+		var lastGamesRefreshAt *time.Time
+		if user.LastGamesRefreshAt != nil {
+			t := user.LastGamesRefreshAt.UTC()
+			lastGamesRefreshAt = &t
+		}
+		var invitedAt *time.Time
+		if user.InvitedAt != nil {
+			t := user.InvitedAt.UTC()
+			invitedAt = &t
+		}
+
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO users (steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, last_games_refresh_at, invited_at, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			user.SteamID, user.Username, user.AvatarURL, user.AvatarSmall, user.ProfileURL, user.Credits,
+			user.LastCreditAt.UTC(), lastGamesRefreshAt, invitedAt, user.CreatedAt.UTC(), user.UpdatedAt.UTC(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore user %s: %w", user.SteamID, err)
+		}
+
+		userIDs[user.ID] = uint64(id)
+		result.UsersCreated++
+	}
+
+	return userIDs, nil
+}
+
+// voteKey identifies a vote independently of its database ID
+type voteKey struct {
+	fromUserID    uint64
+	toUserID      uint64
+	achievementID string
+	createdAt     int64
+}
+
+// restoreVotes inserts all votes that are not already present, with remapped user IDs
+func restoreVotes(tx *sql.Tx, votes []models.Vote, userIDs map[uint64]uint64, result *models.ImportResult) error {
+	existing := make(map[voteKey]bool)
+	rows, err := tx.Query(`SELECT from_user_id, to_user_id, achievement_id, created_at FROM votes`)
+	if err != nil {
+		return fmt.Errorf("failed to load existing votes: %w", err)
+	}
+	for rows.Next() {
+		var key voteKey
+		var createdAt time.Time
+		if err := rows.Scan(&key.fromUserID, &key.toUserID, &key.achievementID, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan vote row: %w", err)
+		}
+		key.createdAt = createdAt.Unix()
+		existing[key] = true
+	}
+	rows.Close()
+
+	for _, vote := range votes {
+		key := voteKey{
+			fromUserID:    userIDs[vote.FromUserID],
+			toUserID:      userIDs[vote.ToUserID],
+			achievementID: vote.AchievementID,
+			createdAt:     vote.CreatedAt.Unix(),
+		}
+		if existing[key] {
+			result.VotesSkipped++
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO votes (from_user_id, to_user_id, achievement_id, points, is_secret, is_invalidated, comment, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			key.fromUserID, key.toUserID, vote.AchievementID, vote.Points, vote.IsSecret, vote.IsInvalidated,
+			vote.Comment, vote.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore vote %d: %w", vote.ID, err)
+		}
+		existing[key] = true
+		result.VotesImported++
+	}
+
+	return nil
+}
+
+// loadChatChannelIDs returns a map of channel slug -> database channel ID
+func loadChatChannelIDs(tx *sql.Tx) (map[string]uint64, error) {
+	rows, err := tx.Query(`SELECT id, slug FROM chat_channels`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chat channels: %w", err)
+	}
+	defer rows.Close()
+
+	channelIDs := make(map[string]uint64)
+	for rows.Next() {
+		var id uint64
+		var slug string
+		if err := rows.Scan(&id, &slug); err != nil {
+			return nil, fmt.Errorf("failed to scan chat channel row: %w", err)
+		}
+		channelIDs[slug] = id
+	}
+	return channelIDs, nil
+}
+
+// restoreChatChannels inserts all channels whose slug doesn't exist yet
+func restoreChatChannels(tx *sql.Tx, channels []models.ExportChatChannel, result *models.ImportResult) error {
+	for _, channel := range channels {
+		var count int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM chat_channels WHERE slug = ?`, channel.Slug).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check chat channel: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO chat_channels (slug, name, kind, app_id, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			channel.Slug, channel.Name, channel.Kind, channel.AppID, channel.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore chat channel %s: %w", channel.Slug, err)
+		}
+		result.ChatChannelsImported++
+	}
+	return nil
+}
+
+// restoreChatChannelMembers inserts all channel memberships with remapped user IDs, skipping existing ones
+func restoreChatChannelMembers(tx *sql.Tx, members []models.ExportChatChannelMember, userIDs map[uint64]uint64, result *models.ImportResult) error {
+	if len(members) == 0 {
+		return nil
+	}
+	channelIDs, err := loadChatChannelIDs(tx)
+	if err != nil {
+		return err
+	}
+
+	query := database.UpsertSQL("chat_channel_members", []string{"channel_id", "user_id", "joined_at"}, "",
+		[]string{"channel_id", "user_id"}, nil)
+
+	for _, member := range members {
+		res, err := tx.Exec(query, channelIDs[member.Channel], userIDs[member.UserID], member.JoinedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to restore membership of user %d in chat channel %s: %w", member.UserID, member.Channel, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		result.ChatChannelMembersImported += int(affected)
+	}
+	return nil
+}
+
+// chatKey identifies a chat message independently of its database ID
+type chatKey struct {
+	userID    uint64
+	message   string
+	createdAt int64
+}
+
+// restoreChatMessages inserts all chat messages that are not already present, with remapped user, parent and channel IDs,
+// and returns a map of archive message ID -> database message ID
+// Messages of channels that don't exist in this database (and of archives without channels) go to the general channel.
+func restoreChatMessages(tx *sql.Tx, messages []models.ChatMessage, userIDs map[uint64]uint64, result *models.ImportResult) (map[uint64]uint64, error) {
+	channelIDs, err := loadChatChannelIDs(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[chatKey]uint64)
+	rows, err := tx.Query(`SELECT id, user_id, message, created_at FROM chat_messages`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing chat messages: %w", err)
+	}
+	for rows.Next() {
+		var id uint64
+		var key chatKey
+		var createdAt time.Time
+		if err := rows.Scan(&id, &key.userID, &key.message, &createdAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan chat message row: %w", err)
+		}
+		key.createdAt = createdAt.Unix()
+		existing[key] = id
+	}
+	rows.Close()
+
+	// Archive message ID -> database message ID, replies come after the messages they refer to
+	messageIDs := make(map[uint64]uint64, len(messages))
+
+	for _, msg := range messages {
+		key := chatKey{
+			userID:    userIDs[msg.UserID],
+			message:   msg.Message,
+			createdAt: msg.CreatedAt.Unix(),
+		}
+		if id, ok := existing[key]; ok {
+			messageIDs[msg.ID] = id
+			result.ChatMessagesSkipped++
+			continue
+		}
+
+		achievements := msg.Achievements
+		if achievements == "" {
+			achievements = "[]"
+		}
+
+		var parentID *uint64
+		if msg.ParentID != nil {
+			if id, ok := messageIDs[*msg.ParentID]; ok {
+				parentID = &id
+			}
+		}
+
+		channelID, ok := channelIDs[msg.Channel]
+		if !ok {
+			channelID = models.GeneralChatChannelID
+		}
+
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO chat_messages (channel_id, user_id, message, achievements, created_at, edited_at, parent_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			channelID, key.userID, msg.Message, achievements, msg.CreatedAt.UTC(), msg.EditedAt, parentID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore chat message %d: %w", msg.ID, err)
+		}
+		existing[key] = uint64(id)
+		messageIDs[msg.ID] = uint64(id)
+		result.ChatMessagesImported++
+	}
+
+	return messageIDs, nil
+}
+
+// restoreChatReactions inserts all reactions with remapped message and user IDs, skipping existing ones
+func restoreChatReactions(tx *sql.Tx, reactions []models.ExportChatReaction, messageIDs, userIDs map[uint64]uint64, result *models.ImportResult) error {
+	query := database.UpsertSQL("chat_reactions", []string{"message_id", "user_id", "emoji", "created_at"}, "",
+		[]string{"message_id", "user_id", "emoji"}, nil)
+
+	for _, reaction := range reactions {
+		res, err := tx.Exec(query, messageIDs[reaction.MessageID], userIDs[reaction.UserID], reaction.Emoji, reaction.CreatedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to restore chat reaction on message %d: %w", reaction.MessageID, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		result.ChatReactionsImported += int(affected)
+	}
+	return nil
+}
+
+// restoreChatMutes inserts the mutes of all players that are not muted yet
+func restoreChatMutes(tx *sql.Tx, mutes []models.ChatMute, userIDs map[uint64]uint64, result *models.ImportResult) error {
+	for _, mute := range mutes {
+		userID := userIDs[mute.User.ID]
+		var count int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM chat_mutes WHERE user_id = ?`, userID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check mute status: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO chat_mutes (user_id, reason, muted_by, muted_until, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			userID, mute.Reason, mute.MutedBy, mute.MutedUntil.UTC(), mute.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore chat mute for user %d: %w", mute.User.ID, err)
+		}
+		result.ChatMutesImported++
+	}
+	return nil
+}
+
+// directMessageKey identifies a direct message independently of its database ID
+type directMessageKey struct {
+	senderID    uint64
+	recipientID uint64
+	message     string
+	createdAt   int64
+}
+
+// restoreDirectMessages inserts all direct messages that are not already present, with remapped user IDs
+func restoreDirectMessages(tx *sql.Tx, messages []models.DirectMessage, userIDs map[uint64]uint64, result *models.ImportResult) error {
+	existing := make(map[directMessageKey]bool)
+	rows, err := tx.Query(`SELECT sender_id, recipient_id, message, created_at FROM direct_messages`)
+	if err != nil {
+		return fmt.Errorf("failed to load existing direct messages: %w", err)
+	}
+	for rows.Next() {
+		var key directMessageKey
+		var createdAt time.Time
+		if err := rows.Scan(&key.senderID, &key.recipientID, &key.message, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan direct message row: %w", err)
+		}
+		key.createdAt = createdAt.Unix()
+		existing[key] = true
+	}
+	rows.Close()
+
+	for _, msg := range messages {
+		key := directMessageKey{
+			senderID:    userIDs[msg.SenderID],
+			recipientID: userIDs[msg.RecipientID],
+			message:     msg.Message,
+			createdAt:   msg.CreatedAt.Unix(),
+		}
+		if existing[key] {
+			result.DirectMessagesSkipped++
+			continue
+		}
+
+		var readAt *time.Time
+		if msg.ReadAt != nil {
+			t := msg.ReadAt.UTC()
+			readAt = &t
+		}
+		_, err := tx.Exec(`
+			INSERT INTO direct_messages (sender_id, recipient_id, message, created_at, read_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			key.senderID, key.recipientID, msg.Message, msg.CreatedAt.UTC(), readAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore direct message %d: %w", msg.ID, err)
+		}
+		existing[key] = true
+		result.DirectMessagesImported++
+	}
+
+	return nil
+}
+
+// restoreBans inserts all bans for Steam IDs that are not banned yet
+func restoreBans(tx *sql.Tx, bans []models.BannedUser, result *models.ImportResult) error {
+	for _, ban := range bans {
+		var count int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM banned_users WHERE steam_id = ?`, ban.SteamID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check ban status: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO banned_users (steam_id, username, reason, banned_by, banned_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			ban.SteamID, ban.Username, ban.Reason, ban.BannedBy, ban.BannedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore ban for %s: %w", ban.SteamID, err)
+		}
+		result.BansImported++
+	}
+	return nil
+}
+
+// appIDMap maps the app IDs of archived custom games to the ones in the database
+type appIDMap map[int]int
+
+// get returns the database app ID of an archived app ID, Steam app IDs stay the same
+func (m appIDMap) get(appID int) int {
+	if id, ok := m[appID]; ok {
+		return id
+	}
+	return appID
+}
+
+// restoreCustomGames inserts all custom games whose name doesn't exist yet and returns the app ID map
+// Cover images are not part of the archive, so new games are created without one.
+func restoreCustomGames(tx *sql.Tx, games []models.CustomGame, result *models.ImportResult) (appIDMap, error) {
+	appIDs := make(appIDMap, len(games))
+
+	for _, game := range games {
+		var existingID int64
+		err := tx.QueryRow(`SELECT id FROM custom_games WHERE name = ? ORDER BY id LIMIT 1`, game.Name).Scan(&existingID)
+		if err == nil {
+			appIDs[game.AppID] = models.CustomGameAppID(existingID)
+			result.CustomGamesMatched++
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to look up custom game %q: %w", game.Name, err)
+		}
+
+		categories, err := marshalCategories(game.Categories)
+		if err != nil {
+			return nil, err
+		}
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO custom_games (name, categories, max_players, has_image, created_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			game.Name, categories, game.MaxPlayers, false, game.CreatedBy, game.CreatedAt.UTC(), game.UpdatedAt.UTC(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore custom game %q: %w", game.Name, err)
+		}
+		appIDs[game.AppID] = models.CustomGameAppID(id)
+		result.CustomGamesCreated++
+	}
+
+	return appIDs, nil
+}
+
+// restoreGameMetadata upserts the metadata of all games with remapped app IDs
+func restoreGameMetadata(tx *sql.Tx, metadata []models.GameMetadata, appIDs appIDMap, result *models.ImportResult) error {
+	for _, meta := range metadata {
+		meta.AppID = appIDs.get(meta.AppID)
+		meta.UpdatedAt = meta.UpdatedAt.UTC()
+		if err := saveGameMetadata(tx, &meta); err != nil {
+			return err
+		}
+		result.GameMetadataImported++
+	}
+	return nil
+}
+
+// restoreGameOwners upserts all game ownership entries with remapped app IDs
+func restoreGameOwners(tx *sql.Tx, owners []models.ExportGameOwner, appIDs appIDMap, result *models.ImportResult) error {
+	if len(owners) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(database.UpsertSQL("game_owners",
+		[]string{"app_id", "steam_id", "playtime_forever", "created_at", "updated_at"}, "",
+		[]string{"app_id", "steam_id"},
+		[]string{"playtime_forever", "updated_at"}))
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, owner := range owners {
+		_, err := stmt.Exec(appIDs.get(owner.AppID), owner.SteamID, owner.PlaytimeForever, owner.CreatedAt.UTC(), owner.UpdatedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to restore game owner %d for %s: %w", owner.AppID, owner.SteamID, err)
+		}
+		result.GameOwnersImported++
+	}
+
+	return nil
+}
+
+// pollKey identifies a poll independently of its database ID
+type pollKey struct {
+	createdBy uint64
+	title     string
+	createdAt int64
+}
+
+// restorePolls inserts all polls that are not already present together with their options, with remapped
+// user and app IDs, and returns a map of archive poll ID -> database poll ID for the inserted polls
+func restorePolls(tx *sql.Tx, polls []models.Poll, userIDs map[uint64]uint64, appIDs appIDMap, result *models.ImportResult) (map[uint64]uint64, error) {
+	existing := make(map[pollKey]bool)
+	rows, err := tx.Query(`SELECT created_by, title, created_at FROM polls`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing polls: %w", err)
+	}
+	for rows.Next() {
+		var key pollKey
+		var createdAt time.Time
+		if err := rows.Scan(&key.createdBy, &key.title, &createdAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan poll row: %w", err)
+		}
+		key.createdAt = createdAt.Unix()
+		existing[key] = true
+	}
+	rows.Close()
+
+	pollIDs := make(map[uint64]uint64, len(polls))
+
+	for _, poll := range polls {
+		key := pollKey{
+			createdBy: userIDs[poll.CreatedBy.ID],
+			title:     poll.Title,
+			createdAt: poll.CreatedAt.Unix(),
+		}
+		if existing[key] {
+			result.PollsSkipped++
+			continue
+		}
+
+		var closesAt, closedAt *time.Time
+		if poll.ClosesAt != nil {
+			t := poll.ClosesAt.UTC()
+			closesAt = &t
+		}
+		if poll.ClosedAt != nil {
+			t := poll.ClosedAt.UTC()
+			closedAt = &t
+		}
+		var winnerAppID *int
+		if poll.WinnerAppID != nil {
+			appID := appIDs.get(*poll.WinnerAppID)
+			winnerAppID = &appID
+		}
+
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO polls (title, voting_method, created_by, closes_at, closed_at, winner_app_id, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			poll.Title, string(poll.VotingMethod), key.createdBy, closesAt, closedAt, winnerAppID, poll.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore poll %d: %w", poll.ID, err)
+		}
+		for _, option := range poll.Options {
+			if _, err := tx.Exec(`
+				INSERT INTO poll_options (poll_id, app_id, name, position)
+				VALUES (?, ?, ?, ?)`,
+				id, appIDs.get(option.AppID), option.Name, option.Position,
+			); err != nil {
+				return nil, fmt.Errorf("failed to restore option %d of poll %d: %w", option.AppID, poll.ID, err)
+			}
+		}
+		existing[key] = true
+		pollIDs[poll.ID] = uint64(id)
+		result.PollsImported++
+	}
+
+	return pollIDs, nil
+}
+
+// restorePollBallots inserts the ballots of all restored polls with remapped poll, user and app IDs
+// Ballots of polls that were already present are skipped, so a changed ballot is never mixed with the archived one.
+func restorePollBallots(tx *sql.Tx, ballots []models.ExportPollBallot, pollIDs, userIDs map[uint64]uint64, appIDs appIDMap, result *models.ImportResult) error {
+	for _, ballot := range ballots {
+		pollID, ok := pollIDs[ballot.PollID]
+		if !ok {
+			continue
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO poll_ballots (poll_id, user_id, app_id, preference, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			pollID, userIDs[ballot.UserID], appIDs.get(ballot.AppID), ballot.Preference, ballot.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore ballot of user %d in poll %d: %w", ballot.UserID, ballot.PollID, err)
+		}
+		result.PollBallotsImported++
+	}
+	return nil
+}
+
+// gameSessionKey identifies a game session independently of its database ID
+type gameSessionKey struct {
+	createdBy uint64
+	appID     int
+	createdAt int64
+}
+
+// restoreGameSessions inserts all game sessions that are not already present together with their players,
+// with remapped user and app IDs
+func restoreGameSessions(tx *sql.Tx, sessions []models.ExportGameSession, userIDs map[uint64]uint64, appIDs appIDMap, result *models.ImportResult) error {
+	existing := make(map[gameSessionKey]bool)
+	rows, err := tx.Query(`SELECT created_by, app_id, created_at FROM game_sessions`)
+	if err != nil {
+		return fmt.Errorf("failed to load existing game sessions: %w", err)
+	}
+	for rows.Next() {
+		var key gameSessionKey
+		var createdAt time.Time
+		if err := rows.Scan(&key.createdBy, &key.appID, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan game session row: %w", err)
+		}
+		key.createdAt = createdAt.Unix()
+		existing[key] = true
+	}
+	rows.Close()
+
+	for _, session := range sessions {
+		key := gameSessionKey{
+			createdBy: userIDs[session.CreatedBy],
+			appID:     appIDs.get(session.AppID),
+			createdAt: session.CreatedAt.Unix(),
+		}
+		if existing[key] {
+			result.GameSessionsSkipped++
+			continue
+		}
+
+		var startsAt, readyAt *time.Time
+		if session.StartsAt != nil {
+			t := session.StartsAt.UTC()
+			startsAt = &t
+		}
+		if session.ReadyAt != nil {
+			t := session.ReadyAt.UTC()
+			readyAt = &t
+		}
+
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO game_sessions (app_id, title, created_by, starts_at, ready_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			key.appID, session.Title, key.createdBy, startsAt, readyAt, session.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore game session %d: %w", session.ID, err)
+		}
+		for _, userID := range session.PlayerIDs {
+			if _, err := tx.Exec(`INSERT INTO game_session_players (session_id, user_id) VALUES (?, ?)`, id, userIDs[userID]); err != nil {
+				return fmt.Errorf("failed to restore player %d of game session %d: %w", userID, session.ID, err)
+			}
+		}
+		existing[key] = true
+		result.GameSessionsImported++
+	}
+
+	return nil
+}
+
+// restoreInstallStatus inserts the install statuses with remapped user and app IDs,
+// keeping the statuses players already reported in this database
+func restoreInstallStatus(tx *sql.Tx, statuses []models.GameInstallStatus, userIDs map[uint64]uint64, appIDs appIDMap, result *models.ImportResult) error {
+	query := database.UpsertSQL("game_install_status", []string{"user_id", "app_id", "status", "progress", "updated_at"}, "",
+		[]string{"user_id", "app_id"}, nil)
+
+	for _, status := range statuses {
+		res, err := tx.Exec(query, userIDs[status.UserID], appIDs.get(status.AppID), string(status.Status), status.Progress, status.UpdatedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to restore install status of user %d for app %d: %w", status.UserID, status.AppID, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		result.InstallStatusesImported += int(affected)
+	}
+	return nil
+}
+
+// tournamentKey identifies a tournament independently of its database ID
+type tournamentKey struct {
+	createdBy uint64
+	name      string
+	createdAt int64
+}
+
+// restoreTournaments inserts all tournaments that are not already present together with their registrations,
+// teams and matches, with remapped user, app, team and match IDs
+func restoreTournaments(tx *sql.Tx, tournaments []models.ExportTournament, userIDs map[uint64]uint64, appIDs appIDMap, result *models.ImportResult) error {
+	existing := make(map[tournamentKey]bool)
+	rows, err := tx.Query(`SELECT created_by, name, created_at FROM tournaments`)
+	if err != nil {
+		return fmt.Errorf("failed to load existing tournaments: %w", err)
+	}
+	for rows.Next() {
+		var key tournamentKey
+		var createdAt time.Time
+		if err := rows.Scan(&key.createdBy, &key.name, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan tournament row: %w", err)
+		}
+		key.createdAt = createdAt.Unix()
+		existing[key] = true
+	}
+	rows.Close()
+
+	for _, t := range tournaments {
+		key := tournamentKey{
+			createdBy: userIDs[t.CreatedBy],
+			name:      t.Name,
+			createdAt: t.CreatedAt.Unix(),
+		}
+		if existing[key] {
+			result.TournamentsSkipped++
+			continue
+		}
+		if err := restoreTournament(tx, &t, key.createdBy, userIDs, appIDs); err != nil {
+			return err
+		}
+		existing[key] = true
+		result.TournamentsImported++
+	}
+
+	return nil
+}
+
+// restoreTournament inserts a single tournament with its registrations, teams and matches
+func restoreTournament(tx *sql.Tx, t *models.ExportTournament, createdBy uint64, userIDs map[uint64]uint64, appIDs appIDMap) error {
+	var startedAt, finishedAt *time.Time
+	if t.StartedAt != nil {
+		started := t.StartedAt.UTC()
+		startedAt = &started
+	}
+	if t.FinishedAt != nil {
+		finished := t.FinishedAt.UTC()
+		finishedAt = &finished
+	}
+
+	id, err := database.InsertReturningID(tx, `
+		INSERT INTO tournaments (name, app_id, format, team_size, status, bonus_points, created_by, created_at, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.Name, appIDs.get(t.AppID), string(t.Format), t.TeamSize, string(t.Status), t.BonusPoints, createdBy,
+		t.CreatedAt.UTC(), startedAt, finishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore tournament %d: %w", t.ID, err)
+	}
+
+	for _, registration := range t.Registrations {
+		if _, err := tx.Exec(`
+			INSERT INTO tournament_registrations (tournament_id, user_id, created_at)
+			VALUES (?, ?, ?)`, id, userIDs[registration.UserID], registration.CreatedAt.UTC()); err != nil {
+			return fmt.Errorf("failed to restore registration of user %d for tournament %d: %w", registration.UserID, t.ID, err)
+		}
+	}
+
+	// Archive team ID -> database team ID
+	teamIDs := make(map[uint64]uint64, len(t.Teams))
+	for _, team := range t.Teams {
+		teamID, err := database.InsertReturningID(tx, `
+			INSERT INTO tournament_teams (tournament_id, name, seed)
+			VALUES (?, ?, ?)`, id, team.Name, team.Seed)
+		if err != nil {
+			return fmt.Errorf("failed to restore team %d of tournament %d: %w", team.ID, t.ID, err)
+		}
+		for _, userID := range team.MemberIDs {
+			if _, err := tx.Exec(`INSERT INTO tournament_team_members (team_id, user_id) VALUES (?, ?)`, teamID, userIDs[userID]); err != nil {
+				return fmt.Errorf("failed to restore member %d of team %d: %w", userID, team.ID, err)
+			}
+		}
+		teamIDs[team.ID] = uint64(teamID)
+	}
+	remapTeam := func(teamID *uint64) *uint64 {
+		if teamID == nil {
+			return nil
+		}
+		id := teamIDs[*teamID]
+		return &id
+	}
+
+	// Archive match ID -> database match ID, the links are stored like in TournamentRepository.Start
+	matchIDs := make(map[uint64]uint64, len(t.Matches))
+	for _, m := range t.Matches {
+		matchID, err := database.InsertReturningID(tx, `
+			INSERT INTO tournament_matches (tournament_id, bracket, round, position, status)
+			VALUES (?, ?, ?, ?, ?)`, id, m.Bracket, m.Round, m.Position, string(m.Status))
+		if err != nil {
+			return fmt.Errorf("failed to restore match %d of tournament %d: %w", m.ID, t.ID, err)
+		}
+		matchIDs[m.ID] = uint64(matchID)
+	}
+	remapMatch := func(matchID *uint64) *uint64 {
+		if matchID == nil {
+			return nil
+		}
+		id := matchIDs[*matchID]
+		return &id
+	}
+	for _, m := range t.Matches {
+		m.ID = matchIDs[m.ID]
+		m.Team1ID = remapTeam(m.Team1ID)
+		m.Team2ID = remapTeam(m.Team2ID)
+		m.WinnerTeamID = remapTeam(m.WinnerTeamID)
+		m.WinnerNextMatchID = remapMatch(m.WinnerNextMatchID)
+		m.LoserNextMatchID = remapMatch(m.LoserNextMatchID)
+		if m.ReportedBy != nil {
+			reportedBy := userIDs[*m.ReportedBy]
+			m.ReportedBy = &reportedBy
+		}
+		if err := updateMatch(tx, &m, true); err != nil {
+			return err
+		}
+	}
+
+	if t.WinnerTeamID != nil {
+		if _, err := tx.Exec(`UPDATE tournaments SET winner_team_id = ? WHERE id = ?`, teamIDs[*t.WinnerTeamID], id); err != nil {
+			return fmt.Errorf("failed to restore the winner of tournament %d: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// gameMatchKey identifies a recorded match independently of its database ID
+type gameMatchKey struct {
+	recordedBy uint64
+	appID      int
+	playedAt   int64
+	createdAt  int64
+}
+
+// restoreGameMatches inserts all matches that are not already present together with their players,
+// with remapped user and app IDs
+// The matches are inserted in archive order, so the rating history keeps following the order they were rated in.
+func restoreGameMatches(tx *sql.Tx, matches []models.GameMatch, userIDs map[uint64]uint64, appIDs appIDMap, result *models.ImportResult) error {
+	existing := make(map[gameMatchKey]bool)
+	rows, err := tx.Query(`SELECT recorded_by, app_id, played_at, created_at FROM game_matches`)
+	if err != nil {
+		return fmt.Errorf("failed to load existing matches: %w", err)
+	}
+	for rows.Next() {
+		var key gameMatchKey
+		var playedAt, createdAt time.Time
+		if err := rows.Scan(&key.recordedBy, &key.appID, &playedAt, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan match row: %w", err)
+		}
+		key.playedAt = playedAt.Unix()
+		key.createdAt = createdAt.Unix()
+		existing[key] = true
+	}
+	rows.Close()
+
+	for _, match := range matches {
+		key := gameMatchKey{
+			recordedBy: userIDs[match.RecordedBy],
+			appID:      appIDs.get(match.AppID),
+			playedAt:   match.PlayedAt.Unix(),
+			createdAt:  match.CreatedAt.Unix(),
+		}
+		if existing[key] {
+			result.GameMatchesSkipped++
+			continue
+		}
+
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO game_matches (app_id, winner_team, recorded_by, played_at, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			key.appID, match.WinnerTeam, key.recordedBy, match.PlayedAt.UTC(), match.CreatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore match %d: %w", match.ID, err)
+		}
+		for _, p := range match.Players {
+			if _, err := tx.Exec(`
+				INSERT INTO game_match_players (match_id, user_id, team, rating_before, rating_after)
+				VALUES (?, ?, ?, ?, ?)`, id, userIDs[p.User.ID], p.Team, p.RatingBefore, p.RatingAfter); err != nil {
+				return fmt.Errorf("failed to restore player %d of match %d: %w", p.User.ID, match.ID, err)
+			}
+		}
+		existing[key] = true
+		result.GameMatchesImported++
+	}
+
+	return nil
+}
+
+// restorePlayerRatings inserts the ratings with remapped user and app IDs,
+// keeping the ratings players already have in this database
+func restorePlayerRatings(tx *sql.Tx, ratings []models.PlayerRating, userIDs map[uint64]uint64, appIDs appIDMap, result *models.ImportResult) error {
+	query := database.UpsertSQL("player_ratings",
+		[]string{"user_id", "app_id", "rating", "matches", "wins", "losses", "draws", "updated_at"}, "",
+		[]string{"user_id", "app_id"}, nil)
+
+	for _, rating := range ratings {
+		res, err := tx.Exec(query,
+			userIDs[rating.User.ID], appIDs.get(rating.AppID), rating.Rating, rating.Matches, rating.Wins, rating.Losses, rating.Draws, rating.UpdatedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore rating of user %d for app %d: %w", rating.User.ID, rating.AppID, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		result.PlayerRatingsImported += int(affected)
+	}
+	return nil
+}
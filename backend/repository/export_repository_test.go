@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestRestoreChatData(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewExportRepository()
+		chatRepo := NewChatRepository()
+		at := dbtest.Timestamp(0)
+		appID := 730
+
+		parentID := uint64(100)
+		archive := &models.ExportArchive{
+			FormatVersion: models.ExportFormatVersion,
+			Users: []models.User{
+				{ID: 10, SteamID: "76561190000000010", Username: "alice", LastCreditAt: at, CreatedAt: at, UpdatedAt: at},
+				{ID: 11, SteamID: "76561190000000011", Username: "bob", LastCreditAt: at, CreatedAt: at, UpdatedAt: at},
+			},
+			ChatChannels: []models.ExportChatChannel{
+				{Slug: "general", Name: "General", Kind: models.ChatChannelGlobal, CreatedAt: at},
+				{Slug: models.GameChatChannelSlug(appID), Name: "Counter-Strike 2", Kind: models.ChatChannelGame, AppID: &appID, CreatedAt: at},
+			},
+			ChatChannelMembers: []models.ExportChatChannelMember{
+				{Channel: models.GameChatChannelSlug(appID), UserID: 10, JoinedAt: at},
+			},
+			ChatMessages: []models.ChatMessage{
+				{ID: 100, UserID: 10, Message: "rush b", Channel: models.GameChatChannelSlug(appID), CreatedAt: at},
+				{ID: 101, UserID: 11, Message: "no", Channel: models.GameChatChannelSlug(appID), ParentID: &parentID, CreatedAt: at.Add(time.Minute)},
+			},
+			ChatReactions: []models.ExportChatReaction{
+				{MessageID: 100, UserID: 11, Emoji: "👎", CreatedAt: at},
+			},
+			ChatMutes: []models.ChatMute{
+				{User: models.PublicUser{ID: 11}, Reason: "spam", MutedBy: "76561190000000010", MutedUntil: at.Add(time.Hour), CreatedAt: at},
+			},
+			DirectMessages: []models.DirectMessage{
+				{ID: 5, SenderID: 10, RecipientID: 11, Message: "gg", CreatedAt: at},
+			},
+		}
+
+		result, err := repo.Restore(archive, models.ImportModeReplace)
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if result.ChatChannelsImported != 1 || result.ChatChannelMembersImported != 1 || result.ChatMessagesImported != 2 ||
+			result.ChatReactionsImported != 1 || result.ChatMutesImported != 1 || result.DirectMessagesImported != 1 {
+			t.Fatalf("unexpected import result: %+v", result)
+		}
+
+		messages, err := chatRepo.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		if len(messages) != 2 || messages[0].Channel != models.GameChatChannelSlug(appID) ||
+			messages[1].ParentID == nil || *messages[1].ParentID != messages[0].ID {
+			t.Fatalf("unexpected restored messages: %+v", messages)
+		}
+		if reactions, err := chatRepo.GetAllReactions(); err != nil || len(reactions) != 1 || reactions[0].MessageID != messages[0].ID {
+			t.Errorf("unexpected restored reactions: %+v, %v", reactions, err)
+		}
+		if members, err := chatRepo.GetAllChannelMembers(); err != nil || len(members) != 1 || members[0].UserID != messages[0].UserID {
+			t.Errorf("unexpected restored channel members: %+v, %v", members, err)
+		}
+
+		// Merging the same archive again adds nothing
+		result, err = repo.Restore(archive, models.ImportModeMerge)
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if result.ChatChannelsImported != 0 || result.ChatChannelMembersImported != 0 || result.ChatMessagesSkipped != 2 ||
+			result.ChatReactionsImported != 0 || result.ChatMutesImported != 0 || result.DirectMessagesSkipped != 1 {
+			t.Errorf("unexpected merge result: %+v", result)
+		}
+	})
+}
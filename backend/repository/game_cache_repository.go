@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
 )
 
 // GameCache represents a cached game entry in the database
@@ -33,7 +34,12 @@ func NewGameCacheRepository() *GameCacheRepository {
 }
 
 // GetByAppID finds a cached game by App ID
+// Custom games are not cached from Steam, they are returned from their own table instead.
 func (r *GameCacheRepository) GetByAppID(appID int) (*GameCache, error) {
+	if models.IsCustomAppID(appID) {
+		return r.getCustomGame(appID)
+	}
+
 	cache := &GameCache{}
 	err := database.DB.QueryRow(`
 		SELECT app_id, name, categories, is_free, price_cents, original_cents, discount_percent, price_formatted, review_score, fetch_failed, fetched_at
@@ -50,6 +56,25 @@ func (r *GameCacheRepository) GetByAppID(appID int) (*GameCache, error) {
 	return cache, nil
 }
 
+// getCustomGame returns a custom game as cache entry, so it can be used wherever Steam games are
+func (r *GameCacheRepository) getCustomGame(appID int) (*GameCache, error) {
+	game, err := NewCustomGameRepository().GetByAppID(appID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	categories, err := marshalCategories(game.Categories)
+	if err != nil {
+		return nil, err
+	}
+	return &GameCache{
+		AppID:       game.AppID,
+		Name:        game.Name,
+		Categories:  categories,
+		ReviewScore: -1,
+		FetchedAt:   game.UpdatedAt,
+	}, nil
+}
+
 // GetAll returns all cached games
 func (r *GameCacheRepository) GetAll() ([]GameCache, error) {
 	rows, err := database.DB.Query(`
@@ -135,25 +160,13 @@ func (r *GameCacheRepository) GetGamesNeedingSync(maxAge, retryDelay time.Durati
 // InsertIfNotExists adds a game to the cache only if it doesn't already exist
 // This is used when a new user joins - we record their games without overwriting existing data
 func (r *GameCacheRepository) InsertIfNotExists(appID int, name string) error {
-	if database.IsSQLite() {
-		_, err := database.DB.Exec(`
-			INSERT OR IGNORE INTO game_cache (app_id, name, categories, review_score, fetched_at)
-			VALUES (?, ?, '[]', -1, '1970-01-01 00:00:00')`,
-			appID, name,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to insert game if not exists: %w", err)
-		}
-	} else {
-		// MySQL/MariaDB - INSERT IGNORE
-		_, err := database.DB.Exec(`
-			INSERT IGNORE INTO game_cache (app_id, name, categories, review_score, fetched_at)
-			VALUES (?, ?, '[]', -1, '1970-01-01 00:00:00')`,
-			appID, name,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to insert game if not exists: %w", err)
-		}
+	_, err := database.DB.Exec(database.UpsertSQL("game_cache",
+		[]string{"app_id", "name", "categories", "review_score", "fetched_at"}, "?, ?, '[]', -1, '1970-01-01 00:00:00'",
+		[]string{"app_id"}, nil),
+		appID, name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert game if not exists: %w", err)
 	}
 	return nil
 }
@@ -202,43 +215,13 @@ func (r *GameCacheRepository) UpsertWithStatus(appID int, name string, categorie
 		price = &GamePriceInfo{ReviewScore: -1}
 	}
 
-	// Use database-specific upsert syntax
-	if database.IsSQLite() {
-		_, err = database.DB.Exec(`
-			INSERT INTO game_cache (app_id, name, categories, is_free, price_cents, original_cents, discount_percent, price_formatted, review_score, fetch_failed, fetched_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
-			ON CONFLICT(app_id) DO UPDATE SET
-				name = excluded.name,
-				categories = excluded.categories,
-				is_free = excluded.is_free,
-				price_cents = excluded.price_cents,
-				original_cents = excluded.original_cents,
-				discount_percent = excluded.discount_percent,
-				price_formatted = excluded.price_formatted,
-				review_score = excluded.review_score,
-				fetch_failed = excluded.fetch_failed,
-				fetched_at = CURRENT_TIMESTAMP`,
-			appID, name, string(categoriesJSON), price.IsFree, price.PriceCents, price.OriginalCents, price.DiscountPercent, price.PriceFormatted, price.ReviewScore, fetchFailed,
-		)
-	} else {
-		// MySQL/MariaDB syntax
-		_, err = database.DB.Exec(`
-			INSERT INTO game_cache (app_id, name, categories, is_free, price_cents, original_cents, discount_percent, price_formatted, review_score, fetch_failed, fetched_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
-			ON DUPLICATE KEY UPDATE
-				name = VALUES(name),
-				categories = VALUES(categories),
-				is_free = VALUES(is_free),
-				price_cents = VALUES(price_cents),
-				original_cents = VALUES(original_cents),
-				discount_percent = VALUES(discount_percent),
-				price_formatted = VALUES(price_formatted),
-				review_score = VALUES(review_score),
-				fetch_failed = VALUES(fetch_failed),
-				fetched_at = CURRENT_TIMESTAMP`,
-			appID, name, string(categoriesJSON), price.IsFree, price.PriceCents, price.OriginalCents, price.DiscountPercent, price.PriceFormatted, price.ReviewScore, fetchFailed,
-		)
-	}
+	_, err = database.DB.Exec(database.UpsertSQL("game_cache",
+		[]string{"app_id", "name", "categories", "is_free", "price_cents", "original_cents", "discount_percent", "price_formatted", "review_score", "fetch_failed", "fetched_at"},
+		"?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP",
+		[]string{"app_id"},
+		[]string{"name", "categories", "is_free", "price_cents", "original_cents", "discount_percent", "price_formatted", "review_score", "fetch_failed", "fetched_at"}),
+		appID, name, string(categoriesJSON), price.IsFree, price.PriceCents, price.OriginalCents, price.DiscountPercent, price.PriceFormatted, price.ReviewScore, fetchFailed,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to upsert game cache: %w", err)
 	}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// GameMetadataRepository handles curated game metadata
+type GameMetadataRepository struct{}
+
+// NewGameMetadataRepository creates a new game metadata repository
+func NewGameMetadataRepository() *GameMetadataRepository {
+	return &GameMetadataRepository{}
+}
+
+const gameMetadataColumns = `app_id, max_players, min_players, lan_capable, dedicated_server_required, install_size_mb, tags, notes, source, updated_by, updated_at`
+
+// scanGameMetadata scans a game_metadata row selected with gameMetadataColumns
+func scanGameMetadata(row rowScanner) (*models.GameMetadata, error) {
+	var meta models.GameMetadata
+	var tags string
+	if err := row.Scan(&meta.AppID, &meta.MaxPlayers, &meta.MinPlayers, &meta.LANCapable, &meta.DedicatedServerRequired,
+		&meta.InstallSizeMB, &tags, &meta.Notes, &meta.Source, &meta.UpdatedBy, &meta.UpdatedAt); err != nil {
+		return nil, err
+	}
+	meta.Tags = []string{}
+	if tags != "" {
+		if err := json.Unmarshal([]byte(tags), &meta.Tags); err != nil {
+			return nil, fmt.Errorf("failed to parse tags of game %d: %w", meta.AppID, err)
+		}
+	}
+	return &meta, nil
+}
+
+// GetAll returns the metadata of all games
+func (r *GameMetadataRepository) GetAll() ([]models.GameMetadata, error) {
+	rows, err := database.DB.Query(`SELECT ` + gameMetadataColumns + ` FROM game_metadata ORDER BY app_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game metadata: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.GameMetadata{}
+	for rows.Next() {
+		meta, err := scanGameMetadata(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game metadata: %w", err)
+		}
+		entries = append(entries, *meta)
+	}
+	return entries, rows.Err()
+}
+
+// Get returns the metadata of a game, or nil if there is none
+func (r *GameMetadataRepository) Get(appID int) (*models.GameMetadata, error) {
+	meta, err := scanGameMetadata(database.DB.QueryRow(`SELECT `+gameMetadataColumns+` FROM game_metadata WHERE app_id = ?`, appID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// Save creates or replaces the metadata of a game (with retry for SQLITE_BUSY)
+func (r *GameMetadataRepository) Save(meta *models.GameMetadata) error {
+	return database.WithRetry(func() error {
+		return saveGameMetadata(database.DB, meta)
+	})
+}
+
+// Delete removes the metadata of a game and reports whether there was any
+func (r *GameMetadataRepository) Delete(appID int) (bool, error) {
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(`DELETE FROM game_metadata WHERE app_id = ?`, appID)
+		if err != nil {
+			return fmt.Errorf("failed to delete game metadata: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	return affected > 0, err
+}
+
+// ApplySeed syncs the seeded entries with the given seed file contents in one transaction
+// Entries edited by an admin are left alone, seeded entries missing from the file are removed.
+// Returns the number of created, updated and removed entries.
+func (r *GameMetadataRepository) ApplySeed(seed []models.GameMetadata) (int, error) {
+	changed := 0
+	err := database.WithTransaction(func(tx *sql.Tx) error {
+		changed = 0
+
+		// The rows are read completely before writing, as SQLite cannot write while a read is open
+		rows, err := tx.Query(`SELECT ` + gameMetadataColumns + ` FROM game_metadata`)
+		if err != nil {
+			return fmt.Errorf("failed to get game metadata: %w", err)
+		}
+		existing := make(map[int]*models.GameMetadata)
+		for rows.Next() {
+			meta, err := scanGameMetadata(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan game metadata: %w", err)
+			}
+			existing[meta.AppID] = meta
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read game metadata: %w", err)
+		}
+		rows.Close()
+
+		seeded := make(map[int]bool, len(seed))
+		for i := range seed {
+			meta := seed[i]
+			seeded[meta.AppID] = true
+			if current, ok := existing[meta.AppID]; ok {
+				if current.Source != models.GameMetadataSourceSeed || sameGameMetadata(current, &meta) {
+					continue
+				}
+			}
+			meta.Source = models.GameMetadataSourceSeed
+			meta.UpdatedBy = ""
+			meta.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+			if err := saveGameMetadata(tx, &meta); err != nil {
+				return err
+			}
+			changed++
+		}
+
+		for appID, meta := range existing {
+			if meta.Source != models.GameMetadataSourceSeed || seeded[appID] {
+				continue
+			}
+			if _, err := tx.Exec(`DELETE FROM game_metadata WHERE app_id = ?`, appID); err != nil {
+				return fmt.Errorf("failed to delete game metadata: %w", err)
+			}
+			changed++
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// sameGameMetadata reports whether two entries have the same editable fields
+func sameGameMetadata(a, b *models.GameMetadata) bool {
+	if a.MaxPlayers != b.MaxPlayers || a.MinPlayers != b.MinPlayers || a.LANCapable != b.LANCapable ||
+		a.DedicatedServerRequired != b.DedicatedServerRequired || a.InstallSizeMB != b.InstallSizeMB ||
+		a.Notes != b.Notes || len(a.Tags) != len(b.Tags) {
+		return false
+	}
+	for i := range a.Tags {
+		if a.Tags[i] != b.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// saveGameMetadata upserts a metadata entry
+func saveGameMetadata(db database.Execer, meta *models.GameMetadata) error {
+	tags := meta.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	query := database.UpsertSQL("game_metadata", strings.Split(gameMetadataColumns, ", "), "",
+		[]string{"app_id"},
+		[]string{"max_players", "min_players", "lan_capable", "dedicated_server_required", "install_size_mb", "tags", "notes", "source", "updated_by", "updated_at"})
+
+	_, err = db.Exec(query,
+		meta.AppID, meta.MaxPlayers, meta.MinPlayers, meta.LANCapable, meta.DedicatedServerRequired, meta.InstallSizeMB,
+		string(tagsJSON), meta.Notes, meta.Source, meta.UpdatedBy, meta.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save game metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestGameMetadataApplySeed(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewGameMetadataRepository()
+
+		changed, err := repo.ApplySeed([]models.GameMetadata{
+			{AppID: 730, MaxPlayers: 10, Tags: []string{"shooter"}},
+			{AppID: 945360, MaxPlayers: 15},
+			{AppID: 1509960, MaxPlayers: 8},
+		})
+		if err != nil || changed != 3 {
+			t.Fatalf("ApplySeed created %d entries (%v), want 3", changed, err)
+		}
+
+		// An admin edit is never overwritten by the seed
+		edited := &models.GameMetadata{AppID: 945360, MaxPlayers: 12, LANCapable: true, Notes: "house rules",
+			Source: models.GameMetadataSourceAdmin, UpdatedBy: "76561190000000001", UpdatedAt: dbtest.Timestamp(0)}
+		if err := repo.Save(edited); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		changed, err = repo.ApplySeed([]models.GameMetadata{
+			{AppID: 730, MaxPlayers: 10, Tags: []string{"shooter"}},
+			{AppID: 945360, MaxPlayers: 15},
+		})
+		if err != nil || changed != 1 {
+			t.Fatalf("ApplySeed changed %d entries (%v), want 1 (removed PICO PARK)", changed, err)
+		}
+
+		entries, err := repo.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 entries, got %+v", entries)
+		}
+		if entries[0].AppID != 730 || entries[0].Source != models.GameMetadataSourceSeed || len(entries[0].Tags) != 1 {
+			t.Errorf("unexpected seeded entry: %+v", entries[0])
+		}
+		admin := entries[1]
+		if admin.MaxPlayers != 12 || !admin.LANCapable || admin.Notes != "house rules" || admin.Source != models.GameMetadataSourceAdmin || len(admin.Tags) != 0 {
+			t.Errorf("admin entry was changed by the seed: %+v", admin)
+		}
+
+		deleted, err := repo.Delete(945360)
+		if err != nil || !deleted {
+			t.Errorf("Delete returned %t (%v), want true", deleted, err)
+		}
+		if meta, err := repo.Get(945360); err != nil || meta != nil {
+			t.Errorf("expected no entry after Delete, got %+v (%v)", meta, err)
+		}
+		if deleted, _ := repo.Delete(945360); deleted {
+			t.Error("expected deleting a missing entry to report false")
+		}
+	})
+}
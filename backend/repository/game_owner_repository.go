@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"database/sql"
 	"fmt"
 	"time"
 
@@ -111,6 +110,30 @@ func (r *GameOwnerRepository) GetGamesByUserSteamID(steamID string) ([]GameOwner
 	return games, nil
 }
 
+// GetAll returns all game ownership entries (used for exports)
+func (r *GameOwnerRepository) GetAll() ([]GameOwner, error) {
+	rows, err := database.DB.Query(`
+		SELECT app_id, steam_id, playtime_forever, created_at, updated_at
+		FROM game_owners
+		ORDER BY app_id, steam_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all game owners: %w", err)
+	}
+	defer rows.Close()
+
+	var owners []GameOwner
+	for rows.Next() {
+		var owner GameOwner
+		err := rows.Scan(&owner.AppID, &owner.SteamID, &owner.PlaytimeForever, &owner.CreatedAt, &owner.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game owner row: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+
+	return owners, nil
+}
+
 // GetAllOwnersGroupedByAppID returns a map of appID -> []steamID for all games
 func (r *GameOwnerRepository) GetAllOwnersGroupedByAppID() (map[int][]string, error) {
 	rows, err := database.DB.Query(`
@@ -138,35 +161,21 @@ func (r *GameOwnerRepository) GetAllOwnersGroupedByAppID() (map[int][]string, er
 
 // Upsert creates or updates a game ownership entry
 func (r *GameOwnerRepository) Upsert(appID int, steamID string, playtimeForever int) error {
-	if database.IsSQLite() {
-		_, err := database.DB.Exec(`
-			INSERT INTO game_owners (app_id, steam_id, playtime_forever, created_at, updated_at)
-			VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-			ON CONFLICT(app_id, steam_id) DO UPDATE SET
-				playtime_forever = excluded.playtime_forever,
-				updated_at = CURRENT_TIMESTAMP`,
-			appID, steamID, playtimeForever,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to upsert game owner: %w", err)
-		}
-	} else {
-		// MySQL/MariaDB syntax
-		_, err := database.DB.Exec(`
-			INSERT INTO game_owners (app_id, steam_id, playtime_forever, created_at, updated_at)
-			VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-			ON DUPLICATE KEY UPDATE
-				playtime_forever = VALUES(playtime_forever),
-				updated_at = CURRENT_TIMESTAMP`,
-			appID, steamID, playtimeForever,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to upsert game owner: %w", err)
-		}
+	_, err := database.DB.Exec(gameOwnerUpsertSQL(), appID, steamID, playtimeForever)
+	if err != nil {
+		return fmt.Errorf("failed to upsert game owner: %w", err)
 	}
 	return nil
 }
 
+// gameOwnerUpsertSQL returns the statement to create or update an ownership (app ID, Steam ID, playtime)
+func gameOwnerUpsertSQL() string {
+	return database.UpsertSQL("game_owners",
+		[]string{"app_id", "steam_id", "playtime_forever", "created_at", "updated_at"}, "?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP",
+		[]string{"app_id", "steam_id"},
+		[]string{"playtime_forever", "updated_at"})
+}
+
 // UpsertBatch upserts multiple game ownerships for a user efficiently
 func (r *GameOwnerRepository) UpsertBatch(steamID string, games []struct {
 	AppID           int
@@ -183,22 +192,7 @@ func (r *GameOwnerRepository) UpsertBatch(steamID string, games []struct {
 	}
 	defer tx.Rollback()
 
-	var stmt *sql.Stmt
-	if database.IsSQLite() {
-		stmt, err = tx.Prepare(`
-			INSERT INTO game_owners (app_id, steam_id, playtime_forever, created_at, updated_at)
-			VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-			ON CONFLICT(app_id, steam_id) DO UPDATE SET
-				playtime_forever = excluded.playtime_forever,
-				updated_at = CURRENT_TIMESTAMP`)
-	} else {
-		stmt, err = tx.Prepare(`
-			INSERT INTO game_owners (app_id, steam_id, playtime_forever, created_at, updated_at)
-			VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-			ON DUPLICATE KEY UPDATE
-				playtime_forever = VALUES(playtime_forever),
-				updated_at = CURRENT_TIMESTAMP`)
-	}
+	stmt, err := tx.Prepare(gameOwnerUpsertSQL())
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
@@ -236,6 +230,23 @@ func (r *GameOwnerRepository) DeleteByAppID(appID int) error {
 	return nil
 }
 
+// Delete removes a single ownership entry and reports whether it existed
+func (r *GameOwnerRepository) Delete(appID int, steamID string) (bool, error) {
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(`DELETE FROM game_owners WHERE app_id = ? AND steam_id = ?`, appID, steamID)
+		if err != nil {
+			return fmt.Errorf("failed to delete game owner: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	return affected > 0, err
+}
+
 // DeleteAll removes all game ownership entries
 func (r *GameOwnerRepository) DeleteAll() error {
 	_, err := database.DB.Exec(`DELETE FROM game_owners`)
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+)
+
+func TestGameCacheUpsert(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(t *testing.T, repo *GameCacheRepository)
+		wantName    string
+		wantFree    bool
+		wantPrice   int
+		wantFailed  bool
+		wantScore   int
+		wantCatsLen int
+	}{
+		{
+			name: "insert",
+			setup: func(t *testing.T, repo *GameCacheRepository) {
+				mustUpsertGame(t, repo, "Counter-Strike 2", []string{"Multi-player"}, &GamePriceInfo{IsFree: true, ReviewScore: 87}, false)
+			},
+			wantName: "Counter-Strike 2", wantFree: true, wantScore: 87, wantCatsLen: 1,
+		},
+		{
+			name: "update overwrites all fields",
+			setup: func(t *testing.T, repo *GameCacheRepository) {
+				mustUpsertGame(t, repo, "Old Name", []string{"Multi-player"}, &GamePriceInfo{IsFree: true, ReviewScore: 50}, false)
+				mustUpsertGame(t, repo, "New Name", []string{"Multi-player", "Co-op"}, &GamePriceInfo{PriceCents: 999, OriginalCents: 1999, DiscountPercent: 50, ReviewScore: 91}, false)
+			},
+			wantName: "New Name", wantPrice: 999, wantScore: 91, wantCatsLen: 2,
+		},
+		{
+			name: "failed fetch is recorded",
+			setup: func(t *testing.T, repo *GameCacheRepository) {
+				mustUpsertGame(t, repo, "Removed Game", nil, nil, true)
+			},
+			wantName: "Removed Game", wantFailed: true, wantScore: -1,
+		},
+		{
+			name: "insert if not exists keeps existing data",
+			setup: func(t *testing.T, repo *GameCacheRepository) {
+				mustUpsertGame(t, repo, "Counter-Strike 2", []string{"Multi-player"}, &GamePriceInfo{ReviewScore: 87}, false)
+				if err := repo.InsertIfNotExists(730, "Placeholder"); err != nil {
+					t.Fatalf("InsertIfNotExists failed: %v", err)
+				}
+			},
+			wantName: "Counter-Strike 2", wantScore: 87, wantCatsLen: 1,
+		},
+		{
+			name: "insert if not exists creates a stale placeholder",
+			setup: func(t *testing.T, repo *GameCacheRepository) {
+				if err := repo.InsertIfNotExists(730, "Placeholder"); err != nil {
+					t.Fatalf("InsertIfNotExists failed: %v", err)
+				}
+			},
+			wantName: "Placeholder", wantScore: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtest.Run(t, func(t *testing.T) {
+				repo := NewGameCacheRepository()
+				tt.setup(t, repo)
+
+				game, err := repo.GetByAppID(730)
+				if err != nil || game == nil {
+					t.Fatalf("GetByAppID failed: game=%v err=%v", game, err)
+				}
+				if game.Name != tt.wantName || game.IsFree != tt.wantFree || game.PriceCents != tt.wantPrice ||
+					game.FetchFailed != tt.wantFailed || game.ReviewScore != tt.wantScore || len(game.GetCategories()) != tt.wantCatsLen {
+					t.Errorf("unexpected game cache entry: %+v", game)
+				}
+
+				all, err := repo.GetAll()
+				if err != nil {
+					t.Fatalf("GetAll failed: %v", err)
+				}
+				if len(all) != 1 {
+					t.Errorf("expected exactly one cached game, got %d", len(all))
+				}
+			})
+		})
+	}
+}
+
+// mustUpsertGame upserts app 730 and fails the test on error
+func mustUpsertGame(t *testing.T, repo *GameCacheRepository, name string, categories []string, price *GamePriceInfo, fetchFailed bool) {
+	t.Helper()
+
+	if err := repo.UpsertWithStatus(730, name, categories, price, fetchFailed); err != nil {
+		t.Fatalf("UpsertWithStatus failed: %v", err)
+	}
+}
+
+func TestGameOwnerUpsert(t *testing.T) {
+	type game = struct {
+		AppID           int
+		PlaytimeForever int
+	}
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, repo *GameOwnerRepository)
+		want  map[int]int // app ID -> playtime of steam ID "owner"
+	}{
+		{
+			name: "single upsert updates playtime",
+			setup: func(t *testing.T, repo *GameOwnerRepository) {
+				for _, playtime := range []int{10, 25} {
+					if err := repo.Upsert(730, "owner", playtime); err != nil {
+						t.Fatalf("Upsert failed: %v", err)
+					}
+				}
+			},
+			want: map[int]int{730: 25},
+		},
+		{
+			name: "batch upsert inserts and updates",
+			setup: func(t *testing.T, repo *GameOwnerRepository) {
+				if err := repo.Upsert(730, "owner", 10); err != nil {
+					t.Fatalf("Upsert failed: %v", err)
+				}
+				if err := repo.UpsertBatch("owner", []game{{730, 40}, {570, 5}}); err != nil {
+					t.Fatalf("UpsertBatch failed: %v", err)
+				}
+			},
+			want: map[int]int{730: 40, 570: 5},
+		},
+		{
+			name: "empty batch is a no-op",
+			setup: func(t *testing.T, repo *GameOwnerRepository) {
+				if err := repo.UpsertBatch("owner", nil); err != nil {
+					t.Fatalf("UpsertBatch failed: %v", err)
+				}
+			},
+			want: map[int]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtest.Run(t, func(t *testing.T) {
+				repo := NewGameOwnerRepository()
+				tt.setup(t, repo)
+
+				games, err := repo.GetGamesByUserSteamID("owner")
+				if err != nil {
+					t.Fatalf("GetGamesByUserSteamID failed: %v", err)
+				}
+				got := make(map[int]int)
+				for _, g := range games {
+					got[g.AppID] = g.PlaytimeForever
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got playtimes %v, want %v", got, tt.want)
+				}
+			})
+		})
+	}
+}
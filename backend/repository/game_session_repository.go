@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// GameSessionRepository handles planned game session database operations
+type GameSessionRepository struct{}
+
+// NewGameSessionRepository creates a new game session repository
+func NewGameSessionRepository() *GameSessionRepository {
+	return &GameSessionRepository{}
+}
+
+// sessionColumns are the columns read by scanSession
+const sessionColumns = `
+	s.id, s.app_id, s.title, s.starts_at, s.ready_at, s.created_at,
+	u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url`
+
+// scanSession scans a session row selected with sessionColumns
+func scanSession(row rowScanner) (*models.GameSession, error) {
+	var s models.GameSession
+	err := row.Scan(
+		&s.ID, &s.AppID, &s.Title, &s.StartsAt, &s.ReadyAt, &s.CreatedAt,
+		&s.CreatedBy.ID, &s.CreatedBy.SteamID, &s.CreatedBy.Username, &s.CreatedBy.AvatarURL, &s.CreatedBy.AvatarSmall, &s.CreatedBy.ProfileURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Create creates a session together with its players
+func (r *GameSessionRepository) Create(session *models.GameSession, userIDs []uint64) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO game_sessions (app_id, title, created_by, starts_at, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			session.AppID, session.Title, session.CreatedBy.ID, session.StartsAt, session.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create game session: %w", err)
+		}
+
+		for _, userID := range userIDs {
+			if _, err := tx.Exec(`INSERT INTO game_session_players (session_id, user_id) VALUES (?, ?)`, id, userID); err != nil {
+				return fmt.Errorf("failed to add player %d to game session: %w", userID, err)
+			}
+		}
+
+		session.ID = uint64(id)
+		return nil
+	})
+}
+
+// GetByID returns a session without players, or nil if it does not exist
+func (r *GameSessionRepository) GetByID(id uint64) (*models.GameSession, error) {
+	session, err := scanSession(database.DB.QueryRow(`
+		SELECT`+sessionColumns+`
+		FROM game_sessions s
+		JOIN users u ON s.created_by = u.id
+		WHERE s.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game session: %w", err)
+	}
+	return session, nil
+}
+
+// GetRecent returns the most recently planned sessions without players
+func (r *GameSessionRepository) GetRecent(limit int) ([]models.GameSession, error) {
+	rows, err := database.DB.Query(`
+		SELECT`+sessionColumns+`
+		FROM game_sessions s
+		JOIN users u ON s.created_by = u.id
+		ORDER BY s.created_at DESC, s.id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.GameSession
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game session row: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
+
+// GetAll returns all sessions with their player IDs in insertion order, for exports
+func (r *GameSessionRepository) GetAll() ([]models.ExportGameSession, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, app_id, title, created_by, starts_at, ready_at, created_at
+		FROM game_sessions
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all game sessions: %w", err)
+	}
+
+	var sessions []models.ExportGameSession
+	for rows.Next() {
+		var s models.ExportGameSession
+		if err := rows.Scan(&s.ID, &s.AppID, &s.Title, &s.CreatedBy, &s.StartsAt, &s.ReadyAt, &s.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan game session row: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	rows.Close()
+
+	// Players are loaded after the session rows are closed, see PollRepository.queryPolls
+	for i := range sessions {
+		if sessions[i].PlayerIDs, err = r.GetPlayerIDs(sessions[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return sessions, nil
+}
+
+// GetPlayerIDs returns the user IDs of all players of a session
+func (r *GameSessionRepository) GetPlayerIDs(sessionID uint64) ([]uint64, error) {
+	rows, err := database.DB.Query(`
+		SELECT user_id FROM game_session_players
+		WHERE session_id = ?
+		ORDER BY user_id`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game session players: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uint64
+	for rows.Next() {
+		var userID uint64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan game session player: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+// GetIDsByPlayer returns the IDs of all sessions of a game the user takes part in
+func (r *GameSessionRepository) GetIDsByPlayer(appID int, userID uint64) ([]uint64, error) {
+	rows, err := database.DB.Query(`
+		SELECT s.id FROM game_sessions s
+		JOIN game_session_players p ON p.session_id = s.id
+		WHERE s.app_id = ? AND p.user_id = ?
+		ORDER BY s.id`, appID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game sessions of player: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan game session id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// SetReadyAt stores when all players became ready, or clears it with nil
+// Returns false if the value was already set that way, so session_ready is only sent once.
+func (r *GameSessionRepository) SetReadyAt(sessionID uint64, readyAt *time.Time) (bool, error) {
+	query := `UPDATE game_sessions SET ready_at = ? WHERE id = ? AND ready_at IS NULL`
+	if readyAt == nil {
+		query = `UPDATE game_sessions SET ready_at = ? WHERE id = ? AND ready_at IS NOT NULL`
+	}
+
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(query, readyAt, sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to update game session readiness: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Delete deletes a session and its player list
+func (r *GameSessionRepository) Delete(sessionID uint64) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM game_session_players WHERE session_id = ?`, sessionID); err != nil {
+			return fmt.Errorf("failed to delete game session players: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM game_sessions WHERE id = ?`, sessionID); err != nil {
+			return fmt.Errorf("failed to delete game session: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// voteFixture describes a vote to insert before a test
+type voteFixture struct {
+	from, to    string // usernames
+	achievement string
+	points      int
+	at          time.Duration // offset from dbtest.Timestamp(0)
+	invalidated bool
+}
+
+// createUsers creates one user per username and returns them by username
+func createUsers(t *testing.T, usernames ...string) map[string]*models.User {
+	t.Helper()
+
+	repo := NewUserRepository()
+	users := make(map[string]*models.User, len(usernames))
+	for _, username := range usernames {
+		user := &models.User{
+			SteamID:      "7656119" + username,
+			Username:     username,
+			LastCreditAt: time.Now().UTC(),
+		}
+		if err := repo.Create(user); err != nil {
+			t.Fatalf("failed to create user %s: %v", username, err)
+		}
+		users[username] = user
+	}
+	return users
+}
+
+// createVotes inserts votes with explicit timestamps
+func createVotes(t *testing.T, users map[string]*models.User, votes []voteFixture) {
+	t.Helper()
+
+	for _, v := range votes {
+		dbtest.Exec(t, `
+			INSERT INTO votes (from_user_id, to_user_id, achievement_id, points, is_invalidated, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			users[v.from].ID, users[v.to].ID, v.achievement, v.points, v.invalidated, dbtest.Timestamp(v.at),
+		)
+	}
+}
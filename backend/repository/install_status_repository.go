@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// InstallStatusRepository handles per-player install status database operations
+type InstallStatusRepository struct{}
+
+// NewInstallStatusRepository creates a new install status repository
+func NewInstallStatusRepository() *InstallStatusRepository {
+	return &InstallStatusRepository{}
+}
+
+// Set creates or updates the install status of a game for a player
+func (r *InstallStatusRepository) Set(userID uint64, appID int, status models.InstallStatus, progress int) error {
+	return database.WithRetry(func() error {
+		query := database.UpsertSQL("game_install_status",
+			[]string{"user_id", "app_id", "status", "progress", "updated_at"}, "?, ?, ?, ?, CURRENT_TIMESTAMP",
+			[]string{"user_id", "app_id"},
+			[]string{"status", "progress", "updated_at"})
+
+		if _, err := database.DB.Exec(query, userID, appID, string(status), progress); err != nil {
+			return fmt.Errorf("failed to set install status: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes the install status of a game for a player (= not installed)
+func (r *InstallStatusRepository) Delete(userID uint64, appID int) error {
+	return database.WithRetry(func() error {
+		_, err := database.DB.Exec(`DELETE FROM game_install_status WHERE user_id = ? AND app_id = ?`, userID, appID)
+		if err != nil {
+			return fmt.Errorf("failed to delete install status: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByUserID returns all install statuses of a player
+func (r *InstallStatusRepository) GetByUserID(userID uint64) ([]models.GameInstallStatus, error) {
+	return r.query(`
+		SELECT user_id, app_id, status, progress, updated_at
+		FROM game_install_status
+		WHERE user_id = ?
+		ORDER BY app_id`, userID)
+}
+
+// GetByAppID returns the install statuses of all players for a game
+func (r *InstallStatusRepository) GetByAppID(appID int) ([]models.GameInstallStatus, error) {
+	return r.query(`
+		SELECT user_id, app_id, status, progress, updated_at
+		FROM game_install_status
+		WHERE app_id = ?
+		ORDER BY user_id`, appID)
+}
+
+// GetAll returns the install statuses of all players and games, for exports
+func (r *InstallStatusRepository) GetAll() ([]models.GameInstallStatus, error) {
+	return r.query(`
+		SELECT user_id, app_id, status, progress, updated_at
+		FROM game_install_status
+		ORDER BY user_id, app_id`)
+}
+
+// query runs a select on game_install_status and scans all rows
+func (r *InstallStatusRepository) query(query string, args ...interface{}) ([]models.GameInstallStatus, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get install status: %w", err)
+	}
+	defer rows.Close()
+
+	statuses := []models.GameInstallStatus{}
+	for rows.Next() {
+		var s models.GameInstallStatus
+		if err := rows.Scan(&s.UserID, &s.AppID, &s.Status, &s.Progress, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan install status row: %w", err)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
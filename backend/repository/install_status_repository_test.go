@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestInstallStatus(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewInstallStatusRepository()
+		users := createUsers(t, "alice", "bob")
+
+		steps := []struct {
+			user     string
+			status   models.InstallStatus
+			progress int
+		}{
+			{"alice", models.InstallStatusDownloading, 40},
+			{"alice", models.InstallStatusReady, 0}, // updates the existing row
+			{"bob", models.InstallStatusInstalled, 0},
+			{"bob", models.InstallStatusNone, 0}, // deletes the row
+		}
+		for _, step := range steps {
+			var err error
+			if step.status == models.InstallStatusNone {
+				err = repo.Delete(users[step.user].ID, 730)
+			} else {
+				err = repo.Set(users[step.user].ID, 730, step.status, step.progress)
+			}
+			if err != nil {
+				t.Fatalf("failed to set %s to %s: %v", step.user, step.status, err)
+			}
+		}
+
+		statuses, err := repo.GetByAppID(730)
+		if err != nil {
+			t.Fatalf("GetByAppID failed: %v", err)
+		}
+		got := make(map[uint64]models.InstallStatus)
+		for _, status := range statuses {
+			got[status.UserID] = status.Status
+		}
+		want := map[uint64]models.InstallStatus{users["alice"].ID: models.InstallStatusReady}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got statuses %v, want %v", got, want)
+		}
+	})
+}
+
+func TestGameSessionReadyAt(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewGameSessionRepository()
+		users := createUsers(t, "alice", "bob")
+
+		session := &models.GameSession{
+			AppID:     730,
+			Title:     "Wingman",
+			CreatedBy: models.PublicUser{ID: users["alice"].ID},
+			CreatedAt: dbtest.Timestamp(0),
+		}
+		if err := repo.Create(session, []uint64{users["alice"].ID, users["bob"].ID}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		ids, err := repo.GetIDsByPlayer(730, users["bob"].ID)
+		if err != nil || !reflect.DeepEqual(ids, []uint64{session.ID}) {
+			t.Fatalf("GetIDsByPlayer = %v, %v; want [%d]", ids, err, session.ID)
+		}
+		if ids, _ := repo.GetIDsByPlayer(570, users["bob"].ID); len(ids) != 0 {
+			t.Errorf("expected no sessions for another game, got %v", ids)
+		}
+
+		readyAt := dbtest.Timestamp(0)
+		steps := []struct {
+			name    string
+			readyAt bool
+			changed bool
+		}{
+			{"first ready", true, true},
+			{"ready again is not announced twice", true, false},
+			{"someone dropped out", false, true},
+			{"still not ready", false, false},
+			{"ready once more", true, true},
+		}
+		for _, step := range steps {
+			value := &readyAt
+			if !step.readyAt {
+				value = nil
+			}
+			changed, err := repo.SetReadyAt(session.ID, value)
+			if err != nil {
+				t.Fatalf("%s: SetReadyAt failed: %v", step.name, err)
+			}
+			if changed != step.changed {
+				t.Errorf("%s: changed = %t, want %t", step.name, changed, step.changed)
+			}
+		}
+
+		loaded, err := repo.GetByID(session.ID)
+		if err != nil || loaded == nil {
+			t.Fatalf("GetByID failed: session=%v err=%v", loaded, err)
+		}
+		if loaded.ReadyAt == nil || !loaded.ReadyAt.Equal(readyAt) || loaded.CreatedBy.Username != "alice" {
+			t.Errorf("unexpected session: %+v", loaded)
+		}
+	})
+}
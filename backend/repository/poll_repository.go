@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// PollRepository handles game poll database operations
+type PollRepository struct{}
+
+// NewPollRepository creates a new poll repository
+func NewPollRepository() *PollRepository {
+	return &PollRepository{}
+}
+
+// pollColumns are the columns read by scanPoll
+const pollColumns = `
+	p.id, p.title, p.voting_method, p.closes_at, p.closed_at, p.winner_app_id, p.created_at,
+	u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPoll scans a poll row selected with pollColumns
+func scanPoll(row rowScanner) (*models.Poll, error) {
+	var p models.Poll
+	err := row.Scan(
+		&p.ID, &p.Title, &p.VotingMethod, &p.ClosesAt, &p.ClosedAt, &p.WinnerAppID, &p.CreatedAt,
+		&p.CreatedBy.ID, &p.CreatedBy.SteamID, &p.CreatedBy.Username, &p.CreatedBy.AvatarURL, &p.CreatedBy.AvatarSmall, &p.CreatedBy.ProfileURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Create creates a poll together with its options
+func (r *PollRepository) Create(poll *models.Poll) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO polls (title, voting_method, created_by, closes_at, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			poll.Title, string(poll.VotingMethod), poll.CreatedBy.ID, poll.ClosesAt, poll.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create poll: %w", err)
+		}
+
+		for _, option := range poll.Options {
+			if _, err := tx.Exec(`
+				INSERT INTO poll_options (poll_id, app_id, name, position)
+				VALUES (?, ?, ?, ?)`,
+				id, option.AppID, option.Name, option.Position,
+			); err != nil {
+				return fmt.Errorf("failed to create poll option %d: %w", option.AppID, err)
+			}
+		}
+
+		poll.ID = uint64(id)
+		return nil
+	})
+}
+
+// GetByID returns a poll with its options, or nil if it does not exist
+func (r *PollRepository) GetByID(id uint64) (*models.Poll, error) {
+	poll, err := scanPoll(database.DB.QueryRow(`
+		SELECT`+pollColumns+`
+		FROM polls p
+		JOIN users u ON p.created_by = u.id
+		WHERE p.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if poll.Options, err = r.getOptions(poll.ID); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+// GetRecent returns all open polls followed by the most recently closed ones
+func (r *PollRepository) GetRecent(limit int) ([]models.Poll, error) {
+	return r.queryPolls(`
+		SELECT`+pollColumns+`
+		FROM polls p
+		JOIN users u ON p.created_by = u.id
+		ORDER BY CASE WHEN p.closed_at IS NULL THEN 0 ELSE 1 END, p.created_at DESC, p.id DESC
+		LIMIT ?`, limit)
+}
+
+// GetAll returns all polls with their options in insertion order, for exports
+func (r *PollRepository) GetAll() ([]models.Poll, error) {
+	return r.queryPolls(`
+		SELECT` + pollColumns + `
+		FROM polls p
+		JOIN users u ON p.created_by = u.id
+		ORDER BY p.id`)
+}
+
+// queryPolls returns the polls selected with pollColumns, each with its options
+func (r *PollRepository) queryPolls(query string, args ...interface{}) ([]models.Poll, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get polls: %w", err)
+	}
+
+	var polls []models.Poll
+	for rows.Next() {
+		poll, err := scanPoll(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan poll row: %w", err)
+		}
+		polls = append(polls, *poll)
+	}
+	rows.Close()
+
+	// Options are loaded after the poll rows are closed, so SQLite is not asked
+	// for a second query while the first one still holds the connection
+	for i := range polls {
+		if polls[i].Options, err = r.getOptions(polls[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return polls, nil
+}
+
+// GetDueForClosing returns the IDs of open polls whose timer ran out
+func (r *PollRepository) GetDueForClosing(now time.Time) ([]uint64, error) {
+	rows, err := database.DB.Query(`
+		SELECT id FROM polls
+		WHERE closed_at IS NULL AND closes_at IS NOT NULL AND closes_at <= ?
+		ORDER BY closes_at`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get polls due for closing: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan poll id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// getOptions returns the options of a poll in display order
+func (r *PollRepository) getOptions(pollID uint64) ([]models.PollOption, error) {
+	rows, err := database.DB.Query(`
+		SELECT app_id, name, position FROM poll_options
+		WHERE poll_id = ?
+		ORDER BY position`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll options: %w", err)
+	}
+	defer rows.Close()
+
+	options := []models.PollOption{}
+	for rows.Next() {
+		var option models.PollOption
+		if err := rows.Scan(&option.AppID, &option.Name, &option.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan poll option: %w", err)
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// SaveBallot replaces the ballot of a user
+// appIDs are stored in order of preference; an empty list withdraws the ballot.
+func (r *PollRepository) SaveBallot(pollID, userID uint64, appIDs []int) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM poll_ballots WHERE poll_id = ? AND user_id = ?`, pollID, userID); err != nil {
+			return fmt.Errorf("failed to delete previous ballot: %w", err)
+		}
+
+		for i, appID := range appIDs {
+			if _, err := tx.Exec(`
+				INSERT INTO poll_ballots (poll_id, user_id, app_id, preference)
+				VALUES (?, ?, ?, ?)`,
+				pollID, userID, appID, i+1,
+			); err != nil {
+				return fmt.Errorf("failed to save ballot: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetBallots returns all ballots of a poll, each with app IDs in order of preference
+func (r *PollRepository) GetBallots(pollID uint64) ([]models.PollBallot, error) {
+	rows, err := database.DB.Query(`
+		SELECT user_id, app_id FROM poll_ballots
+		WHERE poll_id = ?
+		ORDER BY user_id, preference`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ballots: %w", err)
+	}
+	defer rows.Close()
+
+	var ballots []models.PollBallot
+	for rows.Next() {
+		var userID uint64
+		var appID int
+		if err := rows.Scan(&userID, &appID); err != nil {
+			return nil, fmt.Errorf("failed to scan ballot row: %w", err)
+		}
+		if n := len(ballots); n == 0 || ballots[n-1].UserID != userID {
+			ballots = append(ballots, models.PollBallot{UserID: userID})
+		}
+		ballots[len(ballots)-1].AppIDs = append(ballots[len(ballots)-1].AppIDs, appID)
+	}
+	return ballots, nil
+}
+
+// GetAllBallots returns the single ballot rows of all polls, for exports
+func (r *PollRepository) GetAllBallots() ([]models.ExportPollBallot, error) {
+	rows, err := database.DB.Query(`
+		SELECT poll_id, user_id, app_id, preference, created_at
+		FROM poll_ballots
+		ORDER BY poll_id, user_id, preference`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all ballots: %w", err)
+	}
+	defer rows.Close()
+
+	var ballots []models.ExportPollBallot
+	for rows.Next() {
+		var ballot models.ExportPollBallot
+		if err := rows.Scan(&ballot.PollID, &ballot.UserID, &ballot.AppID, &ballot.Preference, &ballot.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ballot row: %w", err)
+		}
+		ballots = append(ballots, ballot)
+	}
+
+	return ballots, nil
+}
+
+// Close marks a poll as closed and stores the winner
+// Returns false if the poll was already closed, so a poll is only announced once.
+func (r *PollRepository) Close(pollID uint64, winnerAppID *int, closedAt time.Time) (bool, error) {
+	var affected int64
+	err := database.WithRetry(func() error {
+		result, err := database.DB.Exec(`
+			UPDATE polls SET closed_at = ?, winner_app_id = ?
+			WHERE id = ? AND closed_at IS NULL`,
+			closedAt, winnerAppID, pollID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to close poll: %w", err)
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// createPoll creates a poll by alice with Counter-Strike 2, Dota 2 and Team Fortress 2
+func createPoll(t *testing.T, users map[string]*models.User, closesAt *time.Time) *models.Poll {
+	t.Helper()
+
+	poll := &models.Poll{
+		Title:        "Next game",
+		VotingMethod: models.PollVotingRanked,
+		CreatedBy:    models.PublicUser{ID: users["alice"].ID},
+		ClosesAt:     closesAt,
+		CreatedAt:    dbtest.Timestamp(0),
+		Options: []models.PollOption{
+			{AppID: 730, Name: "Counter-Strike 2", Position: 0},
+			{AppID: 570, Name: "Dota 2", Position: 1},
+			{AppID: 440, Name: "Team Fortress 2", Position: 2},
+		},
+	}
+	if err := NewPollRepository().Create(poll); err != nil {
+		t.Fatalf("failed to create poll: %v", err)
+	}
+	return poll
+}
+
+func TestPollBallots(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewPollRepository()
+		users := createUsers(t, "alice", "bob")
+		poll := createPoll(t, users, nil)
+
+		ballots := []struct {
+			user   string
+			appIDs []int
+		}{
+			{"alice", []int{730, 570}},
+			{"bob", []int{440}},
+			{"bob", []int{570, 440, 730}}, // replaces the previous ballot
+		}
+		for _, b := range ballots {
+			if err := repo.SaveBallot(poll.ID, users[b.user].ID, b.appIDs); err != nil {
+				t.Fatalf("SaveBallot failed: %v", err)
+			}
+		}
+
+		got, err := repo.GetBallots(poll.ID)
+		if err != nil {
+			t.Fatalf("GetBallots failed: %v", err)
+		}
+		want := map[uint64][]int{
+			users["alice"].ID: {730, 570},
+			users["bob"].ID:   {570, 440, 730},
+		}
+		gotByUser := make(map[uint64][]int)
+		for _, ballot := range got {
+			gotByUser[ballot.UserID] = ballot.AppIDs
+		}
+		if !reflect.DeepEqual(gotByUser, want) {
+			t.Errorf("got ballots %v, want %v", gotByUser, want)
+		}
+
+		loaded, err := repo.GetByID(poll.ID)
+		if err != nil || loaded == nil {
+			t.Fatalf("GetByID failed: poll=%v err=%v", loaded, err)
+		}
+		if loaded.CreatedBy.Username != "alice" || loaded.VotingMethod != models.PollVotingRanked || len(loaded.Options) != 3 || loaded.Options[1].Name != "Dota 2" {
+			t.Errorf("unexpected poll: %+v", loaded)
+		}
+		if !loaded.IsOpen() || loaded.ClosesAt != nil || loaded.WinnerAppID != nil {
+			t.Errorf("expected an open poll without timer and winner: %+v", loaded)
+		}
+	})
+}
+
+func TestPollClosing(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewPollRepository()
+		users := createUsers(t, "alice")
+
+		due := dbtest.Timestamp(time.Minute)
+		later := dbtest.Timestamp(time.Hour)
+		duePoll := createPoll(t, users, &due)
+		createPoll(t, users, &later)
+		createPoll(t, users, nil)
+
+		ids, err := repo.GetDueForClosing(dbtest.Timestamp(30 * time.Minute))
+		if err != nil {
+			t.Fatalf("GetDueForClosing failed: %v", err)
+		}
+		if !reflect.DeepEqual(ids, []uint64{duePoll.ID}) {
+			t.Fatalf("got due polls %v, want [%d]", ids, duePoll.ID)
+		}
+
+		winner := 570
+		closed, err := repo.Close(duePoll.ID, &winner, dbtest.Timestamp(30*time.Minute))
+		if err != nil || !closed {
+			t.Fatalf("Close failed: closed=%t err=%v", closed, err)
+		}
+		if closed, _ := repo.Close(duePoll.ID, nil, dbtest.Timestamp(31*time.Minute)); closed {
+			t.Error("expected closing a closed poll to report false")
+		}
+
+		loaded, err := repo.GetByID(duePoll.ID)
+		if err != nil || loaded == nil {
+			t.Fatalf("GetByID failed: poll=%v err=%v", loaded, err)
+		}
+		if loaded.IsOpen() || loaded.WinnerAppID == nil || *loaded.WinnerAppID != winner {
+			t.Errorf("expected poll closed with winner %d: %+v", winner, loaded)
+		}
+		if !loaded.ClosedAt.Equal(dbtest.Timestamp(30 * time.Minute)) {
+			t.Errorf("closed_at = %v, want %v", loaded.ClosedAt, dbtest.Timestamp(30*time.Minute))
+		}
+
+		if ids, _ := repo.GetDueForClosing(dbtest.Timestamp(2 * time.Hour)); len(ids) != 1 || ids[0] == duePoll.ID {
+			t.Errorf("expected only the later poll to be due, got %v", ids)
+		}
+
+		polls, err := repo.GetRecent(10)
+		if err != nil {
+			t.Fatalf("GetRecent failed: %v", err)
+		}
+		if len(polls) != 3 || polls[2].ID != duePoll.ID {
+			t.Errorf("expected the closed poll to be listed last, got %+v", polls)
+		}
+	})
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// RatingRepository handles match history and per-game rating database operations
+type RatingRepository struct{}
+
+// NewRatingRepository creates a new rating repository
+func NewRatingRepository() *RatingRepository {
+	return &RatingRepository{}
+}
+
+// ratingColumns are the columns read by scanRating
+const ratingColumns = `
+	u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url,
+	r.app_id, r.rating, r.matches, r.wins, r.losses, r.draws, r.updated_at`
+
+// scanRating scans a player_ratings row joined with users, selected with ratingColumns
+func scanRating(row rowScanner) (*models.PlayerRating, error) {
+	var rating models.PlayerRating
+	err := row.Scan(
+		&rating.User.ID, &rating.User.SteamID, &rating.User.Username, &rating.User.AvatarURL, &rating.User.AvatarSmall, &rating.User.ProfileURL,
+		&rating.AppID, &rating.Rating, &rating.Matches, &rating.Wins, &rating.Losses, &rating.Draws, &rating.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// queryRatings runs a query selecting ratingColumns and scans all rows
+func (r *RatingRepository) queryRatings(query string, args ...interface{}) ([]models.PlayerRating, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := []models.PlayerRating{}
+	for rows.Next() {
+		rating, err := scanRating(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rating row: %w", err)
+		}
+		ratings = append(ratings, *rating)
+	}
+	return ratings, nil
+}
+
+// GetRatings returns the current ratings of the given players in a game by user ID
+// Players without a rating yet are missing from the map.
+func (r *RatingRepository) GetRatings(appID int, userIDs []uint64) (map[uint64]models.PlayerRating, error) {
+	ratings := make(map[uint64]models.PlayerRating, len(userIDs))
+	if len(userIDs) == 0 {
+		return ratings, nil
+	}
+
+	args := make([]interface{}, 0, len(userIDs)+1)
+	args = append(args, appID)
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(userIDs)), ", ")
+
+	list, err := r.queryRatings(`
+		SELECT `+ratingColumns+`
+		FROM player_ratings r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.app_id = ? AND r.user_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, rating := range list {
+		ratings[rating.User.ID] = rating
+	}
+	return ratings, nil
+}
+
+// GetLadder returns all ratings of a game, best first
+func (r *RatingRepository) GetLadder(appID int) ([]models.PlayerRating, error) {
+	return r.queryRatings(`
+		SELECT `+ratingColumns+`
+		FROM player_ratings r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.app_id = ?
+		ORDER BY r.rating DESC, r.matches DESC, u.username`, appID)
+}
+
+// GetByUserID returns the ratings of a player in all games, most played first
+func (r *RatingRepository) GetByUserID(userID uint64) ([]models.PlayerRating, error) {
+	return r.queryRatings(`
+		SELECT `+ratingColumns+`
+		FROM player_ratings r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.user_id = ?
+		ORDER BY r.matches DESC, r.app_id`, userID)
+}
+
+// GetHistory returns the rating of a player after each match of a game in the order the matches were rated
+// Matches can be recorded with an earlier played_at, so the rating chain follows the IDs instead.
+func (r *RatingRepository) GetHistory(userID uint64, appID int) ([]models.RatingHistoryEntry, error) {
+	rows, err := database.DB.Query(`
+		SELECT m.id, m.played_at, p.team, m.winner_team, p.rating_before, p.rating_after
+		FROM game_match_players p
+		JOIN game_matches m ON p.match_id = m.id
+		WHERE p.user_id = ? AND m.app_id = ?
+		ORDER BY m.id`, userID, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.RatingHistoryEntry{}
+	for rows.Next() {
+		var entry models.RatingHistoryEntry
+		if err := rows.Scan(&entry.MatchID, &entry.PlayedAt, &entry.Team, &entry.WinnerTeam, &entry.RatingBefore, &entry.RatingAfter); err != nil {
+			return nil, fmt.Errorf("failed to scan rating history row: %w", err)
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
+// GetMatches returns the most recent matches with their players, optionally of one game (appID 0 = all)
+func (r *RatingRepository) GetMatches(appID int, limit int) ([]models.GameMatch, error) {
+	query := `SELECT id, app_id, winner_team, recorded_by, played_at, created_at FROM game_matches`
+	args := []interface{}{}
+	if appID != 0 {
+		query += ` WHERE app_id = ?`
+		args = append(args, appID)
+	}
+	query += ` ORDER BY played_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matches: %w", err)
+	}
+
+	matches := []models.GameMatch{}
+	for rows.Next() {
+		var m models.GameMatch
+		if err := rows.Scan(&m.ID, &m.AppID, &m.WinnerTeam, &m.RecordedBy, &m.PlayedAt, &m.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan match row: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	rows.Close()
+
+	for i := range matches {
+		if matches[i].Players, err = r.getMatchPlayers(matches[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// GetAllMatches returns all matches with their players in the order they were rated, for exports
+func (r *RatingRepository) GetAllMatches() ([]models.GameMatch, error) {
+	rows, err := database.DB.Query(`SELECT id, app_id, winner_team, recorded_by, played_at, created_at FROM game_matches ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all matches: %w", err)
+	}
+
+	var matches []models.GameMatch
+	for rows.Next() {
+		var m models.GameMatch
+		if err := rows.Scan(&m.ID, &m.AppID, &m.WinnerTeam, &m.RecordedBy, &m.PlayedAt, &m.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan match row: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	rows.Close()
+
+	for i := range matches {
+		if matches[i].Players, err = r.getMatchPlayers(matches[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// GetAllRatings returns the ratings of all players in all games, for exports
+func (r *RatingRepository) GetAllRatings() ([]models.PlayerRating, error) {
+	return r.queryRatings(`
+		SELECT ` + ratingColumns + `
+		FROM player_ratings r
+		JOIN users u ON r.user_id = u.id
+		ORDER BY r.app_id, u.id`)
+}
+
+// getMatchPlayers returns the players of a match ordered by team
+func (r *RatingRepository) getMatchPlayers(matchID uint64) ([]models.MatchPlayer, error) {
+	rows, err := database.DB.Query(`
+		SELECT u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url,
+			p.team, p.rating_before, p.rating_after
+		FROM game_match_players p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.match_id = ?
+		ORDER BY p.team, u.username`, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match players: %w", err)
+	}
+	defer rows.Close()
+
+	players := []models.MatchPlayer{}
+	for rows.Next() {
+		var p models.MatchPlayer
+		err := rows.Scan(
+			&p.User.ID, &p.User.SteamID, &p.User.Username, &p.User.AvatarURL, &p.User.AvatarSmall, &p.User.ProfileURL,
+			&p.Team, &p.RatingBefore, &p.RatingAfter,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan match player: %w", err)
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// RecordMatch stores a match with its players and the new ratings in one transaction
+func (r *RatingRepository) RecordMatch(match *models.GameMatch, ratings []models.PlayerRating) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		id, err := database.InsertReturningID(tx, `
+			INSERT INTO game_matches (app_id, winner_team, recorded_by, played_at, created_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			match.AppID, match.WinnerTeam, match.RecordedBy, match.PlayedAt, match.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create match: %w", err)
+		}
+
+		for _, p := range match.Players {
+			_, err := tx.Exec(`
+				INSERT INTO game_match_players (match_id, user_id, team, rating_before, rating_after)
+				VALUES (?, ?, ?, ?, ?)`, id, p.User.ID, p.Team, p.RatingBefore, p.RatingAfter)
+			if err != nil {
+				return fmt.Errorf("failed to add match player: %w", err)
+			}
+		}
+
+		query := database.UpsertSQL("player_ratings",
+			[]string{"user_id", "app_id", "rating", "matches", "wins", "losses", "draws", "updated_at"}, "",
+			[]string{"user_id", "app_id"},
+			[]string{"rating", "matches", "wins", "losses", "draws", "updated_at"})
+		for _, rating := range ratings {
+			_, err := tx.Exec(query,
+				rating.User.ID, rating.AppID, rating.Rating, rating.Matches, rating.Wins, rating.Losses, rating.Draws, rating.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to update rating: %w", err)
+			}
+		}
+
+		match.ID = uint64(id)
+		return nil
+	})
+}
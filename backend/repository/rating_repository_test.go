@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestRecordMatch(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewRatingRepository()
+		users := createUsers(t, "alice", "bob")
+
+		// alice beats bob twice; the second match updates the existing ratings
+		results := [][2]float64{{1016, 984}, {1030.5, 969.5}}
+		for i, result := range results {
+			winner := 1
+			match := &models.GameMatch{
+				AppID:      730,
+				WinnerTeam: &winner,
+				RecordedBy: users["alice"].ID,
+				PlayedAt:   dbtest.Timestamp(time.Duration(i) * time.Hour),
+				CreatedAt:  dbtest.Timestamp(0),
+				Players: []models.MatchPlayer{
+					{User: users["alice"].ToPublic(), Team: 1, RatingBefore: 1000, RatingAfter: result[0]},
+					{User: users["bob"].ToPublic(), Team: 2, RatingBefore: 1000, RatingAfter: result[1]},
+				},
+			}
+			ratings := []models.PlayerRating{
+				{User: users["alice"].ToPublic(), AppID: 730, Rating: result[0], Matches: i + 1, Wins: i + 1, UpdatedAt: dbtest.Timestamp(0)},
+				{User: users["bob"].ToPublic(), AppID: 730, Rating: result[1], Matches: i + 1, Losses: i + 1, UpdatedAt: dbtest.Timestamp(0)},
+			}
+			if err := repo.RecordMatch(match, ratings); err != nil {
+				t.Fatalf("RecordMatch %d failed: %v", i+1, err)
+			}
+			if match.ID == 0 {
+				t.Fatalf("match %d did not get an ID", i+1)
+			}
+		}
+
+		ladder, err := repo.GetLadder(730)
+		if err != nil {
+			t.Fatalf("GetLadder failed: %v", err)
+		}
+		if len(ladder) != 2 || ladder[0].User.Username != "alice" || ladder[0].Rating != 1030.5 || ladder[0].Wins != 2 || ladder[1].Losses != 2 {
+			t.Fatalf("unexpected ladder: %+v", ladder)
+		}
+
+		current, err := repo.GetRatings(730, []uint64{users["bob"].ID})
+		if err != nil || len(current) != 1 || current[users["bob"].ID].Rating != 969.5 {
+			t.Fatalf("GetRatings = %+v, %v", current, err)
+		}
+		if other, _ := repo.GetRatings(570, []uint64{users["bob"].ID}); len(other) != 0 {
+			t.Errorf("expected no ratings for another game, got %+v", other)
+		}
+
+		history, err := repo.GetHistory(users["bob"].ID, 730)
+		if err != nil {
+			t.Fatalf("GetHistory failed: %v", err)
+		}
+		if len(history) != 2 || history[0].RatingAfter != 984 || history[1].RatingAfter != 969.5 || history[1].WinnerTeam == nil || *history[1].WinnerTeam != 1 {
+			t.Errorf("unexpected history: %+v", history)
+		}
+
+		matches, err := repo.GetMatches(0, 1)
+		if err != nil {
+			t.Fatalf("GetMatches failed: %v", err)
+		}
+		if len(matches) != 1 || !matches[0].PlayedAt.Equal(dbtest.Timestamp(time.Hour)) || len(matches[0].Players) != 2 || matches[0].Players[0].Team != 1 {
+			t.Errorf("unexpected latest match: %+v", matches)
+		}
+	})
+}
+
+func TestRatingHistoryFollowsRecordingOrder(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewRatingRepository()
+		users := createUsers(t, "alice", "bob")
+
+		// The second match is backdated before the first, but was rated after it
+		results := []struct {
+			playedAt time.Duration
+			before   float64
+			after    float64
+		}{{time.Hour, 1000, 1016}, {0, 1016, 1030.5}}
+		for _, result := range results {
+			match := &models.GameMatch{
+				AppID:      730,
+				RecordedBy: users["alice"].ID,
+				PlayedAt:   dbtest.Timestamp(result.playedAt),
+				CreatedAt:  dbtest.Timestamp(time.Hour),
+				Players:    []models.MatchPlayer{{User: users["alice"].ToPublic(), Team: 1, RatingBefore: result.before, RatingAfter: result.after}},
+			}
+			if err := repo.RecordMatch(match, nil); err != nil {
+				t.Fatalf("RecordMatch failed: %v", err)
+			}
+		}
+
+		history, err := repo.GetHistory(users["alice"].ID, 730)
+		if err != nil {
+			t.Fatalf("GetHistory failed: %v", err)
+		}
+		if len(history) != 2 || history[0].RatingAfter != 1016 || history[1].RatingBefore != 1016 {
+			t.Errorf("expected the history in rating order, got %+v", history)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// SyncJobRepository handles the persisted state of background sync jobs
+type SyncJobRepository struct{}
+
+// NewSyncJobRepository creates a new sync job repository
+func NewSyncJobRepository() *SyncJobRepository {
+	return &SyncJobRepository{}
+}
+
+// Get returns the state of a job, or nil if it never ran
+func (r *SyncJobRepository) Get(name string) (*models.SyncJob, error) {
+	var job models.SyncJob
+	err := database.DB.QueryRow(`
+		SELECT name, status, processed, total, failures, paused_until, last_error, started_at, finished_at, updated_at
+		FROM sync_jobs WHERE name = ?`, name,
+	).Scan(&job.Name, &job.Status, &job.Processed, &job.Total, &job.Failures, &job.PausedUntil, &job.LastError,
+		&job.StartedAt, &job.FinishedAt, &job.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync job: %w", err)
+	}
+	return &job, nil
+}
+
+// Save creates or updates the state of a job
+func (r *SyncJobRepository) Save(job *models.SyncJob) error {
+	return database.WithRetry(func() error {
+		query := database.UpsertSQL("sync_jobs",
+			[]string{"name", "status", "processed", "total", "failures", "paused_until", "last_error", "started_at", "finished_at", "updated_at"}, "",
+			[]string{"name"},
+			[]string{"status", "processed", "total", "failures", "paused_until", "last_error", "started_at", "finished_at", "updated_at"})
+
+		_, err := database.DB.Exec(query,
+			job.Name, string(job.Status), job.Processed, job.Total, job.Failures, job.PausedUntil, job.LastError,
+			job.StartedAt, job.FinishedAt, job.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save sync job: %w", err)
+		}
+		return nil
+	})
+}
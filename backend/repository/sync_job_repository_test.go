@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestSyncJobSave(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewSyncJobRepository()
+
+		job, err := repo.Get("game_library")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if job != nil {
+			t.Fatalf("expected no job before the first save, got %+v", job)
+		}
+
+		started := dbtest.Timestamp(0)
+		pausedUntil := dbtest.Timestamp(5 * time.Minute)
+		job = &models.SyncJob{
+			Name:        "game_library",
+			Status:      models.SyncJobPaused,
+			Processed:   40,
+			Total:       120,
+			Failures:    2,
+			PausedUntil: &pausedUntil,
+			LastError:   "rate limited (429)",
+			StartedAt:   &started,
+			UpdatedAt:   dbtest.Timestamp(time.Minute),
+		}
+		if err := repo.Save(job); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		// Saving again updates the job in place
+		finished := dbtest.Timestamp(time.Hour)
+		job.Status = models.SyncJobIdle
+		job.Processed, job.Total, job.Failures = 0, 0, 0
+		job.PausedUntil = nil
+		job.FinishedAt = &finished
+		job.UpdatedAt = finished
+		if err := repo.Save(job); err != nil {
+			t.Fatalf("second Save failed: %v", err)
+		}
+
+		got, err := repo.Get("game_library")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if got == nil {
+			t.Fatal("expected the saved job")
+		}
+		if got.Status != models.SyncJobIdle || got.Processed != 0 || got.Failures != 0 || got.LastError != "rate limited (429)" {
+			t.Errorf("unexpected job state: %+v", got)
+		}
+		if got.PausedUntil != nil {
+			t.Errorf("expected paused_until to be cleared, got %v", got.PausedUntil)
+		}
+		if got.StartedAt == nil || !got.StartedAt.Equal(started) {
+			t.Errorf("started_at = %v, want %v", got.StartedAt, started)
+		}
+		if got.FinishedAt == nil || !got.FinishedAt.Equal(finished) {
+			t.Errorf("finished_at = %v, want %v", got.FinishedAt, finished)
+		}
+	})
+}
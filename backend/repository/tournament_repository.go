@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// TournamentRepository handles tournament database operations
+type TournamentRepository struct{}
+
+// NewTournamentRepository creates a new tournament repository
+func NewTournamentRepository() *TournamentRepository {
+	return &TournamentRepository{}
+}
+
+// tournamentColumns are the columns read by scanTournament
+const tournamentColumns = `
+	id, name, app_id, format, team_size, status, bonus_points, created_by,
+	winner_team_id, created_at, started_at, finished_at`
+
+// matchColumns are the columns read by GetMatches
+const matchColumns = `
+	id, bracket, round, position, team1_id, team2_id, score1, score2, winner_team_id,
+	status, is_bye, reported_by, winner_next_match_id, winner_next_slot, loser_next_match_id, loser_next_slot`
+
+// scanTournament scans a tournament row selected with tournamentColumns
+func scanTournament(row rowScanner) (*models.Tournament, error) {
+	var t models.Tournament
+	err := row.Scan(
+		&t.ID, &t.Name, &t.AppID, &t.Format, &t.TeamSize, &t.Status, &t.BonusPoints, &t.CreatedBy,
+		&t.WinnerTeamID, &t.CreatedAt, &t.StartedAt, &t.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create creates a tournament in registration state
+func (r *TournamentRepository) Create(t *models.Tournament) error {
+	return database.WithRetry(func() error {
+		id, err := database.InsertReturningID(database.DB, `
+			INSERT INTO tournaments (name, app_id, format, team_size, status, bonus_points, created_by, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			t.Name, t.AppID, string(t.Format), t.TeamSize, string(t.Status), t.BonusPoints, t.CreatedBy, t.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create tournament: %w", err)
+		}
+
+		t.ID = uint64(id)
+		return nil
+	})
+}
+
+// GetByID returns a tournament with players, teams and matches, or nil if it does not exist
+func (r *TournamentRepository) GetByID(id uint64) (*models.Tournament, error) {
+	t, err := scanTournament(database.DB.QueryRow(`SELECT `+tournamentColumns+` FROM tournaments WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament: %w", err)
+	}
+
+	if t.Players, err = r.getPlayers(t.ID); err != nil {
+		return nil, err
+	}
+	if t.Teams, err = r.getTeams(t.ID); err != nil {
+		return nil, err
+	}
+	if t.Matches, err = r.GetMatches(t.ID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetAll returns all tournaments with their registered players, newest first
+func (r *TournamentRepository) GetAll() ([]models.Tournament, error) {
+	rows, err := database.DB.Query(`SELECT ` + tournamentColumns + ` FROM tournaments ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournaments: %w", err)
+	}
+
+	var tournaments []models.Tournament
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan tournament row: %w", err)
+		}
+		tournaments = append(tournaments, *t)
+	}
+	rows.Close()
+
+	for i := range tournaments {
+		if tournaments[i].Players, err = r.getPlayers(tournaments[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return tournaments, nil
+}
+
+// GetAllForExport returns all tournaments in insertion order with their registrations, teams and matches,
+// referencing players by user ID only
+func (r *TournamentRepository) GetAllForExport() ([]models.ExportTournament, error) {
+	rows, err := database.DB.Query(`SELECT ` + tournamentColumns + ` FROM tournaments ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournaments: %w", err)
+	}
+
+	var tournaments []models.ExportTournament
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan tournament row: %w", err)
+		}
+		tournaments = append(tournaments, models.ExportTournament{
+			ID:           t.ID,
+			Name:         t.Name,
+			AppID:        t.AppID,
+			Format:       t.Format,
+			TeamSize:     t.TeamSize,
+			Status:       t.Status,
+			BonusPoints:  t.BonusPoints,
+			CreatedBy:    t.CreatedBy,
+			WinnerTeamID: t.WinnerTeamID,
+			CreatedAt:    t.CreatedAt,
+			StartedAt:    t.StartedAt,
+			FinishedAt:   t.FinishedAt,
+		})
+	}
+	rows.Close()
+
+	for i := range tournaments {
+		t := &tournaments[i]
+		if t.Registrations, err = r.getRegistrations(t.ID); err != nil {
+			return nil, err
+		}
+		if t.Teams, err = r.getExportTeams(t.ID); err != nil {
+			return nil, err
+		}
+		if t.Matches, err = r.GetMatches(t.ID); err != nil {
+			return nil, err
+		}
+	}
+	return tournaments, nil
+}
+
+// getRegistrations returns the registrations of a tournament in registration order
+func (r *TournamentRepository) getRegistrations(tournamentID uint64) ([]models.ExportTournamentRegistration, error) {
+	rows, err := database.DB.Query(`
+		SELECT user_id, created_at FROM tournament_registrations
+		WHERE tournament_id = ?
+		ORDER BY created_at, user_id`, tournamentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament registrations: %w", err)
+	}
+	defer rows.Close()
+
+	registrations := []models.ExportTournamentRegistration{}
+	for rows.Next() {
+		var registration models.ExportTournamentRegistration
+		if err := rows.Scan(&registration.UserID, &registration.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tournament registration: %w", err)
+		}
+		registrations = append(registrations, registration)
+	}
+	return registrations, nil
+}
+
+// getExportTeams returns the teams of a tournament with the user IDs of their members in seed order
+func (r *TournamentRepository) getExportTeams(tournamentID uint64) ([]models.ExportTournamentTeam, error) {
+	teams, err := r.getTeams(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	exportTeams := make([]models.ExportTournamentTeam, 0, len(teams))
+	for _, team := range teams {
+		memberIDs := make([]uint64, 0, len(team.Members))
+		for _, member := range team.Members {
+			memberIDs = append(memberIDs, member.ID)
+		}
+		exportTeams = append(exportTeams, models.ExportTournamentTeam{ID: team.ID, Name: team.Name, Seed: team.Seed, MemberIDs: memberIDs})
+	}
+	return exportTeams, nil
+}
+
+// getPlayers returns the registered players of a tournament in registration order
+func (r *TournamentRepository) getPlayers(tournamentID uint64) ([]models.PublicUser, error) {
+	return queryPublicUsers(`
+		SELECT u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url
+		FROM tournament_registrations tr
+		JOIN users u ON tr.user_id = u.id
+		WHERE tr.tournament_id = ?
+		ORDER BY tr.created_at, u.id`, tournamentID)
+}
+
+// getTeams returns the teams of a tournament with their members in seed order
+func (r *TournamentRepository) getTeams(tournamentID uint64) ([]models.TournamentTeam, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, name, seed FROM tournament_teams
+		WHERE tournament_id = ?
+		ORDER BY seed, id`, tournamentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament teams: %w", err)
+	}
+
+	teams := []models.TournamentTeam{}
+	for rows.Next() {
+		var team models.TournamentTeam
+		if err := rows.Scan(&team.ID, &team.Name, &team.Seed); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan tournament team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+	rows.Close()
+
+	for i := range teams {
+		teams[i].Members, err = queryPublicUsers(`
+			SELECT u.id, u.steam_id, u.username, u.avatar_url, u.avatar_small, u.profile_url
+			FROM tournament_team_members m
+			JOIN users u ON m.user_id = u.id
+			WHERE m.team_id = ?
+			ORDER BY u.username`, teams[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return teams, nil
+}
+
+// GetMatches returns all matches of a tournament in bracket order
+func (r *TournamentRepository) GetMatches(tournamentID uint64) ([]models.TournamentMatch, error) {
+	rows, err := database.DB.Query(`
+		SELECT `+matchColumns+` FROM tournament_matches
+		WHERE tournament_id = ?
+		ORDER BY id`, tournamentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []models.TournamentMatch{}
+	for rows.Next() {
+		var m models.TournamentMatch
+		err := rows.Scan(
+			&m.ID, &m.Bracket, &m.Round, &m.Position, &m.Team1ID, &m.Team2ID, &m.Score1, &m.Score2, &m.WinnerTeamID,
+			&m.Status, &m.IsBye, &m.ReportedBy, &m.WinnerNextMatchID, &m.WinnerNextSlot, &m.LoserNextMatchID, &m.LoserNextSlot,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tournament match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// IsRegistered checks if a user is registered for a tournament
+func (r *TournamentRepository) IsRegistered(tournamentID, userID uint64) (bool, error) {
+	var count int
+	err := database.DB.QueryRow(`
+		SELECT COUNT(*) FROM tournament_registrations
+		WHERE tournament_id = ? AND user_id = ?`, tournamentID, userID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check tournament registration: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Register signs a user up for a tournament
+func (r *TournamentRepository) Register(tournamentID, userID uint64) error {
+	return database.WithRetry(func() error {
+		_, err := database.DB.Exec(`
+			INSERT INTO tournament_registrations (tournament_id, user_id, created_at)
+			VALUES (?, ?, ?)`, tournamentID, userID, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to register for tournament: %w", err)
+		}
+		return nil
+	})
+}
+
+// Unregister removes the registration of a user
+func (r *TournamentRepository) Unregister(tournamentID, userID uint64) error {
+	return database.WithRetry(func() error {
+		_, err := database.DB.Exec(`
+			DELETE FROM tournament_registrations
+			WHERE tournament_id = ? AND user_id = ?`, tournamentID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to unregister from tournament: %w", err)
+		}
+		return nil
+	})
+}
+
+// Start stores the teams and the bracket and moves the tournament to running
+// build receives the stored team IDs in seed order and returns matches with temporary IDs
+// (see services.BuildBracket), which are replaced by the stored IDs.
+func (r *TournamentRepository) Start(tournamentID uint64, teams []models.TournamentTeam, build func(teamIDs []uint64) []models.TournamentMatch, startedAt time.Time) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		teamIDs := make([]uint64, 0, len(teams))
+		for _, team := range teams {
+			id, err := database.InsertReturningID(tx, `
+				INSERT INTO tournament_teams (tournament_id, name, seed)
+				VALUES (?, ?, ?)`, tournamentID, team.Name, team.Seed)
+			if err != nil {
+				return fmt.Errorf("failed to create tournament team: %w", err)
+			}
+			for _, member := range team.Members {
+				if _, err := tx.Exec(`INSERT INTO tournament_team_members (team_id, user_id) VALUES (?, ?)`, id, member.ID); err != nil {
+					return fmt.Errorf("failed to add team member: %w", err)
+				}
+			}
+			teamIDs = append(teamIDs, uint64(id))
+		}
+
+		matches := build(teamIDs)
+		matchIDs := make(map[uint64]uint64, len(matches))
+		for _, m := range matches {
+			id, err := database.InsertReturningID(tx, `
+				INSERT INTO tournament_matches (tournament_id, bracket, round, position, status)
+				VALUES (?, ?, ?, ?, ?)`, tournamentID, m.Bracket, m.Round, m.Position, string(m.Status))
+			if err != nil {
+				return fmt.Errorf("failed to create tournament match: %w", err)
+			}
+			matchIDs[m.ID] = uint64(id)
+		}
+
+		// Links can only be stored once every match has its real ID
+		for i := range matches {
+			m := &matches[i]
+			m.ID = matchIDs[m.ID]
+			if m.WinnerNextMatchID != nil {
+				id := matchIDs[*m.WinnerNextMatchID]
+				m.WinnerNextMatchID = &id
+			}
+			if m.LoserNextMatchID != nil {
+				id := matchIDs[*m.LoserNextMatchID]
+				m.LoserNextMatchID = &id
+			}
+			if err := updateMatch(tx, m, true); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(`
+			UPDATE tournaments SET status = ?, started_at = ?
+			WHERE id = ?`, string(models.TournamentStatusRunning), startedAt, tournamentID); err != nil {
+			return fmt.Errorf("failed to start tournament: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveMatches stores the teams, scores and state of matches after a result was reported or confirmed
+func (r *TournamentRepository) SaveMatches(matches []models.TournamentMatch) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		for i := range matches {
+			if err := updateMatch(tx, &matches[i], false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// updateMatch writes the mutable fields of a match, and its links if withLinks is set
+func updateMatch(tx *sql.Tx, m *models.TournamentMatch, withLinks bool) error {
+	_, err := tx.Exec(`
+		UPDATE tournament_matches SET
+			team1_id = ?, team2_id = ?, score1 = ?, score2 = ?, winner_team_id = ?,
+			status = ?, is_bye = ?, reported_by = ?
+		WHERE id = ?`,
+		m.Team1ID, m.Team2ID, m.Score1, m.Score2, m.WinnerTeamID,
+		string(m.Status), m.IsBye, m.ReportedBy, m.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update tournament match: %w", err)
+	}
+
+	if withLinks {
+		_, err = tx.Exec(`
+			UPDATE tournament_matches SET
+				winner_next_match_id = ?, winner_next_slot = ?, loser_next_match_id = ?, loser_next_slot = ?
+			WHERE id = ?`,
+			m.WinnerNextMatchID, m.WinnerNextSlot, m.LoserNextMatchID, m.LoserNextSlot, m.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to link tournament match: %w", err)
+		}
+	}
+	return nil
+}
+
+// Finish marks a tournament as finished and stores the winning team
+func (r *TournamentRepository) Finish(tournamentID uint64, winnerTeamID *uint64, finishedAt time.Time) error {
+	return database.WithRetry(func() error {
+		_, err := database.DB.Exec(`
+			UPDATE tournaments SET status = ?, winner_team_id = ?, finished_at = ?
+			WHERE id = ?`, string(models.TournamentStatusFinished), winnerTeamID, finishedAt, tournamentID)
+		if err != nil {
+			return fmt.Errorf("failed to finish tournament: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete deletes a tournament with all registrations, teams and matches
+func (r *TournamentRepository) Delete(tournamentID uint64) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		statements := []string{
+			`DELETE FROM tournament_matches WHERE tournament_id = ?`,
+			`DELETE FROM tournament_team_members WHERE team_id IN (SELECT id FROM tournament_teams WHERE tournament_id = ?)`,
+			`DELETE FROM tournament_teams WHERE tournament_id = ?`,
+			`DELETE FROM tournament_registrations WHERE tournament_id = ?`,
+			`DELETE FROM tournaments WHERE id = ?`,
+		}
+		for _, stmt := range statements {
+			if _, err := tx.Exec(stmt, tournamentID); err != nil {
+				return fmt.Errorf("failed to delete tournament: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// queryPublicUsers runs a query selecting the public user columns and scans all rows
+func queryPublicUsers(query string, args ...interface{}) ([]models.PublicUser, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.PublicUser{}
+	for rows.Next() {
+		var u models.PublicUser
+		if err := rows.Scan(&u.ID, &u.SteamID, &u.Username, &u.AvatarURL, &u.AvatarSmall, &u.ProfileURL); err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestTournamentLifecycle(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewTournamentRepository()
+		users := createUsers(t, "alice", "bob", "carol")
+
+		tournament := &models.Tournament{
+			Name:        "Aim Cup",
+			AppID:       730,
+			Format:      models.TournamentSingleElimination,
+			TeamSize:    2,
+			Status:      models.TournamentStatusRegistration,
+			BonusPoints: 10,
+			CreatedBy:   users["alice"].ID,
+			CreatedAt:   dbtest.Timestamp(0),
+		}
+		if err := repo.Create(tournament); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		for _, username := range []string{"alice", "bob", "carol"} {
+			if err := repo.Register(tournament.ID, users[username].ID); err != nil {
+				t.Fatalf("failed to register %s: %v", username, err)
+			}
+		}
+		if registered, err := repo.IsRegistered(tournament.ID, users["bob"].ID); err != nil || !registered {
+			t.Fatalf("IsRegistered = %t, %v; want true", registered, err)
+		}
+
+		teams := []models.TournamentTeam{
+			{Name: "Team alice", Seed: 1, Members: []models.PublicUser{{ID: users["alice"].ID}, {ID: users["bob"].ID}}},
+			{Name: "carol", Seed: 2, Members: []models.PublicUser{{ID: users["carol"].ID}}},
+		}
+		// A match with temporary ID 1 whose winner moves on to match 2
+		build := func(teamIDs []uint64) []models.TournamentMatch {
+			next := uint64(2)
+			return []models.TournamentMatch{
+				{ID: 1, Bracket: models.BracketWinners, Round: 1, Team1ID: &teamIDs[0], Team2ID: &teamIDs[1],
+					Status: models.MatchStatusPending, WinnerNextMatchID: &next, WinnerNextSlot: 1},
+				{ID: 2, Bracket: models.BracketWinners, Round: 2, Status: models.MatchStatusPending},
+			}
+		}
+		if err := repo.Start(tournament.ID, teams, build, dbtest.Timestamp(0)); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+
+		loaded, err := repo.GetByID(tournament.ID)
+		if err != nil || loaded == nil {
+			t.Fatalf("GetByID failed: tournament=%v err=%v", loaded, err)
+		}
+		if loaded.Status != models.TournamentStatusRunning || len(loaded.Players) != 3 || len(loaded.Teams) != 2 || len(loaded.Teams[0].Members) != 2 {
+			t.Fatalf("unexpected tournament after start: %+v", loaded)
+		}
+		if len(loaded.Matches) != 2 {
+			t.Fatalf("got %d matches, want 2", len(loaded.Matches))
+		}
+		first, final := loaded.Matches[0], loaded.Matches[1]
+		if first.WinnerNextMatchID == nil || *first.WinnerNextMatchID != final.ID || first.WinnerNextSlot != 1 {
+			t.Errorf("first match is not linked to the final: %+v", first)
+		}
+		if first.Team1ID == nil || *first.Team1ID != loaded.Teams[0].ID {
+			t.Errorf("first match does not start with the top seed: %+v", first)
+		}
+
+		// Team alice wins, which is worth 10 ranking points for alice and bob
+		winner := loaded.Teams[0].ID
+		score1, score2 := 2, 0
+		first.Score1, first.Score2, first.WinnerTeamID = &score1, &score2, &winner
+		first.Status = models.MatchStatusConfirmed
+		if err := repo.SaveMatches([]models.TournamentMatch{first}); err != nil {
+			t.Fatalf("SaveMatches failed: %v", err)
+		}
+		if err := repo.Finish(tournament.ID, &winner, dbtest.Timestamp(0)); err != nil {
+			t.Fatalf("Finish failed: %v", err)
+		}
+
+		matches, err := repo.GetMatches(tournament.ID)
+		if err != nil || matches[0].Status != models.MatchStatusConfirmed || matches[0].Score1 == nil || *matches[0].Score1 != 2 {
+			t.Fatalf("result was not saved: %+v, %v", matches, err)
+		}
+
+		assertTournamentPoints(t, users, map[string]int{"alice": 10, "bob": 10, "carol": 0})
+
+		// Deleting the tournament takes the points away again
+		if err := repo.Delete(tournament.ID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if deleted, err := repo.GetByID(tournament.ID); err != nil || deleted != nil {
+			t.Fatalf("GetByID after delete = %v, %v; want nil", deleted, err)
+		}
+		assertTournamentPoints(t, users, map[string]int{"alice": 0, "bob": 0, "carol": 0})
+	})
+}
+
+// assertTournamentPoints checks the tournament share of the ranking bonus per user
+func assertTournamentPoints(t *testing.T, users map[string]*models.User, want map[string]int) {
+	t.Helper()
+
+	rankings, err := NewVoteRepository().GetGlobalRanking()
+	if err != nil {
+		t.Fatalf("GetGlobalRanking failed: %v", err)
+	}
+	for _, ranking := range rankings {
+		for username, points := range want {
+			if ranking.User.ID != users[username].ID {
+				continue
+			}
+			if ranking.TournamentPoints != points || ranking.BonusPoints != points || ranking.TotalScore != points {
+				t.Errorf("%s: tournament=%d bonus=%d total=%d, want %d each",
+					username, ranking.TournamentPoints, ranking.BonusPoints, ranking.TotalScore, points)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/guided-traffic/rate-your-mate/backend/database"
@@ -20,20 +21,15 @@ func NewUserRepository() *UserRepository {
 // Create creates a new user in the database (with retry for SQLITE_BUSY)
 func (r *UserRepository) Create(user *models.User) error {
 	return database.WithRetry(func() error {
-		result, err := database.DB.Exec(`
-			INSERT INTO users (steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			user.SteamID, user.Username, user.AvatarURL, user.AvatarSmall, user.ProfileURL, user.Credits, user.LastCreditAt,
+		id, err := database.InsertReturningID(database.DB, `
+			INSERT INTO users (steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, invited_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			user.SteamID, user.Username, user.AvatarURL, user.AvatarSmall, user.ProfileURL, user.Credits, user.LastCreditAt, user.InvitedAt,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create user: %w", err)
 		}
 
-		id, err := result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("failed to get last insert id: %w", err)
-		}
-
 		user.ID = uint64(id)
 		return nil
 	})
@@ -43,10 +39,10 @@ func (r *UserRepository) Create(user *models.User) error {
 func (r *UserRepository) GetByID(id uint64) (*models.User, error) {
 	user := &models.User{}
 	err := database.DB.QueryRow(`
-		SELECT id, steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, last_games_refresh_at, created_at, updated_at
+		SELECT id, steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, last_games_refresh_at, invited_at, created_at, updated_at
 		FROM users WHERE id = ?`, id,
 	).Scan(&user.ID, &user.SteamID, &user.Username, &user.AvatarURL, &user.AvatarSmall, &user.ProfileURL,
-		&user.Credits, &user.LastCreditAt, &user.LastGamesRefreshAt, &user.CreatedAt, &user.UpdatedAt)
+		&user.Credits, &user.LastCreditAt, &user.LastGamesRefreshAt, &user.InvitedAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -62,10 +58,10 @@ func (r *UserRepository) GetByID(id uint64) (*models.User, error) {
 func (r *UserRepository) GetBySteamID(steamID string) (*models.User, error) {
 	user := &models.User{}
 	err := database.DB.QueryRow(`
-		SELECT id, steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, last_games_refresh_at, created_at, updated_at
+		SELECT id, steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, last_games_refresh_at, invited_at, created_at, updated_at
 		FROM users WHERE steam_id = ?`, steamID,
 	).Scan(&user.ID, &user.SteamID, &user.Username, &user.AvatarURL, &user.AvatarSmall, &user.ProfileURL,
-		&user.Credits, &user.LastCreditAt, &user.LastGamesRefreshAt, &user.CreatedAt, &user.UpdatedAt)
+		&user.Credits, &user.LastCreditAt, &user.LastGamesRefreshAt, &user.InvitedAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -80,7 +76,7 @@ func (r *UserRepository) GetBySteamID(steamID string) (*models.User, error) {
 // GetAll returns all users
 func (r *UserRepository) GetAll() ([]models.User, error) {
 	rows, err := database.DB.Query(`
-		SELECT id, steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, last_games_refresh_at, created_at, updated_at
+		SELECT id, steam_id, username, avatar_url, avatar_small, profile_url, credits, last_credit_at, last_games_refresh_at, invited_at, created_at, updated_at
 		FROM users ORDER BY username`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
@@ -91,7 +87,7 @@ func (r *UserRepository) GetAll() ([]models.User, error) {
 	for rows.Next() {
 		var user models.User
 		err := rows.Scan(&user.ID, &user.SteamID, &user.Username, &user.AvatarURL, &user.AvatarSmall, &user.ProfileURL,
-			&user.Credits, &user.LastCreditAt, &user.LastGamesRefreshAt, &user.CreatedAt, &user.UpdatedAt)
+			&user.Credits, &user.LastCreditAt, &user.LastGamesRefreshAt, &user.InvitedAt, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user row: %w", err)
 		}
@@ -210,15 +206,16 @@ func (r *UserRepository) ResetAllCredits() (int64, error) {
 }
 
 // GiveEveryoneCredit gives each user 1 credit (respecting max credits, with retry for SQLITE_BUSY)
+// Invited users who have not logged in yet are skipped.
 func (r *UserRepository) GiveEveryoneCredit(maxCredits int) (int64, error) {
 	var rowsAffected int64
 
 	err := database.WithRetry(func() error {
 		result, err := database.DB.Exec(`
 			UPDATE users
-			SET credits = MIN(credits + 1, ?), updated_at = CURRENT_TIMESTAMP
-			WHERE credits < ?`,
-			maxCredits, maxCredits)
+			SET credits = credits + 1, updated_at = CURRENT_TIMESTAMP
+			WHERE credits < ? AND invited_at IS NULL`,
+			maxCredits)
 		if err != nil {
 			return fmt.Errorf("failed to give everyone credit: %w", err)
 		}
@@ -242,12 +239,13 @@ func (r *UserRepository) ShiftAllLastCreditAt(duration time.Duration) error {
 	newTime := time.Now()
 
 	// Get all users and update their last_credit_at by adding the pause duration
+	// The rows are read completely before updating, as SQLite cannot write while a read is open
 	rows, err := database.DB.Query(`SELECT id, last_credit_at FROM users`)
 	if err != nil {
 		return fmt.Errorf("failed to query users: %w", err)
 	}
-	defer rows.Close()
 
+	shifted := make(map[uint64]time.Time)
 	for rows.Next() {
 		var userID uint64
 		var lastCreditAt time.Time
@@ -263,23 +261,33 @@ func (r *UserRepository) ShiftAllLastCreditAt(duration time.Duration) error {
 		if newLastCreditAt.After(newTime) {
 			newLastCreditAt = newTime
 		}
-
-		// Update this user
-		_, err := database.DB.Exec(`
-			UPDATE users
-			SET last_credit_at = ?, updated_at = CURRENT_TIMESTAMP
-			WHERE id = ?`,
-			newLastCreditAt, userID)
-		if err != nil {
-			return fmt.Errorf("failed to update user %d: %w", userID, err)
-		}
+		shifted[userID] = newLastCreditAt
 	}
-
-	return rows.Err()
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("failed to read users: %w", err)
+	}
+	rows.Close()
+
+	// Update all users in one transaction
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		for userID, lastCreditAt := range shifted {
+			_, err := tx.Exec(`
+				UPDATE users
+				SET last_credit_at = ?, updated_at = CURRENT_TIMESTAMP
+				WHERE id = ?`,
+				lastCreditAt, userID)
+			if err != nil {
+				return fmt.Errorf("failed to update user %d: %w", userID, err)
+			}
+		}
+		return nil
+	})
 }
 
 // FindOrCreate finds a user by Steam ID or creates a new one
-// Always updates profile data (username, avatar) on each login to reflect Steam profile changes
+// Always updates profile data (username, avatar) on each login to reflect Steam profile changes.
+// An invited placeholder is claimed on its first login and reported as new user.
 func (r *UserRepository) FindOrCreate(steamID, username, avatarURL, avatarSmall, profileURL string) (*models.User, bool, error) {
 	// Try to find existing user
 	user, err := r.GetBySteamID(steamID)
@@ -287,6 +295,20 @@ func (r *UserRepository) FindOrCreate(steamID, username, avatarURL, avatarSmall,
 		return nil, false, err
 	}
 
+	if user != nil && user.InvitedAt != nil {
+		user.Username = username
+		user.AvatarURL = avatarURL
+		user.AvatarSmall = avatarSmall
+		user.ProfileURL = profileURL
+		user.Credits = 0
+		user.LastCreditAt = time.Now()
+		if err := r.claimInvitation(user); err != nil {
+			return nil, false, err
+		}
+		user.InvitedAt = nil
+		return user, true, nil // true = first login of an invited user
+	}
+
 	if user != nil {
 		// Always update profile data on login to catch Steam profile changes
 		// This ensures users who set a custom avatar after using default get their new avatar
@@ -320,32 +342,132 @@ func (r *UserRepository) FindOrCreate(steamID, username, avatarURL, avatarSmall,
 	return user, true, nil // true = new user created
 }
 
-// DeleteByID deletes a user by ID and returns the number of rows affected
-func (r *UserRepository) DeleteByID(id uint64) error {
+// claimInvitation turns an invited placeholder into a regular user (with retry for SQLITE_BUSY)
+// Credits are reset so the placeholder time does not count as credit time.
+func (r *UserRepository) claimInvitation(user *models.User) error {
 	return database.WithRetry(func() error {
-		_, err := database.DB.Exec(`DELETE FROM users WHERE id = ?`, id)
+		_, err := database.DB.Exec(`
+			UPDATE users
+			SET username = ?, avatar_url = ?, avatar_small = ?, profile_url = ?, credits = ?, last_credit_at = ?,
+				invited_at = NULL, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?`,
+			user.Username, user.AvatarURL, user.AvatarSmall, user.ProfileURL, user.Credits, user.LastCreditAt, user.ID,
+		)
 		if err != nil {
-			return fmt.Errorf("failed to delete user: %w", err)
+			return fmt.Errorf("failed to claim invitation: %w", err)
 		}
 		return nil
 	})
 }
 
-// DeleteBySteamID deletes a user by Steam ID
+// DeleteByID deletes a user by ID together with all their data
+func (r *UserRepository) DeleteByID(id uint64) error {
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		_, err := deleteUsers(tx, `id = ?`, id)
+		return err
+	})
+}
+
+// DeleteBySteamID deletes a user by Steam ID together with all their data
 func (r *UserRepository) DeleteBySteamID(steamID string) error {
-	return database.WithRetry(func() error {
-		_, err := database.DB.Exec(`DELETE FROM users WHERE steam_id = ?`, steamID)
-		if err != nil {
-			return fmt.Errorf("failed to delete user: %w", err)
+	return database.WithTransaction(func(tx *sql.Tx) error {
+		_, err := deleteUsers(tx, `steam_id = ?`, steamID)
+		return err
+	})
+}
+
+// userDataDeletes delete the rows that belong to a user (every ? is the user ID), children first
+// SQLite doesn't enforce foreign keys, so their ON DELETE CASCADE has to be done explicitly.
+var userDataDeletes = []string{
+	`DELETE FROM chat_reactions WHERE user_id = ?`,
+	`DELETE FROM chat_mutes WHERE user_id = ?`,
+	`DELETE FROM chat_channel_members WHERE user_id = ?`,
+	`DELETE FROM direct_messages WHERE sender_id = ? OR recipient_id = ?`,
+	`DELETE FROM votes WHERE from_user_id = ? OR to_user_id = ?`,
+	// Polls created by the user go with their options and all ballots
+	`DELETE FROM poll_ballots WHERE user_id = ? OR poll_id IN (SELECT id FROM polls WHERE created_by = ?)`,
+	`DELETE FROM poll_options WHERE poll_id IN (SELECT id FROM polls WHERE created_by = ?)`,
+	`DELETE FROM polls WHERE created_by = ?`,
+	`DELETE FROM game_install_status WHERE user_id = ?`,
+	// Sessions created by the user go with their player lists
+	`DELETE FROM game_session_players WHERE user_id = ? OR session_id IN (SELECT id FROM game_sessions WHERE created_by = ?)`,
+	`DELETE FROM game_sessions WHERE created_by = ?`,
+	// Tournaments created by the user go with their teams, registrations and matches
+	`DELETE FROM tournament_matches WHERE tournament_id IN (SELECT id FROM tournaments WHERE created_by = ?)`,
+	`DELETE FROM tournament_team_members WHERE user_id = ? OR team_id IN (SELECT id FROM tournament_teams
+		WHERE tournament_id IN (SELECT id FROM tournaments WHERE created_by = ?))`,
+	`DELETE FROM tournament_teams WHERE tournament_id IN (SELECT id FROM tournaments WHERE created_by = ?)`,
+	`DELETE FROM tournament_registrations WHERE user_id = ? OR tournament_id IN (SELECT id FROM tournaments WHERE created_by = ?)`,
+	`DELETE FROM tournaments WHERE created_by = ?`,
+	// Matches recorded by the user go with their players
+	`DELETE FROM game_match_players WHERE user_id = ? OR match_id IN (SELECT id FROM game_matches WHERE recorded_by = ?)`,
+	`DELETE FROM game_matches WHERE recorded_by = ?`,
+	`DELETE FROM player_ratings WHERE user_id = ?`,
+}
+
+// deleteUsers deletes the users matching where with all their data and returns the number of deleted users
+func deleteUsers(tx *sql.Tx, where string, args ...interface{}) (int64, error) {
+	rows, err := tx.Query(`SELECT id FROM users WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get users to delete: %w", err)
+	}
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan user row: %w", err)
 		}
-		return nil
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if _, err := deleteChatMessages(tx, `user_id = ?`, id); err != nil {
+			return 0, err
+		}
+		for _, stmt := range userDataDeletes {
+			stmtArgs := make([]interface{}, strings.Count(stmt, "?"))
+			for i := range stmtArgs {
+				stmtArgs[i] = id
+			}
+			if _, err := tx.Exec(stmt, stmtArgs...); err != nil {
+				return 0, fmt.Errorf("failed to delete user data: %w", err)
+			}
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM users WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteFakeUsers deletes all fake users (Steam ID prefix FAKE_) including all their data
+// and game ownerships. Returns the number of deleted users.
+func (r *UserRepository) DeleteFakeUsers() (int64, error) {
+	var deleted int64
+	err := database.WithTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM game_owners WHERE steam_id LIKE 'FAKE_%'`); err != nil {
+			return fmt.Errorf("failed to delete fake user data: %w", err)
+		}
+		var err error
+		deleted, err = deleteUsers(tx, `steam_id LIKE 'FAKE_%'`)
+		return err
 	})
+
+	return deleted, err
 }
 
 // GetAllForAdmin returns all users with admin-relevant info
 func (r *UserRepository) GetAllForAdmin() ([]models.AdminUserInfo, error) {
 	rows, err := database.DB.Query(`
-		SELECT id, steam_id, username, avatar_small, created_at
+		SELECT id, steam_id, username, avatar_small, invited_at, created_at
 		FROM users ORDER BY username`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
@@ -355,7 +477,7 @@ func (r *UserRepository) GetAllForAdmin() ([]models.AdminUserInfo, error) {
 	var users []models.AdminUserInfo
 	for rows.Next() {
 		var user models.AdminUserInfo
-		err := rows.Scan(&user.ID, &user.SteamID, &user.Username, &user.AvatarSmall, &user.CreatedAt)
+		err := rows.Scan(&user.ID, &user.SteamID, &user.Username, &user.AvatarSmall, &user.InvitedAt, &user.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user row: %w", err)
 		}
@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestShiftAllLastCreditAt(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastCreditAt time.Duration // offset from now
+		shift        time.Duration
+		want         time.Duration // offset from now
+	}{
+		{name: "shifted by the pause", lastCreditAt: -2 * time.Hour, shift: 30 * time.Minute, want: -90 * time.Minute},
+		{name: "zero pause keeps the timestamp", lastCreditAt: -time.Hour, shift: 0, want: -time.Hour},
+		{name: "never moved into the future", lastCreditAt: -10 * time.Minute, shift: time.Hour, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtest.Run(t, func(t *testing.T) {
+				repo := NewUserRepository()
+				users := createUsers(t, "alice", "bob")
+				now := time.Now().UTC().Truncate(time.Second)
+				for _, user := range users {
+					if err := repo.UpdateCredits(user.ID, 0, now.Add(tt.lastCreditAt)); err != nil {
+						t.Fatalf("failed to set last_credit_at: %v", err)
+					}
+				}
+
+				if err := repo.ShiftAllLastCreditAt(tt.shift); err != nil {
+					t.Fatalf("ShiftAllLastCreditAt failed: %v", err)
+				}
+
+				for username, user := range users {
+					updated, err := repo.GetByID(user.ID)
+					if err != nil {
+						t.Fatalf("failed to reload %s: %v", username, err)
+					}
+					want := now.Add(tt.want)
+					if diff := updated.LastCreditAt.Sub(want); diff < -2*time.Second || diff > 2*time.Second {
+						t.Errorf("%s: last_credit_at = %v, want %v", username, updated.LastCreditAt.UTC(), want)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestGiveEveryoneCredit(t *testing.T) {
+	tests := []struct {
+		name         string
+		credits      map[string]int
+		maxCredits   int
+		wantAffected int64
+		want         map[string]int
+	}{
+		{
+			name:         "below maximum",
+			credits:      map[string]int{"alice": 0, "bob": 4},
+			maxCredits:   10,
+			wantAffected: 2,
+			want:         map[string]int{"alice": 1, "bob": 5},
+		},
+		{
+			name:         "capped at maximum",
+			credits:      map[string]int{"alice": 9, "bob": 10},
+			maxCredits:   10,
+			wantAffected: 1,
+			want:         map[string]int{"alice": 10, "bob": 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtest.Run(t, func(t *testing.T) {
+				repo := NewUserRepository()
+				users := createUsers(t, "alice", "bob")
+				for username, credits := range tt.credits {
+					if err := repo.UpdateCredits(users[username].ID, credits, time.Now()); err != nil {
+						t.Fatalf("failed to set credits: %v", err)
+					}
+				}
+
+				affected, err := repo.GiveEveryoneCredit(tt.maxCredits)
+				if err != nil {
+					t.Fatalf("GiveEveryoneCredit failed: %v", err)
+				}
+				if affected != tt.wantAffected {
+					t.Errorf("affected %d users, want %d", affected, tt.wantAffected)
+				}
+				for username, want := range tt.want {
+					user, err := repo.GetByID(users[username].ID)
+					if err != nil {
+						t.Fatalf("failed to reload %s: %v", username, err)
+					}
+					if user.Credits != want {
+						t.Errorf("%s: credits = %d, want %d", username, user.Credits, want)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestBans(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewUserRepository()
+		users := createUsers(t, "alice", "bob")
+		alice := users["alice"]
+
+		if err := repo.BanUser(alice.SteamID, alice.Username, "griefing", "admin"); err != nil {
+			t.Fatalf("BanUser failed: %v", err)
+		}
+		if err := repo.BanUser(alice.SteamID, alice.Username, "again", "admin"); err == nil {
+			t.Error("expected banning the same Steam ID twice to fail")
+		}
+
+		tests := []struct {
+			steamID string
+			banned  bool
+		}{
+			{alice.SteamID, true},
+			{users["bob"].SteamID, false},
+			{"76561190000000000", false},
+		}
+		for _, tt := range tests {
+			banned, err := repo.IsBanned(tt.steamID)
+			if err != nil {
+				t.Fatalf("IsBanned(%s) failed: %v", tt.steamID, err)
+			}
+			if banned != tt.banned {
+				t.Errorf("IsBanned(%s) = %t, want %t", tt.steamID, banned, tt.banned)
+			}
+		}
+
+		ban, err := repo.GetBannedUser(alice.SteamID)
+		if err != nil || ban == nil {
+			t.Fatalf("GetBannedUser failed: ban=%v err=%v", ban, err)
+		}
+		if ban.Username != "alice" || ban.Reason != "griefing" || ban.BannedBy != "admin" || ban.BannedAt.IsZero() {
+			t.Errorf("unexpected ban entry: %+v", ban)
+		}
+
+		if err := repo.UnbanUser(alice.SteamID); err != nil {
+			t.Fatalf("UnbanUser failed: %v", err)
+		}
+		if banned, _ := repo.IsBanned(alice.SteamID); banned {
+			t.Error("expected alice to be unbanned")
+		}
+		bans, err := repo.GetAllBannedUsers()
+		if err != nil {
+			t.Fatalf("GetAllBannedUsers failed: %v", err)
+		}
+		if len(bans) != 0 {
+			t.Errorf("expected no bans left, got %d", len(bans))
+		}
+	})
+}
+
+func TestFindOrCreateClaimsInvitation(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewUserRepository()
+		invitedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+		invited := &models.User{
+			SteamID:      "76561190000000004",
+			Username:     "placeholder",
+			Credits:      3,
+			LastCreditAt: invitedAt,
+			InvitedAt:    &invitedAt,
+		}
+		if err := repo.Create(invited); err != nil {
+			t.Fatalf("failed to create invited user: %v", err)
+		}
+
+		// Invited users are skipped by the credit ticker
+		if affected, err := repo.GiveEveryoneCredit(10); err != nil || affected != 0 {
+			t.Errorf("GiveEveryoneCredit affected %d users (%v), want 0", affected, err)
+		}
+
+		user, isNew, err := repo.FindOrCreate(invited.SteamID, "NightOwl", "avatar.jpg", "avatar.jpg", "https://steamcommunity.com/profiles/76561190000000004/")
+		if err != nil {
+			t.Fatalf("FindOrCreate failed: %v", err)
+		}
+		if !isNew || user.ID != invited.ID {
+			t.Errorf("expected the placeholder %d to be claimed as new user, got id=%d isNew=%t", invited.ID, user.ID, isNew)
+		}
+
+		claimed, err := repo.GetByID(invited.ID)
+		if err != nil {
+			t.Fatalf("failed to reload user: %v", err)
+		}
+		if claimed.InvitedAt != nil || claimed.Username != "NightOwl" || claimed.Credits != 0 || !claimed.LastCreditAt.After(invitedAt) {
+			t.Errorf("unexpected claimed user: %+v", claimed)
+		}
+
+		// The second login is a regular one
+		if _, isNew, err := repo.FindOrCreate(invited.SteamID, "NightOwl", "avatar.jpg", "avatar.jpg", claimed.ProfileURL); err != nil || isNew {
+			t.Errorf("expected an existing user on the second login, got isNew=%t (%v)", isNew, err)
+		}
+	})
+}
+
+// countRows returns the number of rows in a table
+func countRows(t *testing.T, table string) int {
+	t.Helper()
+
+	var count int
+	if err := database.DB.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&count); err != nil {
+		t.Fatalf("failed to count %s: %v", table, err)
+	}
+	return count
+}
+
+func TestDeleteUserRemovesChatData(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewUserRepository()
+		chatRepo := NewChatRepository()
+		dmRepo := NewDirectMessageRepository()
+		users := createUsers(t, "alice", "bob", "carol")
+		alice, bob, carol := users["alice"], users["bob"], users["carol"]
+
+		for _, user := range []*models.User{alice, bob} {
+			if err := chatRepo.Mute(user.ID, "spam", "admin", time.Now().UTC().Add(time.Hour)); err != nil {
+				t.Fatalf("Mute failed: %v", err)
+			}
+		}
+
+		// Bob replies to and reacts on alice's message, alice reacts on bob's reply
+		parent := &models.ChatMessage{UserID: alice.ID, Message: "who is in?", Achievements: "[]"}
+		if err := chatRepo.Create(parent); err != nil {
+			t.Fatalf("failed to create message: %v", err)
+		}
+		reply := &models.ChatMessage{UserID: bob.ID, Message: "me", Achievements: "[]", ParentID: &parent.ID}
+		if err := chatRepo.Create(reply); err != nil {
+			t.Fatalf("failed to create reply: %v", err)
+		}
+		if _, err := chatRepo.AddReaction(parent.ID, bob.ID, "👍"); err != nil {
+			t.Fatalf("AddReaction failed: %v", err)
+		}
+		if _, err := chatRepo.AddReaction(reply.ID, alice.ID, "🔥"); err != nil {
+			t.Fatalf("AddReaction failed: %v", err)
+		}
+
+		for _, dm := range []*models.DirectMessage{
+			{SenderID: alice.ID, RecipientID: bob.ID, Message: "hi", CreatedAt: time.Now().UTC()},
+			{SenderID: bob.ID, RecipientID: alice.ID, Message: "hey", CreatedAt: time.Now().UTC()},
+			{SenderID: bob.ID, RecipientID: carol.ID, Message: "gg", CreatedAt: time.Now().UTC()},
+		} {
+			if err := dmRepo.Create(dm); err != nil {
+				t.Fatalf("failed to create direct message: %v", err)
+			}
+		}
+
+		channel, err := chatRepo.EnsureGameChannel(730, "Counter-Strike 2")
+		if err != nil {
+			t.Fatalf("EnsureGameChannel failed: %v", err)
+		}
+		for _, user := range []*models.User{alice, bob} {
+			if _, err := chatRepo.JoinChannel(channel.ID, user.ID); err != nil {
+				t.Fatalf("JoinChannel failed: %v", err)
+			}
+		}
+
+		if err := repo.DeleteByID(alice.ID); err != nil {
+			t.Fatalf("DeleteByID failed: %v", err)
+		}
+		if user, err := repo.GetByID(alice.ID); err != nil || user != nil {
+			t.Fatalf("expected alice to be deleted, got %v, %v", user, err)
+		}
+		if count := countRows(t, "chat_mutes"); count != 1 {
+			t.Errorf("expected only bob's mute to remain, got %d mutes", count)
+		}
+		if count := countRows(t, "chat_reactions"); count != 0 {
+			t.Errorf("expected alice's reactions and the ones on her message to be deleted, got %d", count)
+		}
+		if count := countRows(t, "chat_messages"); count != 1 {
+			t.Errorf("expected only bob's reply to remain, got %d messages", count)
+		}
+		if msg, err := chatRepo.GetByID(reply.ID); err != nil || msg == nil || msg.ParentID != nil {
+			t.Errorf("expected the reply to stay without a parent, got %+v, %v", msg, err)
+		}
+		if members, err := chatRepo.GetChannelMemberIDs(channel.ID); err != nil || countRows(t, "chat_channel_members") != 1 || len(members) != 1 {
+			t.Errorf("expected only bob to stay in the game channel, got %v, %v", members, err)
+		}
+		if count := countRows(t, "direct_messages"); count != 1 {
+			t.Errorf("expected only the message between bob and carol to remain, got %d", count)
+		}
+	})
+}
+
+func TestDeleteUserRemovesOwnedData(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		repo := NewUserRepository()
+		pollRepo := NewPollRepository()
+		sessionRepo := NewGameSessionRepository()
+		installRepo := NewInstallStatusRepository()
+		tournamentRepo := NewTournamentRepository()
+		ratingRepo := NewRatingRepository()
+		users := createUsers(t, "alice", "bob", "carol")
+		alice, bob, carol := users["alice"], users["bob"], users["carol"]
+
+		createVotes(t, users, []voteFixture{
+			{from: "alice", to: "bob", achievement: "pro-player", points: 1},
+			{from: "bob", to: "alice", achievement: "pro-player", points: 1},
+			{from: "bob", to: "carol", achievement: "pro-player", points: 1},
+		})
+
+		// Alice's poll goes with bob's ballot, alice's ballot in bob's poll goes on its own
+		alicePoll := createPoll(t, users, nil)
+		bobPoll := &models.Poll{
+			Title:        "Next map",
+			VotingMethod: models.PollVotingSingle,
+			CreatedBy:    models.PublicUser{ID: bob.ID},
+			CreatedAt:    dbtest.Timestamp(0),
+			Options:      []models.PollOption{{AppID: 730, Name: "Counter-Strike 2"}, {AppID: 570, Name: "Dota 2", Position: 1}},
+		}
+		if err := pollRepo.Create(bobPoll); err != nil {
+			t.Fatalf("failed to create poll: %v", err)
+		}
+		for _, ballot := range []struct {
+			poll *models.Poll
+			user *models.User
+		}{{alicePoll, bob}, {bobPoll, alice}, {bobPoll, carol}} {
+			if err := pollRepo.SaveBallot(ballot.poll.ID, ballot.user.ID, []int{730}); err != nil {
+				t.Fatalf("SaveBallot failed: %v", err)
+			}
+		}
+
+		// Alice's session goes with its player list, she leaves bob's session
+		aliceSession := &models.GameSession{AppID: 730, CreatedBy: models.PublicUser{ID: alice.ID}, CreatedAt: dbtest.Timestamp(0)}
+		bobSession := &models.GameSession{AppID: 570, CreatedBy: models.PublicUser{ID: bob.ID}, CreatedAt: dbtest.Timestamp(0)}
+		for _, session := range []*models.GameSession{aliceSession, bobSession} {
+			if err := sessionRepo.Create(session, []uint64{alice.ID, bob.ID}); err != nil {
+				t.Fatalf("failed to create session: %v", err)
+			}
+		}
+		for _, user := range []*models.User{alice, bob} {
+			if err := installRepo.Set(user.ID, 730, models.InstallStatusInstalled, 100); err != nil {
+				t.Fatalf("failed to set install status: %v", err)
+			}
+		}
+
+		// Alice's tournament goes as a whole, she leaves bob's tournament
+		var tournaments []*models.Tournament
+		for _, creator := range []*models.User{alice, bob} {
+			tournament := &models.Tournament{
+				Name: creator.Username + " Cup", AppID: 730, Format: models.TournamentSingleElimination, TeamSize: 2,
+				Status: models.TournamentStatusRegistration, CreatedBy: creator.ID, CreatedAt: dbtest.Timestamp(0),
+			}
+			if err := tournamentRepo.Create(tournament); err != nil {
+				t.Fatalf("failed to create tournament: %v", err)
+			}
+			for _, user := range []*models.User{alice, bob, carol} {
+				if err := tournamentRepo.Register(tournament.ID, user.ID); err != nil {
+					t.Fatalf("failed to register %s: %v", user.Username, err)
+				}
+			}
+			teams := []models.TournamentTeam{
+				{Name: "Team alice", Seed: 1, Members: []models.PublicUser{{ID: alice.ID}, {ID: carol.ID}}},
+				{Name: "bob", Seed: 2, Members: []models.PublicUser{{ID: bob.ID}}},
+			}
+			build := func(teamIDs []uint64) []models.TournamentMatch {
+				return []models.TournamentMatch{{ID: 1, Bracket: models.BracketWinners, Round: 1,
+					Team1ID: &teamIDs[0], Team2ID: &teamIDs[1], Status: models.MatchStatusPending}}
+			}
+			if err := tournamentRepo.Start(tournament.ID, teams, build, dbtest.Timestamp(0)); err != nil {
+				t.Fatalf("failed to start tournament: %v", err)
+			}
+			tournaments = append(tournaments, tournament)
+		}
+
+		// The match alice recorded goes, she leaves the match bob recorded
+		for _, m := range []struct {
+			recordedBy *models.User
+			players    [2]*models.User
+		}{{alice, [2]*models.User{bob, carol}}, {bob, [2]*models.User{alice, carol}}} {
+			match := &models.GameMatch{
+				AppID: 730, RecordedBy: m.recordedBy.ID, PlayedAt: dbtest.Timestamp(0), CreatedAt: dbtest.Timestamp(0),
+				Players: []models.MatchPlayer{
+					{User: m.players[0].ToPublic(), Team: 1, RatingBefore: 1000, RatingAfter: 1000},
+					{User: m.players[1].ToPublic(), Team: 2, RatingBefore: 1000, RatingAfter: 1000},
+				},
+			}
+			ratings := []models.PlayerRating{
+				{User: m.players[0].ToPublic(), AppID: 730, Rating: 1000, Matches: 1, Draws: 1, UpdatedAt: dbtest.Timestamp(0)},
+				{User: m.players[1].ToPublic(), AppID: 730, Rating: 1000, Matches: 1, Draws: 1, UpdatedAt: dbtest.Timestamp(0)},
+			}
+			if err := ratingRepo.RecordMatch(match, ratings); err != nil {
+				t.Fatalf("RecordMatch failed: %v", err)
+			}
+		}
+
+		if err := repo.DeleteByID(alice.ID); err != nil {
+			t.Fatalf("DeleteByID failed: %v", err)
+		}
+
+		if count := countRows(t, "votes"); count != 1 {
+			t.Errorf("expected only bob's vote for carol to remain, got %d votes", count)
+		}
+		if poll, err := pollRepo.GetByID(alicePoll.ID); err != nil || poll != nil {
+			t.Errorf("expected alice's poll to be deleted, got %+v, %v", poll, err)
+		}
+		if count := countRows(t, "poll_options"); count != 2 {
+			t.Errorf("expected only the options of bob's poll to remain, got %d", count)
+		}
+		ballots, err := pollRepo.GetBallots(bobPoll.ID)
+		if err != nil || countRows(t, "poll_ballots") != 1 || len(ballots) != 1 || ballots[0].UserID != carol.ID {
+			t.Errorf("expected only carol's ballot to remain, got %+v, %v", ballots, err)
+		}
+		if session, err := sessionRepo.GetByID(aliceSession.ID); err != nil || session != nil {
+			t.Errorf("expected alice's session to be deleted, got %+v, %v", session, err)
+		}
+		players, err := sessionRepo.GetPlayerIDs(bobSession.ID)
+		if err != nil || countRows(t, "game_session_players") != 1 || len(players) != 1 || players[0] != bob.ID {
+			t.Errorf("expected only bob to remain in his session, got %v, %v", players, err)
+		}
+		if count := countRows(t, "game_install_status"); count != 1 {
+			t.Errorf("expected only bob's install status to remain, got %d", count)
+		}
+		if tournament, err := tournamentRepo.GetByID(tournaments[0].ID); err != nil || tournament != nil {
+			t.Errorf("expected alice's tournament to be deleted, got %+v, %v", tournament, err)
+		}
+		tournament, err := tournamentRepo.GetByID(tournaments[1].ID)
+		if err != nil || tournament == nil {
+			t.Fatalf("GetByID failed: tournament=%v err=%v", tournament, err)
+		}
+		if len(tournament.Players) != 2 || len(tournament.Teams) != 2 || len(tournament.Teams[0].Members) != 1 || tournament.Teams[0].Members[0].ID != carol.ID {
+			t.Errorf("expected alice to leave bob's tournament, got %+v", tournament)
+		}
+		for table, want := range map[string]int{"tournament_teams": 2, "tournament_team_members": 2, "tournament_registrations": 2, "tournament_matches": 1} {
+			if count := countRows(t, table); count != want {
+				t.Errorf("expected %d rows in %s, got %d", want, table, count)
+			}
+		}
+		matches, err := ratingRepo.GetMatches(0, 10)
+		if err != nil || len(matches) != 1 || matches[0].RecordedBy != bob.ID || len(matches[0].Players) != 1 || matches[0].Players[0].User.ID != carol.ID {
+			t.Errorf("expected only bob's match with carol to remain, got %+v, %v", matches, err)
+		}
+		if count := countRows(t, "game_match_players"); count != 1 {
+			t.Errorf("expected only carol's match player row to remain, got %d", count)
+		}
+		if ladder, err := ratingRepo.GetLadder(730); err != nil || countRows(t, "player_ratings") != 2 || len(ladder) != 2 {
+			t.Errorf("expected alice's rating to be deleted, got %+v, %v", ladder, err)
+		}
+	})
+}
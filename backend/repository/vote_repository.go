@@ -19,7 +19,7 @@ func NewVoteRepository() *VoteRepository {
 // Create creates a new vote (with retry for SQLITE_BUSY)
 func (r *VoteRepository) Create(vote *models.Vote) error {
 	return database.WithRetry(func() error {
-		result, err := database.DB.Exec(`
+		id, err := database.InsertReturningID(database.DB, `
 			INSERT INTO votes (from_user_id, to_user_id, achievement_id, points, is_secret, comment)
 			VALUES (?, ?, ?, ?, ?, ?)`,
 			vote.FromUserID, vote.ToUserID, vote.AchievementID, vote.Points, vote.IsSecret, vote.Comment,
@@ -28,11 +28,6 @@ func (r *VoteRepository) Create(vote *models.Vote) error {
 			return fmt.Errorf("failed to create vote: %w", err)
 		}
 
-		id, err := result.LastInsertId()
-		if err != nil {
-			return fmt.Errorf("failed to get last insert id: %w", err)
-		}
-
 		vote.ID = uint64(id)
 		return nil
 	})
@@ -135,7 +130,7 @@ func (r *VoteRepository) GetLeaderboard(topN int) ([]AchievementLeaderboard, err
 		FROM votes v
 		JOIN users u ON v.to_user_id = u.id
 		WHERE v.is_invalidated = 0
-		GROUP BY v.achievement_id, v.to_user_id
+		GROUP BY v.achievement_id, u.id
 		ORDER BY v.achievement_id, vote_count DESC`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
@@ -229,7 +224,7 @@ type Champion struct {
 	User       *models.PublicUser `json:"user"`
 	TotalScore int                `json:"total_score"`  // Net votes + bonus points
 	NetVotes   int                `json:"net_votes"`    // Positive - negative votes
-	BonusPoints int               `json:"bonus_points"` // Bonus from achievement placements
+	BonusPoints int               `json:"bonus_points"` // Bonus from achievement placements and tournament wins
 	Rank       int                `json:"rank"`
 }
 
@@ -243,6 +238,7 @@ type ChampionsResult struct {
 // GetChampions calculates the top 3 players based on:
 // 1. Net votes (positive - negative)
 // 2. Bonus points from holding top 3 positions in positive achievements (1st: +5, 2nd: +3, 3rd: +2)
+// 3. Bonus points of won tournaments
 // Tie-breaking for achievement positions: first vote wins (earlier created_at)
 func (r *VoteRepository) GetChampions() (*ChampionsResult, error) {
 	result := &ChampionsResult{}
@@ -311,6 +307,30 @@ func (r *VoteRepository) ToggleInvalidation(voteID uint64) (bool, error) {
 	return newState, err
 }
 
+// GetAll returns all votes in insertion order (used for exports)
+func (r *VoteRepository) GetAll() ([]models.Vote, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, from_user_id, to_user_id, achievement_id, points, is_secret, is_invalidated, comment, created_at
+		FROM votes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all votes: %w", err)
+	}
+	defer rows.Close()
+
+	var votes []models.Vote
+	for rows.Next() {
+		var vote models.Vote
+		err := rows.Scan(&vote.ID, &vote.FromUserID, &vote.ToUserID, &vote.AchievementID, &vote.Points,
+			&vote.IsSecret, &vote.IsInvalidated, &vote.Comment, &vote.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote row: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	return votes, nil
+}
+
 // DeleteAll deletes all votes from the database (admin only)
 func (r *VoteRepository) DeleteAll() (int64, error) {
 	var rowsAffected int64
@@ -333,11 +353,12 @@ func (r *VoteRepository) DeleteAll() (int64, error) {
 
 // PlayerRanking represents a user's global ranking based on total score (net votes + bonus points)
 type PlayerRanking struct {
-	User        models.PublicUser `json:"user"`
-	TotalScore  int               `json:"total_score"`  // net votes + bonus points
-	NetVotes    int               `json:"net_votes"`    // positive votes - negative votes
-	BonusPoints int               `json:"bonus_points"` // bonus from achievement placements
-	Rank        int               `json:"rank"`
+	User             models.PublicUser `json:"user"`
+	TotalScore       int               `json:"total_score"`       // net votes + bonus points
+	NetVotes         int               `json:"net_votes"`         // positive votes - negative votes
+	BonusPoints      int               `json:"bonus_points"`      // bonus from achievement placements and tournament wins
+	TournamentPoints int               `json:"tournament_points"` // part of the bonus from tournament wins
+	Rank             int               `json:"rank"`
 }
 
 // GlobalRankingResult contains the global ranking data
@@ -412,14 +433,46 @@ func (r *VoteRepository) getAchievementBonusPoints() (map[uint64]int, error) {
 	return bonusPoints, nil
 }
 
+// getTournamentBonusPoints returns the bonus points each user won with tournaments
+// Every member of the winning team gets the bonus points of the tournament.
+func (r *VoteRepository) getTournamentBonusPoints() (map[uint64]int, error) {
+	rows, err := database.DB.Query(`
+		SELECT m.user_id, SUM(t.bonus_points)
+		FROM tournaments t
+		JOIN tournament_team_members m ON m.team_id = t.winner_team_id
+		WHERE t.status = 'finished' AND t.bonus_points > 0
+		GROUP BY m.user_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament bonus points: %w", err)
+	}
+	defer rows.Close()
+
+	bonusPoints := make(map[uint64]int)
+	for rows.Next() {
+		var userID uint64
+		var points int
+		if err := rows.Scan(&userID, &points); err != nil {
+			return nil, fmt.Errorf("failed to scan tournament bonus row: %w", err)
+		}
+		bonusPoints[userID] = points
+	}
+
+	return bonusPoints, nil
+}
+
 // GetGlobalRanking calculates the global ranking based on total score (net votes + bonus points)
 // Users with the same total score share the same rank
 func (r *VoteRepository) GetGlobalRanking() ([]PlayerRanking, error) {
-	// Step 1: Get bonus points from achievement positions
+	// Step 1: Get bonus points from achievement positions and tournament wins
 	bonusPoints, err := r.getAchievementBonusPoints()
 	if err != nil {
 		return nil, err
 	}
+	tournamentPoints, err := r.getTournamentBonusPoints()
+	if err != nil {
+		return nil, err
+	}
 
 	// Step 2: Calculate net votes per user (excluding invalidated votes)
 	rows, err := database.DB.Query(`
@@ -460,12 +513,13 @@ func (r *VoteRepository) GetGlobalRanking() ([]PlayerRanking, error) {
 			return nil, fmt.Errorf("failed to scan ranking row: %w", err)
 		}
 
-		bonus := bonusPoints[user.ID]
+		bonus := bonusPoints[user.ID] + tournamentPoints[user.ID]
 		rankings = append(rankings, PlayerRanking{
-			User:        user,
-			TotalScore:  netVotes + bonus,
-			NetVotes:    netVotes,
-			BonusPoints: bonus,
+			User:             user,
+			TotalScore:       netVotes + bonus,
+			NetVotes:         netVotes,
+			BonusPoints:      bonus,
+			TournamentPoints: tournamentPoints[user.ID],
 		})
 	}
 
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+)
+
+func TestGetGlobalRanking(t *testing.T) {
+	type want struct {
+		username string
+		rank     int
+		netVotes int
+		bonus    int
+	}
+
+	tests := []struct {
+		name   string
+		votes  []voteFixture
+		banned []string
+		want   []want
+	}{
+		{
+			name: "negative votes are subtracted",
+			votes: []voteFixture{
+				{from: "bob", to: "alice", achievement: "pro-player", points: 3},
+				{from: "alice", to: "bob", achievement: "toxic", points: 2},
+			},
+			want: []want{
+				{"alice", 1, 3, 5},
+				{"carol", 2, 0, 0},
+				{"dave", 2, 0, 0},
+				{"bob", 4, -2, 0},
+			},
+		},
+		{
+			name: "invalidated votes are ignored",
+			votes: []voteFixture{
+				{from: "bob", to: "alice", achievement: "pro-player", points: 3, invalidated: true},
+				{from: "alice", to: "bob", achievement: "teamplayer", points: 1},
+			},
+			want: []want{
+				{"bob", 1, 1, 5},
+				{"alice", 2, 0, 0},
+				{"carol", 2, 0, 0},
+				{"dave", 2, 0, 0},
+			},
+		},
+		{
+			name: "banned players are excluded",
+			votes: []voteFixture{
+				{from: "bob", to: "carol", achievement: "teamplayer", points: 3},
+				{from: "bob", to: "alice", achievement: "pro-player", points: 1},
+			},
+			banned: []string{"carol"},
+			want: []want{
+				{"alice", 1, 1, 5},
+				{"bob", 2, 0, 0},
+				{"dave", 2, 0, 0},
+			},
+		},
+		{
+			name: "equal scores share a rank",
+			votes: []voteFixture{
+				{from: "carol", to: "alice", achievement: "pro-player", points: 2},
+				{from: "carol", to: "bob", achievement: "clutch-king", points: 2},
+				{from: "alice", to: "dave", achievement: "rage-quitter", points: 1},
+			},
+			want: []want{
+				{"alice", 1, 2, 5},
+				{"bob", 1, 2, 5},
+				{"carol", 3, 0, 0},
+				{"dave", 4, -1, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtest.Run(t, func(t *testing.T) {
+				users := createUsers(t, "alice", "bob", "carol", "dave")
+				createVotes(t, users, tt.votes)
+				for _, username := range tt.banned {
+					if err := NewUserRepository().BanUser(users[username].SteamID, username, "test", "admin"); err != nil {
+						t.Fatalf("failed to ban %s: %v", username, err)
+					}
+				}
+
+				rankings, err := NewVoteRepository().GetGlobalRanking()
+				if err != nil {
+					t.Fatalf("GetGlobalRanking failed: %v", err)
+				}
+				if len(rankings) != len(tt.want) {
+					t.Fatalf("expected %d rankings, got %d", len(tt.want), len(rankings))
+				}
+				for i, w := range tt.want {
+					got := rankings[i]
+					if got.User.Username != w.username || got.Rank != w.rank || got.NetVotes != w.netVotes || got.BonusPoints != w.bonus {
+						t.Errorf("position %d: got %s rank=%d net=%d bonus=%d, want %s rank=%d net=%d bonus=%d",
+							i+1, got.User.Username, got.Rank, got.NetVotes, got.BonusPoints, w.username, w.rank, w.netVotes, w.bonus)
+					}
+					if got.TotalScore != got.NetVotes+got.BonusPoints {
+						t.Errorf("%s: total score %d is not net votes + bonus", got.User.Username, got.TotalScore)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestAchievementBonusTieBreak(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes []voteFixture
+		want  map[string]int
+	}{
+		{
+			name: "earlier first vote wins a tie",
+			votes: []voteFixture{
+				{from: "dave", to: "alice", achievement: "pro-player", points: 2, at: 10 * time.Minute},
+				{from: "dave", to: "bob", achievement: "pro-player", points: 2, at: 5 * time.Minute},
+				{from: "dave", to: "carol", achievement: "pro-player", points: 2, at: 20 * time.Minute},
+			},
+			want: map[string]int{"bob": 5, "alice": 3, "carol": 2},
+		},
+		{
+			name: "more points beat an earlier vote",
+			votes: []voteFixture{
+				{from: "dave", to: "alice", achievement: "pro-player", points: 1, at: 0},
+				{from: "dave", to: "bob", achievement: "pro-player", points: 3, at: time.Hour},
+			},
+			want: map[string]int{"bob": 5, "alice": 3},
+		},
+		{
+			name: "first vote is the earliest of several",
+			votes: []voteFixture{
+				{from: "dave", to: "alice", achievement: "teamplayer", points: 1, at: 30 * time.Minute},
+				{from: "carol", to: "alice", achievement: "teamplayer", points: 1, at: -time.Hour},
+				{from: "dave", to: "bob", achievement: "teamplayer", points: 2, at: 0},
+			},
+			want: map[string]int{"alice": 5, "bob": 3},
+		},
+		{
+			name: "only the top three get a bonus",
+			votes: []voteFixture{
+				{from: "alice", to: "bob", achievement: "support-hero", points: 3},
+				{from: "alice", to: "carol", achievement: "support-hero", points: 2},
+				{from: "alice", to: "dave", achievement: "support-hero", points: 1, at: time.Minute},
+				{from: "bob", to: "alice", achievement: "support-hero", points: 1, at: 2 * time.Minute},
+			},
+			want: map[string]int{"bob": 5, "carol": 3, "dave": 2},
+		},
+		{
+			name: "bonus adds up across achievements",
+			votes: []voteFixture{
+				{from: "bob", to: "alice", achievement: "pro-player", points: 1},
+				{from: "bob", to: "alice", achievement: "stratege", points: 1},
+			},
+			want: map[string]int{"alice": 10},
+		},
+		{
+			name: "negative and invalidated votes give no bonus",
+			votes: []voteFixture{
+				{from: "bob", to: "alice", achievement: "toxic", points: 3},
+				{from: "alice", to: "bob", achievement: "good-sport", points: 3, invalidated: true},
+			},
+			want: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtest.Run(t, func(t *testing.T) {
+				users := createUsers(t, "alice", "bob", "carol", "dave")
+				createVotes(t, users, tt.votes)
+
+				bonus, err := NewVoteRepository().getAchievementBonusPoints()
+				if err != nil {
+					t.Fatalf("getAchievementBonusPoints failed: %v", err)
+				}
+
+				got := make(map[string]int)
+				for username, user := range users {
+					if points := bonus[user.ID]; points != 0 {
+						got[username] = points
+					}
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("got bonus %v, want %v", got, tt.want)
+				}
+				for username, points := range tt.want {
+					if got[username] != points {
+						t.Errorf("%s: got bonus %d, want %d (all: %v)", username, got[username], points, got)
+					}
+				}
+			})
+		})
+	}
+}
@@ -4,47 +4,59 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
 )
 
 const (
 	avatarsDir = "data/avatars"
+	// maxAvatarSize limits downloaded avatars, Steam's full size avatars are a few KB
+	maxAvatarSize = 2 << 20
 )
 
+// AvatarSizes are the widths avatars can be resized to
+var AvatarSizes = []int{32, 64, 128}
+
+// smallAvatarSize is the width used for avatar_small
+const smallAvatarSize = 64
+
 // AvatarCacheService handles caching of Steam avatars locally
+// Avatars are evicted when the disk quota is exceeded. An evicted avatar is downloaded again
+// when it is requested, as long as its source URL is known since the last restart.
 type AvatarCacheService struct {
-	httpClient *http.Client
+	cache      *fileCache
 	baseDir    string
 	backendURL string
+	// offline keeps local URLs for avatars that failed to download instead of falling back to Steam
+	offline bool
+	// sources maps cached filenames to the URL they were downloaded from
+	sourcesMu sync.Mutex
+	sources   map[string]string
 }
 
 // NewAvatarCacheService creates a new avatar cache service
-func NewAvatarCacheService(backendURL string) *AvatarCacheService {
-	svc := &AvatarCacheService{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		baseDir:    avatarsDir,
-		backendURL: strings.TrimSuffix(backendURL, "/"),
-	}
-
-	// Ensure the avatars directory exists at startup
-	if err := svc.ensureDir(); err != nil {
-		log.Printf("Warning: Could not create avatars directory: %v", err)
-	}
-
-	return svc
+func NewAvatarCacheService(cfg *config.Config) *AvatarCacheService {
+	s := newAvatarCacheService(avatarsDir, cfg.BackendURL, int64(cfg.AvatarCacheMaxMB)<<20, cfg.ImageCacheMaxAge,
+		&http.Client{Timeout: 30 * time.Second})
+	s.offline = cfg.OfflineMedia
+	return s
 }
 
-// ensureDir creates the avatars directory if it doesn't exist
-func (s *AvatarCacheService) ensureDir() error {
-	return os.MkdirAll(s.baseDir, 0755)
+// newAvatarCacheService creates an avatar cache service for the given directory
+func newAvatarCacheService(baseDir, backendURL string, maxBytes int64, maxAge time.Duration, httpClient *http.Client) *AvatarCacheService {
+	return &AvatarCacheService{
+		cache:      newFileCache("avatar", baseDir, maxBytes, maxAge, maxAvatarSize, httpClient, nil),
+		baseDir:    baseDir,
+		backendURL: strings.TrimSuffix(backendURL, "/"),
+		sources:    make(map[string]string),
+	}
 }
 
 // hashURL creates a deterministic filename from an avatar URL
@@ -66,14 +78,12 @@ func (s *AvatarCacheService) GetAvatarFilename(steamID string, avatarURL string)
 
 // GetAvatarPath returns the local file path for a user's avatar
 func (s *AvatarCacheService) GetAvatarPath(steamID string, avatarURL string) string {
-	return filepath.Join(s.baseDir, s.GetAvatarFilename(steamID, avatarURL))
+	return s.cache.path(s.GetAvatarFilename(steamID, avatarURL))
 }
 
 // HasAvatar checks if an avatar is already cached locally
 func (s *AvatarCacheService) HasAvatar(steamID string, avatarURL string) bool {
-	path := s.GetAvatarPath(steamID, avatarURL)
-	_, err := os.Stat(path)
-	return err == nil
+	return s.cache.has(s.GetAvatarFilename(steamID, avatarURL))
 }
 
 // GetLocalAvatarURL returns the full URL for serving the cached avatar
@@ -83,52 +93,49 @@ func (s *AvatarCacheService) GetLocalAvatarURL(steamID string, avatarURL string)
 	return fmt.Sprintf("%s/api/v1/avatars/%s", s.backendURL, filename)
 }
 
-// CacheAvatar downloads and caches a user's avatar
-// Returns the local URL if successful, or the original URL as fallback
-func (s *AvatarCacheService) CacheAvatar(steamID string, avatarURL string) string {
-	if avatarURL == "" {
-		return ""
-	}
-
-	// Skip if already cached
-	if s.HasAvatar(steamID, avatarURL) {
-		return s.GetLocalAvatarURL(steamID, avatarURL)
+// GetLocalFilename returns the cache filename of an avatar URL served by this backend
+// Returns false for external URLs.
+func (s *AvatarCacheService) GetLocalFilename(avatarURL string) (string, bool) {
+	filename, ok := strings.CutPrefix(avatarURL, s.backendURL+"/api/v1/avatars/")
+	if !ok || filename == "" || strings.Contains(filename, "/") {
+		return "", false
 	}
+	filename, _, _ = strings.Cut(filename, "?")
+	return filename, true
+}
 
-	// Ensure directory exists
-	if err := s.ensureDir(); err != nil {
-		log.Printf("Failed to create avatar cache directory: %v", err)
+// GetSmallAvatarURL returns the URL of the small version of a cached avatar
+// SVG avatars scale without loss and URLs that are not served locally are returned unchanged.
+func (s *AvatarCacheService) GetSmallAvatarURL(avatarURL string) string {
+	if !strings.HasPrefix(avatarURL, s.backendURL+"/api/v1/avatars/") || !strings.HasSuffix(avatarURL, ".jpg") {
 		return avatarURL
 	}
+	return fmt.Sprintf("%s?size=%d", avatarURL, smallAvatarSize)
+}
 
-	// Download the avatar
-	resp, err := s.httpClient.Get(avatarURL)
-	if err != nil {
-		log.Printf("Failed to download avatar for user %s from %s: %v", steamID, avatarURL, err)
-		return avatarURL
+// CacheAvatar downloads and caches a user's avatar
+// Returns the local URL if successful, or the original URL as fallback. In offline mode the
+// local URL is returned anyway, the download is retried when the avatar is requested.
+func (s *AvatarCacheService) CacheAvatar(steamID string, avatarURL string) string {
+	if avatarURL == "" {
+		return ""
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Failed to download avatar for user %s from %s: HTTP %d", steamID, avatarURL, resp.StatusCode)
-		return avatarURL
-	}
+	filename := s.GetAvatarFilename(steamID, avatarURL)
+	s.sourcesMu.Lock()
+	s.sources[filename] = avatarURL
+	s.sourcesMu.Unlock()
 
-	// Create the local file
-	localPath := s.GetAvatarPath(steamID, avatarURL)
-	file, err := os.Create(localPath)
-	if err != nil {
-		log.Printf("Failed to create avatar file for user %s: %v", steamID, err)
-		return avatarURL
+	// Skip if already cached
+	if s.cache.has(filename) {
+		return s.GetLocalAvatarURL(steamID, avatarURL)
 	}
-	defer file.Close()
 
-	// Copy the avatar data
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		log.Printf("Failed to save avatar for user %s: %v", steamID, err)
-		// Clean up partial file
-		os.Remove(localPath)
+	if err := s.cache.fetch(filename, avatarURL); err != nil {
+		log.Printf("Failed to cache avatar for user %s from %s: %v", steamID, avatarURL, err)
+		if s.offline {
+			return s.GetLocalAvatarURL(steamID, avatarURL)
+		}
 		return avatarURL
 	}
 
@@ -146,14 +153,46 @@ func (s *AvatarCacheService) CacheAvatarAsync(steamID string, avatarURL string)
 // GetAvatarByFilename returns the full path to an avatar file by its filename
 // Used for serving cached avatars
 func (s *AvatarCacheService) GetAvatarByFilename(filename string) string {
-	return filepath.Join(s.baseDir, filename)
+	return s.cache.path(filename)
 }
 
 // HasAvatarFile checks if an avatar file exists by filename
 func (s *AvatarCacheService) HasAvatarFile(filename string) bool {
-	path := s.GetAvatarByFilename(filename)
-	_, err := os.Stat(path)
-	return err == nil
+	return s.cache.has(filename)
+}
+
+// GetAvatarFile returns the path of an avatar for serving it
+// An evicted avatar is downloaded again if its source URL is known.
+func (s *AvatarCacheService) GetAvatarFile(filename string) (string, bool) {
+	if s.cache.get(filename) {
+		return s.cache.path(filename), true
+	}
+
+	s.sourcesMu.Lock()
+	source, ok := s.sources[filename]
+	s.sourcesMu.Unlock()
+	if !ok {
+		return "", false
+	}
+	if err := s.cache.fetch(filename, source); err != nil {
+		log.Printf("Failed to cache avatar %s from %s: %v", filename, source, err)
+		return "", false
+	}
+	return s.cache.path(filename), true
+}
+
+// GetAvatarVariant returns the path of an avatar resized to one of AvatarSizes
+// The original must be cached already, see GetAvatarFile.
+func (s *AvatarCacheService) GetAvatarVariant(filename string, width int) (string, error) {
+	if !slices.Contains(AvatarSizes, width) {
+		return "", fmt.Errorf("%w: %d", ErrUnsupportedSize, width)
+	}
+	return s.cache.variant(filename, width)
+}
+
+// Stats returns size and hit rate of the avatar cache
+func (s *AvatarCacheService) Stats() CacheStats {
+	return s.cache.stats()
 }
 
 // GetBaseDir returns the base directory for cached avatars
@@ -162,7 +201,7 @@ func (s *AvatarCacheService) GetBaseDir() string {
 }
 
 // CleanupOldAvatars removes old avatar files for a user (e.g., when avatar changes)
-// Keeps only the current avatar file
+// Keeps only the current avatar file and its resized variants
 func (s *AvatarCacheService) CleanupOldAvatars(steamID string, currentFilename string) {
 	pattern := filepath.Join(s.baseDir, steamID+"_*")
 	matches, err := filepath.Glob(pattern)
@@ -171,14 +210,16 @@ func (s *AvatarCacheService) CleanupOldAvatars(steamID string, currentFilename s
 		return
 	}
 
-	currentPath := filepath.Join(s.baseDir, currentFilename)
+	currentVariants := variantPrefix(currentFilename)
 	for _, match := range matches {
-		if match != currentPath {
-			if err := os.Remove(match); err != nil {
-				log.Printf("Failed to remove old avatar %s: %v", match, err)
-			} else {
-				log.Printf("Cleaned up old avatar: %s", match)
-			}
+		filename := filepath.Base(match)
+		if filename == currentFilename || strings.HasPrefix(filename, currentVariants) || strings.HasSuffix(filename, tempFileSuffix) {
+			continue
+		}
+		if err := s.cache.remove(filename); err != nil {
+			log.Printf("Failed to remove old avatar %s: %v", match, err)
+		} else {
+			log.Printf("Cleaned up old avatar: %s", match)
 		}
 	}
 }
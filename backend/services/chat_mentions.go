@@ -0,0 +1,58 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// findMentions returns the players mentioned with @username in a message, in order of appearance
+// Steam names may contain spaces, so the longest matching username wins ("@Pixel Pirate" over
+// "@Pixel"). Names are matched case-insensitively and must not be followed by a letter or digit,
+// an @ directly after a letter or digit (e-mail addresses) is not a mention.
+func findMentions(message string, users []models.User) []models.User {
+	var mentioned []models.User
+	seen := make(map[uint64]bool)
+
+	for i := 0; i < len(message); i++ {
+		if message[i] != '@' {
+			continue
+		}
+		if before, _ := utf8.DecodeLastRuneInString(message[:i]); i > 0 && isMentionRune(before) {
+			continue
+		}
+
+		rest := message[i+1:]
+		best := -1
+		for j, user := range users {
+			name := user.Username
+			if name == "" || len(name) > len(rest) || !strings.EqualFold(rest[:len(name)], name) {
+				continue
+			}
+			if next, _ := utf8.DecodeRuneInString(rest[len(name):]); len(rest) > len(name) && isMentionRune(next) {
+				continue
+			}
+			if best < 0 || len(name) > len(users[best].Username) {
+				best = j
+			}
+		}
+		if best < 0 {
+			continue
+		}
+
+		if !seen[users[best].ID] {
+			seen[users[best].ID] = true
+			mentioned = append(mentioned, users[best])
+		}
+		i += len(users[best].Username)
+	}
+
+	return mentioned
+}
+
+// isMentionRune reports whether r would continue a name
+func isMentionRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
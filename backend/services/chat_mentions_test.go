@@ -0,0 +1,40 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestFindMentions(t *testing.T) {
+	users := []models.User{
+		{ID: 1, Username: "Pixel"},
+		{ID: 2, Username: "Pixel Pirate"},
+		{ID: 3, Username: "Fragmaster"},
+		{ID: 4, Username: "Jörg"},
+	}
+
+	tests := []struct {
+		message string
+		want    []uint64
+	}{
+		{"no mentions here", nil},
+		{"@fragmaster ready?", []uint64{3}},
+		{"gg @Pixel Pirate and @Pixel!", []uint64{2, 1}},
+		{"@Fragmaster, @FRAGMASTER", []uint64{3}},
+		{"@Fragmaster2 is someone else", nil},
+		{"mail me at jörg@Fragmaster.de", nil},
+		{"(@Jörg)", []uint64{4}},
+		{"@", nil},
+	}
+	for _, tt := range tests {
+		var got []uint64
+		for _, user := range findMentions(tt.message, users) {
+			got = append(got, user.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("findMentions(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
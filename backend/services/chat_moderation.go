@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Errors returned when a message is held back by chat moderation
+var (
+	ErrChatMuted          = errors.New("you are muted in the chat")
+	ErrChatMessageBlocked = errors.New("message contains a filtered word")
+	ErrChatSlowMode       = errors.New("slow mode is active")
+	ErrChatRateLimited    = errors.New("too many messages")
+	ErrChatMuteNotFound   = errors.New("player is not muted")
+)
+
+// chatRateWindow is the period the per-player message limit applies to
+const chatRateWindow = time.Minute
+
+// Word filter modes
+const (
+	ChatWordFilterReplace = "replace" // Mask filtered words with asterisks
+	ChatWordFilterBlock   = "block"   // Reject messages containing filtered words
+)
+
+// ChatRateLimitError is returned when a player has to wait before sending another message
+type ChatRateLimitError struct {
+	Err        error // ErrChatSlowMode or ErrChatRateLimited
+	RetryAfter time.Duration
+}
+
+func (e *ChatRateLimitError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", e.Err, e.RetryAfter)
+}
+
+func (e *ChatRateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// chatWordPattern matches the words of a message, letters and digits in any script
+var chatWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// chatWordFilter finds filtered words in chat messages
+// Words are matched as a whole and case-insensitively, so "class" is not caught by "ass".
+type chatWordFilter struct {
+	words map[string]bool
+}
+
+// newChatWordFilter creates a filter for the given words
+func newChatWordFilter(words []string) *chatWordFilter {
+	f := &chatWordFilter{words: make(map[string]bool, len(words))}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			f.words[word] = true
+		}
+	}
+	return f
+}
+
+// apply masks every filtered word with asterisks and reports whether one was found
+func (f *chatWordFilter) apply(message string) (string, bool) {
+	if len(f.words) == 0 {
+		return message, false
+	}
+
+	var b strings.Builder
+	found := false
+	last := 0
+	for _, loc := range chatWordPattern.FindAllStringIndex(message, -1) {
+		word := message[loc[0]:loc[1]]
+		if !f.words[strings.ToLower(word)] {
+			continue
+		}
+		found = true
+		b.WriteString(message[last:loc[0]])
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(word)))
+		last = loc[1]
+	}
+	if !found {
+		return message, false
+	}
+	b.WriteString(message[last:])
+	return b.String(), true
+}
+
+// chatRateLimiter tracks when players sent their last messages
+type chatRateLimiter struct {
+	mu       sync.Mutex
+	sent     map[uint64][]time.Time // Send times within the rate window, oldest first
+	last     map[uint64]time.Time   // Last send time, kept for slow modes longer than the rate window
+	previous map[uint64]time.Time   // Send time before the last one, restored by release
+	prunedAt time.Time
+}
+
+// newChatRateLimiter creates an empty rate limiter
+func newChatRateLimiter() *chatRateLimiter {
+	return &chatRateLimiter{
+		sent:     make(map[uint64][]time.Time),
+		last:     make(map[uint64]time.Time),
+		previous: make(map[uint64]time.Time),
+	}
+}
+
+// reserve records a message sent at now, unless the player has to wait
+// limit is the number of messages per rate window (0 = unlimited), slowMode the
+// minimum time between two messages (0 = off).
+func (l *chatRateLimiter) reserve(userID uint64, now time.Time, limit int, slowMode time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.last[userID]; ok && slowMode > 0 {
+		if wait := last.Add(slowMode).Sub(now); wait > 0 {
+			return &ChatRateLimitError{Err: ErrChatSlowMode, RetryAfter: wait}
+		}
+	}
+
+	// Forget messages that left the rate window
+	sent := l.sent[userID]
+	for len(sent) > 0 && !sent[0].Add(chatRateWindow).After(now) {
+		sent = sent[1:]
+	}
+	if limit > 0 && len(sent) >= limit {
+		l.sent[userID] = sent
+		return &ChatRateLimitError{Err: ErrChatRateLimited, RetryAfter: sent[len(sent)-limit].Add(chatRateWindow).Sub(now)}
+	}
+
+	l.sent[userID] = append(sent, now)
+	if last, ok := l.last[userID]; ok {
+		l.previous[userID] = last
+	}
+	l.last[userID] = now
+	return nil
+}
+
+// release gives back the player's latest reservation when their message was not sent after all
+func (l *chatRateLimiter) release(userID uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if sent := l.sent[userID]; len(sent) > 0 {
+		l.sent[userID] = sent[:len(sent)-1]
+	}
+	if previous, ok := l.previous[userID]; ok {
+		l.last[userID] = previous
+		delete(l.previous, userID)
+	} else {
+		delete(l.last, userID)
+	}
+}
+
+// prune forgets players who can't be limited anymore, at most once per rate window
+// slowMode is the current slow mode, players are kept as long as it or the rate window lasts.
+func (l *chatRateLimiter) prune(now time.Time, slowMode time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.prunedAt) < chatRateWindow {
+		return
+	}
+	l.prunedAt = now
+
+	keep := chatRateWindow
+	if slowMode > keep {
+		keep = slowMode
+	}
+	for userID, last := range l.last {
+		if !last.Add(keep).After(now) {
+			delete(l.sent, userID)
+			delete(l.last, userID)
+			delete(l.previous, userID)
+		}
+	}
+}
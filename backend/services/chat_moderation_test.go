@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChatWordFilter(t *testing.T) {
+	filter := newChatWordFilter([]string{"Noob", " ass ", "Idiot"})
+
+	tests := []struct {
+		message string
+		want    string
+		found   bool
+	}{
+		{"gg wp", "gg wp", false},
+		{"what a NOOB!", "what a ****!", true},
+		{"class and assets stay", "class and assets stay", false},
+		{"noob, idiot", "****, *****", true},
+		{"Größter noob", "Größter ****", true},
+	}
+	for _, tt := range tests {
+		got, found := filter.apply(tt.message)
+		if got != tt.want || found != tt.found {
+			t.Errorf("apply(%q) = %q, %v, want %q, %v", tt.message, got, found, tt.want, tt.found)
+		}
+	}
+
+	if got, found := newChatWordFilter(nil).apply("noob"); got != "noob" || found {
+		t.Errorf("expected an empty filter to keep the message, got %q", got)
+	}
+}
+
+func TestChatRateLimiter(t *testing.T) {
+	limiter := newChatRateLimiter()
+	start := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	// Three messages per minute
+	for i := 0; i < 3; i++ {
+		if err := limiter.reserve(1, start.Add(time.Duration(i)*time.Second), 3, 0); err != nil {
+			t.Fatalf("message %d: unexpected error %v", i, err)
+		}
+	}
+	var rateLimitErr *ChatRateLimitError
+	err := limiter.reserve(1, start.Add(10*time.Second), 3, 0)
+	if !errors.As(err, &rateLimitErr) || !errors.Is(err, ErrChatRateLimited) || rateLimitErr.RetryAfter != 50*time.Second {
+		t.Fatalf("expected to retry after 50s, got %v", err)
+	}
+	if err := limiter.reserve(2, start.Add(10*time.Second), 3, 0); err != nil {
+		t.Errorf("expected other players to be unaffected, got %v", err)
+	}
+	if err := limiter.reserve(1, start.Add(time.Minute), 3, 0); err != nil {
+		t.Errorf("expected the first message to have left the window, got %v", err)
+	}
+
+	// Slow mode applies even after the rate window
+	if err := limiter.reserve(3, start, 0, 2*time.Minute); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	err = limiter.reserve(3, start.Add(90*time.Second), 0, 2*time.Minute)
+	if !errors.As(err, &rateLimitErr) || !errors.Is(err, ErrChatSlowMode) || rateLimitErr.RetryAfter != 30*time.Second {
+		t.Errorf("expected to retry after 30s, got %v", err)
+	}
+	if err := limiter.reserve(3, start.Add(2*time.Minute), 0, 2*time.Minute); err != nil {
+		t.Errorf("expected the slow mode to be over, got %v", err)
+	}
+
+	// Messages that were not sent after all give their slot back
+	if err := limiter.reserve(4, start, 1, time.Minute); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	limiter.release(4)
+	if err := limiter.reserve(4, start.Add(time.Second), 1, time.Minute); err != nil {
+		t.Errorf("expected the released slot to be free again, got %v", err)
+	}
+
+	// Players who can't be limited anymore are forgotten
+	limiter.prune(start.Add(3*time.Minute), 2*time.Minute)
+	if _, ok := limiter.last[3]; !ok {
+		t.Error("expected player 3 to be kept while the slow mode lasts")
+	}
+	limiter.prune(start.Add(3*time.Minute+30*time.Second), 0)
+	if _, ok := limiter.last[3]; !ok {
+		t.Error("expected pruning to run at most once per rate window")
+	}
+	limiter.prune(start.Add(20*time.Minute), 2*time.Minute)
+	if len(limiter.sent) != 0 || len(limiter.last) != 0 || len(limiter.previous) != 0 {
+		t.Errorf("expected all players to be forgotten, got %d", len(limiter.last))
+	}
+}
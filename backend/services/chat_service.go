@@ -0,0 +1,724 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// Errors returned by the chat service
+var (
+	ErrInvalidChatMessage    = errors.New("invalid chat message")
+	ErrChatMessageNotFound   = errors.New("chat message not found")
+	ErrChatForbidden         = errors.New("only the author can change this message")
+	ErrChatEditWindowExpired = errors.New("message can no longer be changed")
+	ErrChatUserNotFound      = errors.New("player not found")
+	ErrInvalidChatReaction   = errors.New("invalid reaction")
+	ErrChatChannelNotFound   = errors.New("chat channel not found")
+	ErrChatChannelReadOnly   = errors.New("only admins can post in this channel")
+	ErrChatChannelPermanent  = errors.New("everybody is in this channel")
+)
+
+// Limits for chat messages and history pages
+const (
+	maxChatMessageLength = 500
+	defaultChatPageSize  = 50
+	maxChatPageSize      = 100
+	maxChatReactionRunes = 10 // Enough for emoji sequences with skin tones and joiners
+	maxChatReactionKinds = 20 // Different emojis per message
+)
+
+// ChatService manages the chat
+// Authors can edit and delete their messages within the configured edit window,
+// moderators can delete any message at any time, mute players and enable slow mode.
+// Messages belong to a channel: everybody is in the general and the announcements channel
+// (where only admins post), game channels are created when the first player joins them and
+// only their members receive their messages live.
+type ChatService struct {
+	cfg           *config.Config
+	chatRepo      *repository.ChatRepository
+	userRepo      *repository.UserRepository
+	gameCacheRepo *repository.GameCacheRepository
+	wsHub         *websocket.Hub
+	wordFilter    *chatWordFilter
+	rateLimiter   *chatRateLimiter
+
+	mu       sync.RWMutex
+	slowMode time.Duration // Starts with CHAT_SLOW_MODE, changed by moderators
+}
+
+// NewChatService creates a new chat service
+func NewChatService(cfg *config.Config, chatRepo *repository.ChatRepository, userRepo *repository.UserRepository, gameCacheRepo *repository.GameCacheRepository, wsHub *websocket.Hub) *ChatService {
+	if cfg.ChatWordFilterMode != ChatWordFilterReplace && cfg.ChatWordFilterMode != ChatWordFilterBlock {
+		log.Printf("ChatService: Unknown word filter mode %q, replacing filtered words", cfg.ChatWordFilterMode)
+	}
+	return &ChatService{
+		cfg:           cfg,
+		chatRepo:      chatRepo,
+		userRepo:      userRepo,
+		gameCacheRepo: gameCacheRepo,
+		wsHub:         wsHub,
+		wordFilter:    newChatWordFilter(cfg.ChatWordFilter),
+		rateLimiter:   newChatRateLimiter(),
+		slowMode:      cfg.ChatSlowMode,
+	}
+}
+
+// History returns a page of general channel messages older than the message with ID before, oldest first
+// before 0 returns the most recent messages. Invalid limits fall back to the default page size.
+func (s *ChatService) History(before uint64, limit int) (*models.ChatHistory, error) {
+	return s.ChannelHistory(models.GeneralChatChannelID, 0, before, limit)
+}
+
+// ChannelHistory returns a page of a channel's messages older than the message with ID before, oldest first
+// The channel is described as seen by the player userID. Every player can read every channel.
+func (s *ChatService) ChannelHistory(channelID, userID, before uint64, limit int) (*models.ChatHistory, error) {
+	channel, err := s.getChannel(channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > maxChatPageSize {
+		limit = defaultChatPageSize
+	}
+
+	// Load one message more than requested to know whether there are older ones
+	messages, err := s.chatRepo.GetBefore(channel.ID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	history := &models.ChatHistory{Channel: channel, Messages: []models.ChatMessageWithUser{}, SlowModeSeconds: int(s.SlowMode().Seconds())}
+	if len(messages) > limit {
+		messages = messages[:limit]
+		history.HasMore = true
+	}
+
+	// Reverse order so oldest is first (for display)
+	for i := len(messages) - 1; i >= 0; i-- {
+		history.Messages = append(history.Messages, messages[i])
+	}
+	if history.HasMore {
+		history.NextCursor = history.Messages[0].ID
+	}
+	return history, nil
+}
+
+// Post creates a message in the general channel, see PostToChannel
+func (s *ChatService) Post(userID uint64, text string, parentID uint64, isModerator bool) (*models.ChatMessageWithUser, error) {
+	return s.PostToChannel(models.GeneralChatChannelID, userID, text, parentID, isModerator)
+}
+
+// PostToChannel creates a chat message with the author's current achievement badges
+// parentID is the message replied to, 0 for none, it must be in the same channel. Muted players
+// can't post, everybody else is held to the rate limit and, unless they are a moderator, to the
+// slow mode. Posting in a game channel joins it. Players mentioned with @username are notified.
+func (s *ChatService) PostToChannel(channelID, userID uint64, text string, parentID uint64, isModerator bool) (*models.ChatMessageWithUser, error) {
+	channel, err := s.getChannel(channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.ReadOnly {
+		return nil, ErrChatChannelReadOnly
+	}
+	message, err := s.prepareMessage(userID, text)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *uint64
+	if parentID > 0 {
+		parentMsg, err := s.get(parentID)
+		if errors.Is(err, ErrChatMessageNotFound) {
+			return nil, fmt.Errorf("%w: the message you reply to no longer exists", ErrInvalidChatMessage)
+		} else if err != nil {
+			return nil, err
+		}
+		if parentMsg.ChannelID != channel.ID {
+			return nil, fmt.Errorf("%w: the message you reply to is in another channel", ErrInvalidChatMessage)
+		}
+		parent = &parentID
+	}
+
+	// Get user's current achievements
+	achievements, err := s.chatRepo.GetUserAchievementBadges(userID)
+	if err != nil {
+		achievements = []models.AchievementBadge{}
+	}
+	achievementsJSON, err := json.Marshal(achievements)
+	if err != nil {
+		achievementsJSON = []byte("[]")
+	}
+
+	// Only accepted messages count against the rate limit, a rejected one doesn't join the channel
+	if err := s.reserveMessage(userID, isModerator); err != nil {
+		return nil, err
+	}
+	if channel.Kind == models.ChatChannelGame && !channel.Joined {
+		if _, err := s.chatRepo.JoinChannel(channel.ID, userID); err != nil {
+			s.rateLimiter.release(userID)
+			return nil, err
+		}
+	}
+
+	chatMsg := &models.ChatMessage{
+		ChannelID:    channel.ID,
+		UserID:       userID,
+		Message:      message,
+		Achievements: string(achievementsJSON),
+		ParentID:     parent,
+	}
+	if err := s.chatRepo.Create(chatMsg); err != nil {
+		s.rateLimiter.release(userID)
+		return nil, err
+	}
+
+	fullMsg, err := s.get(chatMsg.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s.wsHub != nil {
+		if members, err := s.channelMembers(channel); err != nil {
+			log.Printf("ChatService: Failed to load members of channel %s: %v", channel.Slug, err)
+		} else {
+			s.wsHub.BroadcastChatMessage(chatPayload(fullMsg), members)
+			s.notifyMentions(fullMsg, members)
+		}
+	}
+	return fullMsg, nil
+}
+
+// Edit replaces the text of a message, only the author can edit within the edit window
+// Every change takes a slot of the author's rate limit.
+func (s *ChatService) Edit(messageID, userID uint64, text string) (*models.ChatMessageWithUser, error) {
+	msg, err := s.get(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAuthor(msg, userID); err != nil {
+		return nil, err
+	}
+	message, err := s.prepareMessage(userID, text)
+	if err != nil {
+		return nil, err
+	}
+	if message == msg.Message {
+		return msg, nil
+	}
+
+	// Edits count against the rate limit like new messages, but the slow mode leaves typo fixes alone
+	if err := s.reserveMessage(userID, true); err != nil {
+		return nil, err
+	}
+	if err := s.chatRepo.UpdateMessage(msg.ID, message, time.Now().UTC().Truncate(time.Second)); err != nil {
+		s.rateLimiter.release(userID)
+		return nil, err
+	}
+	msg, err = s.get(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if s.wsHub != nil {
+		if members, err := s.messageChannelMembers(msg); err == nil {
+			s.wsHub.BroadcastChatMessageUpdated(chatPayload(msg), members)
+		}
+	}
+	return msg, nil
+}
+
+// Delete removes a message
+// Authors can delete their messages within the edit window, moderators any message.
+func (s *ChatService) Delete(messageID, userID uint64, isModerator bool) error {
+	msg, err := s.get(messageID)
+	if err != nil {
+		return err
+	}
+	if !isModerator {
+		if err := s.checkAuthor(msg, userID); err != nil {
+			return err
+		}
+	}
+
+	deleted, err := s.chatRepo.Delete(msg.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrChatMessageNotFound
+	}
+
+	if msg.User.ID != userID {
+		log.Printf("Chat message %d of %s deleted by moderator %d", msg.ID, msg.User.Username, userID)
+	}
+	if s.wsHub != nil {
+		if members, err := s.messageChannelMembers(msg); err == nil {
+			s.wsHub.BroadcastChatMessageDeleted(msg.ID, msg.ChannelID, members)
+		}
+	}
+	return nil
+}
+
+// React adds a player's reaction to a message and returns the message's reactions
+// Reacting twice with the same emoji has no effect.
+func (s *ChatService) React(messageID, userID uint64, emoji string) ([]models.ChatReaction, error) {
+	emoji, err := normalizeChatReaction(emoji)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMuted(userID); err != nil {
+		return nil, err
+	}
+	msg, err := s.get(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	known := false
+	for _, reaction := range msg.Reactions {
+		if reaction.Emoji == emoji {
+			known = true
+			break
+		}
+	}
+	if !known && len(msg.Reactions) >= maxChatReactionKinds {
+		return nil, fmt.Errorf("%w: a message can have at most %d different reactions", ErrInvalidChatReaction, maxChatReactionKinds)
+	}
+
+	added, err := s.chatRepo.AddReaction(messageID, userID, emoji)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return msg.Reactions, nil
+	}
+	return s.reactionsChanged(msg)
+}
+
+// Unreact removes a player's reaction from a message and returns the message's reactions
+func (s *ChatService) Unreact(messageID, userID uint64, emoji string) ([]models.ChatReaction, error) {
+	msg, err := s.get(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.chatRepo.RemoveReaction(messageID, userID, strings.TrimSpace(emoji))
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return msg.Reactions, nil
+	}
+	return s.reactionsChanged(msg)
+}
+
+// reactionsChanged loads and broadcasts the reactions of a message
+func (s *ChatService) reactionsChanged(msg *models.ChatMessageWithUser) ([]models.ChatReaction, error) {
+	reactions, err := s.chatRepo.GetReactions(msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s.wsHub != nil {
+		if members, err := s.messageChannelMembers(msg); err == nil {
+			s.wsHub.BroadcastChatReactionsUpdated(msg.ID, msg.ChannelID, reactions, members)
+		}
+	}
+	return reactions, nil
+}
+
+// Channels returns all channels as seen by a player
+func (s *ChatService) Channels(userID uint64) ([]models.ChatChannel, error) {
+	channels, err := s.chatRepo.GetChannels(userID)
+	if err != nil {
+		return nil, err
+	}
+	isAdmin, err := s.isAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range channels {
+		viewChatChannel(&channels[i], isAdmin)
+	}
+	return channels, nil
+}
+
+// Join adds a player to a game channel, so they receive its messages live
+// Everybody is in the other channels already, joining them has no effect.
+func (s *ChatService) Join(channelID, userID uint64) (*models.ChatChannel, error) {
+	channel, err := s.getChannel(channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Kind != models.ChatChannelGame || channel.Joined {
+		return channel, nil
+	}
+
+	if _, err := s.chatRepo.JoinChannel(channel.ID, userID); err != nil {
+		return nil, err
+	}
+	channel.Joined = true
+	return channel, nil
+}
+
+// JoinGame adds a player to the channel of a game from the games cache, creating the channel on first use
+func (s *ChatService) JoinGame(appID int, userID uint64) (*models.ChatChannel, error) {
+	game, err := s.gameCacheRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil || game.FetchFailed || game.Name == "" {
+		return nil, fmt.Errorf("%w: unknown game %d", ErrChatChannelNotFound, appID)
+	}
+
+	channel, err := s.chatRepo.EnsureGameChannel(appID, game.Name)
+	if err != nil {
+		return nil, err
+	}
+	return s.Join(channel.ID, userID)
+}
+
+// Leave removes a player from a game channel
+// The general and the announcements channel can't be left.
+func (s *ChatService) Leave(channelID, userID uint64) error {
+	channel, err := s.chatRepo.GetChannel(channelID)
+	if err != nil {
+		return err
+	}
+	if channel == nil {
+		return ErrChatChannelNotFound
+	}
+	if channel.Kind != models.ChatChannelGame {
+		return ErrChatChannelPermanent
+	}
+
+	_, err = s.chatRepo.LeaveChannel(channel.ID, userID)
+	return err
+}
+
+// getChannel returns a channel as seen by a player or ErrChatChannelNotFound
+func (s *ChatService) getChannel(channelID, userID uint64) (*models.ChatChannel, error) {
+	channel, err := s.chatRepo.GetChannel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, ErrChatChannelNotFound
+	}
+	if channel.Kind == models.ChatChannelGame && userID > 0 {
+		if channel.Joined, err = s.chatRepo.IsChannelMember(channel.ID, userID); err != nil {
+			return nil, err
+		}
+	}
+	isAdmin, err := s.isAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+	viewChatChannel(channel, isAdmin)
+	return channel, nil
+}
+
+// channelMembers returns the players who receive a channel's messages live, nil for everybody
+func (s *ChatService) channelMembers(channel *models.ChatChannel) ([]uint64, error) {
+	if channel.Kind != models.ChatChannelGame {
+		return nil, nil
+	}
+	return s.chatRepo.GetChannelMemberIDs(channel.ID)
+}
+
+// messageChannelMembers returns the players who receive changes of a message live, nil for everybody
+func (s *ChatService) messageChannelMembers(msg *models.ChatMessageWithUser) ([]uint64, error) {
+	channel, err := s.chatRepo.GetChannel(msg.ChannelID)
+	if err == nil && channel == nil {
+		err = ErrChatChannelNotFound
+	}
+	if err != nil {
+		log.Printf("ChatService: Failed to load channel %d of message %d: %v", msg.ChannelID, msg.ID, err)
+		return nil, err
+	}
+	members, err := s.channelMembers(channel)
+	if err != nil {
+		log.Printf("ChatService: Failed to load members of channel %s: %v", channel.Slug, err)
+	}
+	return members, err
+}
+
+// isAdmin reports whether a player is an admin, false for unknown players
+func (s *ChatService) isAdmin(userID uint64) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false, err
+	}
+	return user != nil && s.cfg.IsAdmin(user.SteamID), nil
+}
+
+// viewChatChannel sets the flags that depend on the player a channel is shown to
+// Everybody is in the general and the announcements channel, only admins post announcements.
+func viewChatChannel(channel *models.ChatChannel, isAdmin bool) {
+	switch channel.Kind {
+	case models.ChatChannelGame:
+		channel.ReadOnly = false
+	case models.ChatChannelAnnouncements:
+		channel.Joined = true
+		channel.ReadOnly = !isAdmin
+	default:
+		channel.Joined = true
+		channel.ReadOnly = false
+	}
+}
+
+// notifyMentions sends a new message to every player mentioned in it, except the author
+// members limits the notified players to a game channel's members, nil notifies everybody.
+func (s *ChatService) notifyMentions(msg *models.ChatMessageWithUser, members []uint64) {
+	if !strings.Contains(msg.Message, "@") {
+		return
+	}
+	users, err := s.userRepo.GetAll()
+	if err != nil {
+		log.Printf("ChatService: Failed to load players for mentions: %v", err)
+		return
+	}
+
+	var isMember map[uint64]bool
+	if members != nil {
+		isMember = make(map[uint64]bool, len(members))
+		for _, userID := range members {
+			isMember[userID] = true
+		}
+	}
+
+	payload := chatPayload(msg)
+	for _, user := range findMentions(msg.Message, users) {
+		if user.ID != msg.User.ID && (isMember == nil || isMember[user.ID]) {
+			s.wsHub.NotifyChatMention(user.ID, payload)
+		}
+	}
+}
+
+// SlowMode returns the minimum time between two messages of a player, 0 when slow mode is off
+func (s *ChatService) SlowMode() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.slowMode
+}
+
+// SetSlowMode changes the minimum time between two messages of a player, 0 turns slow mode off
+func (s *ChatService) SetSlowMode(slowMode time.Duration) {
+	s.mu.Lock()
+	s.slowMode = slowMode
+	s.mu.Unlock()
+
+	if s.wsHub != nil {
+		s.wsHub.BroadcastChatSlowModeUpdated(int(slowMode.Seconds()))
+	}
+}
+
+// Mute silences a player for the given duration, replacing an earlier mute
+// Moderators can't be muted.
+func (s *ChatService) Mute(userID uint64, duration time.Duration, reason, mutedBy string) (*models.ChatMute, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrChatUserNotFound
+	}
+	if s.cfg.IsModerator(user.SteamID) {
+		return nil, fmt.Errorf("%w: moderators cannot be muted", ErrChatForbidden)
+	}
+
+	now := time.Now().UTC()
+	if err := s.chatRepo.Mute(userID, strings.TrimSpace(reason), mutedBy, now.Add(duration).Truncate(time.Second)); err != nil {
+		return nil, err
+	}
+	mute, err := s.chatRepo.GetActiveMute(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if mute == nil {
+		return nil, ErrChatMuteNotFound
+	}
+
+	log.Printf("Chat: %s muted until %v by %s", user.Username, mute.MutedUntil, mutedBy)
+	if s.wsHub != nil {
+		s.wsHub.NotifyChatMuteUpdated(userID, &websocket.ChatMutePayload{
+			MutedUntil: mute.MutedUntil.Format(time.RFC3339),
+			Reason:     mute.Reason,
+		})
+	}
+	return mute, nil
+}
+
+// Unmute lifts a player's mute
+func (s *ChatService) Unmute(userID uint64) error {
+	unmuted, err := s.chatRepo.Unmute(userID)
+	if err != nil {
+		return err
+	}
+	if !unmuted {
+		return ErrChatMuteNotFound
+	}
+
+	if s.wsHub != nil {
+		s.wsHub.NotifyChatMuteUpdated(userID, &websocket.ChatMutePayload{})
+	}
+	return nil
+}
+
+// ModerationStatus returns the slow mode and all active mutes
+func (s *ChatService) ModerationStatus() (*models.ChatModerationStatus, error) {
+	mutes, err := s.chatRepo.GetActiveMutes(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &models.ChatModerationStatus{
+		SlowModeSeconds: int(s.SlowMode().Seconds()),
+		Mutes:           mutes,
+	}, nil
+}
+
+// CheckOutgoing applies the chat's mute, word filter and rate limit to a message sent outside the
+// channels (direct messages) and returns the filtered text. The slow mode only applies to channels.
+// Call ReleaseOutgoing if the message can't be stored afterwards.
+func (s *ChatService) CheckOutgoing(userID uint64, text string) (string, error) {
+	message, err := s.prepareMessage(userID, text)
+	if err != nil {
+		return "", err
+	}
+	if err := s.reserveMessage(userID, true); err != nil {
+		return "", err
+	}
+	return message, nil
+}
+
+// ReleaseOutgoing gives back the rate limit slot taken by CheckOutgoing
+func (s *ChatService) ReleaseOutgoing(userID uint64) {
+	s.rateLimiter.release(userID)
+}
+
+// reserveMessage takes a message slot of the player's rate limit
+// Moderators, edits and messages outside the channels skip the slow mode.
+func (s *ChatService) reserveMessage(userID uint64, skipSlowMode bool) error {
+	now := time.Now()
+	slowMode := s.SlowMode()
+	s.rateLimiter.prune(now, slowMode)
+	if skipSlowMode {
+		slowMode = 0
+	}
+	return s.rateLimiter.reserve(userID, now, s.cfg.ChatRateLimit, slowMode)
+}
+
+// checkMuted returns ErrChatMuted while a player is muted
+func (s *ChatService) checkMuted(userID uint64) error {
+	mute, err := s.chatRepo.GetActiveMute(userID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if mute != nil {
+		return fmt.Errorf("%w until %s", ErrChatMuted, mute.MutedUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// prepareMessage checks that a player may write and applies the word filter
+func (s *ChatService) prepareMessage(userID uint64, text string) (string, error) {
+	if err := s.checkMuted(userID); err != nil {
+		return "", err
+	}
+
+	message, err := normalizeChatMessage(text)
+	if err != nil {
+		return "", err
+	}
+	filtered, found := s.wordFilter.apply(message)
+	if found && s.cfg.ChatWordFilterMode == ChatWordFilterBlock {
+		return "", ErrChatMessageBlocked
+	}
+	return filtered, nil
+}
+
+// get returns a message or ErrChatMessageNotFound
+func (s *ChatService) get(messageID uint64) (*models.ChatMessageWithUser, error) {
+	msg, err := s.chatRepo.GetByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, ErrChatMessageNotFound
+	}
+	return msg, nil
+}
+
+// checkAuthor verifies that a user wrote a message and may still change it
+func (s *ChatService) checkAuthor(msg *models.ChatMessageWithUser, userID uint64) error {
+	if msg.User.ID != userID {
+		return ErrChatForbidden
+	}
+	if time.Since(msg.CreatedAt) > s.cfg.ChatEditWindow {
+		return fmt.Errorf("%w: messages can only be changed within %v", ErrChatEditWindowExpired, s.cfg.ChatEditWindow)
+	}
+	return nil
+}
+
+// normalizeChatMessage trims a message and cuts it to the maximum length
+func normalizeChatMessage(text string) (string, error) {
+	message := strings.TrimSpace(text)
+	if message == "" {
+		return "", fmt.Errorf("%w: message cannot be empty", ErrInvalidChatMessage)
+	}
+	if runes := []rune(message); len(runes) > maxChatMessageLength {
+		message = string(runes[:maxChatMessageLength])
+	}
+	return message, nil
+}
+
+// normalizeChatReaction trims a reaction and checks that it is a single emoji
+// Letters, digits and spaces are rejected, so reactions can't be abused as a second chat.
+func normalizeChatReaction(emoji string) (string, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" || len([]rune(emoji)) > maxChatReactionRunes {
+		return "", fmt.Errorf("%w: reactions must be a single emoji", ErrInvalidChatReaction)
+	}
+	for _, r := range emoji {
+		if r < utf8.RuneSelf || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			return "", fmt.Errorf("%w: reactions must be a single emoji", ErrInvalidChatReaction)
+		}
+	}
+	return emoji, nil
+}
+
+// chatPayload converts a message for WebSocket broadcasts
+func chatPayload(msg *models.ChatMessageWithUser) *websocket.ChatMessagePayload {
+	payload := &websocket.ChatMessagePayload{
+		ID:           msg.ID,
+		ChannelID:    msg.ChannelID,
+		UserID:       msg.User.ID,
+		Username:     msg.User.Username,
+		SteamID:      msg.User.SteamID,
+		AvatarSmall:  msg.User.AvatarSmall,
+		Message:      msg.Message,
+		Achievements: msg.Achievements,
+		CreatedAt:    msg.CreatedAt.Format(time.RFC3339),
+		Reactions:    msg.Reactions,
+	}
+	if msg.ParentID != nil {
+		payload.ParentID = *msg.ParentID
+	}
+	if msg.Parent != nil {
+		payload.Parent = msg.Parent
+	}
+	if msg.EditedAt != nil {
+		payload.EditedAt = msg.EditedAt.Format(time.RFC3339)
+	}
+	return payload
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+)
+
+// createChatUsers creates one player per username
+func createChatUsers(t *testing.T, usernames ...string) map[string]*models.User {
+	t.Helper()
+
+	userRepo := repository.NewUserRepository()
+	users := make(map[string]*models.User, len(usernames))
+	for i, username := range usernames {
+		user := &models.User{SteamID: fmt.Sprintf("765611900000001%02d", i), Username: username, LastCreditAt: time.Now().UTC()}
+		if err := userRepo.Create(user); err != nil {
+			t.Fatalf("failed to create user %s: %v", username, err)
+		}
+		users[username] = user
+	}
+	return users
+}
+
+func TestChatHistory(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := NewChatService(&config.Config{ChatEditWindow: time.Minute}, repository.NewChatRepository(), repository.NewUserRepository(), repository.NewGameCacheRepository(), nil)
+		alice := createChatUsers(t, "alice")["alice"]
+
+		for _, text := range []string{"one", "two", "three"} {
+			if _, err := service.Post(alice.ID, text, 0, false); err != nil {
+				t.Fatalf("Post failed: %v", err)
+			}
+		}
+		if _, err := service.Post(alice.ID, "   ", 0, false); !errors.Is(err, ErrInvalidChatMessage) {
+			t.Errorf("expected ErrInvalidChatMessage for a blank message, got %v", err)
+		}
+
+		history, err := service.History(0, 2)
+		if err != nil {
+			t.Fatalf("History failed: %v", err)
+		}
+		if len(history.Messages) != 2 || history.Messages[0].Message != "two" || history.Messages[1].Message != "three" || !history.HasMore {
+			t.Fatalf("unexpected first page: %+v", history)
+		}
+
+		history, err = service.History(history.NextCursor, 2)
+		if err != nil {
+			t.Fatalf("History failed: %v", err)
+		}
+		if len(history.Messages) != 1 || history.Messages[0].Message != "one" || history.HasMore || history.NextCursor != 0 {
+			t.Errorf("unexpected last page: %+v", history)
+		}
+	})
+}
+
+func TestChatEditAndDelete(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := NewChatService(&config.Config{ChatEditWindow: time.Minute}, repository.NewChatRepository(), repository.NewUserRepository(), repository.NewGameCacheRepository(), nil)
+		users := createChatUsers(t, "alice", "bob")
+		alice, bob := users["alice"], users["bob"]
+
+		msg, err := service.Post(alice.ID, "helo", 0, false)
+		if err != nil {
+			t.Fatalf("Post failed: %v", err)
+		}
+
+		if _, err := service.Edit(msg.ID, bob.ID, "hijacked"); !errors.Is(err, ErrChatForbidden) {
+			t.Errorf("expected ErrChatForbidden for another player, got %v", err)
+		}
+		edited, err := service.Edit(msg.ID, alice.ID, " hello ")
+		if err != nil {
+			t.Fatalf("Edit failed: %v", err)
+		}
+		if edited.Message != "hello" || edited.EditedAt == nil {
+			t.Errorf("expected the edited message, got %+v", edited)
+		}
+
+		// After the edit window only moderators can remove the message
+		dbtest.Exec(t, `UPDATE chat_messages SET created_at = ? WHERE id = ?`, time.Now().UTC().Add(-time.Hour), msg.ID)
+		if _, err := service.Edit(msg.ID, alice.ID, "too late"); !errors.Is(err, ErrChatEditWindowExpired) {
+			t.Errorf("expected ErrChatEditWindowExpired, got %v", err)
+		}
+		if err := service.Delete(msg.ID, alice.ID, false); !errors.Is(err, ErrChatEditWindowExpired) {
+			t.Errorf("expected ErrChatEditWindowExpired, got %v", err)
+		}
+		if err := service.Delete(msg.ID, bob.ID, true); err != nil {
+			t.Fatalf("moderator Delete failed: %v", err)
+		}
+		if err := service.Delete(msg.ID, bob.ID, true); !errors.Is(err, ErrChatMessageNotFound) {
+			t.Errorf("expected ErrChatMessageNotFound, got %v", err)
+		}
+	})
+}
+
+func TestChatModeration(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		cfg := &config.Config{
+			ModeratorSteamIDs:  []string{"76561190000000101"},
+			ChatEditWindow:     time.Minute,
+			ChatRateLimit:      3,
+			ChatWordFilter:     []string{"noob"},
+			ChatWordFilterMode: ChatWordFilterBlock,
+		}
+		service := NewChatService(cfg, repository.NewChatRepository(), repository.NewUserRepository(), repository.NewGameCacheRepository(), nil)
+		users := createChatUsers(t, "alice", "mod")
+		alice, mod := users["alice"], users["mod"]
+
+		if _, err := service.Post(alice.ID, "you NOOB", 0, false); !errors.Is(err, ErrChatMessageBlocked) {
+			t.Errorf("expected ErrChatMessageBlocked, got %v", err)
+		}
+
+		// Muted players can't post until the mute is lifted
+		if _, err := service.Mute(mod.ID, time.Hour, "", alice.SteamID); !errors.Is(err, ErrChatForbidden) {
+			t.Errorf("expected moderators to be unmutable, got %v", err)
+		}
+		mute, err := service.Mute(alice.ID, 10*time.Minute, " spam ", mod.SteamID)
+		if err != nil {
+			t.Fatalf("Mute failed: %v", err)
+		}
+		if mute.User.ID != alice.ID || mute.Reason != "spam" || time.Until(mute.MutedUntil) < 9*time.Minute {
+			t.Errorf("unexpected mute: %+v", mute)
+		}
+		if _, err := service.Post(alice.ID, "hello", 0, false); !errors.Is(err, ErrChatMuted) {
+			t.Errorf("expected ErrChatMuted, got %v", err)
+		}
+		status, err := service.ModerationStatus()
+		if err != nil || len(status.Mutes) != 1 {
+			t.Fatalf("expected one active mute, got %+v, %v", status, err)
+		}
+		if err := service.Unmute(alice.ID); err != nil {
+			t.Fatalf("Unmute failed: %v", err)
+		}
+		if err := service.Unmute(alice.ID); !errors.Is(err, ErrChatMuteNotFound) {
+			t.Errorf("expected ErrChatMuteNotFound, got %v", err)
+		}
+
+		// Slow mode holds back players, but not moderators
+		service.SetSlowMode(time.Minute)
+		hello, err := service.Post(alice.ID, "hello", 0, false)
+		if err != nil {
+			t.Fatalf("Post failed: %v", err)
+		}
+		var rateLimitErr *ChatRateLimitError
+		if _, err := service.Post(alice.ID, "hello again", 0, false); !errors.As(err, &rateLimitErr) || !errors.Is(err, ErrChatSlowMode) {
+			t.Errorf("expected ErrChatSlowMode, got %v", err)
+		}
+		var announcement *models.ChatMessageWithUser
+		for i := 0; i < 3; i++ {
+			if announcement, err = service.Post(mod.ID, "announcement", 0, true); err != nil {
+				t.Fatalf("moderator Post %d failed: %v", i, err)
+			}
+		}
+		if _, err := service.Post(mod.ID, "one too many", 0, true); !errors.Is(err, ErrChatRateLimited) {
+			t.Errorf("expected ErrChatRateLimited, got %v", err)
+		}
+
+		// Edits count against the rate limit, but not the slow mode
+		if _, err := service.Edit(hello.ID, alice.ID, "hello everyone"); err != nil {
+			t.Errorf("expected an edit during the slow mode to pass, got %v", err)
+		}
+		if _, err := service.Edit(announcement.ID, mod.ID, "announcement!"); !errors.Is(err, ErrChatRateLimited) {
+			t.Errorf("expected ErrChatRateLimited for an edit, got %v", err)
+		}
+	})
+}
+
+func TestChatRepliesAndReactions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := NewChatService(&config.Config{ChatEditWindow: time.Minute}, repository.NewChatRepository(), repository.NewUserRepository(), repository.NewGameCacheRepository(), nil)
+		users := createChatUsers(t, "alice", "bob")
+		alice, bob := users["alice"], users["bob"]
+
+		question, err := service.Post(alice.ID, "CS or Rocket League?", 0, false)
+		if err != nil {
+			t.Fatalf("Post failed: %v", err)
+		}
+		reply, err := service.Post(bob.ID, "@alice CS!", question.ID, false)
+		if err != nil {
+			t.Fatalf("reply failed: %v", err)
+		}
+		if reply.Parent == nil || reply.Parent.ID != question.ID || reply.Parent.Username != "alice" {
+			t.Errorf("expected a reply preview of the question, got %+v", reply.Parent)
+		}
+		if _, err := service.Post(bob.ID, "hello?", 999, false); !errors.Is(err, ErrInvalidChatMessage) {
+			t.Errorf("expected ErrInvalidChatMessage for a missing parent, got %v", err)
+		}
+
+		for _, tt := range []struct {
+			userID uint64
+			emoji  string
+		}{{alice.ID, "👍"}, {bob.ID, "👍"}, {bob.ID, "🔥"}, {bob.ID, " 👍 "}} {
+			if _, err := service.React(question.ID, tt.userID, tt.emoji); err != nil {
+				t.Fatalf("React failed: %v", err)
+			}
+		}
+		reactions, err := service.Unreact(question.ID, bob.ID, "🔥")
+		if err != nil {
+			t.Fatalf("Unreact failed: %v", err)
+		}
+		if len(reactions) != 1 || reactions[0].Emoji != "👍" || reactions[0].Count != 2 {
+			t.Errorf("unexpected reactions: %+v", reactions)
+		}
+		for _, emoji := range []string{"", "lol", "👍 👍", "👍👍👍👍👍👍👍👍👍👍👍"} {
+			if _, err := service.React(question.ID, bob.ID, emoji); !errors.Is(err, ErrInvalidChatReaction) {
+				t.Errorf("expected ErrInvalidChatReaction for %q, got %v", emoji, err)
+			}
+		}
+		if _, err := service.React(999, bob.ID, "👍"); !errors.Is(err, ErrChatMessageNotFound) {
+			t.Errorf("expected ErrChatMessageNotFound, got %v", err)
+		}
+
+		history, err := service.History(0, 10)
+		if err != nil {
+			t.Fatalf("History failed: %v", err)
+		}
+		if len(history.Messages) != 2 || len(history.Messages[0].Reactions) != 1 || history.Messages[1].ParentID == nil {
+			t.Errorf("unexpected history: %+v", history.Messages)
+		}
+	})
+}
+
+func TestChatChannels(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		cfg := &config.Config{AdminSteamIDs: []string{"76561190000000100"}, ChatEditWindow: time.Minute, ChatRateLimit: 10}
+		gameCacheRepo := repository.NewGameCacheRepository()
+		service := NewChatService(cfg, repository.NewChatRepository(), repository.NewUserRepository(), gameCacheRepo, nil)
+		users := createChatUsers(t, "admin", "alice", "bob")
+		admin, alice, bob := users["admin"], users["alice"], users["bob"]
+
+		if err := gameCacheRepo.InsertIfNotExists(730, "Counter-Strike 2"); err != nil {
+			t.Fatalf("failed to cache game: %v", err)
+		}
+		if _, err := service.JoinGame(999, alice.ID); !errors.Is(err, ErrChatChannelNotFound) {
+			t.Errorf("expected ErrChatChannelNotFound for an unknown game, got %v", err)
+		}
+		channel, err := service.JoinGame(730, alice.ID)
+		if err != nil {
+			t.Fatalf("JoinGame failed: %v", err)
+		}
+		if !channel.Joined || channel.ReadOnly || channel.Name != "Counter-Strike 2" {
+			t.Errorf("unexpected game channel: %+v", channel)
+		}
+
+		// Only admins post announcements, players still read them
+		if _, err := service.PostToChannel(models.AnnouncementsChatChannelID, alice.ID, "hi", 0, false); !errors.Is(err, ErrChatChannelReadOnly) {
+			t.Errorf("expected ErrChatChannelReadOnly, got %v", err)
+		}
+		announcement, err := service.PostToChannel(models.AnnouncementsChatChannelID, admin.ID, "Pizza is here!", 0, true)
+		if err != nil {
+			t.Fatalf("PostToChannel failed: %v", err)
+		}
+		history, err := service.ChannelHistory(models.AnnouncementsChatChannelID, alice.ID, 0, 0)
+		if err != nil {
+			t.Fatalf("ChannelHistory failed: %v", err)
+		}
+		if len(history.Messages) != 1 || history.Messages[0].ID != announcement.ID || !history.Channel.ReadOnly || !history.Channel.Joined {
+			t.Errorf("unexpected announcements history: %+v", history)
+		}
+
+		// Replies stay within their channel, posting in a game channel joins it
+		if _, err := service.PostToChannel(channel.ID, bob.ID, "me!", announcement.ID, false); !errors.Is(err, ErrInvalidChatMessage) {
+			t.Errorf("expected ErrInvalidChatMessage for a reply to another channel, got %v", err)
+		}
+		if _, err := service.PostToChannel(channel.ID, bob.ID, "me!", 0, false); err != nil {
+			t.Fatalf("PostToChannel failed: %v", err)
+		}
+		channels, err := service.Channels(bob.ID)
+		if err != nil {
+			t.Fatalf("Channels failed: %v", err)
+		}
+		for _, c := range channels {
+			if !c.Joined || c.ReadOnly != (c.Kind == models.ChatChannelAnnouncements) {
+				t.Errorf("unexpected channel for bob: %+v", c)
+			}
+		}
+		if history, err := service.History(0, 0); err != nil || len(history.Messages) != 0 {
+			t.Errorf("expected the general channel to be empty, got %+v, %v", history, err)
+		}
+
+		if err := service.Leave(models.GeneralChatChannelID, bob.ID); !errors.Is(err, ErrChatChannelPermanent) {
+			t.Errorf("expected ErrChatChannelPermanent, got %v", err)
+		}
+		if err := service.Leave(channel.ID, bob.ID); err != nil {
+			t.Fatalf("Leave failed: %v", err)
+		}
+
+		// A post held back by the slow mode doesn't join the channel
+		service.SetSlowMode(time.Minute)
+		if _, err := service.PostToChannel(channel.ID, bob.ID, "me again", 0, false); !errors.Is(err, ErrChatSlowMode) {
+			t.Errorf("expected ErrChatSlowMode, got %v", err)
+		}
+		if channels, _ := service.Channels(bob.ID); channels[2].Joined {
+			t.Errorf("expected bob not to rejoin the game channel, got %+v", channels[2])
+		}
+		if _, err := service.ChannelHistory(999, bob.ID, 0, 0); !errors.Is(err, ErrChatChannelNotFound) {
+			t.Errorf("expected ErrChatChannelNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// Errors returned by the custom game service
+var (
+	ErrInvalidCustomGame  = errors.New("invalid custom game")
+	ErrCustomGameNotFound = errors.New("custom game not found")
+)
+
+// Limits for custom games
+const (
+	maxCustomGameNameLength     = 100
+	maxCustomGameCategories     = 20
+	maxCustomGameCategoryLength = 64
+)
+
+// CustomGameService manages non-Steam games
+// Custom games are listed next to the Steam games. As there is no library to read ownership from,
+// players declare themselves whether they own a custom game.
+type CustomGameService struct {
+	customGameRepo    *repository.CustomGameRepository
+	gameOwnerRepo     *repository.GameOwnerRepository
+	imageCacheService *ImageCacheService
+	gameService       *GameService
+	wsHub             *websocket.Hub
+}
+
+// NewCustomGameService creates a new custom game service and registers it with the game service
+func NewCustomGameService(customGameRepo *repository.CustomGameRepository, gameOwnerRepo *repository.GameOwnerRepository, imageCacheService *ImageCacheService, gameService *GameService, wsHub *websocket.Hub) *CustomGameService {
+	s := &CustomGameService{
+		customGameRepo:    customGameRepo,
+		gameOwnerRepo:     gameOwnerRepo,
+		imageCacheService: imageCacheService,
+		gameService:       gameService,
+		wsHub:             wsHub,
+	}
+	gameService.customGameService = s
+	return s
+}
+
+// List returns all custom games
+func (s *CustomGameService) List() ([]models.CustomGame, error) {
+	return s.customGameRepo.GetAll()
+}
+
+// Create adds a new custom game
+func (s *CustomGameService) Create(req *models.CustomGameRequest, adminSteamID string) (*models.CustomGame, error) {
+	game := &models.CustomGame{CreatedBy: adminSteamID}
+	if err := applyCustomGameRequest(game, req); err != nil {
+		return nil, err
+	}
+	game.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	game.UpdatedAt = game.CreatedAt
+
+	if err := s.customGameRepo.Create(game); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Custom game %q (%d) created by %s", game.Name, game.AppID, adminSteamID)
+	s.gamesChanged(game.AppID)
+	return game, nil
+}
+
+// Update changes name, categories and max players of a custom game
+func (s *CustomGameService) Update(appID int, req *models.CustomGameRequest) (*models.CustomGame, error) {
+	game, err := s.get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCustomGameRequest(game, req); err != nil {
+		return nil, err
+	}
+	game.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+	if err := s.customGameRepo.Update(game); err != nil {
+		return nil, err
+	}
+
+	s.gamesChanged(game.AppID)
+	return game, nil
+}
+
+// Delete removes a custom game with its declared owners and cover image
+// Polls, sessions and matches of the game keep the name they were created with.
+func (s *CustomGameService) Delete(appID int) error {
+	game, err := s.get(appID)
+	if err != nil {
+		return err
+	}
+	if err := s.customGameRepo.Delete(game); err != nil {
+		return err
+	}
+	if err := s.imageCacheService.DeleteImage(game.AppID); err != nil {
+		log.Printf("Failed to delete cover image of custom game %d: %v", game.AppID, err)
+	}
+
+	log.Printf("Custom game %q (%d) deleted", game.Name, game.AppID)
+	s.gamesChanged(game.AppID)
+	return nil
+}
+
+// SetImage stores an uploaded cover image for a custom game
+func (s *CustomGameService) SetImage(appID int, r io.Reader) (*models.CustomGame, error) {
+	game, err := s.get(appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.imageCacheService.SaveImage(game.AppID, r); err != nil {
+		return nil, err
+	}
+
+	game.HasImage = true
+	game.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	if err := s.customGameRepo.Update(game); err != nil {
+		return nil, err
+	}
+
+	s.gamesChanged(game.AppID)
+	return game, nil
+}
+
+// SetOwned declares whether a player owns a custom game
+func (s *CustomGameService) SetOwned(appID int, steamID string, owned bool) error {
+	if _, err := s.get(appID); err != nil {
+		return err
+	}
+
+	if owned {
+		if err := s.gameOwnerRepo.Upsert(appID, steamID, 0); err != nil {
+			return err
+		}
+	} else if removed, err := s.gameOwnerRepo.Delete(appID, steamID); err != nil || !removed {
+		return err
+	}
+
+	s.gamesChanged(appID)
+	return nil
+}
+
+// get returns a custom game or ErrCustomGameNotFound
+func (s *CustomGameService) get(appID int) (*models.CustomGame, error) {
+	game, err := s.customGameRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, ErrCustomGameNotFound
+	}
+	return game, nil
+}
+
+// gamesChanged drops the cached games list and tells the clients to reload it
+func (s *CustomGameService) gamesChanged(appID int) {
+	s.gameService.InvalidateCache()
+	if s.wsHub != nil {
+		s.wsHub.BroadcastGamesUpdated(appID)
+	}
+}
+
+// listGames returns all custom games as entries of the games list, with their declared owners
+func (s *CustomGameService) listGames() ([]models.Game, error) {
+	customGames, err := s.customGameRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	games := make([]models.Game, 0, len(customGames))
+	for _, custom := range customGames {
+		owners, err := s.gameOwnerRepo.GetSteamIDsByAppID(custom.AppID)
+		if err != nil {
+			return nil, err
+		}
+		if owners == nil {
+			owners = []string{}
+		}
+
+		game := models.Game{
+			AppID:       custom.AppID,
+			Name:        custom.Name,
+			Categories:  custom.Categories,
+			OwnerCount:  len(owners),
+			Owners:      owners,
+			ReviewScore: -1,
+			MaxPlayers:  custom.MaxPlayers,
+			IsCustom:    true,
+		}
+		if custom.HasImage {
+			// The version changes with every upload, so browsers do not keep showing a replaced cover
+			game.HeaderImageURL = fmt.Sprintf("%s?v=%d", s.imageCacheService.GetLocalImageURL(custom.AppID), custom.UpdatedAt.Unix())
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// applyCustomGameRequest validates a request and copies it to the game
+// Categories are trimmed and deduplicated case-insensitively, keeping the first spelling.
+func applyCustomGameRequest(game *models.CustomGame, req *models.CustomGameRequest) error {
+	name := strings.TrimSpace(req.Name)
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidCustomGame)
+	case utf8.RuneCountInString(name) > maxCustomGameNameLength:
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidCustomGame, maxCustomGameNameLength)
+	case req.MaxPlayers < 0:
+		return fmt.Errorf("%w: max_players must not be negative", ErrInvalidCustomGame)
+	}
+
+	categories := []string{}
+	seen := make(map[string]bool)
+	for _, category := range req.Categories {
+		category = strings.TrimSpace(category)
+		key := strings.ToLower(category)
+		if category == "" || seen[key] {
+			continue
+		}
+		if utf8.RuneCountInString(category) > maxCustomGameCategoryLength {
+			return fmt.Errorf("%w: category %q is longer than %d characters", ErrInvalidCustomGame, category, maxCustomGameCategoryLength)
+		}
+		seen[key] = true
+		categories = append(categories, category)
+	}
+	if len(categories) > maxCustomGameCategories {
+		return fmt.Errorf("%w: at most %d categories are allowed", ErrInvalidCustomGame, maxCustomGameCategories)
+	}
+
+	game.Name = name
+	game.Categories = categories
+	game.MaxPlayers = req.MaxPlayers
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+)
+
+func TestCustomGameServiceListsGames(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		steamClient, err := steam.NewFakeClient("../defaults/steam-fake")
+		if err != nil {
+			t.Fatalf("failed to load Steam fixtures: %v", err)
+		}
+
+		gameCacheRepo := repository.NewGameCacheRepository()
+		gameOwnerRepo := repository.NewGameOwnerRepository()
+		// The games list caches Steam header images in the background, which fails right away here
+		imageCacheService := newImageCacheService(t.TempDir(), 0, 0, &http.Client{Transport: offlineTransport{}})
+		gameService := NewGameService(&config.Config{}, steamClient, repository.NewUserRepository(), gameCacheRepo, gameOwnerRepo,
+			imageCacheService, NewGameMetadataService(&config.Config{}, repository.NewGameMetadataRepository()))
+		service := NewCustomGameService(repository.NewCustomGameRepository(), gameOwnerRepo, imageCacheService, gameService, nil)
+
+		game, err := service.Create(&models.CustomGameRequest{
+			Name:       "  Bomberman LAN ",
+			Categories: []string{"LAN PvP", "lan pvp", " "},
+			MaxPlayers: 8,
+		}, "76561190000000001")
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if game.Name != "Bomberman LAN" || len(game.Categories) != 1 {
+			t.Errorf("expected a normalized custom game, got %+v", game)
+		}
+
+		// Custom games are listed even without owners and without Steam games
+		games, _, err := gameService.GetMultiplayerGamesCached()
+		if err != nil {
+			t.Fatalf("GetMultiplayerGamesCached failed: %v", err)
+		}
+		if len(games.AllGames) != 1 || !games.AllGames[0].IsCustom || games.AllGames[0].MaxPlayers != 8 {
+			t.Fatalf("expected the custom game in the games list, got %+v", games.AllGames)
+		}
+
+		// Declared owners are counted next to the owners of Steam games
+		if err := gameCacheRepo.Upsert(730, "Counter-Strike 2", []string{"Multi-player"}, nil); err != nil {
+			t.Fatalf("failed to cache game: %v", err)
+		}
+		if err := gameOwnerRepo.Upsert(730, "76561190000000001", 120); err != nil {
+			t.Fatalf("failed to add owner: %v", err)
+		}
+		for _, steamID := range []string{"76561190000000001", "76561190000000002"} {
+			if err := service.SetOwned(game.AppID, steamID, true); err != nil {
+				t.Fatalf("SetOwned failed: %v", err)
+			}
+		}
+		if err := service.SetOwned(game.AppID, "76561190000000002", false); err != nil {
+			t.Fatalf("SetOwned failed: %v", err)
+		}
+		games, _, err = gameService.GetMultiplayerGamesCached()
+		if err != nil {
+			t.Fatalf("GetMultiplayerGamesCached failed: %v", err)
+		}
+		if len(games.AllGames) != 2 {
+			t.Fatalf("expected the Steam and the custom game, got %+v", games.AllGames)
+		}
+		for _, g := range games.AllGames {
+			if g.AppID == game.AppID && (g.OwnerCount != 1 || g.Owners[0] != "76561190000000001") {
+				t.Errorf("expected one declared owner, got %+v", g)
+			}
+		}
+
+		// Ownership can only be declared for custom games
+		if err := service.SetOwned(730, "76561190000000002", true); !errors.Is(err, ErrCustomGameNotFound) {
+			t.Errorf("expected ErrCustomGameNotFound, got %v", err)
+		}
+
+		// Uploaded covers are re-encoded, anything else is rejected
+		var cover bytes.Buffer
+		if err := png.Encode(&cover, image.NewRGBA(image.Rect(0, 0, 46, 21))); err != nil {
+			t.Fatalf("failed to encode cover: %v", err)
+		}
+		if game, err = service.SetImage(game.AppID, &cover); err != nil || !game.HasImage {
+			t.Fatalf("SetImage failed: %+v, %v", game, err)
+		}
+		if !imageCacheService.HasImage(game.AppID) {
+			t.Errorf("expected the cover image to be stored")
+		}
+		if _, err := service.SetImage(game.AppID, strings.NewReader("not an image")); !errors.Is(err, ErrInvalidImage) {
+			t.Errorf("expected ErrInvalidImage, got %v", err)
+		}
+
+		if _, err := service.Update(game.AppID, &models.CustomGameRequest{Name: " "}); !errors.Is(err, ErrInvalidCustomGame) {
+			t.Errorf("expected ErrInvalidCustomGame, got %v", err)
+		}
+
+		if err := service.Delete(game.AppID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if imageCacheService.HasImage(game.AppID) {
+			t.Errorf("expected the cover image to be deleted")
+		}
+		games, _, err = gameService.GetMultiplayerGamesCached()
+		if err != nil {
+			t.Fatalf("GetMultiplayerGamesCached failed: %v", err)
+		}
+		if len(games.AllGames) != 1 || games.AllGames[0].AppID != 730 {
+			t.Errorf("expected only the Steam game after delete, got %+v", games.AllGames)
+		}
+	})
+}
+
+// offlineTransport fails every request, as if there was no internet connection
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("offline")
+}
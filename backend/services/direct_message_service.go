@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// ErrDirectMessageToSelf is returned when a player writes to themselves
+var ErrDirectMessageToSelf = errors.New("you cannot send a direct message to yourself")
+
+// DirectMessageService manages private one-to-one conversations
+// Messages are only ever sent to their two participants. The chat's mutes, word
+// filter and rate limit apply to direct messages as well.
+type DirectMessageService struct {
+	dmRepo      *repository.DirectMessageRepository
+	userRepo    *repository.UserRepository
+	chatService *ChatService
+	wsHub       *websocket.Hub
+}
+
+// NewDirectMessageService creates a new direct message service
+func NewDirectMessageService(dmRepo *repository.DirectMessageRepository, userRepo *repository.UserRepository, chatService *ChatService, wsHub *websocket.Hub) *DirectMessageService {
+	return &DirectMessageService{
+		dmRepo:      dmRepo,
+		userRepo:    userRepo,
+		chatService: chatService,
+		wsHub:       wsHub,
+	}
+}
+
+// Send delivers a direct message from sender to recipient
+func (s *DirectMessageService) Send(senderID, recipientID uint64, text string) (*models.DirectMessage, error) {
+	if senderID == recipientID {
+		return nil, ErrDirectMessageToSelf
+	}
+	sender, err := s.userRepo.GetByID(senderID)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := s.userRepo.GetByID(recipientID)
+	if err != nil {
+		return nil, err
+	}
+	if sender == nil || recipient == nil {
+		return nil, ErrChatUserNotFound
+	}
+
+	message, err := s.chatService.CheckOutgoing(senderID, text)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &models.DirectMessage{
+		SenderID:    senderID,
+		RecipientID: recipientID,
+		Message:     message,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	if err := s.dmRepo.Create(msg); err != nil {
+		s.chatService.ReleaseOutgoing(senderID)
+		return nil, err
+	}
+
+	if s.wsHub != nil {
+		payload := &websocket.DirectMessagePayload{
+			ID:                msg.ID,
+			SenderID:          sender.ID,
+			SenderUsername:    sender.Username,
+			SenderAvatarSmall: sender.AvatarSmall,
+			RecipientID:       recipient.ID,
+			Message:           msg.Message,
+			CreatedAt:         msg.CreatedAt.Format(time.RFC3339),
+		}
+		s.wsHub.NotifyDirectMessage(recipient.ID, payload)
+		s.wsHub.NotifyDirectMessage(sender.ID, payload)
+	}
+	return msg, nil
+}
+
+// Conversations returns a player's conversations with their unread counts
+func (s *DirectMessageService) Conversations(userID uint64) (*models.ConversationList, error) {
+	conversations, err := s.dmRepo.GetConversations(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.ConversationList{Conversations: conversations}
+	for _, c := range conversations {
+		list.UnreadCount += c.UnreadCount
+	}
+	return list, nil
+}
+
+// UnreadCount returns the number of unread direct messages of a player
+func (s *DirectMessageService) UnreadCount(userID uint64) (int, error) {
+	return s.dmRepo.GetUnreadCount(userID)
+}
+
+// History returns a page of the conversation with partner older than the message with ID before, oldest first
+// before 0 returns the most recent messages. Invalid limits fall back to the default page size.
+func (s *DirectMessageService) History(userID, partnerID, before uint64, limit int) (*models.DirectMessageHistory, error) {
+	partner, err := s.userRepo.GetByID(partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if partner == nil {
+		return nil, ErrChatUserNotFound
+	}
+	if limit < 1 || limit > maxChatPageSize {
+		limit = defaultChatPageSize
+	}
+
+	// Load one message more than requested to know whether there are older ones
+	messages, err := s.dmRepo.GetConversation(userID, partnerID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	history := &models.DirectMessageHistory{Partner: partner.ToPublic(), Messages: []models.DirectMessage{}}
+	if len(messages) > limit {
+		messages = messages[:limit]
+		history.HasMore = true
+	}
+
+	// Reverse order so oldest is first (for display)
+	for i := len(messages) - 1; i >= 0; i-- {
+		history.Messages = append(history.Messages, messages[i])
+	}
+	if history.HasMore {
+		history.NextCursor = history.Messages[0].ID
+	}
+	return history, nil
+}
+
+// MarkRead marks all messages from partner to the player as read and sends the partner a read receipt
+// Returns the ID of the newest message marked, 0 if there was nothing to mark.
+func (s *DirectMessageService) MarkRead(userID, partnerID uint64) (uint64, error) {
+	readAt := time.Now().UTC().Truncate(time.Second)
+	lastID, err := s.dmRepo.MarkRead(userID, partnerID, readAt)
+	if err != nil {
+		return 0, err
+	}
+	if lastID == 0 {
+		return 0, nil
+	}
+
+	if s.wsHub != nil {
+		s.wsHub.NotifyDirectMessagesRead(partnerID, &websocket.DirectMessagesReadPayload{
+			ReaderID:   userID,
+			LastReadID: lastID,
+			ReadAt:     readAt.Format(time.RFC3339),
+		})
+	}
+	return lastID, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+)
+
+func TestDirectMessageService(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		cfg := &config.Config{ChatEditWindow: time.Minute, ChatWordFilter: []string{"noob"}, ChatWordFilterMode: ChatWordFilterReplace}
+		userRepo := repository.NewUserRepository()
+		chatService := NewChatService(cfg, repository.NewChatRepository(), userRepo, repository.NewGameCacheRepository(), nil)
+		service := NewDirectMessageService(repository.NewDirectMessageRepository(), userRepo, chatService, nil)
+		users := createChatUsers(t, "alice", "bob")
+		alice, bob := users["alice"], users["bob"]
+
+		if _, err := service.Send(alice.ID, alice.ID, "note to self"); !errors.Is(err, ErrDirectMessageToSelf) {
+			t.Errorf("expected ErrDirectMessageToSelf, got %v", err)
+		}
+		if _, err := service.Send(alice.ID, 999, "hello?"); !errors.Is(err, ErrChatUserNotFound) {
+			t.Errorf("expected ErrChatUserNotFound, got %v", err)
+		}
+
+		msg, err := service.Send(alice.ID, bob.ID, " you noob ")
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if msg.Message != "you ****" {
+			t.Errorf("expected the word filter to apply, got %q", msg.Message)
+		}
+		if _, err := service.Send(bob.ID, alice.ID, "rematch?"); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+
+		list, err := service.Conversations(bob.ID)
+		if err != nil {
+			t.Fatalf("Conversations failed: %v", err)
+		}
+		if len(list.Conversations) != 1 || list.UnreadCount != 1 || list.Conversations[0].LastMessage.Message != "rematch?" {
+			t.Errorf("unexpected conversations: %+v", list)
+		}
+
+		history, err := service.History(bob.ID, alice.ID, 0, 0)
+		if err != nil {
+			t.Fatalf("History failed: %v", err)
+		}
+		if history.Partner.Username != "alice" || len(history.Messages) != 2 || history.Messages[0].ID != msg.ID || history.HasMore {
+			t.Errorf("unexpected history: %+v", history)
+		}
+
+		lastID, err := service.MarkRead(bob.ID, alice.ID)
+		if err != nil || lastID != msg.ID {
+			t.Fatalf("expected message %d to be read, got %d, %v", msg.ID, lastID, err)
+		}
+		if count, err := service.UnreadCount(bob.ID); err != nil || count != 0 {
+			t.Errorf("expected no unread messages, got %d, %v", count, err)
+		}
+		if count, err := service.UnreadCount(alice.ID); err != nil || count != 1 {
+			t.Errorf("expected one unread message for alice, got %d, %v", count, err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package services
+
+import "math"
+
+// Elo settings for match ratings
+const (
+	// InitialRating is the rating of a player before their first match in a game
+	InitialRating = 1000.0
+	// eloK is the maximum rating change of a two-team match
+	eloK = 32.0
+)
+
+// EloChanges returns the rating change of each team for a match result
+// Teams are rated by the average rating of their players. winnerTeam is the index of the
+// winning team, or -1 for a draw. With more than two teams the winner beats every other team,
+// the other teams are not compared with each other, and all changes are scaled by 1/(teams-1).
+func EloChanges(teamRatings []float64, winnerTeam int) []float64 {
+	changes := make([]float64, len(teamRatings))
+	if len(teamRatings) < 2 {
+		return changes
+	}
+
+	for i := range teamRatings {
+		for j := range teamRatings {
+			if i == j {
+				continue
+			}
+
+			var score float64
+			switch {
+			case winnerTeam < 0:
+				score = 0.5
+			case winnerTeam == i:
+				score = 1
+			case winnerTeam == j:
+				score = 0
+			default:
+				continue
+			}
+
+			expected := 1 / (1 + math.Pow(10, (teamRatings[j]-teamRatings[i])/400))
+			changes[i] += eloK * (score - expected)
+		}
+		changes[i] /= float64(len(teamRatings) - 1)
+	}
+	return changes
+}
+
+// averageRating returns the average of player ratings
+func averageRating(ratings []float64) float64 {
+	if len(ratings) == 0 {
+		return InitialRating
+	}
+	sum := 0.0
+	for _, rating := range ratings {
+		sum += rating
+	}
+	return sum / float64(len(ratings))
+}
+
+// roundRating rounds a rating to one decimal
+func roundRating(rating float64) float64 {
+	return math.Round(rating*10) / 10
+}
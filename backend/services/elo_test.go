@@ -0,0 +1,39 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEloChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		ratings []float64
+		winner  int
+		want    []float64
+	}{
+		{"even match", []float64{1000, 1000}, 0, []float64{16, -16}},
+		{"even draw", []float64{1000, 1000}, -1, []float64{0, 0}},
+		{"favourite wins", []float64{1400, 1000}, 0, []float64{2.9, -2.9}},
+		{"underdog wins", []float64{1400, 1000}, 1, []float64{-29.1, 29.1}},
+		{"underdog draws", []float64{1400, 1000}, -1, []float64{-13.1, 13.1}},
+		{"three teams", []float64{1000, 1000, 1000}, 2, []float64{-8, -8, 16}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EloChanges(tt.ratings, tt.winner)
+			sum := 0.0
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 0.05 {
+					t.Errorf("team %d: got %.2f, want %.1f", i, got[i], tt.want[i])
+				}
+				sum += got[i]
+			}
+			// Equal-sized teams neither create nor destroy rating points
+			if math.Abs(sum) > 1e-9 {
+				t.Errorf("changes do not add up to zero: %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,817 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+)
+
+// maxReportedValidationProblems limits how many problems an ImportValidationError lists
+const maxReportedValidationProblems = 20
+
+// ImportValidationError is returned when an archive fails validation before anything is written
+type ImportValidationError struct {
+	Problems []string
+}
+
+func (e *ImportValidationError) Error() string {
+	return fmt.Sprintf("invalid export archive: %s", strings.Join(e.Problems, "; "))
+}
+
+// ExportOptions controls what an archive contains
+type ExportOptions struct {
+	DirectMessages bool // Include the private messages between players, off by default
+}
+
+// ImportOptions controls how an archive is imported
+type ImportOptions struct {
+	Mode          models.ImportMode
+	ApplySettings bool // Overwrite the runtime settings with the ones from the archive
+}
+
+// ExportService creates and restores full event archives
+type ExportService struct {
+	cfg            *config.Config
+	appVersion     string
+	userRepo       *repository.UserRepository
+	voteRepo       *repository.VoteRepository
+	chatRepo       *repository.ChatRepository
+	dmRepo         *repository.DirectMessageRepository
+	gameOwnerRepo  *repository.GameOwnerRepository
+	customGameRepo *repository.CustomGameRepository
+	metadataRepo   *repository.GameMetadataRepository
+	pollRepo       *repository.PollRepository
+	sessionRepo    *repository.GameSessionRepository
+	installRepo    *repository.InstallStatusRepository
+	tournamentRepo *repository.TournamentRepository
+	ratingRepo     *repository.RatingRepository
+	exportRepo     *repository.ExportRepository
+}
+
+// NewExportService creates a new export service
+func NewExportService(cfg *config.Config, appVersion string, userRepo *repository.UserRepository, voteRepo *repository.VoteRepository, chatRepo *repository.ChatRepository, dmRepo *repository.DirectMessageRepository, gameOwnerRepo *repository.GameOwnerRepository, customGameRepo *repository.CustomGameRepository, metadataRepo *repository.GameMetadataRepository, pollRepo *repository.PollRepository, sessionRepo *repository.GameSessionRepository, installRepo *repository.InstallStatusRepository, tournamentRepo *repository.TournamentRepository, ratingRepo *repository.RatingRepository, exportRepo *repository.ExportRepository) *ExportService {
+	return &ExportService{
+		cfg:            cfg,
+		appVersion:     appVersion,
+		userRepo:       userRepo,
+		voteRepo:       voteRepo,
+		chatRepo:       chatRepo,
+		dmRepo:         dmRepo,
+		gameOwnerRepo:  gameOwnerRepo,
+		customGameRepo: customGameRepo,
+		metadataRepo:   metadataRepo,
+		pollRepo:       pollRepo,
+		sessionRepo:    sessionRepo,
+		installRepo:    installRepo,
+		tournamentRepo: tournamentRepo,
+		ratingRepo:     ratingRepo,
+		exportRepo:     exportRepo,
+	}
+}
+
+// Export builds an archive containing all event data and the current runtime settings
+func (s *ExportService) Export(opts ExportOptions) (*models.ExportArchive, error) {
+	users, err := s.userRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.voteRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	channels, err := s.chatRepo.GetAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.chatRepo.GetAllChannelMembers()
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.chatRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := s.chatRepo.GetAllReactions()
+	if err != nil {
+		return nil, err
+	}
+	mutes, err := s.chatRepo.GetAllMutes()
+	if err != nil {
+		return nil, err
+	}
+	var directMessages []models.DirectMessage
+	if opts.DirectMessages {
+		if directMessages, err = s.dmRepo.GetAll(); err != nil {
+			return nil, err
+		}
+	}
+	bans, err := s.userRepo.GetAllBannedUsers()
+	if err != nil {
+		return nil, err
+	}
+	owners, err := s.gameOwnerRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	customGames, err := s.customGameRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.metadataRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	polls, err := s.pollRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	ballots, err := s.pollRepo.GetAllBallots()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessionRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	installStatus, err := s.installRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	tournaments, err := s.tournamentRepo.GetAllForExport()
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.ratingRepo.GetAllMatches()
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.ratingRepo.GetAllRatings()
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &models.ExportArchive{
+		FormatVersion:      models.ExportFormatVersion,
+		ExportedAt:         time.Now().UTC(),
+		AppVersion:         s.appVersion,
+		SourceDB:           string(database.GetDBType()),
+		Settings:           s.currentSettings(),
+		Users:              users,
+		Votes:              votes,
+		ChatChannels:       make([]models.ExportChatChannel, 0, len(channels)),
+		ChatChannelMembers: members,
+		ChatMessages:       messages,
+		ChatReactions:      reactions,
+		ChatMutes:          mutes,
+		DirectMessages:     directMessages,
+		BannedUsers:        bans,
+		GameOwners:         make([]models.ExportGameOwner, 0, len(owners)),
+		CustomGames:        customGames,
+		GameMetadata:       metadata,
+		Polls:              polls,
+		PollBallots:        ballots,
+		GameSessions:       sessions,
+		GameInstallStatus:  installStatus,
+		Tournaments:        tournaments,
+		GameMatches:        matches,
+		PlayerRatings:      ratings,
+	}
+	for _, channel := range channels {
+		archive.ChatChannels = append(archive.ChatChannels, models.ExportChatChannel{
+			Slug:      channel.Slug,
+			Name:      channel.Name,
+			Kind:      channel.Kind,
+			AppID:     channel.AppID,
+			CreatedAt: channel.CreatedAt,
+		})
+	}
+	for _, owner := range owners {
+		archive.GameOwners = append(archive.GameOwners, models.ExportGameOwner{
+			AppID:           owner.AppID,
+			SteamID:         owner.SteamID,
+			PlaytimeForever: owner.PlaytimeForever,
+			CreatedAt:       owner.CreatedAt,
+			UpdatedAt:       owner.UpdatedAt,
+		})
+	}
+
+	// Always emit arrays instead of null for empty tables
+	if archive.Users == nil {
+		archive.Users = []models.User{}
+	}
+	if archive.Votes == nil {
+		archive.Votes = []models.Vote{}
+	}
+	if archive.ChatChannelMembers == nil {
+		archive.ChatChannelMembers = []models.ExportChatChannelMember{}
+	}
+	if archive.ChatMessages == nil {
+		archive.ChatMessages = []models.ChatMessage{}
+	}
+	if archive.ChatReactions == nil {
+		archive.ChatReactions = []models.ExportChatReaction{}
+	}
+	if archive.ChatMutes == nil {
+		archive.ChatMutes = []models.ChatMute{}
+	}
+	if archive.DirectMessages == nil {
+		archive.DirectMessages = []models.DirectMessage{}
+	}
+	if archive.BannedUsers == nil {
+		archive.BannedUsers = []models.BannedUser{}
+	}
+	if archive.GameMetadata == nil {
+		archive.GameMetadata = []models.GameMetadata{}
+	}
+	if archive.Polls == nil {
+		archive.Polls = []models.Poll{}
+	}
+	if archive.PollBallots == nil {
+		archive.PollBallots = []models.ExportPollBallot{}
+	}
+	if archive.GameSessions == nil {
+		archive.GameSessions = []models.ExportGameSession{}
+	}
+	if archive.Tournaments == nil {
+		archive.Tournaments = []models.ExportTournament{}
+	}
+	if archive.GameMatches == nil {
+		archive.GameMatches = []models.GameMatch{}
+	}
+
+	return archive, nil
+}
+
+// currentSettings returns the runtime settings as stored in an archive
+func (s *ExportService) currentSettings() models.ExportSettings {
+	settings := models.ExportSettings{
+		CreditIntervalMinutes:  s.cfg.CreditIntervalMinutes,
+		CreditMax:              s.cfg.CreditMax,
+		VotingPaused:           s.cfg.VotingPaused,
+		VoteVisibilityMode:     s.cfg.VoteVisibilityMode,
+		MinVotesForRanking:     s.cfg.MinVotesForRanking,
+		NegativeVotingDisabled: s.cfg.NegativeVotingDisabled,
+	}
+	if !s.cfg.CountdownTarget.IsZero() {
+		target := s.cfg.CountdownTarget
+		settings.CountdownTarget = &target
+	}
+	return settings
+}
+
+// Validate checks an archive for structural problems and dangling references.
+// It returns an *ImportValidationError describing all problems found.
+func (s *ExportService) Validate(archive *models.ExportArchive, applySettings bool) error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if archive == nil {
+		return &ImportValidationError{Problems: []string{"archive is empty"}}
+	}
+
+	if archive.FormatVersion < 1 || archive.FormatVersion > models.ExportFormatVersion {
+		addProblem("unsupported format_version %d (supported: 1-%d)", archive.FormatVersion, models.ExportFormatVersion)
+	}
+
+	userIDs := make(map[uint64]bool, len(archive.Users))
+	steamIDs := make(map[string]bool, len(archive.Users))
+	for _, user := range archive.Users {
+		if user.SteamID == "" {
+			addProblem("user %d has no steam_id", user.ID)
+		} else if steamIDs[user.SteamID] {
+			addProblem("duplicate user steam_id %s", user.SteamID)
+		}
+		if userIDs[user.ID] {
+			addProblem("duplicate user id %d", user.ID)
+		}
+		userIDs[user.ID] = true
+		steamIDs[user.SteamID] = true
+	}
+
+	for _, vote := range archive.Votes {
+		if !userIDs[vote.FromUserID] {
+			addProblem("vote %d references unknown from_user_id %d", vote.ID, vote.FromUserID)
+		}
+		if !userIDs[vote.ToUserID] {
+			addProblem("vote %d references unknown to_user_id %d", vote.ID, vote.ToUserID)
+		}
+		if vote.FromUserID == vote.ToUserID {
+			addProblem("vote %d is a self-vote", vote.ID)
+		}
+		if !models.IsValidAchievement(vote.AchievementID) {
+			addProblem("vote %d has unknown achievement_id %q", vote.ID, vote.AchievementID)
+		}
+		if vote.Points < 1 || vote.Points > 3 {
+			addProblem("vote %d has invalid points %d", vote.ID, vote.Points)
+		}
+	}
+
+	channelSlugs := make(map[string]bool, len(archive.ChatChannels))
+	for _, channel := range archive.ChatChannels {
+		if channel.Slug == "" {
+			addProblem("chat channel %q has no slug", channel.Name)
+		} else if channelSlugs[channel.Slug] {
+			addProblem("duplicate chat channel slug %s", channel.Slug)
+		}
+		channelSlugs[channel.Slug] = true
+		switch channel.Kind {
+		case models.ChatChannelGlobal, models.ChatChannelAnnouncements:
+		case models.ChatChannelGame:
+			if channel.AppID == nil || channel.Slug != models.GameChatChannelSlug(*channel.AppID) {
+				addProblem("game chat channel %s doesn't match its app_id", channel.Slug)
+			}
+		default:
+			addProblem("chat channel %s has unknown kind %q", channel.Slug, channel.Kind)
+		}
+	}
+
+	for _, member := range archive.ChatChannelMembers {
+		if !channelSlugs[member.Channel] {
+			addProblem("chat channel member references unknown channel %q", member.Channel)
+		}
+		if !userIDs[member.UserID] {
+			addProblem("chat channel member of %s references unknown user_id %d", member.Channel, member.UserID)
+		}
+	}
+
+	messageIDs := make(map[uint64]bool, len(archive.ChatMessages))
+	for _, msg := range archive.ChatMessages {
+		messageIDs[msg.ID] = true
+		if !userIDs[msg.UserID] {
+			addProblem("chat message %d references unknown user_id %d", msg.ID, msg.UserID)
+		}
+		if strings.TrimSpace(msg.Message) == "" {
+			addProblem("chat message %d is empty", msg.ID)
+		}
+	}
+
+	for _, reaction := range archive.ChatReactions {
+		if !messageIDs[reaction.MessageID] {
+			addProblem("chat reaction references unknown message_id %d", reaction.MessageID)
+		}
+		if !userIDs[reaction.UserID] {
+			addProblem("chat reaction on message %d references unknown user_id %d", reaction.MessageID, reaction.UserID)
+		}
+		if reaction.Emoji == "" {
+			addProblem("chat reaction on message %d has no emoji", reaction.MessageID)
+		}
+	}
+
+	mutedUserIDs := make(map[uint64]bool, len(archive.ChatMutes))
+	for _, mute := range archive.ChatMutes {
+		if !userIDs[mute.User.ID] {
+			addProblem("chat mute references unknown user_id %d", mute.User.ID)
+		}
+		if mutedUserIDs[mute.User.ID] {
+			addProblem("duplicate chat mute for user_id %d", mute.User.ID)
+		}
+		mutedUserIDs[mute.User.ID] = true
+	}
+
+	for _, msg := range archive.DirectMessages {
+		if !userIDs[msg.SenderID] {
+			addProblem("direct message %d references unknown sender_id %d", msg.ID, msg.SenderID)
+		}
+		if !userIDs[msg.RecipientID] {
+			addProblem("direct message %d references unknown recipient_id %d", msg.ID, msg.RecipientID)
+		}
+		if msg.SenderID == msg.RecipientID {
+			addProblem("direct message %d is sent to its sender", msg.ID)
+		}
+		if strings.TrimSpace(msg.Message) == "" {
+			addProblem("direct message %d is empty", msg.ID)
+		}
+	}
+
+	for _, ban := range archive.BannedUsers {
+		if ban.SteamID == "" {
+			addProblem("ban %d has no steam_id", ban.ID)
+		}
+	}
+
+	customAppIDs := make(map[int]bool, len(archive.CustomGames))
+	customGameNames := make(map[string]bool, len(archive.CustomGames))
+	for _, game := range archive.CustomGames {
+		if game.AppID != models.CustomGameAppID(game.ID) {
+			addProblem("custom game %d has app_id %d instead of %d", game.ID, game.AppID, models.CustomGameAppID(game.ID))
+		}
+		if strings.TrimSpace(game.Name) == "" {
+			addProblem("custom game %d has no name", game.ID)
+		} else if customGameNames[game.Name] {
+			addProblem("duplicate custom game name %q", game.Name)
+		}
+		customAppIDs[game.AppID] = true
+		customGameNames[game.Name] = true
+	}
+	// knownApp reports whether an app ID is a Steam game or one of the archived custom games
+	knownApp := func(appID int) bool {
+		return appID > 0 && (!models.IsCustomAppID(appID) || customAppIDs[appID])
+	}
+
+	for _, owner := range archive.GameOwners {
+		if owner.AppID <= 0 || owner.SteamID == "" {
+			addProblem("game owner entry (%d, %q) is incomplete", owner.AppID, owner.SteamID)
+		} else if !knownApp(owner.AppID) {
+			addProblem("game owner entry of %s references unknown custom game %d", owner.SteamID, owner.AppID)
+		}
+	}
+
+	for _, meta := range archive.GameMetadata {
+		if !knownApp(meta.AppID) {
+			addProblem("game metadata references unknown app_id %d", meta.AppID)
+		}
+	}
+
+	// Archive poll ID -> app IDs of its options
+	pollOptions := make(map[uint64]map[int]bool, len(archive.Polls))
+	for _, poll := range archive.Polls {
+		if pollOptions[poll.ID] != nil {
+			addProblem("duplicate poll id %d", poll.ID)
+		}
+		pollOptions[poll.ID] = make(map[int]bool, len(poll.Options))
+		if !userIDs[poll.CreatedBy.ID] {
+			addProblem("poll %d references unknown created_by %d", poll.ID, poll.CreatedBy.ID)
+		}
+		if strings.TrimSpace(poll.Title) == "" {
+			addProblem("poll %d has no title", poll.ID)
+		}
+		if !poll.VotingMethod.IsValid() {
+			addProblem("poll %d has unknown voting_method %q", poll.ID, poll.VotingMethod)
+		}
+		for _, option := range poll.Options {
+			if !knownApp(option.AppID) {
+				addProblem("poll %d has an option with unknown app_id %d", poll.ID, option.AppID)
+			} else if pollOptions[poll.ID][option.AppID] {
+				addProblem("poll %d has a duplicate option for app_id %d", poll.ID, option.AppID)
+			}
+			pollOptions[poll.ID][option.AppID] = true
+		}
+		if poll.WinnerAppID != nil && !pollOptions[poll.ID][*poll.WinnerAppID] {
+			addProblem("poll %d has a winner_app_id %d that is not one of its options", poll.ID, *poll.WinnerAppID)
+		}
+	}
+
+	// Poll ID, user ID and app ID identify a ballot row
+	type ballotKey struct {
+		pollID, userID uint64
+		appID          int
+	}
+	ballots := make(map[ballotKey]bool, len(archive.PollBallots))
+	for _, ballot := range archive.PollBallots {
+		options, ok := pollOptions[ballot.PollID]
+		if !ok {
+			addProblem("poll ballot references unknown poll_id %d", ballot.PollID)
+			continue
+		}
+		key := ballotKey{ballot.PollID, ballot.UserID, ballot.AppID}
+		if ballots[key] {
+			addProblem("ballot of user %d in poll %d lists app_id %d twice", ballot.UserID, ballot.PollID, ballot.AppID)
+		}
+		ballots[key] = true
+		if !userIDs[ballot.UserID] {
+			addProblem("ballot in poll %d references unknown user_id %d", ballot.PollID, ballot.UserID)
+		}
+		if !options[ballot.AppID] {
+			addProblem("ballot in poll %d references app_id %d that is not one of its options", ballot.PollID, ballot.AppID)
+		}
+		if ballot.Preference < 1 {
+			addProblem("ballot in poll %d has invalid preference %d", ballot.PollID, ballot.Preference)
+		}
+	}
+
+	sessionIDs := make(map[uint64]bool, len(archive.GameSessions))
+	for _, session := range archive.GameSessions {
+		if sessionIDs[session.ID] {
+			addProblem("duplicate game session id %d", session.ID)
+		}
+		sessionIDs[session.ID] = true
+		if !userIDs[session.CreatedBy] {
+			addProblem("game session %d references unknown created_by %d", session.ID, session.CreatedBy)
+		}
+		if !knownApp(session.AppID) {
+			addProblem("game session %d references unknown app_id %d", session.ID, session.AppID)
+		}
+		players := make(map[uint64]bool, len(session.PlayerIDs))
+		for _, userID := range session.PlayerIDs {
+			if !userIDs[userID] {
+				addProblem("game session %d references unknown player %d", session.ID, userID)
+			} else if players[userID] {
+				addProblem("game session %d lists player %d twice", session.ID, userID)
+			}
+			players[userID] = true
+		}
+	}
+
+	for _, status := range archive.GameInstallStatus {
+		if !userIDs[status.UserID] {
+			addProblem("install status references unknown user_id %d", status.UserID)
+		}
+		if !knownApp(status.AppID) {
+			addProblem("install status of user %d references unknown app_id %d", status.UserID, status.AppID)
+		}
+		// "none" is never stored, it is the absence of a row
+		if !status.Status.IsValid() || status.Status == models.InstallStatusNone {
+			addProblem("install status of user %d for app %d has invalid status %q", status.UserID, status.AppID, status.Status)
+		}
+		if status.Progress < 0 || status.Progress > 100 {
+			addProblem("install status of user %d for app %d has invalid progress %d", status.UserID, status.AppID, status.Progress)
+		}
+	}
+
+	tournamentIDs := make(map[uint64]bool, len(archive.Tournaments))
+	for _, t := range archive.Tournaments {
+		if tournamentIDs[t.ID] {
+			addProblem("duplicate tournament id %d", t.ID)
+		}
+		tournamentIDs[t.ID] = true
+		if !userIDs[t.CreatedBy] {
+			addProblem("tournament %d references unknown created_by %d", t.ID, t.CreatedBy)
+		}
+		if strings.TrimSpace(t.Name) == "" {
+			addProblem("tournament %d has no name", t.ID)
+		}
+		if !knownApp(t.AppID) {
+			addProblem("tournament %d references unknown app_id %d", t.ID, t.AppID)
+		}
+		if !t.Format.IsValid() {
+			addProblem("tournament %d has unknown format %q", t.ID, t.Format)
+		}
+		if !t.Status.IsValid() {
+			addProblem("tournament %d has unknown status %q", t.ID, t.Status)
+		}
+		if t.TeamSize < 1 || t.BonusPoints < 0 {
+			addProblem("tournament %d has invalid team_size %d or bonus_points %d", t.ID, t.TeamSize, t.BonusPoints)
+		}
+
+		registered := make(map[uint64]bool, len(t.Registrations))
+		for _, registration := range t.Registrations {
+			if !userIDs[registration.UserID] {
+				addProblem("registration for tournament %d references unknown user_id %d", t.ID, registration.UserID)
+			} else if registered[registration.UserID] {
+				addProblem("user %d is registered twice for tournament %d", registration.UserID, t.ID)
+			}
+			registered[registration.UserID] = true
+		}
+
+		// Team and match IDs only have to be unique within their tournament
+		teamIDs := make(map[uint64]bool, len(t.Teams))
+		for _, team := range t.Teams {
+			if teamIDs[team.ID] {
+				addProblem("tournament %d has duplicate team id %d", t.ID, team.ID)
+			}
+			teamIDs[team.ID] = true
+			members := make(map[uint64]bool, len(team.MemberIDs))
+			for _, userID := range team.MemberIDs {
+				if !userIDs[userID] {
+					addProblem("team %d of tournament %d references unknown member %d", team.ID, t.ID, userID)
+				} else if members[userID] {
+					addProblem("team %d of tournament %d lists member %d twice", team.ID, t.ID, userID)
+				}
+				members[userID] = true
+			}
+		}
+		knownTeam := func(teamID *uint64) bool {
+			return teamID == nil || teamIDs[*teamID]
+		}
+		if !knownTeam(t.WinnerTeamID) {
+			addProblem("tournament %d references unknown winner_team_id %d", t.ID, *t.WinnerTeamID)
+		}
+
+		matchIDs := make(map[uint64]bool, len(t.Matches))
+		for _, m := range t.Matches {
+			if matchIDs[m.ID] {
+				addProblem("tournament %d has duplicate match id %d", t.ID, m.ID)
+			}
+			matchIDs[m.ID] = true
+		}
+		knownMatch := func(matchID *uint64) bool {
+			return matchID == nil || matchIDs[*matchID]
+		}
+		for _, m := range t.Matches {
+			if !knownTeam(m.Team1ID) || !knownTeam(m.Team2ID) || !knownTeam(m.WinnerTeamID) {
+				addProblem("match %d of tournament %d references an unknown team", m.ID, t.ID)
+			}
+			if !knownMatch(m.WinnerNextMatchID) || !knownMatch(m.LoserNextMatchID) {
+				addProblem("match %d of tournament %d links to an unknown match", m.ID, t.ID)
+			}
+			if m.ReportedBy != nil && !userIDs[*m.ReportedBy] {
+				addProblem("match %d of tournament %d references unknown reported_by %d", m.ID, t.ID, *m.ReportedBy)
+			}
+			if !m.Status.IsValid() {
+				addProblem("match %d of tournament %d has unknown status %q", m.ID, t.ID, m.Status)
+			}
+		}
+	}
+
+	for _, match := range archive.GameMatches {
+		if !userIDs[match.RecordedBy] {
+			addProblem("match %d references unknown recorded_by %d", match.ID, match.RecordedBy)
+		}
+		if !knownApp(match.AppID) {
+			addProblem("match %d references unknown app_id %d", match.ID, match.AppID)
+		}
+		teams := make(map[int]bool)
+		players := make(map[uint64]bool, len(match.Players))
+		for _, p := range match.Players {
+			if !userIDs[p.User.ID] {
+				addProblem("match %d references unknown player %d", match.ID, p.User.ID)
+			} else if players[p.User.ID] {
+				addProblem("match %d lists player %d twice", match.ID, p.User.ID)
+			}
+			if p.Team < 1 {
+				addProblem("player %d of match %d has invalid team %d", p.User.ID, match.ID, p.Team)
+			}
+			players[p.User.ID] = true
+			teams[p.Team] = true
+		}
+		if match.WinnerTeam != nil && !teams[*match.WinnerTeam] {
+			addProblem("match %d has a winner_team %d without players", match.ID, *match.WinnerTeam)
+		}
+	}
+
+	// User ID -> app IDs with a rating
+	rated := make(map[uint64]map[int]bool)
+	for _, rating := range archive.PlayerRatings {
+		if !userIDs[rating.User.ID] {
+			addProblem("rating references unknown user_id %d", rating.User.ID)
+		}
+		if !knownApp(rating.AppID) {
+			addProblem("rating of user %d references unknown app_id %d", rating.User.ID, rating.AppID)
+		}
+		if rated[rating.User.ID] == nil {
+			rated[rating.User.ID] = make(map[int]bool)
+		} else if rated[rating.User.ID][rating.AppID] {
+			addProblem("duplicate rating of user %d for app %d", rating.User.ID, rating.AppID)
+		}
+		rated[rating.User.ID][rating.AppID] = true
+	}
+
+	if applySettings {
+		settings := archive.Settings
+		if settings.CreditIntervalMinutes < 1 || settings.CreditIntervalMinutes > 60 {
+			addProblem("settings.credit_interval_minutes must be between 1 and 60")
+		}
+		if settings.CreditMax < 1 || settings.CreditMax > 100 {
+			addProblem("settings.credit_max must be between 1 and 100")
+		}
+		switch settings.VoteVisibilityMode {
+		case "user_choice", "all_secret", "all_public":
+		default:
+			addProblem("settings.vote_visibility_mode %q is invalid", settings.VoteVisibilityMode)
+		}
+		if settings.MinVotesForRanking < 0 || settings.MinVotesForRanking > 1000 {
+			addProblem("settings.min_votes_for_ranking must be between 0 and 1000")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(problems) > maxReportedValidationProblems {
+		remaining := len(problems) - maxReportedValidationProblems
+		problems = append(problems[:maxReportedValidationProblems], fmt.Sprintf("... and %d more", remaining))
+	}
+	return &ImportValidationError{Problems: problems}
+}
+
+// Import validates an archive and restores it into the current database
+func (s *ExportService) Import(archive *models.ExportArchive, opts ImportOptions) (*models.ImportResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.ImportModeMerge
+	}
+	if opts.Mode != models.ImportModeMerge && opts.Mode != models.ImportModeReplace {
+		return nil, &ImportValidationError{Problems: []string{fmt.Sprintf("unknown import mode %q", opts.Mode)}}
+	}
+
+	if err := s.Validate(archive, opts.ApplySettings); err != nil {
+		return nil, err
+	}
+
+	normalizeArchiveTimestamps(archive)
+
+	result, err := s.exportRepo.Restore(archive, opts.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ApplySettings {
+		s.applySettings(archive.Settings)
+		result.SettingsApplied = true
+	}
+
+	log.Printf("Import (%s) from %s archive finished: %d users created, %d matched, %d votes, %d chat channels, %d chat messages, %d chat reactions, %d chat mutes, %d direct messages, %d bans, %d game owners, %d custom games, %d game metadata, %d polls, %d game sessions, %d tournaments, %d matches",
+		opts.Mode, archive.SourceDB, result.UsersCreated, result.UsersMatched, result.VotesImported,
+		result.ChatChannelsImported, result.ChatMessagesImported, result.ChatReactionsImported, result.ChatMutesImported,
+		result.DirectMessagesImported, result.BansImported, result.GameOwnersImported,
+		result.CustomGamesCreated, result.GameMetadataImported, result.PollsImported, result.GameSessionsImported, result.TournamentsImported, result.GameMatchesImported)
+
+	return result, nil
+}
+
+// applySettings overwrites the runtime settings with the archived ones
+func (s *ExportService) applySettings(settings models.ExportSettings) {
+	s.cfg.CreditIntervalMinutes = settings.CreditIntervalMinutes
+	s.cfg.CreditMax = settings.CreditMax
+	s.cfg.VoteVisibilityMode = settings.VoteVisibilityMode
+	s.cfg.MinVotesForRanking = settings.MinVotesForRanking
+	s.cfg.NegativeVotingDisabled = settings.NegativeVotingDisabled
+
+	if settings.VotingPaused && !s.cfg.VotingPaused {
+		s.cfg.VotingPausedAt = time.Now()
+	} else if !settings.VotingPaused {
+		s.cfg.VotingPausedAt = time.Time{}
+	}
+	s.cfg.VotingPaused = settings.VotingPaused
+
+	if settings.CountdownTarget != nil {
+		s.cfg.CountdownTarget = *settings.CountdownTarget
+	} else {
+		s.cfg.CountdownTarget = time.Time{}
+	}
+}
+
+// normalizeArchiveTimestamps replaces missing timestamps with the current time
+// so that hand-written or truncated archives don't end up with year-1 dates
+func normalizeArchiveTimestamps(archive *models.ExportArchive) {
+	now := time.Now().UTC()
+	fill := func(t *time.Time) {
+		if t.IsZero() {
+			*t = now
+		}
+	}
+
+	for i := range archive.Users {
+		fill(&archive.Users[i].LastCreditAt)
+		fill(&archive.Users[i].CreatedAt)
+		fill(&archive.Users[i].UpdatedAt)
+	}
+	for i := range archive.Votes {
+		fill(&archive.Votes[i].CreatedAt)
+	}
+	for i := range archive.ChatChannels {
+		fill(&archive.ChatChannels[i].CreatedAt)
+	}
+	for i := range archive.ChatChannelMembers {
+		fill(&archive.ChatChannelMembers[i].JoinedAt)
+	}
+	for i := range archive.ChatMessages {
+		fill(&archive.ChatMessages[i].CreatedAt)
+	}
+	for i := range archive.ChatReactions {
+		fill(&archive.ChatReactions[i].CreatedAt)
+	}
+	for i := range archive.ChatMutes {
+		fill(&archive.ChatMutes[i].MutedUntil)
+		fill(&archive.ChatMutes[i].CreatedAt)
+	}
+	for i := range archive.DirectMessages {
+		fill(&archive.DirectMessages[i].CreatedAt)
+	}
+	for i := range archive.BannedUsers {
+		fill(&archive.BannedUsers[i].BannedAt)
+	}
+	for i := range archive.GameOwners {
+		fill(&archive.GameOwners[i].CreatedAt)
+		fill(&archive.GameOwners[i].UpdatedAt)
+	}
+	for i := range archive.CustomGames {
+		fill(&archive.CustomGames[i].CreatedAt)
+		fill(&archive.CustomGames[i].UpdatedAt)
+	}
+	for i := range archive.GameMetadata {
+		fill(&archive.GameMetadata[i].UpdatedAt)
+	}
+	for i := range archive.Polls {
+		fill(&archive.Polls[i].CreatedAt)
+	}
+	for i := range archive.PollBallots {
+		fill(&archive.PollBallots[i].CreatedAt)
+	}
+	for i := range archive.GameSessions {
+		fill(&archive.GameSessions[i].CreatedAt)
+	}
+	for i := range archive.GameInstallStatus {
+		fill(&archive.GameInstallStatus[i].UpdatedAt)
+	}
+	for i := range archive.GameMatches {
+		fill(&archive.GameMatches[i].PlayedAt)
+		fill(&archive.GameMatches[i].CreatedAt)
+	}
+	for i := range archive.PlayerRatings {
+		fill(&archive.PlayerRatings[i].UpdatedAt)
+	}
+	for i := range archive.Tournaments {
+		fill(&archive.Tournaments[i].CreatedAt)
+		for j := range archive.Tournaments[i].Registrations {
+			fill(&archive.Tournaments[i].Registrations[j].CreatedAt)
+		}
+	}
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+)
+
+// newTestExportService creates an export service backed by the test database
+func newTestExportService() *ExportService {
+	return NewExportService(&config.Config{}, "test",
+		repository.NewUserRepository(),
+		repository.NewVoteRepository(),
+		repository.NewChatRepository(),
+		repository.NewDirectMessageRepository(),
+		repository.NewGameOwnerRepository(),
+		repository.NewCustomGameRepository(),
+		repository.NewGameMetadataRepository(),
+		repository.NewPollRepository(),
+		repository.NewGameSessionRepository(),
+		repository.NewInstallStatusRepository(),
+		repository.NewTournamentRepository(),
+		repository.NewRatingRepository(),
+		repository.NewExportRepository(),
+	)
+}
+
+// exportRoundTrip exports the database and decodes the archive again, like a download and upload
+func exportRoundTrip(t *testing.T, service *ExportService) *models.ExportArchive {
+	t.Helper()
+
+	archive, err := service.Export(ExportOptions{})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := json.Marshal(archive)
+	if err != nil {
+		t.Fatalf("failed to encode archive: %v", err)
+	}
+	var decoded models.ExportArchive
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode archive: %v", err)
+	}
+	return &decoded
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := newTestExportService()
+		customGameRepo := repository.NewCustomGameRepository()
+		gameOwnerRepo := repository.NewGameOwnerRepository()
+		metadataRepo := repository.NewGameMetadataRepository()
+		users := createChatUsers(t, "alice", "bob")
+
+		if err := repository.NewVoteRepository().Create(&models.Vote{
+			FromUserID: users["alice"].ID, ToUserID: users["bob"].ID, AchievementID: "pro-player", Points: 1,
+		}); err != nil {
+			t.Fatalf("failed to create vote: %v", err)
+		}
+		game := &models.CustomGame{Name: "Bomberman LAN", Categories: []string{"LAN PvP"}, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+		if err := customGameRepo.Create(game); err != nil {
+			t.Fatalf("failed to create custom game: %v", err)
+		}
+		if err := gameOwnerRepo.Upsert(game.AppID, users["bob"].SteamID, 0); err != nil {
+			t.Fatalf("failed to add owner: %v", err)
+		}
+		if err := metadataRepo.Save(&models.GameMetadata{AppID: game.AppID, MaxPlayers: 8, Source: models.GameMetadataSourceAdmin, UpdatedAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("failed to save metadata: %v", err)
+		}
+
+		archive := exportRoundTrip(t, service)
+		if len(archive.Users) != 2 || len(archive.Votes) != 1 || len(archive.CustomGames) != 1 || len(archive.GameMetadata) != 1 {
+			t.Fatalf("unexpected archive contents: %+v", archive)
+		}
+
+		// Recreate the custom game under another ID, its owners and metadata have to follow
+		if err := customGameRepo.Delete(game); err != nil {
+			t.Fatalf("failed to delete custom game: %v", err)
+		}
+		if err := customGameRepo.Create(&models.CustomGame{Name: "Other", CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("failed to create custom game: %v", err)
+		}
+
+		result, err := service.Import(archive, ImportOptions{Mode: models.ImportModeReplace})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.UsersCreated != 2 || result.VotesImported != 1 || result.CustomGamesCreated != 1 || result.GameMetadataImported != 1 {
+			t.Errorf("unexpected import result: %+v", result)
+		}
+
+		var restored *models.CustomGame
+		games, err := customGameRepo.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		for i := range games {
+			if games[i].Name == game.Name {
+				restored = &games[i]
+			}
+		}
+		if restored == nil || restored.AppID == game.AppID {
+			t.Fatalf("expected the custom game to be recreated under a new app ID, got %+v", games)
+		}
+		owners, err := gameOwnerRepo.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		if len(owners) != 1 || owners[0].AppID != restored.AppID {
+			t.Errorf("expected the ownership to be remapped to app ID %d, got %+v", restored.AppID, owners)
+		}
+		if meta, err := metadataRepo.Get(restored.AppID); err != nil || meta == nil || meta.MaxPlayers != 8 {
+			t.Errorf("expected the metadata to be remapped, got %+v, %v", meta, err)
+		}
+
+		// A second merge matches everything
+		archive = exportRoundTrip(t, service)
+		result, err = service.Import(archive, ImportOptions{Mode: models.ImportModeMerge})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.UsersMatched != 2 || result.VotesSkipped != 1 || result.CustomGamesMatched != 2 || result.CustomGamesCreated != 0 {
+			t.Errorf("unexpected merge result: %+v", result)
+		}
+	})
+}
+
+func TestImportRejectsUnknownCustomGames(t *testing.T) {
+	service := newTestExportService()
+	archive := &models.ExportArchive{
+		FormatVersion: models.ExportFormatVersion,
+		GameOwners:    []models.ExportGameOwner{{AppID: models.CustomGameAppID(7), SteamID: "76561190000000001"}},
+	}
+
+	var validationErr *ImportValidationError
+	if err := service.Validate(archive, false); !errors.As(err, &validationErr) {
+		t.Fatalf("expected an ImportValidationError, got %v", err)
+	}
+}
+
+func TestImportRejectsDuplicateBallots(t *testing.T) {
+	service := newTestExportService()
+	archive := &models.ExportArchive{
+		FormatVersion: models.ExportFormatVersion,
+		Users:         []models.User{{ID: 1, SteamID: "76561190000000001"}},
+		Polls: []models.Poll{{
+			ID: 1, Title: "Next game", VotingMethod: models.PollVotingSingle, CreatedBy: models.PublicUser{ID: 1},
+			Options: []models.PollOption{{AppID: 730, Name: "Counter-Strike 2"}, {AppID: 570, Name: "Dota 2"}},
+		}},
+		PollBallots: []models.ExportPollBallot{
+			{PollID: 1, UserID: 1, AppID: 730, Preference: 1},
+			{PollID: 1, UserID: 1, AppID: 730, Preference: 2},
+		},
+	}
+
+	var validationErr *ImportValidationError
+	if err := service.Validate(archive, false); !errors.As(err, &validationErr) || len(validationErr.Problems) != 1 {
+		t.Fatalf("expected a single validation problem, got %v", err)
+	}
+}
+
+func TestExportDirectMessagesOptIn(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := newTestExportService()
+		users := createChatUsers(t, "alice", "bob")
+		dm := &models.DirectMessage{SenderID: users["alice"].ID, RecipientID: users["bob"].ID, Message: "gg", CreatedAt: time.Now().UTC()}
+		if err := repository.NewDirectMessageRepository().Create(dm); err != nil {
+			t.Fatalf("failed to create direct message: %v", err)
+		}
+
+		if archive := exportRoundTrip(t, service); len(archive.DirectMessages) != 0 {
+			t.Errorf("expected no direct messages by default, got %+v", archive.DirectMessages)
+		}
+		archive, err := service.Export(ExportOptions{DirectMessages: true})
+		if err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+		if len(archive.DirectMessages) != 1 || archive.DirectMessages[0].Message != "gg" {
+			t.Errorf("expected the direct message on request, got %+v", archive.DirectMessages)
+		}
+	})
+}
+
+func TestExportImportPolls(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := newTestExportService()
+		pollRepo := repository.NewPollRepository()
+		customGameRepo := repository.NewCustomGameRepository()
+		users := createChatUsers(t, "alice", "bob")
+
+		game := &models.CustomGame{Name: "Bomberman LAN", CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+		if err := customGameRepo.Create(game); err != nil {
+			t.Fatalf("failed to create custom game: %v", err)
+		}
+		poll := &models.Poll{
+			Title:        "Next game",
+			VotingMethod: models.PollVotingRanked,
+			CreatedBy:    models.PublicUser{ID: users["alice"].ID},
+			CreatedAt:    time.Now().UTC(),
+			Options: []models.PollOption{
+				{AppID: 730, Name: "Counter-Strike 2", Position: 0},
+				{AppID: game.AppID, Name: game.Name, Position: 1},
+			},
+		}
+		if err := pollRepo.Create(poll); err != nil {
+			t.Fatalf("failed to create poll: %v", err)
+		}
+		if err := pollRepo.SaveBallot(poll.ID, users["bob"].ID, []int{game.AppID, 730}); err != nil {
+			t.Fatalf("SaveBallot failed: %v", err)
+		}
+
+		archive := exportRoundTrip(t, service)
+		if len(archive.Polls) != 1 || len(archive.Polls[0].Options) != 2 || len(archive.PollBallots) != 2 {
+			t.Fatalf("unexpected archive contents: %+v, %+v", archive.Polls, archive.PollBallots)
+		}
+
+		// Recreate the custom game under another ID, the poll options and ballots have to follow
+		if err := customGameRepo.Delete(game); err != nil {
+			t.Fatalf("failed to delete custom game: %v", err)
+		}
+		if err := customGameRepo.Create(&models.CustomGame{Name: "Other", CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("failed to create custom game: %v", err)
+		}
+
+		result, err := service.Import(archive, ImportOptions{Mode: models.ImportModeReplace})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.PollsImported != 1 || result.PollBallotsImported != 2 {
+			t.Errorf("unexpected import result: %+v", result)
+		}
+
+		polls, err := pollRepo.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		if len(polls) != 1 || polls[0].CreatedBy.Username != "alice" || len(polls[0].Options) != 2 {
+			t.Fatalf("unexpected restored polls: %+v", polls)
+		}
+		restoredAppID := polls[0].Options[1].AppID
+		if restoredAppID == game.AppID {
+			t.Errorf("expected the option to be remapped to the recreated custom game, got %d", restoredAppID)
+		}
+		ballots, err := pollRepo.GetBallots(polls[0].ID)
+		if err != nil {
+			t.Fatalf("GetBallots failed: %v", err)
+		}
+		if len(ballots) != 1 || len(ballots[0].AppIDs) != 2 ||
+			ballots[0].AppIDs[0] != restoredAppID || ballots[0].AppIDs[1] != 730 {
+			t.Errorf("unexpected restored ballots: %+v", ballots)
+		}
+
+		// A second merge skips the poll and doesn't touch its ballots
+		result, err = service.Import(archive, ImportOptions{Mode: models.ImportModeMerge})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.PollsImported != 0 || result.PollsSkipped != 1 || result.PollBallotsImported != 0 {
+			t.Errorf("unexpected merge result: %+v", result)
+		}
+	})
+}
+
+func TestExportImportGameSessions(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := newTestExportService()
+		sessionRepo := repository.NewGameSessionRepository()
+		installRepo := repository.NewInstallStatusRepository()
+		userRepo := repository.NewUserRepository()
+		users := createChatUsers(t, "alice", "bob")
+
+		startsAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		session := &models.GameSession{AppID: 730, Title: "Clan war", CreatedBy: models.PublicUser{ID: users["alice"].ID}, StartsAt: &startsAt, CreatedAt: time.Now().UTC()}
+		if err := sessionRepo.Create(session, []uint64{users["alice"].ID, users["bob"].ID}); err != nil {
+			t.Fatalf("failed to create game session: %v", err)
+		}
+		if err := installRepo.Set(users["bob"].ID, 730, models.InstallStatusDownloading, 40); err != nil {
+			t.Fatalf("failed to set install status: %v", err)
+		}
+
+		archive := exportRoundTrip(t, service)
+		if len(archive.GameSessions) != 1 || len(archive.GameSessions[0].PlayerIDs) != 2 || len(archive.GameInstallStatus) != 1 {
+			t.Fatalf("unexpected archive contents: %+v, %+v", archive.GameSessions, archive.GameInstallStatus)
+		}
+
+		// Replacing recreates the players under new IDs, sessions and install statuses have to follow
+		result, err := service.Import(archive, ImportOptions{Mode: models.ImportModeReplace})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.GameSessionsImported != 1 || result.InstallStatusesImported != 1 {
+			t.Errorf("unexpected import result: %+v", result)
+		}
+
+		alice, err := userRepo.GetBySteamID(users["alice"].SteamID)
+		if err != nil || alice == nil {
+			t.Fatalf("GetBySteamID failed: %v", err)
+		}
+		bob, err := userRepo.GetBySteamID(users["bob"].SteamID)
+		if err != nil || bob == nil {
+			t.Fatalf("GetBySteamID failed: %v", err)
+		}
+		sessions, err := sessionRepo.GetRecent(10)
+		if err != nil {
+			t.Fatalf("GetRecent failed: %v", err)
+		}
+		if len(sessions) != 1 || sessions[0].CreatedBy.ID != alice.ID || sessions[0].Title != "Clan war" ||
+			sessions[0].StartsAt == nil || !sessions[0].StartsAt.Equal(startsAt) {
+			t.Fatalf("unexpected restored sessions: %+v", sessions)
+		}
+		if players, err := sessionRepo.GetPlayerIDs(sessions[0].ID); err != nil || len(players) != 2 {
+			t.Errorf("unexpected restored players: %v, %v", players, err)
+		}
+		statuses, err := installRepo.GetByUserID(bob.ID)
+		if err != nil || len(statuses) != 1 || statuses[0].Status != models.InstallStatusDownloading || statuses[0].Progress != 40 {
+			t.Errorf("unexpected restored install status: %+v, %v", statuses, err)
+		}
+
+		// A second merge skips the session and keeps the reported install status
+		if err := installRepo.Set(bob.ID, 730, models.InstallStatusReady, 0); err != nil {
+			t.Fatalf("failed to set install status: %v", err)
+		}
+		result, err = service.Import(archive, ImportOptions{Mode: models.ImportModeMerge})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.GameSessionsImported != 0 || result.GameSessionsSkipped != 1 || result.InstallStatusesImported != 0 {
+			t.Errorf("unexpected merge result: %+v", result)
+		}
+		if statuses, err := installRepo.GetByUserID(bob.ID); err != nil || len(statuses) != 1 || statuses[0].Status != models.InstallStatusReady {
+			t.Errorf("expected the reported install status to be kept, got %+v, %v", statuses, err)
+		}
+	})
+}
+
+func TestExportImportTournaments(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := newTestExportService()
+		tournamentRepo := repository.NewTournamentRepository()
+		users := createChatUsers(t, "alice", "bob", "carol")
+
+		tournament := &models.Tournament{
+			Name: "Aim Cup", AppID: 730, Format: models.TournamentSingleElimination, TeamSize: 2,
+			Status: models.TournamentStatusRegistration, BonusPoints: 10, CreatedBy: users["alice"].ID, CreatedAt: time.Now().UTC(),
+		}
+		if err := tournamentRepo.Create(tournament); err != nil {
+			t.Fatalf("failed to create tournament: %v", err)
+		}
+		for _, username := range []string{"alice", "bob", "carol"} {
+			if err := tournamentRepo.Register(tournament.ID, users[username].ID); err != nil {
+				t.Fatalf("failed to register %s: %v", username, err)
+			}
+		}
+		teams := []models.TournamentTeam{
+			{Name: "Team alice", Seed: 1, Members: []models.PublicUser{{ID: users["alice"].ID}, {ID: users["bob"].ID}}},
+			{Name: "carol", Seed: 2, Members: []models.PublicUser{{ID: users["carol"].ID}}},
+		}
+		build := func(teamIDs []uint64) []models.TournamentMatch {
+			next := uint64(2)
+			return []models.TournamentMatch{
+				{ID: 1, Bracket: models.BracketWinners, Round: 1, Team1ID: &teamIDs[0], Team2ID: &teamIDs[1],
+					Status: models.MatchStatusPending, WinnerNextMatchID: &next, WinnerNextSlot: 1},
+				{ID: 2, Bracket: models.BracketWinners, Round: 2, Status: models.MatchStatusPending},
+			}
+		}
+		if err := tournamentRepo.Start(tournament.ID, teams, build, time.Now().UTC()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		started, err := tournamentRepo.GetByID(tournament.ID)
+		if err != nil || started == nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		first, winner := started.Matches[0], started.Teams[0].ID
+		score1, score2 := 2, 0
+		first.Score1, first.Score2, first.WinnerTeamID, first.ReportedBy = &score1, &score2, &winner, &users["carol"].ID
+		first.Status = models.MatchStatusConfirmed
+		if err := tournamentRepo.SaveMatches([]models.TournamentMatch{first}); err != nil {
+			t.Fatalf("SaveMatches failed: %v", err)
+		}
+		if err := tournamentRepo.Finish(tournament.ID, &winner, time.Now().UTC()); err != nil {
+			t.Fatalf("Finish failed: %v", err)
+		}
+
+		archive := exportRoundTrip(t, service)
+		if len(archive.Tournaments) != 1 || len(archive.Tournaments[0].Registrations) != 3 ||
+			len(archive.Tournaments[0].Teams) != 2 || len(archive.Tournaments[0].Matches) != 2 {
+			t.Fatalf("unexpected archive contents: %+v", archive.Tournaments)
+		}
+
+		result, err := service.Import(archive, ImportOptions{Mode: models.ImportModeReplace})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.TournamentsImported != 1 {
+			t.Errorf("unexpected import result: %+v", result)
+		}
+
+		all, err := tournamentRepo.GetAll()
+		if err != nil || len(all) != 1 {
+			t.Fatalf("GetAll failed: %+v, %v", all, err)
+		}
+		restored, err := tournamentRepo.GetByID(all[0].ID)
+		if err != nil || restored == nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if restored.Status != models.TournamentStatusFinished || len(restored.Players) != 3 || len(restored.Teams) != 2 ||
+			restored.WinnerTeamID == nil || *restored.WinnerTeamID != restored.Teams[0].ID || len(restored.Teams[0].Members) != 2 {
+			t.Fatalf("unexpected restored tournament: %+v", restored)
+		}
+		match, final := restored.Matches[0], restored.Matches[1]
+		if match.Team1ID == nil || *match.Team1ID != restored.Teams[0].ID || match.Team2ID == nil || *match.Team2ID != restored.Teams[1].ID ||
+			match.WinnerTeamID == nil || *match.WinnerTeamID != restored.Teams[0].ID || match.Score1 == nil || *match.Score1 != 2 ||
+			match.WinnerNextMatchID == nil || *match.WinnerNextMatchID != final.ID || match.ReportedBy == nil {
+			t.Errorf("unexpected restored match: %+v", match)
+		}
+
+		// The tournament win still counts for the ranking
+		rankings, err := repository.NewVoteRepository().GetGlobalRanking()
+		if err != nil {
+			t.Fatalf("GetGlobalRanking failed: %v", err)
+		}
+		bonusPoints := make(map[string]int)
+		for _, ranking := range rankings {
+			bonusPoints[ranking.User.Username] = ranking.BonusPoints
+		}
+		if bonusPoints["alice"] != 10 || bonusPoints["bob"] != 10 || bonusPoints["carol"] != 0 {
+			t.Errorf("expected the winning team to keep its bonus points, got %v", bonusPoints)
+		}
+
+		// A second merge skips the tournament
+		result, err = service.Import(archive, ImportOptions{Mode: models.ImportModeMerge})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.TournamentsImported != 0 || result.TournamentsSkipped != 1 {
+			t.Errorf("unexpected merge result: %+v", result)
+		}
+	})
+}
+
+func TestExportImportMatchesAndRatings(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		service := newTestExportService()
+		ratingRepo := repository.NewRatingRepository()
+		userRepo := repository.NewUserRepository()
+		users := createChatUsers(t, "alice", "bob")
+
+		// The second match is backdated, the rating chain still follows the recording order
+		now := time.Now().UTC().Truncate(time.Second)
+		winner := 1
+		for i, result := range [][2]float64{{1016, 984}, {1030.5, 969.5}} {
+			match := &models.GameMatch{
+				AppID: 730, WinnerTeam: &winner, RecordedBy: users["alice"].ID,
+				PlayedAt: now.Add(-time.Duration(i) * time.Hour), CreatedAt: now,
+				Players: []models.MatchPlayer{
+					{User: users["alice"].ToPublic(), Team: 1, RatingBefore: []float64{1000, 1016}[i], RatingAfter: result[0]},
+					{User: users["bob"].ToPublic(), Team: 2, RatingBefore: []float64{1000, 984}[i], RatingAfter: result[1]},
+				},
+			}
+			ratings := []models.PlayerRating{
+				{User: users["alice"].ToPublic(), AppID: 730, Rating: result[0], Matches: i + 1, Wins: i + 1, UpdatedAt: now},
+				{User: users["bob"].ToPublic(), AppID: 730, Rating: result[1], Matches: i + 1, Losses: i + 1, UpdatedAt: now},
+			}
+			if err := ratingRepo.RecordMatch(match, ratings); err != nil {
+				t.Fatalf("RecordMatch failed: %v", err)
+			}
+		}
+
+		archive := exportRoundTrip(t, service)
+		if len(archive.GameMatches) != 2 || len(archive.GameMatches[0].Players) != 2 || len(archive.PlayerRatings) != 2 {
+			t.Fatalf("unexpected archive contents: %+v, %+v", archive.GameMatches, archive.PlayerRatings)
+		}
+
+		result, err := service.Import(archive, ImportOptions{Mode: models.ImportModeReplace})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.GameMatchesImported != 2 || result.PlayerRatingsImported != 2 {
+			t.Errorf("unexpected import result: %+v", result)
+		}
+
+		ladder, err := ratingRepo.GetLadder(730)
+		if err != nil {
+			t.Fatalf("GetLadder failed: %v", err)
+		}
+		if len(ladder) != 2 || ladder[0].User.Username != "alice" || ladder[0].Rating != 1030.5 || ladder[0].Wins != 2 || ladder[1].Losses != 2 {
+			t.Fatalf("unexpected ladder: %+v", ladder)
+		}
+		bob, err := userRepo.GetBySteamID(users["bob"].SteamID)
+		if err != nil || bob == nil {
+			t.Fatalf("GetBySteamID failed: %v", err)
+		}
+		history, err := ratingRepo.GetHistory(bob.ID, 730)
+		if err != nil {
+			t.Fatalf("GetHistory failed: %v", err)
+		}
+		if len(history) != 2 || history[0].RatingAfter != 984 || history[1].RatingBefore != 984 || history[1].RatingAfter != 969.5 {
+			t.Errorf("unexpected rating history: %+v", history)
+		}
+
+		// A second merge skips the matches and keeps the current ratings
+		result, err = service.Import(archive, ImportOptions{Mode: models.ImportModeMerge})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.GameMatchesImported != 0 || result.GameMatchesSkipped != 2 || result.PlayerRatingsImported != 0 {
+			t.Errorf("unexpected merge result: %+v", result)
+		}
+	})
+}
@@ -0,0 +1,472 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Errors returned when a download is not cached
+var (
+	ErrCacheDownloadFailed = errors.New("download failed")
+	ErrCacheInvalidContent = errors.New("invalid content")
+	ErrUnsupportedSize     = errors.New("unsupported image size")
+)
+
+const (
+	// tempFileSuffix marks files that are still being written, they are never served
+	tempFileSuffix = ".tmp"
+	// variantQuality is the JPEG quality of resized images
+	variantQuality = 85
+)
+
+// CacheStats describes the state of a file cache
+type CacheStats struct {
+	Files     int     `json:"files"`
+	Bytes     int64   `json:"bytes"`
+	MaxBytes  int64   `json:"max_bytes"` // 0 = unlimited
+	Hits      int64   `json:"hits"`      // Requests served from disk
+	Misses    int64   `json:"misses"`    // Requests that needed a download
+	HitRate   float64 `json:"hit_rate"`  // Hits / (hits + misses), 0 without requests
+	Downloads int64   `json:"downloads"`
+	Failures  int64   `json:"failures"` // Failed or rejected downloads
+	Evictions int64   `json:"evictions"`
+}
+
+// fileCacheEntry is a file in the cache directory
+type fileCacheEntry struct {
+	size       int64
+	lastAccess time.Time
+}
+
+// fileCache is a directory of downloaded files with a disk quota
+// Files are written to a temporary file and renamed when complete, so a file is either
+// missing or complete. Concurrent downloads of the same file are merged into one request.
+// When the quota is exceeded, the least recently used files are removed; files that were
+// not used for maxAge are removed as well.
+type fileCache struct {
+	name        string // Used in log messages
+	dir         string
+	maxBytes    int64         // 0 = unlimited
+	maxAge      time.Duration // 0 = never expire
+	maxFileSize int64
+	httpClient  *http.Client
+	// keep reports files that cannot be downloaded again (e.g. uploads), they are never evicted
+	keep  func(filename string) bool
+	group singleflight.Group
+	// mu protects the index and the counters
+	mu         sync.Mutex
+	entries    map[string]*fileCacheEntry
+	totalBytes int64
+	hits       int64
+	misses     int64
+	downloads  int64
+	failures   int64
+	evictions  int64
+}
+
+// newFileCache creates a file cache and indexes the files already on disk
+func newFileCache(name, dir string, maxBytes int64, maxAge time.Duration, maxFileSize int64, httpClient *http.Client, keep func(string) bool) *fileCache {
+	c := &fileCache{
+		name:        name,
+		dir:         dir,
+		maxBytes:    maxBytes,
+		maxAge:      maxAge,
+		maxFileSize: maxFileSize,
+		httpClient:  httpClient,
+		keep:        keep,
+		entries:     make(map[string]*fileCacheEntry),
+	}
+	if c.keep == nil {
+		c.keep = func(string) bool { return false }
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("Warning: Could not create %s cache directory: %v", name, err)
+		return c
+	}
+	c.scan()
+	return c
+}
+
+// scan rebuilds the index from the cache directory and removes leftovers of interrupted writes
+// The modification time is used as last access, as access times are not tracked across restarts.
+func (c *fileCache) scan() {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		log.Printf("Failed to read %s cache directory: %v", c.name, err)
+		return
+	}
+
+	c.mu.Lock()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(file.Name(), tempFileSuffix) {
+			os.Remove(filepath.Join(c.dir, file.Name()))
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		c.entries[file.Name()] = &fileCacheEntry{size: info.Size(), lastAccess: info.ModTime()}
+		c.totalBytes += info.Size()
+	}
+	c.mu.Unlock()
+
+	c.cleanup("")
+	log.Printf("Indexed %s cache: %d files, %d KB", c.name, len(c.entries), c.totalBytes/1024)
+}
+
+// path returns the path of a cached file
+func (c *fileCache) path(filename string) string {
+	return filepath.Join(c.dir, filename)
+}
+
+// has reports whether a file is cached, without counting it as a request
+func (c *fileCache) has(filename string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[filename]
+	return ok
+}
+
+// get reports whether a file is cached for serving it, counting the request as hit or miss
+func (c *fileCache) get(filename string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[filename]
+	if !ok {
+		c.misses++
+		return false
+	}
+	c.hits++
+	entry.lastAccess = time.Now()
+	return true
+}
+
+// fetch downloads a file into the cache unless it is cached already
+// Concurrent fetches of the same file share one download.
+func (c *fileCache) fetch(filename, url string) error {
+	if c.has(filename) {
+		return nil
+	}
+
+	_, err, _ := c.group.Do(filename, func() (interface{}, error) {
+		// Another fetch may have finished while this one waited
+		if c.has(filename) {
+			return nil, nil
+		}
+		err := c.download(filename, url)
+		c.mu.Lock()
+		if err != nil {
+			c.failures++
+		} else {
+			c.downloads++
+		}
+		c.mu.Unlock()
+		return nil, err
+	})
+	return err
+}
+
+// download fetches a URL, validates the response and stores it
+func (c *fileCache) download(filename, url string) error {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCacheDownloadFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: HTTP %d", ErrCacheDownloadFailed, resp.StatusCode)
+	}
+	if resp.ContentLength > c.maxFileSize {
+		return fmt.Errorf("%w: %d bytes exceed the limit of %d bytes", ErrCacheInvalidContent, resp.ContentLength, c.maxFileSize)
+	}
+
+	// Read one byte more than allowed to detect oversized responses without a Content-Length
+	data, err := io.ReadAll(io.LimitReader(resp.Body, c.maxFileSize+1))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCacheDownloadFailed, err)
+	}
+	if int64(len(data)) > c.maxFileSize {
+		return fmt.Errorf("%w: response exceeds the limit of %d bytes", ErrCacheInvalidContent, c.maxFileSize)
+	}
+	if err := validateImageContent(data, resp.Header.Get("Content-Type")); err != nil {
+		return err
+	}
+
+	return c.store(filename, data)
+}
+
+// store writes a file atomically and adds it to the index
+func (c *fileCache) store(filename string, data []byte) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s cache directory: %w", c.name, err)
+	}
+
+	file, err := os.CreateTemp(c.dir, filename+"-*"+tempFileSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(file.Name(), c.path(filename)); err != nil {
+		return fmt.Errorf("failed to save cache file: %w", err)
+	}
+
+	c.mu.Lock()
+	entry, replaced := c.entries[filename]
+	if replaced {
+		c.totalBytes -= entry.size
+	}
+	c.entries[filename] = &fileCacheEntry{size: int64(len(data)), lastAccess: time.Now()}
+	c.totalBytes += int64(len(data))
+	c.mu.Unlock()
+
+	// Resized variants of a replaced file are outdated
+	if replaced {
+		c.removeVariants(filename)
+	}
+	c.cleanup(filename)
+	return nil
+}
+
+// remove deletes a file from the cache
+func (c *fileCache) remove(filename string) error {
+	c.mu.Lock()
+	if entry, ok := c.entries[filename]; ok {
+		c.totalBytes -= entry.size
+		delete(c.entries, filename)
+	}
+	c.mu.Unlock()
+
+	if err := os.Remove(c.path(filename)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete cache file: %w", err)
+	}
+	c.removeVariants(filename)
+	return nil
+}
+
+// removeVariants deletes all resized variants and the WebP version of a file
+func (c *fileCache) removeVariants(filename string) {
+	prefix := variantPrefix(filename)
+	webp := webpFilename(filename)
+	c.mu.Lock()
+	var variants []string
+	for name, entry := range c.entries {
+		if name != filename && (strings.HasPrefix(name, prefix) || name == webp) {
+			c.totalBytes -= entry.size
+			delete(c.entries, name)
+			variants = append(variants, name)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, name := range variants {
+		if err := os.Remove(c.path(name)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to delete %s from %s cache: %v", name, c.name, err)
+		}
+	}
+}
+
+// variantPrefix returns the common filename prefix of all resized variants of a file
+func variantPrefix(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + "_w"
+}
+
+// webpFilename returns the filename of a file's WebP version, which is served to clients that accept WebP
+func webpFilename(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ".webp"
+}
+
+// variantFilename returns the filename of a file's variant with the given width
+func variantFilename(filename string, width int) string {
+	return fmt.Sprintf("%s%d.jpg", variantPrefix(filename), width)
+}
+
+// variant returns the path of a copy of a cached image resized to the given width
+// The variant is generated from the cached original on first use and cached as JPEG.
+// Serving it counts as hit or miss like the original.
+func (c *fileCache) variant(filename string, width int) (string, error) {
+	name := variantFilename(filename, width)
+	if c.get(name) {
+		return c.path(name), nil
+	}
+
+	_, err, _ := c.group.Do(name, func() (interface{}, error) {
+		if c.has(name) {
+			return nil, nil
+		}
+		if !c.has(filename) {
+			return nil, fmt.Errorf("%s is not cached", filename)
+		}
+
+		file, err := os.Open(c.path(filename))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", filename, err)
+		}
+		src, _, err := image.Decode(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCacheInvalidContent, err)
+		}
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resizeToWidth(src, width), &jpeg.Options{Quality: variantQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		return nil, c.store(name, buf.Bytes())
+	})
+	if err != nil {
+		return "", err
+	}
+	return c.path(name), nil
+}
+
+// cleanup removes expired files and, while the quota is exceeded, the least recently used ones
+// The file that was just written is never removed, even if it alone exceeds the quota.
+func (c *fileCache) cleanup(current string) {
+	c.mu.Lock()
+	type candidate struct {
+		filename string
+		entry    *fileCacheEntry
+	}
+	candidates := make([]candidate, 0, len(c.entries))
+	for filename, entry := range c.entries {
+		if filename != current && !c.keep(filename) {
+			candidates = append(candidates, candidate{filename, entry})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].entry.lastAccess.Before(candidates[j].entry.lastAccess)
+	})
+
+	var evicted []string
+	expireBefore := time.Now().Add(-c.maxAge)
+	for _, cand := range candidates {
+		expired := c.maxAge > 0 && cand.entry.lastAccess.Before(expireBefore)
+		overQuota := c.maxBytes > 0 && c.totalBytes > c.maxBytes
+		if !expired && !overQuota {
+			// Candidates are sorted by last access, so all following files are newer
+			break
+		}
+		c.totalBytes -= cand.entry.size
+		delete(c.entries, cand.filename)
+		c.evictions++
+		evicted = append(evicted, cand.filename)
+	}
+	c.mu.Unlock()
+
+	for _, filename := range evicted {
+		if err := os.Remove(c.path(filename)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to evict %s from %s cache: %v", filename, c.name, err)
+		}
+	}
+	if len(evicted) > 0 {
+		log.Printf("Evicted %d files from %s cache", len(evicted), c.name)
+	}
+}
+
+// stats returns the current size and counters of the cache
+func (c *fileCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := CacheStats{
+		Files:     len(c.entries),
+		Bytes:     c.totalBytes,
+		MaxBytes:  c.maxBytes,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Downloads: c.downloads,
+		Failures:  c.failures,
+		Evictions: c.evictions,
+	}
+	if requests := c.hits + c.misses; requests > 0 {
+		stats.HitRate = float64(c.hits) / float64(requests)
+	}
+	return stats
+}
+
+// resizeToWidth scales an image down to the given width, keeping the aspect ratio
+// Each target pixel is the average of the source pixels it covers (box filter), which is
+// good enough for thumbnails and only needs the standard library. Images are never scaled up.
+func resizeToWidth(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if width <= 0 || width >= bounds.Dx() {
+		return src
+	}
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// validateImageContent checks that downloaded data is an image
+// The content is sniffed instead of trusting the Content-Type header alone; SVG cannot be
+// sniffed and is accepted if it is declared as such and looks like one.
+func validateImageContent(data []byte, declaredType string) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty response", ErrCacheInvalidContent)
+	}
+	if declaredType != "" && !strings.HasPrefix(declaredType, "image/") {
+		return fmt.Errorf("%w: unexpected content type %q", ErrCacheInvalidContent, declaredType)
+	}
+
+	sniffed := http.DetectContentType(data)
+	if strings.HasPrefix(sniffed, "image/") && sniffed != "image/svg+xml" {
+		return nil
+	}
+	if strings.HasPrefix(declaredType, "image/svg+xml") && bytes.Contains(data[:min(len(data), 1024)], []byte("<svg")) {
+		return nil
+	}
+	return fmt.Errorf("%w: content is %s", ErrCacheInvalidContent, sniffed)
+}
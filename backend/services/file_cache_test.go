@@ -0,0 +1,357 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// testPNG returns a small PNG image
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// testWebP returns a lossless WebP image of a single color
+// Every prefix code has just one symbol, so the pixels take no bits at all.
+func testWebP(width, height int, c color.NRGBA) []byte {
+	var data []byte
+	var bits uint64
+	var n uint
+	write := func(value uint64, count uint) {
+		bits |= value << n
+		for n += count; n >= 8; n -= 8 {
+			data = append(data, byte(bits))
+			bits >>= 8
+		}
+	}
+
+	data = append(data, 0x2f) // VP8L signature
+	write(uint64(width-1), 14)
+	write(uint64(height-1), 14)
+	write(1, 1) // alpha is used
+	write(0, 3) // version
+	write(0, 1) // no transform
+	write(0, 1) // no color cache
+	write(0, 1) // no meta prefix codes
+	// Green, red, blue, alpha and distance codes as simple codes with one 8 bit symbol
+	for _, symbol := range []uint8{c.G, c.R, c.B, c.A, 0} {
+		write(1, 1)
+		write(0, 1)
+		write(1, 1)
+		write(uint64(symbol), 8)
+	}
+	if n > 0 {
+		data = append(data, byte(bits))
+	}
+	if len(data)%2 == 1 {
+		data = append(data, 0)
+	}
+
+	le32 := func(v int) []byte { return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)} }
+	out := append([]byte("RIFF"), le32(12+len(data))...)
+	out = append(out, "WEBPVP8L"...)
+	out = append(out, le32(len(data))...)
+	return append(out, data...)
+}
+
+func TestFileCacheFetch(t *testing.T) {
+	pngData := testPNG(t)
+	var requests atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/slow.png":
+			<-release
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngData)
+		case "/page.png":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>not found</html>"))
+		case "/disguised.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<html>not an image</html>"))
+		case "/huge.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(append(pngData, make([]byte, 4096)...))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cache := newFileCache("test", t.TempDir(), 0, 0, 1024, server.Client(), nil)
+
+	// Concurrent fetches of the same file share one download
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- cache.fetch("slow.png", server.URL+"/slow.png")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected one download, got %d", got)
+	}
+	if data, err := os.ReadFile(cache.path("slow.png")); err != nil || !bytes.Equal(data, pngData) {
+		t.Errorf("expected the complete image on disk, got %d bytes, %v", len(data), err)
+	}
+
+	tests := []struct {
+		path    string
+		wantErr error
+	}{
+		{"/page.png", ErrCacheInvalidContent},
+		{"/disguised.png", ErrCacheInvalidContent},
+		{"/huge.png", ErrCacheInvalidContent},
+		{"/missing.png", ErrCacheDownloadFailed},
+	}
+	for _, tt := range tests {
+		filename := filepath.Base(tt.path)
+		t.Run(filename, func(t *testing.T) {
+			if err := cache.fetch(filename, server.URL+tt.path); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if cache.has(filename) {
+				t.Errorf("expected %s not to be cached", filename)
+			}
+			if _, err := os.Stat(cache.path(filename)); !os.IsNotExist(err) {
+				t.Errorf("expected no file for %s, got %v", filename, err)
+			}
+		})
+	}
+
+	stats := cache.stats()
+	if stats.Files != 1 || stats.Bytes != int64(len(pngData)) || stats.Downloads != 1 || stats.Failures != 4 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestFileCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	// Leftovers of an interrupted write and files from a previous run
+	os.WriteFile(filepath.Join(dir, "partial.jpg-123"+tempFileSuffix), make([]byte, 10), 0644)
+	os.WriteFile(filepath.Join(dir, "old.jpg"), make([]byte, 100), 0644)
+	os.Chtimes(filepath.Join(dir, "old.jpg"), time.Now(), time.Now().Add(-time.Hour))
+
+	keep := func(filename string) bool { return filename == "upload.jpg" }
+	cache := newFileCache("test", dir, 300, 0, 1024, http.DefaultClient, keep)
+
+	if _, err := os.Stat(filepath.Join(dir, "partial.jpg-123"+tempFileSuffix)); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary file to be removed, got %v", err)
+	}
+	if !cache.has("old.jpg") {
+		t.Fatalf("expected existing files to be indexed")
+	}
+
+	for _, filename := range []string{"upload.jpg", "a.jpg", "b.jpg"} {
+		if err := cache.store(filename, make([]byte, 100)); err != nil {
+			t.Fatalf("store failed: %v", err)
+		}
+	}
+	// old.jpg was used least recently and makes room for c.jpg, then a.jpg is used again
+	if !cache.get("a.jpg") || cache.get("old.jpg") {
+		t.Fatalf("expected a.jpg to be cached and old.jpg to be evicted")
+	}
+	if err := cache.store("c.jpg", make([]byte, 100)); err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+
+	// The kept upload is never evicted, so b.jpg goes
+	for filename, want := range map[string]bool{"upload.jpg": true, "a.jpg": true, "b.jpg": false, "c.jpg": true} {
+		if got := cache.has(filename); got != want {
+			t.Errorf("cached %s = %v, want %v", filename, got, want)
+		}
+	}
+
+	stats := cache.stats()
+	if stats.Bytes != 300 || stats.Evictions != 2 || stats.Hits != 1 || stats.Misses != 1 || stats.HitRate != 0.5 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestValidateImageContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		declared string
+		wantErr  bool
+	}{
+		{"png", testPNG(t), "image/png", false},
+		{"png without content type", testPNG(t), "", false},
+		{"svg", []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`), "image/svg+xml", false},
+		{"svg declared as png", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), "image/png", true},
+		{"html", []byte("<html></html>"), "text/html; charset=utf-8", true},
+		{"empty", nil, "image/png", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateImageContent(tt.data, tt.declared); (err != nil) != tt.wantErr {
+				t.Errorf("validateImageContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResizeToWidth(t *testing.T) {
+	// Left half black, right half white
+	src := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 4; x < 8; x++ {
+			src.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+		}
+	}
+
+	dst := resizeToWidth(src, 2)
+	if got := dst.Bounds(); got.Dx() != 2 || got.Dy() != 1 {
+		t.Fatalf("expected 2x1, got %dx%d", got.Dx(), got.Dy())
+	}
+	if r, _, _, _ := dst.At(0, 0).RGBA(); r != 0 {
+		t.Errorf("expected the left pixel to be black, got %d", r>>8)
+	}
+	if r, _, _, _ := dst.At(1, 0).RGBA(); r>>8 != 255 {
+		t.Errorf("expected the right pixel to be white, got %d", r>>8)
+	}
+
+	// Odd sizes average into a middle gray
+	if r, _, _, _ := resizeToWidth(src, 1).At(0, 0).RGBA(); r>>8 < 126 || r>>8 > 128 {
+		t.Errorf("expected gray, got %d", r>>8)
+	}
+	if resizeToWidth(src, 16) != image.Image(src) {
+		t.Errorf("expected images not to be scaled up")
+	}
+}
+
+func TestFileCacheVariant(t *testing.T) {
+	cache := newFileCache("test", t.TempDir(), 0, 0, 1024, http.DefaultClient, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 32))); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	if err := cache.store("a_1.jpg", buf.Bytes()); err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+
+	if _, err := cache.variant("missing.jpg", 16); err == nil {
+		t.Errorf("expected an error for a file that is not cached")
+	}
+
+	path, err := cache.variant("a_1.jpg", 16)
+	if err != nil {
+		t.Fatalf("variant failed: %v", err)
+	}
+	if filepath.Base(path) != "a_1_w16.jpg" {
+		t.Errorf("unexpected variant filename %s", filepath.Base(path))
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open variant: %v", err)
+	}
+	config, err := jpeg.DecodeConfig(file)
+	file.Close()
+	if err != nil || config.Width != 16 || config.Height != 8 {
+		t.Errorf("expected a 16x8 JPEG, got %+v, %v", config, err)
+	}
+
+	// Serving the variant again is a hit
+	if again, err := cache.variant("a_1.jpg", 16); err != nil || again != path {
+		t.Errorf("expected the cached variant, got %s, %v", again, err)
+	}
+	if stats := cache.stats(); stats.Files != 2 || stats.Hits != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// Replacing the original drops its variants
+	if err := cache.store("a_1.jpg", buf.Bytes()); err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+	if cache.has("a_1_w16.jpg") {
+		t.Errorf("expected the variant to be removed with the replaced original")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the variant file to be deleted, got %v", err)
+	}
+}
+
+func TestGetSmallAvatarURL(t *testing.T) {
+	s := newAvatarCacheService(t.TempDir(), "http://localhost:8080/", 0, 0, http.DefaultClient)
+
+	tests := map[string]string{
+		"http://localhost:8080/api/v1/avatars/123_abc.jpg": "http://localhost:8080/api/v1/avatars/123_abc.jpg?size=64",
+		"http://localhost:8080/api/v1/avatars/123_abc.svg": "http://localhost:8080/api/v1/avatars/123_abc.svg",
+		"https://avatars.steamstatic.com/abc_full.jpg":     "https://avatars.steamstatic.com/abc_full.jpg",
+		"": "",
+	}
+	for avatarURL, want := range tests {
+		if got := s.GetSmallAvatarURL(avatarURL); got != want {
+			t.Errorf("GetSmallAvatarURL(%q) = %q, want %q", avatarURL, got, want)
+		}
+	}
+}
+
+func TestSaveWebPImage(t *testing.T) {
+	// A quota of one byte evicts everything that may be evicted
+	s := newImageCacheService(t.TempDir(), 1, 0, http.DefaultClient)
+	appID := models.CustomGameAppID(1)
+	webpName := webpFilename(imageFilename(appID))
+	webp := testWebP(46, 21, color.NRGBA{R: 200, G: 40, B: 40, A: 255})
+
+	if err := s.SaveImage(appID, bytes.NewReader(webp)); err != nil {
+		t.Fatalf("SaveImage failed: %v", err)
+	}
+	file, err := os.Open(s.GetImagePath(appID))
+	if err != nil {
+		t.Fatalf("failed to open image: %v", err)
+	}
+	config, err := jpeg.DecodeConfig(file)
+	file.Close()
+	if err != nil || config.Width != 46 || config.Height != 21 {
+		t.Errorf("expected a 46x21 JPEG, got %+v, %v", config, err)
+	}
+	webpPath := filepath.Join(s.GetBaseDir(), webpName)
+	if data, err := os.ReadFile(webpPath); err != nil || !bytes.Equal(data, webp) {
+		t.Errorf("expected the uploaded WebP to be kept, got %v", err)
+	}
+
+	// The cover is kept with the JPEG and replaced with it
+	s.cache.cleanup("")
+	if !s.cache.has(webpName) {
+		t.Errorf("expected the WebP cover to be exempt from eviction")
+	}
+	if err := s.SaveImage(appID, bytes.NewReader(testPNG(t))); err != nil {
+		t.Fatalf("SaveImage failed: %v", err)
+	}
+	if _, err := os.Stat(webpPath); !os.IsNotExist(err) {
+		t.Errorf("expected a PNG upload to remove the old WebP cover, got %v", err)
+	}
+}
@@ -2,68 +2,212 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
 )
 
-// GameMetadata contains manually curated metadata for a game
-type GameMetadata struct {
-	MaxPlayers int    `json:"max_players"`
-	Notes      string `json:"notes,omitempty"`
-}
+// Errors returned by the game metadata service
+var (
+	ErrInvalidGameMetadata  = errors.New("invalid game metadata")
+	ErrGameMetadataNotFound = errors.New("game metadata not found")
+)
+
+// Limits for curated game metadata
+const (
+	maxGameMetadataTags        = 20
+	maxGameMetadataTagLength   = 32
+	maxGameMetadataNotesLength = 1000
+)
 
-// GameMetadataService manages game metadata loaded from a JSON file
+// GameMetadataService manages manually curated game metadata
+// The entries are stored in the database and cached in memory. game_metadata.json is the seed:
+// it is applied at startup and whenever the file changes, without touching entries an admin edited.
 type GameMetadataService struct {
-	metadata map[string]*GameMetadata // appID (string) -> metadata
-	mu       sync.RWMutex
-	filePath string
+	metadataRepo  *repository.GameMetadataRepository
+	filePath      string
+	watchInterval time.Duration
+	metadata      map[int]*models.GameMetadata
+	mu            sync.RWMutex
+	// seedMu serializes seeding, so the watcher and a manual reload do not apply the file twice
+	seedMu      sync.Mutex
+	seedModTime time.Time
+	// onChange is called after the metadata changed, set by the game service to invalidate its cache
+	onChange func()
+	ticker   *time.Ticker
+	done     chan bool
 }
 
-// NewGameMetadataService creates a new game metadata service
-// filePath is the path to the game_metadata.json file
-func NewGameMetadataService(filePath string) *GameMetadataService {
+// NewGameMetadataService creates a new game metadata service and applies the seed file
+func NewGameMetadataService(cfg *config.Config, metadataRepo *repository.GameMetadataRepository) *GameMetadataService {
 	service := &GameMetadataService{
-		metadata: make(map[string]*GameMetadata),
-		filePath: filePath,
+		metadataRepo:  metadataRepo,
+		filePath:      cfg.GameMetadataPath,
+		watchInterval: cfg.GameMetadataWatchInterval,
+		metadata:      make(map[int]*models.GameMetadata),
+		done:          make(chan bool),
+	}
+	if err := service.Reload(); err != nil {
+		log.Printf("Failed to load game metadata: %v", err)
 	}
-	service.loadMetadata()
 	return service
 }
 
-// loadMetadata loads the metadata from the JSON file
-func (s *GameMetadataService) loadMetadata() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// Start begins watching the seed file for changes
+func (s *GameMetadataService) Start() {
+	if s.watchInterval <= 0 || s.filePath == "" {
+		return
+	}
 
-	data, err := os.ReadFile(s.filePath)
+	s.ticker = time.NewTicker(s.watchInterval)
+	go s.run()
+	log.Printf("Watching game metadata file %s (interval: %v)", s.filePath, s.watchInterval)
+}
+
+// Stop stops watching the seed file
+func (s *GameMetadataService) Stop() {
+	if s.ticker == nil {
+		return
+	}
+	s.ticker.Stop()
+	s.done <- true
+}
+
+// run reloads the metadata whenever the seed file's modification time changes
+// ConfigMap updates replace the file via a symlink swap, which also changes the modification time.
+func (s *GameMetadataService) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.ticker.C:
+			stat, err := os.Stat(s.filePath)
+			if err != nil {
+				continue
+			}
+			s.seedMu.Lock()
+			changed := !stat.ModTime().Equal(s.seedModTime)
+			s.seedMu.Unlock()
+			if !changed {
+				continue
+			}
+
+			log.Printf("Game metadata file %s changed, reloading", s.filePath)
+			if err := s.Reload(); err != nil {
+				log.Printf("Failed to reload game metadata: %v", err)
+			}
+		}
+	}
+}
+
+// Reload applies the seed file and reloads all metadata from the database
+// A missing seed file leaves the database untouched, an invalid one is rejected as a whole.
+func (s *GameMetadataService) Reload() error {
+	if err := s.applySeed(); err != nil {
+		return err
+	}
+	return s.refresh()
+}
+
+// applySeed syncs the seeded database entries with the seed file
+func (s *GameMetadataService) applySeed() error {
+	if s.filePath == "" {
+		return nil
+	}
+
+	s.seedMu.Lock()
+	defer s.seedMu.Unlock()
+
+	stat, err := os.Stat(s.filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Printf("Game metadata file not found at %s, using empty metadata", s.filePath)
-		} else {
-			log.Printf("Error reading game metadata file: %v", err)
+			log.Printf("Game metadata file not found at %s, skipping seed", s.filePath)
+			return nil
 		}
-		return
+		return fmt.Errorf("failed to read game metadata file: %w", err)
 	}
+	// Remember the file even if it is invalid, so a broken file is reported once and not on every tick
+	s.seedModTime = stat.ModTime()
 
-	var metadata map[string]*GameMetadata
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		log.Printf("Error parsing game metadata JSON: %v", err)
-		return
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read game metadata file: %w", err)
+	}
+
+	var entries map[string]*models.GameMetadataRequest
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse game metadata JSON: %w", err)
+	}
+
+	seed := make([]models.GameMetadata, 0, len(entries))
+	for key, req := range entries {
+		appID, err := strconv.Atoi(key)
+		if err != nil || appID <= 0 || req == nil {
+			log.Printf("Skipping game metadata entry %q: invalid app ID", key)
+			continue
+		}
+		meta, err := newGameMetadata(appID, req)
+		if err != nil {
+			log.Printf("Skipping game metadata entry %d: %v", appID, err)
+			continue
+		}
+		seed = append(seed, *meta)
 	}
 
+	changed, err := s.metadataRepo.ApplySeed(seed)
+	if err != nil {
+		return err
+	}
+	log.Printf("Applied game metadata seed for %d games (%d changed)", len(seed), changed)
+	return nil
+}
+
+// refresh reloads the in-memory cache from the database and notifies about the change
+func (s *GameMetadataService) refresh() error {
+	entries, err := s.metadataRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[int]*models.GameMetadata, len(entries))
+	for i := range entries {
+		metadata[entries[i].AppID] = &entries[i]
+	}
+
+	s.mu.Lock()
 	s.metadata = metadata
-	log.Printf("Loaded game metadata for %d games", len(metadata))
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+	return nil
+}
+
+// setOnChange registers the callback for metadata changes
+func (s *GameMetadataService) setOnChange(onChange func()) {
+	s.mu.Lock()
+	s.onChange = onChange
+	s.mu.Unlock()
 }
 
-// GetMetadata returns the metadata for a given app ID
-func (s *GameMetadataService) GetMetadata(appID int) *GameMetadata {
+// GetMetadata returns the metadata for a given app ID, or nil if there is none
+func (s *GameMetadataService) GetMetadata(appID int) *models.GameMetadata {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-
-	// Convert appID to string for map lookup
-	appIDStr := intToString(appID)
-	return s.metadata[appIDStr]
+	return s.metadata[appID]
 }
 
 // GetMaxPlayers returns the max players for a game, or 0 if not known
@@ -75,36 +219,97 @@ func (s *GameMetadataService) GetMaxPlayers(appID int) int {
 	return meta.MaxPlayers
 }
 
-// Reload reloads the metadata from disk
-func (s *GameMetadataService) Reload() {
-	s.loadMetadata()
+// List returns the metadata of all games, ordered by app ID
+func (s *GameMetadataService) List() []models.GameMetadata {
+	s.mu.RLock()
+	entries := make([]models.GameMetadata, 0, len(s.metadata))
+	for _, meta := range s.metadata {
+		entries = append(entries, *meta)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].AppID < entries[j].AppID })
+	return entries
 }
 
-// intToString converts an int to string without importing strconv
-func intToString(n int) string {
-	if n == 0 {
-		return "0"
+// Set creates or replaces the metadata of a game
+// Edited entries are marked as admin entries and are no longer updated from the seed file.
+func (s *GameMetadataService) Set(appID int, req *models.GameMetadataRequest, adminSteamID string) (*models.GameMetadata, error) {
+	if appID <= 0 {
+		return nil, fmt.Errorf("%w: invalid app ID", ErrInvalidGameMetadata)
 	}
+	meta, err := newGameMetadata(appID, req)
+	if err != nil {
+		return nil, err
+	}
+	meta.Source = models.GameMetadataSourceAdmin
+	meta.UpdatedBy = adminSteamID
+	meta.UpdatedAt = time.Now().UTC().Truncate(time.Second)
 
-	negative := n < 0
-	if negative {
-		n = -n
+	if err := s.metadataRepo.Save(meta); err != nil {
+		return nil, err
+	}
+	if err := s.refresh(); err != nil {
+		return nil, err
 	}
 
-	// Max int64 has 19 digits
-	digits := make([]byte, 20)
-	i := len(digits)
+	log.Printf("Game metadata for %d updated by %s", appID, adminSteamID)
+	return meta, nil
+}
 
-	for n > 0 {
-		i--
-		digits[i] = byte('0' + n%10)
-		n /= 10
+// Delete removes the metadata of a game
+// Entries from the seed file come back on the next change of the file or restart.
+func (s *GameMetadataService) Delete(appID int) error {
+	deleted, err := s.metadataRepo.Delete(appID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGameMetadataNotFound
+	}
+	return s.refresh()
+}
+
+// newGameMetadata validates and normalizes editable metadata
+func newGameMetadata(appID int, req *models.GameMetadataRequest) (*models.GameMetadata, error) {
+	switch {
+	case req.MaxPlayers < 0 || req.MinPlayers < 0:
+		return nil, fmt.Errorf("%w: player counts must not be negative", ErrInvalidGameMetadata)
+	case req.MaxPlayers > 0 && req.MinPlayers > req.MaxPlayers:
+		return nil, fmt.Errorf("%w: min_players is larger than max_players", ErrInvalidGameMetadata)
+	case req.InstallSizeMB < 0:
+		return nil, fmt.Errorf("%w: install_size_mb must not be negative", ErrInvalidGameMetadata)
+	case utf8.RuneCountInString(req.Notes) > maxGameMetadataNotesLength:
+		return nil, fmt.Errorf("%w: notes are longer than %d characters", ErrInvalidGameMetadata, maxGameMetadataNotesLength)
 	}
 
-	if negative {
-		i--
-		digits[i] = '-'
+	// Tags are trimmed and deduplicated case-insensitively, keeping the first spelling
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range req.Tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxGameMetadataTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidGameMetadata, tag, maxGameMetadataTagLength)
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxGameMetadataTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidGameMetadata, maxGameMetadataTags)
 	}
 
-	return string(digits[i:])
+	return &models.GameMetadata{
+		AppID:                   appID,
+		MaxPlayers:              req.MaxPlayers,
+		MinPlayers:              req.MinPlayers,
+		LANCapable:              req.LANCapable,
+		DedicatedServerRequired: req.DedicatedServerRequired,
+		InstallSizeMB:           req.InstallSizeMB,
+		Tags:                    tags,
+		Notes:                   strings.TrimSpace(req.Notes),
+	}, nil
 }
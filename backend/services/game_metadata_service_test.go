@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+)
+
+func TestNewGameMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.GameMetadataRequest
+		wantErr bool
+	}{
+		{"valid", models.GameMetadataRequest{MinPlayers: 2, MaxPlayers: 8, InstallSizeMB: 2048}, false},
+		{"unknown max players", models.GameMetadataRequest{MinPlayers: 4}, false},
+		{"negative players", models.GameMetadataRequest{MaxPlayers: -1}, true},
+		{"min above max", models.GameMetadataRequest{MinPlayers: 6, MaxPlayers: 4}, true},
+		{"negative install size", models.GameMetadataRequest{InstallSizeMB: -5}, true},
+		{"tag too long", models.GameMetadataRequest{Tags: []string{"a tag that is way too long to be useful"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newGameMetadata(730, &tt.req)
+			if tt.wantErr != errors.Is(err, ErrInvalidGameMetadata) {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+		})
+	}
+
+	meta, err := newGameMetadata(730, &models.GameMetadataRequest{Tags: []string{" Shooter ", "shooter", "", "LAN"}, Notes: "  bring a headset "})
+	if err != nil {
+		t.Fatalf("newGameMetadata failed: %v", err)
+	}
+	if len(meta.Tags) != 2 || meta.Tags[0] != "Shooter" || meta.Tags[1] != "LAN" || meta.Notes != "bring a headset" {
+		t.Errorf("unexpected normalized metadata: %+v", meta)
+	}
+}
+
+func TestGameMetadataServiceSeedAndEdit(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "game_metadata.json")
+		writeSeed := func(content string, modTime time.Time) {
+			t.Helper()
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatalf("failed to write seed file: %v", err)
+			}
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatalf("failed to set seed file time: %v", err)
+			}
+		}
+		writeSeed(`{"730": {"max_players": 32, "notes": "CS2"}, "945360": {"max_players": 15}}`, time.Now().Add(-time.Hour))
+
+		service := NewGameMetadataService(&config.Config{GameMetadataPath: path}, repository.NewGameMetadataRepository())
+		if got := service.GetMaxPlayers(730); got != 32 {
+			t.Errorf("seeded max players = %d, want 32", got)
+		}
+
+		changes := 0
+		service.setOnChange(func() { changes++ })
+
+		if _, err := service.Set(945360, &models.GameMetadataRequest{MaxPlayers: 10, LANCapable: true}, "76561190000000001"); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if changes != 1 {
+			t.Errorf("expected the change callback after Set, got %d calls", changes)
+		}
+
+		// The seed file changes: the seeded entry follows, the edited one stays
+		writeSeed(`{"730": {"max_players": 10}, "945360": {"max_players": 15}}`, time.Now())
+		if err := service.Reload(); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+		if got := service.GetMaxPlayers(730); got != 10 {
+			t.Errorf("reloaded max players = %d, want 10", got)
+		}
+		if meta := service.GetMetadata(945360); meta == nil || meta.MaxPlayers != 10 || !meta.LANCapable || meta.Source != models.GameMetadataSourceAdmin {
+			t.Errorf("admin entry was overwritten: %+v", meta)
+		}
+		if changes != 2 {
+			t.Errorf("expected the change callback after Reload, got %d calls", changes)
+		}
+
+		// An invalid file keeps the current metadata
+		writeSeed(`{not json`, time.Now().Add(time.Minute))
+		if err := service.Reload(); err == nil {
+			t.Error("expected Reload to fail for an invalid file")
+		}
+		if got := service.GetMaxPlayers(730); got != 10 {
+			t.Errorf("max players after invalid file = %d, want 10", got)
+		}
+
+		if err := service.Delete(945360); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if err := service.Delete(945360); !errors.Is(err, ErrGameMetadataNotFound) {
+			t.Errorf("expected ErrGameMetadataNotFound, got %v", err)
+		}
+		if len(service.List()) != 1 {
+			t.Errorf("expected 1 entry left, got %+v", service.List())
+		}
+	})
+}
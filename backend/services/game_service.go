@@ -1,10 +1,10 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"sort"
 	"strings"
 	"sync"
@@ -13,17 +13,16 @@ import (
 	"github.com/guided-traffic/rate-your-mate/backend/config"
 	"github.com/guided-traffic/rate-your-mate/backend/models"
 	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
 )
 
 const (
-	steamAPIBaseURL   = "https://api.steampowered.com"
-	steamStoreBaseURL = "https://store.steampowered.com/api"
-	steamCDNBaseURL   = "https://steamcdn-a.akamaihd.net/steam/apps"
+	steamCDNBaseURL = "https://steamcdn-a.akamaihd.net/steam/apps"
 
 	// Cache settings
-	gameCacheMaxAge       = 24 * time.Hour  // Refresh game data after 24 hours
-	failedFetchRetryDelay = 24 * time.Hour  // Wait 24 hours before retrying failed fetches (e.g., removed games)
-	rateLimitPausePeriod  = 5 * time.Minute // Pause for 5 minutes after 429 error
+	gameCacheMaxAge       = 24 * time.Hour         // Refresh game data after 24 hours
+	failedFetchRetryDelay = 24 * time.Hour         // Wait 24 hours before retrying failed fetches (e.g., removed games)
+	storeRequestDelay     = 300 * time.Millisecond // Delay between two Steam Store requests of one worker
 )
 
 // SyncProgressCallback is called to report sync progress
@@ -37,10 +36,13 @@ type GameService struct {
 	gameOwnerRepo       *repository.GameOwnerRepository
 	imageCacheService   *ImageCacheService
 	gameMetadataService *GameMetadataService
-	httpClient          *http.Client
+	steamClient         steam.Client
 	cache               *gamesCache
 	rateLimiter         *rateLimiter
 	syncProgress        *syncProgress
+	syncScheduler       *GameSyncScheduler // Set by NewGameSyncScheduler
+	customGameService   *CustomGameService // Set by NewCustomGameService
+	registrations       sync.WaitGroup     // Background library registrations
 }
 
 // syncProgress tracks background sync status
@@ -64,25 +66,28 @@ type gamesCache struct {
 type rateLimiter struct {
 	mu          sync.RWMutex
 	pausedUntil time.Time
-	isPaused    bool
+	failures    int // 429 responses in a row, reset by the next successful request
 }
 
 // NewGameService creates a new game service
-func NewGameService(cfg *config.Config, userRepo *repository.UserRepository, gameCacheRepo *repository.GameCacheRepository, gameOwnerRepo *repository.GameOwnerRepository, imageCacheService *ImageCacheService, gameMetadataService *GameMetadataService) *GameService {
-	return &GameService{
+func NewGameService(cfg *config.Config, steamClient steam.Client, userRepo *repository.UserRepository, gameCacheRepo *repository.GameCacheRepository, gameOwnerRepo *repository.GameOwnerRepository, imageCacheService *ImageCacheService, gameMetadataService *GameMetadataService) *GameService {
+	s := &GameService{
 		cfg:                 cfg,
 		userRepo:            userRepo,
 		gameCacheRepo:       gameCacheRepo,
 		gameOwnerRepo:       gameOwnerRepo,
 		imageCacheService:   imageCacheService,
 		gameMetadataService: gameMetadataService,
-		httpClient: &http.Client{
-			Timeout: 15 * time.Second,
-		},
-		cache:        &gamesCache{},
-		rateLimiter:  &rateLimiter{},
-		syncProgress: &syncProgress{},
+		steamClient:         steamClient,
+		cache:               &gamesCache{},
+		rateLimiter:         &rateLimiter{},
+		syncProgress:        &syncProgress{},
 	}
+	// Edited metadata has to show up in the cached games response right away
+	if gameMetadataService != nil {
+		gameMetadataService.setOnChange(s.InvalidateCache)
+	}
+	return s
 }
 
 // GetMultiplayerGames returns all multiplayer games owned by registered players
@@ -121,18 +126,67 @@ func (s *GameService) InvalidateCache() {
 
 // isRateLimited checks if we're currently rate limited
 func (s *GameService) isRateLimited() bool {
+	return !s.RateLimitedUntil().IsZero()
+}
+
+// RateLimitedUntil returns the end of the current rate limit pause, or zero if not paused
+func (s *GameService) RateLimitedUntil() time.Time {
 	s.rateLimiter.mu.RLock()
 	defer s.rateLimiter.mu.RUnlock()
-	return s.rateLimiter.isPaused && time.Now().Before(s.rateLimiter.pausedUntil)
+	if time.Now().Before(s.rateLimiter.pausedUntil) {
+		return s.rateLimiter.pausedUntil
+	}
+	return time.Time{}
 }
 
-// setRateLimited sets the rate limit pause
-func (s *GameService) setRateLimited() {
+// setRateLimited pauses requests with exponential backoff, honoring Steam's Retry-After
+func (s *GameService) setRateLimited(retryAfter time.Duration) {
 	s.rateLimiter.mu.Lock()
 	defer s.rateLimiter.mu.Unlock()
-	s.rateLimiter.isPaused = true
-	s.rateLimiter.pausedUntil = time.Now().Add(rateLimitPausePeriod)
-	log.Printf("Steam API rate limited - pausing requests for %v", rateLimitPausePeriod)
+	delay := backoffDelay(s.cfg.GameSyncBackoffMin, s.cfg.GameSyncBackoffMax, s.rateLimiter.failures, retryAfter)
+	s.rateLimiter.failures++
+	if until := time.Now().Add(delay); until.After(s.rateLimiter.pausedUntil) {
+		s.rateLimiter.pausedUntil = until
+	}
+	log.Printf("Steam API rate limited (%d in a row) - pausing requests for %v", s.rateLimiter.failures, delay)
+}
+
+// resetRateLimitBackoff resets the backoff after a successful request
+func (s *GameService) resetRateLimitBackoff() {
+	s.rateLimiter.mu.Lock()
+	s.rateLimiter.failures = 0
+	s.rateLimiter.mu.Unlock()
+}
+
+// rateLimitState returns the pause end and the number of 429 responses in a row
+func (s *GameService) rateLimitState() (time.Time, int) {
+	s.rateLimiter.mu.RLock()
+	defer s.rateLimiter.mu.RUnlock()
+	return s.rateLimiter.pausedUntil, s.rateLimiter.failures
+}
+
+// restoreRateLimit restores a persisted rate limit state after a restart
+func (s *GameService) restoreRateLimit(pausedUntil time.Time, failures int) {
+	s.rateLimiter.mu.Lock()
+	s.rateLimiter.pausedUntil = pausedUntil
+	s.rateLimiter.failures = failures
+	s.rateLimiter.mu.Unlock()
+}
+
+// backoffDelay returns the pause after a 429 response
+// The pause starts at minDelay and doubles with every previous failure up to maxDelay.
+// A longer Retry-After requested by Steam always wins.
+func backoffDelay(minDelay, maxDelay time.Duration, failures int, retryAfter time.Duration) time.Duration {
+	if minDelay <= 0 {
+		minDelay = time.Minute
+	}
+	maxDelay = max(maxDelay, minDelay)
+
+	delay := minDelay
+	for i := 0; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return max(min(delay, maxDelay), retryAfter)
 }
 
 // fetchMultiplayerGames fetches all games from all users and filters for multiplayer
@@ -257,14 +311,18 @@ func (s *GameService) fetchMultiplayerGames() (*models.GamesResponse, error) {
 	// Fetch categories from Steam Store API (with rate limiting and DB caching)
 	s.fetchGameCategories(gamesToFetch)
 
+	s.mergeCustomGames(gameMap)
+
 	// Filter for multiplayer games and build response
 	var allGames []models.Game
 	pinnedGameIDs := s.cfg.PinnedGameIDs
 
 	for _, game := range gameMap {
-		if game.HasMultiplayerCategory() {
+		if game.IsCustom || game.HasMultiplayerCategory() {
 			// Only cache images for multiplayer games (after filtering)
-			s.imageCacheService.CacheImageAsync(game.AppID)
+			if !game.IsCustom {
+				s.imageCacheService.CacheImageAsync(game.AppID)
+			}
 
 			// Check if pinned
 			for _, pinnedID := range pinnedGameIDs {
@@ -352,25 +410,23 @@ func (s *GameService) enrichGamesWithMetadata(games []models.Game) {
 		return
 	}
 	for i := range games {
-		if maxPlayers := s.gameMetadataService.GetMaxPlayers(games[i].AppID); maxPlayers > 0 {
-			games[i].MaxPlayers = maxPlayers
+		meta := s.gameMetadataService.GetMetadata(games[i].AppID)
+		if meta == nil {
+			continue
 		}
+		// Custom games bring their own max players, which curated metadata only overrides if it knows better
+		if meta.MaxPlayers > 0 || !games[i].IsCustom {
+			games[i].MaxPlayers = meta.MaxPlayers
+		}
+		games[i].MinPlayers = meta.MinPlayers
+		games[i].LANCapable = meta.LANCapable
+		games[i].DedicatedServerRequired = meta.DedicatedServerRequired
+		games[i].InstallSizeMB = meta.InstallSizeMB
+		games[i].Tags = meta.Tags
+		games[i].Notes = meta.Notes
 	}
 }
 
-// ownedGamesResponse represents Steam API response for owned games
-type ownedGamesResponse struct {
-	Response struct {
-		GameCount int `json:"game_count"`
-		Games     []struct {
-			AppID           int    `json:"appid"`
-			Name            string `json:"name"`
-			PlaytimeForever int    `json:"playtime_forever"`
-			ImgIconURL      string `json:"img_icon_url"`
-		} `json:"games"`
-	} `json:"response"`
-}
-
 // fetchUserGames fetches all games owned by a user
 func (s *GameService) fetchUserGames(steamID string) ([]models.GameOwnership, error) {
 	// Skip fake users (used for development/testing)
@@ -378,47 +434,18 @@ func (s *GameService) fetchUserGames(steamID string) ([]models.GameOwnership, er
 		return []models.GameOwnership{}, nil
 	}
 
-	if s.cfg.SteamAPIKey == "" {
-		return nil, fmt.Errorf("Steam API key not configured")
-	}
-
-	url := fmt.Sprintf(
-		"%s/IPlayerService/GetOwnedGames/v1/?key=%s&steamid=%s&include_appinfo=true&include_played_free_games=true",
-		steamAPIBaseURL,
-		s.cfg.SteamAPIKey,
-		steamID,
-	)
-
-	log.Printf("[STEAM API] GET /IPlayerService/GetOwnedGames/v1 - Fetching games for user: %s", steamID)
-	start := time.Now()
-	resp, err := s.httpClient.Get(url)
-	duration := time.Since(start)
+	ownedGames, err := s.steamClient.GetOwnedGames(steamID)
 	if err != nil {
-		log.Printf("[STEAM API] ERROR - GetOwnedGames failed for user %s after %v: %v", steamID, duration, err)
-		return nil, fmt.Errorf("failed to call Steam API: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[STEAM API] ERROR - GetOwnedGames returned status %d for user %s after %v", resp.StatusCode, steamID, duration)
-		return nil, fmt.Errorf("Steam API returned status %d", resp.StatusCode)
-	}
-
-	var apiResp ownedGamesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		log.Printf("[STEAM API] ERROR - Failed to parse GetOwnedGames response for user %s: %v", steamID, err)
-		return nil, fmt.Errorf("failed to parse Steam API response: %w", err)
+		return nil, err
 	}
 
-	log.Printf("[STEAM API] OK - GetOwnedGames returned %d games for user %s in %v", len(apiResp.Response.Games), steamID, duration)
-
 	var games []models.GameOwnership
 	var gamesToSave []struct {
 		AppID           int
 		PlaytimeForever int
 	}
 
-	for _, g := range apiResp.Response.Games {
+	for _, g := range ownedGames {
 		games = append(games, models.GameOwnership{
 			SteamID:         steamID,
 			AppID:           g.AppID,
@@ -462,26 +489,48 @@ func (s *GameService) RefreshUserGames(steamID string) (int, error) {
 	return len(games), nil
 }
 
-// storeAppDetailsResponse represents Steam Store API response
-type storeAppDetailsResponse map[string]struct {
-	Success bool `json:"success"`
-	Data    struct {
-		Name        string `json:"name"`
-		HeaderImage string `json:"header_image"`
-		IsFree      bool   `json:"is_free"`
-		Categories  []struct {
-			ID          int    `json:"id"`
-			Description string `json:"description"`
-		} `json:"categories"`
-		PriceOverview *struct {
-			Currency         string `json:"currency"`
-			Initial          int    `json:"initial"`
-			Final            int    `json:"final"`
-			DiscountPercent  int    `json:"discount_percent"`
-			InitialFormatted string `json:"initial_formatted"`
-			FinalFormatted   string `json:"final_formatted"`
-		} `json:"price_overview"`
-	} `json:"data"`
+// RefreshAllLibraries re-fetches the game libraries of all registered players from Steam
+// and adds newly discovered games to the cache so the next sync fetches their details.
+// Returns the number of refreshed players and the number of newly cached games.
+func (s *GameService) RefreshAllLibraries() (int, int, error) {
+	users, err := s.userRepo.GetAll()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	refreshed := 0
+	newGames := 0
+	for _, user := range users {
+		if strings.HasPrefix(user.SteamID, "FAKE_") {
+			continue
+		}
+
+		games, err := s.fetchUserGames(user.SteamID)
+		if err != nil {
+			log.Printf("[GameRefresh] Failed to refresh games for user %s: %v", user.SteamID, err)
+			continue
+		}
+		refreshed++
+
+		for _, g := range games {
+			exists, err := s.gameCacheRepo.GetByAppID(g.AppID)
+			if err != nil {
+				log.Printf("[GameRefresh] Failed to check game %d: %v", g.AppID, err)
+				continue
+			}
+			if exists != nil {
+				continue
+			}
+			if err := s.gameCacheRepo.InsertIfNotExists(g.AppID, g.Name); err != nil {
+				log.Printf("[GameRefresh] Failed to insert game %d: %v", g.AppID, err)
+				continue
+			}
+			newGames++
+		}
+	}
+
+	s.InvalidateCache()
+	return refreshed, newGames, nil
 }
 
 // fetchGameCategories fetches categories for multiple games from Steam Store API
@@ -492,131 +541,102 @@ func (s *GameService) fetchGameCategories(games []*models.Game) {
 	}
 
 	// Check if we're rate limited
-	if s.isRateLimited() {
-		log.Printf("Skipping Steam Store API calls - rate limited until %v", s.rateLimiter.pausedUntil)
+	if until := s.RateLimitedUntil(); !until.IsZero() {
+		log.Printf("Skipping Steam Store API calls - rate limited until %v", until)
 		return
 	}
 
-	// Process sequentially with delays to avoid rate limits
-	const delayBetweenRequests = 300 * time.Millisecond
+	if err := s.syncGamesParallel(context.Background(), games, nil); err != nil {
+		log.Printf("Stopped category fetches: %v", err)
+	}
+}
 
-	for _, game := range games {
-		// Check rate limit before each request
-		if s.isRateLimited() {
-			log.Printf("Rate limit hit - stopping category fetches")
-			return
-		}
+// syncGame fetches the store data of a game and saves it to the DB cache
+// Games that are no longer available are cached as failed so they are not retried for 24 hours.
+func (s *GameService) syncGame(game *models.Game) error {
+	if until := s.RateLimitedUntil(); !until.IsZero() {
+		return &steam.RateLimitError{RetryAfter: time.Until(until)}
+	}
 
-		storeData, err := s.fetchGameCategoriesFromStore(game.AppID)
-		if err != nil {
-			log.Printf("Could not fetch data for %s (%d): %v", game.Name, game.AppID, err)
-
-			// Check if this is a "game not found" error (not a rate limit or network error)
-			// Cache the failure so we don't retry for 24 hours
-			if strings.Contains(err.Error(), "game not found") || strings.Contains(err.Error(), "not accessible") {
-				log.Printf("Game %s (%d) appears to be unavailable (removed from Steam Store?) - caching failure for %v", game.Name, game.AppID, failedFetchRetryDelay)
-				if cacheErr := s.gameCacheRepo.UpsertWithStatus(game.AppID, game.Name, []string{}, nil, true); cacheErr != nil {
-					log.Printf("Failed to cache failed fetch for game %d: %v", game.AppID, cacheErr)
-				}
+	storeData, err := s.fetchGameCategoriesFromStore(game.AppID)
+	if err != nil {
+		log.Printf("Could not fetch data for %s (%d): %v", game.Name, game.AppID, err)
+
+		// Check if this is a "game not found" error (not a rate limit or network error)
+		// Cache the failure so we don't retry for 24 hours
+		if errors.Is(err, steam.ErrAppNotFound) {
+			log.Printf("Game %s (%d) appears to be unavailable (removed from Steam Store?) - caching failure for %v", game.Name, game.AppID, failedFetchRetryDelay)
+			if cacheErr := s.gameCacheRepo.UpsertWithStatus(game.AppID, game.Name, []string{}, nil, true); cacheErr != nil {
+				log.Printf("Failed to cache failed fetch for game %d: %v", game.AppID, cacheErr)
 			}
-			continue
 		}
+		return err
+	}
 
-		game.Categories = storeData.Categories
-		if storeData.Name != "" {
-			game.Name = storeData.Name
-		}
-		game.IsFree = storeData.IsFree
-		game.PriceCents = storeData.PriceCents
-		game.OriginalCents = storeData.OriginalCents
-		game.DiscountPercent = storeData.DiscountPercent
-		game.PriceFormatted = storeData.PriceFormatted
-		game.ReviewScore = storeData.ReviewScore
-
-		// Cache image using the header_image URL from Steam API
-		if storeData.HeaderImageURL != "" {
-			s.imageCacheService.CacheImageFromURLAsync(game.AppID, storeData.HeaderImageURL)
-		}
+	game.Categories = storeData.Categories
+	if storeData.Name != "" {
+		game.Name = storeData.Name
+	}
+	game.IsFree = storeData.IsFree
+	game.PriceCents = storeData.PriceCents
+	game.OriginalCents = storeData.OriginalCents
+	game.DiscountPercent = storeData.DiscountPercent
+	game.PriceFormatted = storeData.PriceFormatted
+	game.ReviewScore = storeData.ReviewScore
 
-		// Save to DB cache
-		priceInfo := &repository.GamePriceInfo{
-			IsFree:          storeData.IsFree,
-			PriceCents:      storeData.PriceCents,
-			OriginalCents:   storeData.OriginalCents,
-			DiscountPercent: storeData.DiscountPercent,
-			PriceFormatted:  storeData.PriceFormatted,
-			ReviewScore:     storeData.ReviewScore,
-		}
-		if err := s.gameCacheRepo.Upsert(game.AppID, game.Name, storeData.Categories, priceInfo); err != nil {
-			log.Printf("Failed to cache game %d: %v", game.AppID, err)
-		}
+	// Cache image using the header_image URL from Steam API
+	if storeData.HeaderImageURL != "" {
+		s.imageCacheService.CacheImageFromURLAsync(game.AppID, storeData.HeaderImageURL)
+	}
 
-		time.Sleep(delayBetweenRequests)
+	// Save to DB cache
+	priceInfo := &repository.GamePriceInfo{
+		IsFree:          storeData.IsFree,
+		PriceCents:      storeData.PriceCents,
+		OriginalCents:   storeData.OriginalCents,
+		DiscountPercent: storeData.DiscountPercent,
+		PriceFormatted:  storeData.PriceFormatted,
+		ReviewScore:     storeData.ReviewScore,
+	}
+	if err := s.gameCacheRepo.Upsert(game.AppID, game.Name, storeData.Categories, priceInfo); err != nil {
+		log.Printf("Failed to cache game %d: %v", game.AppID, err)
 	}
+	return nil
 }
 
 // fetchGameCategoriesFromStore fetches categories and price for a single game from Steam Store
 // Returns GameStoreData and error. Handles 429 rate limiting.
 func (s *GameService) fetchGameCategoriesFromStore(appID int) (*GameStoreData, error) {
-	url := fmt.Sprintf("%s/appdetails?appids=%d&cc=de", steamStoreBaseURL, appID)
-
-	log.Printf("[STEAM STORE API] GET /appdetails - Fetching details for game %d", appID)
-	start := time.Now()
-	resp, err := s.httpClient.Get(url)
-	duration := time.Since(start)
+	details, err := s.steamClient.GetAppDetails(appID)
 	if err != nil {
-		log.Printf("[STEAM STORE API] ERROR - appdetails failed for game %d after %v: %v", appID, duration, err)
-		return nil, fmt.Errorf("failed to call Steam Store API: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Handle rate limiting
-	if resp.StatusCode == http.StatusTooManyRequests {
-		log.Printf("[STEAM STORE API] WARN - Rate limited (429) for game %d after %v", appID, duration)
-		s.setRateLimited()
-		return nil, fmt.Errorf("rate limited (429)")
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[STEAM STORE API] ERROR - appdetails returned status %d for game %d after %v", resp.StatusCode, appID, duration)
-		return nil, fmt.Errorf("Steam Store API returned status %d", resp.StatusCode)
-	}
-
-	var apiResp storeAppDetailsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		log.Printf("[STEAM STORE API] ERROR - Failed to parse appdetails response for game %d: %v", appID, err)
-		return nil, fmt.Errorf("failed to parse Steam Store API response: %w", err)
-	}
-
-	appIDStr := fmt.Sprintf("%d", appID)
-	appData, ok := apiResp[appIDStr]
-	if !ok || !appData.Success {
-		log.Printf("[STEAM STORE API] WARN - Game %d not found or not accessible after %v", appID, duration)
-		return nil, fmt.Errorf("game not found or not accessible")
+		var rateLimitErr *steam.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			s.setRateLimited(rateLimitErr.RetryAfter)
+		}
+		return nil, err
 	}
-
-	log.Printf("[STEAM STORE API] OK - appdetails returned data for game %d (%s) in %v", appID, appData.Data.Name, duration)
+	s.resetRateLimitBackoff()
 
 	var categories []string
-	for _, cat := range appData.Data.Categories {
+	for _, cat := range details.Categories {
 		categories = append(categories, cat.Description)
 	}
 
 	// Build price info
 	data := &GameStoreData{
-		Name:           appData.Data.Name,
-		HeaderImageURL: appData.Data.HeaderImage,
+		Name:           details.Name,
+		HeaderImageURL: details.HeaderImage,
 		Categories:     categories,
-		IsFree:         appData.Data.IsFree,
+		IsFree:         details.IsFree,
 	}
 
-	if appData.Data.IsFree {
+	if details.IsFree {
 		data.PriceFormatted = "Free"
-	} else if appData.Data.PriceOverview != nil {
-		data.PriceCents = appData.Data.PriceOverview.Final
-		data.OriginalCents = appData.Data.PriceOverview.Initial
-		data.DiscountPercent = appData.Data.PriceOverview.DiscountPercent
-		data.PriceFormatted = appData.Data.PriceOverview.FinalFormatted
+	} else if details.PriceOverview != nil {
+		data.PriceCents = details.PriceOverview.Final
+		data.OriginalCents = details.PriceOverview.Initial
+		data.DiscountPercent = details.PriceOverview.DiscountPercent
+		data.PriceFormatted = details.PriceOverview.FinalFormatted
 	}
 
 	// Fetch review score from Steam Review API
@@ -638,61 +658,22 @@ type GameStoreData struct {
 	ReviewScore     int // Percentage of positive reviews (0-100), -1 if not enough reviews
 }
 
-// steamReviewResponse represents the Steam Review API response
-type steamReviewResponse struct {
-	Success      int `json:"success"`
-	QuerySummary struct {
-		NumReviews      int    `json:"num_reviews"`
-		ReviewScore     int    `json:"review_score"`
-		ReviewScoreDesc string `json:"review_score_desc"`
-		TotalPositive   int    `json:"total_positive"`
-		TotalNegative   int    `json:"total_negative"`
-		TotalReviews    int    `json:"total_reviews"`
-	} `json:"query_summary"`
-}
-
 // fetchGameReviewScore fetches the review score percentage from Steam Review API
 // Returns the percentage of positive reviews (0-100), or -1 if not enough reviews
 func (s *GameService) fetchGameReviewScore(appID int) int {
-	url := fmt.Sprintf("https://store.steampowered.com/appreviews/%d?json=1&purchase_type=all&language=all", appID)
-
-	log.Printf("[STEAM STORE API] GET /appreviews - Fetching reviews for game %d", appID)
-	start := time.Now()
-	resp, err := s.httpClient.Get(url)
-	duration := time.Since(start)
+	summary, err := s.steamClient.GetReviewSummary(appID)
 	if err != nil {
-		log.Printf("[STEAM STORE API] ERROR - appreviews failed for game %d after %v: %v", appID, duration, err)
-		return -1
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[STEAM STORE API] ERROR - appreviews returned status %d for game %d after %v", resp.StatusCode, appID, duration)
-		return -1
-	}
-
-	var reviewResp steamReviewResponse
-	if err := json.NewDecoder(resp.Body).Decode(&reviewResp); err != nil {
-		log.Printf("[STEAM STORE API] ERROR - Failed to parse appreviews response for game %d: %v", appID, err)
 		return -1
 	}
 
-	if reviewResp.Success != 1 {
-		log.Printf("[STEAM STORE API] WARN - appreviews returned unsuccessful for game %d after %v", appID, duration)
-		return -1
-	}
-
-	totalReviews := reviewResp.QuerySummary.TotalPositive + reviewResp.QuerySummary.TotalNegative
+	totalReviews := summary.TotalPositive + summary.TotalNegative
 	if totalReviews < 10 {
 		// Not enough reviews for a meaningful percentage
-		log.Printf("[STEAM STORE API] OK - appreviews for game %d has only %d reviews (not enough) in %v", appID, totalReviews, duration)
 		return -1
 	}
 
 	// Calculate percentage of positive reviews
-	percentage := (reviewResp.QuerySummary.TotalPositive * 100) / totalReviews
-	log.Printf("[STEAM STORE API] OK - appreviews for game %d: %d%% positive (%d reviews) in %v", appID, percentage, totalReviews, duration)
-	return percentage
+	return (summary.TotalPositive * 100) / totalReviews
 }
 
 // fetchGameDetails fetches full details for a single game (used for pinned games not in library)
@@ -756,7 +737,7 @@ func (s *GameService) fetchGameDetails(appID int) (*models.Game, error) {
 	storeData, err := s.fetchGameCategoriesFromStore(appID)
 	if err != nil {
 		// Cache the failure if it's a "game not found" error
-		if strings.Contains(err.Error(), "game not found") || strings.Contains(err.Error(), "not accessible") {
+		if errors.Is(err, steam.ErrAppNotFound) {
 			log.Printf("Pinned game (%d) appears to be unavailable - caching failure for %v", appID, failedFetchRetryDelay)
 			if cacheErr := s.gameCacheRepo.UpsertWithStatus(appID, fmt.Sprintf("Unknown Game %d", appID), []string{}, nil, true); cacheErr != nil {
 				log.Printf("Failed to cache failed fetch for pinned game %d: %v", appID, cacheErr)
@@ -817,7 +798,6 @@ func (s *GameService) PrefetchPinnedGames() {
 	log.Printf("[GameSync] Prefetching %d pinned games in background...", len(pinnedIDs))
 
 	go func() {
-		const delayBetweenRequests = 300 * time.Millisecond
 		fetched := 0
 		skipped := 0
 
@@ -864,7 +844,7 @@ func (s *GameService) PrefetchPinnedGames() {
 			log.Printf("[GameSync] Prefetched pinned game %d: %s", appID, storeData.Name)
 			fetched++
 
-			time.Sleep(delayBetweenRequests)
+			time.Sleep(storeRequestDelay)
 		}
 
 		log.Printf("[GameSync] Pinned games prefetch complete: %d fetched, %d already cached", fetched, skipped)
@@ -885,6 +865,17 @@ func (s *GameService) IsSyncing() bool {
 	return s.syncProgress.isSyncing
 }
 
+// beginSync marks a sync as running, returns false if one is already in progress
+func (s *GameService) beginSync() bool {
+	s.syncProgress.mu.Lock()
+	defer s.syncProgress.mu.Unlock()
+	if s.syncProgress.isSyncing {
+		return false
+	}
+	s.syncProgress.isSyncing = true
+	return true
+}
+
 // setSyncProgress updates the sync progress
 func (s *GameService) setSyncProgress(isSyncing bool, phase, current string, processed, total int) {
 	s.syncProgress.mu.Lock()
@@ -936,14 +927,16 @@ func (s *GameService) buildGamesFromCache() (*models.GamesResponse, bool, error)
 
 	// If no game owners in DB, we need a sync
 	if len(ownersMap) == 0 {
-		log.Printf("[GameSync] No game owners in DB, loading pinned games only")
+		log.Printf("[GameSync] No game owners in DB, loading pinned and custom games only")
 		pinnedGames := s.loadPinnedGamesFromCache(&needsSync)
-		// Enrich pinned games with custom metadata
+		allGames := s.loadCustomGames()
+		// Enrich games with custom metadata
 		s.enrichGamesWithMetadata(pinnedGames)
+		s.enrichGamesWithMetadata(allGames)
 		needsSync = true // Trigger sync to populate game owners
 		return &models.GamesResponse{
 			PinnedGames: pinnedGames,
-			AllGames:    []models.Game{},
+			AllGames:    allGames,
 		}, needsSync, nil
 	}
 
@@ -953,6 +946,11 @@ func (s *GameService) buildGamesFromCache() (*models.GamesResponse, bool, error)
 	gameMap := make(map[int]*models.Game)
 
 	for appID, owners := range ownersMap {
+		// Custom games are merged below, their owners are declared by the players
+		if models.IsCustomAppID(appID) {
+			continue
+		}
+
 		// Try to load game details from DB cache
 		cached, err := s.gameCacheRepo.GetByAppID(appID)
 		if err != nil || cached == nil {
@@ -992,12 +990,16 @@ func (s *GameService) buildGamesFromCache() (*models.GamesResponse, bool, error)
 
 	log.Printf("[GameSync] Loaded %d games from DB cache, needsSync: %v", len(gameMap), needsSync)
 
+	s.mergeCustomGames(gameMap)
+
 	// Filter for multiplayer games and build response
 	var allGames []models.Game
 
 	for _, game := range gameMap {
-		if game.HasMultiplayerCategory() {
-			s.imageCacheService.CacheImageAsync(game.AppID)
+		if game.IsCustom || game.HasMultiplayerCategory() {
+			if !game.IsCustom {
+				s.imageCacheService.CacheImageAsync(game.AppID)
+			}
 			for _, pinnedID := range pinnedGameIDs {
 				if pinnedID == game.AppID {
 					game.IsPinned = true
@@ -1091,6 +1093,28 @@ func (s *GameService) buildGamesFromCache() (*models.GamesResponse, bool, error)
 	}, needsSync, nil
 }
 
+// loadCustomGames returns the custom games with their declared owners
+// Custom games are always listed, regardless of their categories and owners.
+func (s *GameService) loadCustomGames() []models.Game {
+	if s.customGameService == nil {
+		return []models.Game{}
+	}
+	games, err := s.customGameService.listGames()
+	if err != nil {
+		log.Printf("Failed to load custom games: %v", err)
+		return []models.Game{}
+	}
+	return games
+}
+
+// mergeCustomGames adds the custom games to a map of Steam games
+func (s *GameService) mergeCustomGames(gameMap map[int]*models.Game) {
+	games := s.loadCustomGames()
+	for i := range games {
+		gameMap[games[i].AppID] = &games[i]
+	}
+}
+
 // loadPinnedGamesFromCache loads pinned games from DB cache
 func (s *GameService) loadPinnedGamesFromCache(needsSync *bool) []models.Game {
 	pinnedGameIDs := s.cfg.PinnedGameIDs
@@ -1126,246 +1150,66 @@ func (s *GameService) loadPinnedGamesFromCache(needsSync *bool) []models.Game {
 	return pinnedGames
 }
 
-// SyncGames triggers a sync for all games that need updating
-// This can be called at any time - it checks the DB for games with stale/missing data
-func (s *GameService) SyncGames(progressCallback SyncProgressCallback) {
-	s.TriggerSyncIfNeeded(progressCallback)
-}
-
-// RegisterUserGames records a user's games in the cache and triggers sync if needed
-// This is called when a new user registers - their games are added to the DB
-// and a sync is triggered to fetch missing data
-func (s *GameService) RegisterUserGames(steamID string, progressCallback SyncProgressCallback) {
+// RegisterUserGames records the games of one or more users in the cache and triggers sync if needed
+// This is called when a new user registers or players are invited - their games are added
+// to the DB one library after the other and a single sync is triggered to fetch missing data
+func (s *GameService) RegisterUserGames(steamIDs ...string) {
+	s.registrations.Add(1)
 	go func() {
-		log.Printf("GameService: Registering games for new user %s", steamID)
-
-		// Fetch new user's game library from Steam
-		userGames, err := s.fetchUserGames(steamID)
-		if err != nil {
-			log.Printf("GameService: Failed to fetch games for new user %s: %v", steamID, err)
-			return
+		defer s.registrations.Done()
+		for _, steamID := range steamIDs {
+			s.registerLibrary(steamID)
 		}
 
-		if len(userGames) == 0 {
-			log.Printf("GameService: New user %s has no games", steamID)
-			return
-		}
-
-		log.Printf("GameService: New user %s has %d games, inserting into cache", steamID, len(userGames))
-
-		// Insert all games into cache (without overwriting existing data)
-		// Games that already exist will be skipped
-		newGames := 0
-		for _, g := range userGames {
-			err := s.gameCacheRepo.InsertIfNotExists(g.AppID, g.Name)
-			if err != nil {
-				log.Printf("GameService: Failed to insert game %d: %v", g.AppID, err)
-			} else {
-				newGames++
-			}
-		}
-
-		log.Printf("GameService: Inserted %d games for user %s", newGames, steamID)
-
-		// Invalidate response cache so new user's ownership is reflected
+		// Invalidate response cache so the new users' ownership is reflected
 		s.InvalidateCache()
 
 		// Now trigger a sync to fetch missing data
-		s.TriggerSyncIfNeeded(progressCallback)
+		s.TriggerSyncIfNeeded()
 	}()
 }
 
-// TriggerSyncIfNeeded checks if there are games that need syncing and starts a sync
-func (s *GameService) TriggerSyncIfNeeded(progressCallback SyncProgressCallback) {
-	// Check if sync is already running
-	s.syncProgress.mu.RLock()
-	isSyncing := s.syncProgress.isSyncing
-	s.syncProgress.mu.RUnlock()
-
-	if isSyncing {
-		log.Println("GameService: Sync already in progress, skipping")
-		return
-	}
+// registerLibrary fetches a user's game library and inserts unknown games into the cache
+func (s *GameService) registerLibrary(steamID string) {
+	log.Printf("GameService: Registering games for new user %s", steamID)
 
-	// Check if there are games needing sync
-	count, err := s.gameCacheRepo.CountGamesNeedingSync(gameCacheMaxAge, failedFetchRetryDelay)
+	// Fetch new user's game library from Steam
+	userGames, err := s.fetchUserGames(steamID)
 	if err != nil {
-		log.Printf("GameService: Failed to count games needing sync: %v", err)
+		log.Printf("GameService: Failed to fetch games for new user %s: %v", steamID, err)
 		return
 	}
 
-	if count == 0 {
-		log.Println("GameService: No games need syncing")
-		if progressCallback != nil {
-			progressCallback("complete", "", 0, 0)
-		}
+	if len(userGames) == 0 {
+		log.Printf("GameService: New user %s has no games", steamID)
 		return
 	}
 
-	log.Printf("GameService: %d games need syncing, starting sync", count)
-	s.runSync(progressCallback)
-}
-
-// runSync performs the actual sync work
-func (s *GameService) runSync(progressCallback SyncProgressCallback) {
-	// Set syncing state
-	s.syncProgress.mu.Lock()
-	if s.syncProgress.isSyncing {
-		s.syncProgress.mu.Unlock()
-		log.Println("GameService: Sync already in progress, skipping")
-		return
-	}
-	s.syncProgress.isSyncing = true
-	s.syncProgress.mu.Unlock()
-
-	go func() {
-		defer func() {
-			s.setSyncProgress(false, "", "", 0, 0)
-		}()
-
-		log.Println("GameService: Starting sync")
-
-		// Get all games that need syncing
-		gamesToSync, err := s.gameCacheRepo.GetGamesNeedingSync(gameCacheMaxAge, failedFetchRetryDelay)
-		if err != nil {
-			log.Printf("GameService: Failed to get games needing sync: %v", err)
-			return
-		}
-
-		if len(gamesToSync) == 0 {
-			log.Println("GameService: No games to sync")
-			if progressCallback != nil {
-				progressCallback("complete", "", 0, 0)
-			}
-			return
-		}
-
-		// Convert to models.Game for the fetch function
-		var games []*models.Game
-		for _, g := range gamesToSync {
-			games = append(games, &models.Game{
-				AppID: g.AppID,
-				Name:  g.Name,
-			})
-		}
-
-		totalToFetch := len(games)
-		log.Printf("GameService: Syncing %d games", totalToFetch)
-
-		s.setSyncProgress(true, "fetching_categories", "", 0, totalToFetch)
-		if progressCallback != nil {
-			progressCallback("fetching_categories", "", 0, totalToFetch)
-		}
-
-		// Fetch game data with progress reporting
-		s.fetchGameCategoriesWithProgress(games, func(processed int, currentGame string) {
-			s.setSyncProgress(true, "fetching_categories", currentGame, processed, totalToFetch)
-			if progressCallback != nil {
-				progressCallback("fetching_categories", currentGame, processed, totalToFetch)
-			}
-		})
+	log.Printf("GameService: New user %s has %d games, inserting into cache", steamID, len(userGames))
 
-		// Invalidate response cache
-		s.InvalidateCache()
-
-		// Count multiplayer games
-		multiplayerCount := 0
-		for _, game := range games {
-			if game.HasMultiplayerCategory() {
-				multiplayerCount++
-			}
-		}
-
-		log.Printf("GameService: Sync batch complete. Synced %d games (%d multiplayer)", totalToFetch, multiplayerCount)
-
-		// Check if there are more games to sync (new users may have joined during sync)
-		remainingCount, err := s.gameCacheRepo.CountGamesNeedingSync(gameCacheMaxAge, failedFetchRetryDelay)
+	// Insert all games into cache (without overwriting existing data)
+	// Games that already exist will be skipped
+	newGames := 0
+	for _, g := range userGames {
+		err := s.gameCacheRepo.InsertIfNotExists(g.AppID, g.Name)
 		if err != nil {
-			log.Printf("GameService: Failed to count remaining games: %v", err)
-		} else if remainingCount > 0 {
-			log.Printf("GameService: %d more games need syncing, continuing...", remainingCount)
-			// Reset sync state and continue
-			s.syncProgress.mu.Lock()
-			s.syncProgress.isSyncing = false
-			s.syncProgress.mu.Unlock()
-			// Recursive call to sync remaining games
-			s.runSync(progressCallback)
-			return
+			log.Printf("GameService: Failed to insert game %d: %v", g.AppID, err)
+		} else {
+			newGames++
 		}
+	}
 
-		log.Println("GameService: All games synced")
-		if progressCallback != nil {
-			progressCallback("complete", "", multiplayerCount, totalToFetch)
-		}
-	}()
+	log.Printf("GameService: Inserted %d games for user %s", newGames, steamID)
 }
 
-// fetchGameCategoriesWithProgress fetches categories with progress callback
-func (s *GameService) fetchGameCategoriesWithProgress(games []*models.Game, progressCallback func(processed int, currentGame string)) {
-	if len(games) == 0 {
+// TriggerSyncIfNeeded asks the background scheduler to sync all games with stale or missing data
+// Progress is reported through the scheduler's callback.
+func (s *GameService) TriggerSyncIfNeeded() {
+	if s.syncScheduler == nil {
+		log.Println("GameService: No sync scheduler configured, skipping sync")
 		return
 	}
-
-	if s.isRateLimited() {
-		log.Printf("Skipping Steam Store API calls - rate limited until %v", s.rateLimiter.pausedUntil)
-		return
-	}
-
-	const delayBetweenRequests = 300 * time.Millisecond
-
-	for i, game := range games {
-		if s.isRateLimited() {
-			log.Printf("Rate limit hit - stopping category fetches")
-			return
-		}
-
-		if progressCallback != nil {
-			progressCallback(i, game.Name)
-		}
-
-		storeData, err := s.fetchGameCategoriesFromStore(game.AppID)
-		if err != nil {
-			log.Printf("Could not fetch data for %s (%d): %v", game.Name, game.AppID, err)
-
-			if strings.Contains(err.Error(), "game not found") || strings.Contains(err.Error(), "not accessible") {
-				log.Printf("Game %s (%d) appears to be unavailable - caching failure", game.Name, game.AppID)
-				if cacheErr := s.gameCacheRepo.UpsertWithStatus(game.AppID, game.Name, []string{}, nil, true); cacheErr != nil {
-					log.Printf("Failed to cache failed fetch for game %d: %v", game.AppID, cacheErr)
-				}
-			}
-			continue
-		}
-
-		game.Categories = storeData.Categories
-		if storeData.Name != "" {
-			game.Name = storeData.Name
-		}
-		game.IsFree = storeData.IsFree
-		game.PriceCents = storeData.PriceCents
-		game.OriginalCents = storeData.OriginalCents
-		game.DiscountPercent = storeData.DiscountPercent
-		game.PriceFormatted = storeData.PriceFormatted
-		game.ReviewScore = storeData.ReviewScore
-
-		// Save to DB cache
-		priceInfo := &repository.GamePriceInfo{
-			IsFree:          storeData.IsFree,
-			PriceCents:      storeData.PriceCents,
-			OriginalCents:   storeData.OriginalCents,
-			DiscountPercent: storeData.DiscountPercent,
-			PriceFormatted:  storeData.PriceFormatted,
-			ReviewScore:     storeData.ReviewScore,
-		}
-		if err := s.gameCacheRepo.Upsert(game.AppID, game.Name, storeData.Categories, priceInfo); err != nil {
-			log.Printf("Failed to cache game %d: %v", game.AppID, err)
-		}
-
-		time.Sleep(delayBetweenRequests)
-	}
-
-	if progressCallback != nil {
-		progressCallback(len(games), "")
-	}
+	s.syncScheduler.Trigger()
 }
 
 // Helper function to check if a slice contains a string
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// DefaultGameSuggestionLimit is the number of suggestions returned if no limit is given
+const DefaultGameSuggestionLimit = 20
+
+// SuggestGames ranks the cached multiplayer games for a group of players
+// Games are ordered by coverage (how many players can play them right away, everyone for
+// free games), then by whether the group fits the player limit, the cost for missing
+// owners and the review score.
+func (s *GameService) SuggestGames(players []models.User, limit int) ([]models.GameSuggestion, error) {
+	games, _, err := s.GetMultiplayerGamesCached()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultGameSuggestionLimit
+	}
+
+	// Pinned games are listed separately, but may also be owned by players
+	candidates := make(map[int]models.Game)
+	for _, game := range games.PinnedGames {
+		candidates[game.AppID] = game
+	}
+	for _, game := range games.AllGames {
+		candidates[game.AppID] = game
+	}
+
+	return rankGameSuggestions(candidates, players, limit), nil
+}
+
+// rankGameSuggestions builds and orders the suggestions for the candidate games, best first
+func rankGameSuggestions(candidates map[int]models.Game, players []models.User, limit int) []models.GameSuggestion {
+	suggestions := make([]models.GameSuggestion, 0, len(candidates))
+	for _, game := range candidates {
+		suggestion := buildGameSuggestion(game, players)
+
+		// Skip games nobody in the group owns, unless everyone can get them for free
+		if len(suggestion.PresentOwners) == 0 && !game.IsFree {
+			continue
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Coverage != b.Coverage {
+			return a.Coverage > b.Coverage
+		}
+		if a.FitsGroup != b.FitsGroup {
+			return a.FitsGroup
+		}
+		if costA, costB := sortableCost(a.MissingCostCents), sortableCost(b.MissingCostCents); costA != costB {
+			return costA < costB
+		}
+		if a.ReviewScore != b.ReviewScore {
+			return a.ReviewScore > b.ReviewScore
+		}
+		return a.Name < b.Name
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// buildGameSuggestion calculates coverage and costs of a game for the given players
+func buildGameSuggestion(game models.Game, players []models.User) models.GameSuggestion {
+	owners := make(map[string]bool, len(game.Owners))
+	for _, steamID := range game.Owners {
+		owners[steamID] = true
+	}
+
+	suggestion := models.GameSuggestion{
+		Game:          game,
+		PlayerCount:   len(players),
+		PresentOwners: []string{},
+		MissingOwners: []string{},
+		FitsGroup:     game.MaxPlayers == 0 || game.MaxPlayers >= len(players),
+	}
+
+	for _, player := range players {
+		if owners[player.SteamID] {
+			suggestion.PresentOwners = append(suggestion.PresentOwners, player.SteamID)
+		} else {
+			suggestion.MissingOwners = append(suggestion.MissingOwners, player.SteamID)
+		}
+	}
+
+	// Everyone can play a free game right away, whether it's in their library or not
+	switch {
+	case game.IsFree:
+		suggestion.Coverage = 1
+	case len(players) > 0:
+		suggestion.Coverage = float64(len(suggestion.PresentOwners)) / float64(len(players))
+	}
+
+	switch {
+	case game.IsFree || len(suggestion.MissingOwners) == 0:
+		suggestion.MissingCostCents = 0
+	case game.PriceCents > 0:
+		suggestion.MissingCostCents = game.PriceCents * len(suggestion.MissingOwners)
+	default:
+		// No price in the store data (e.g. not sold anymore or not synced yet)
+		suggestion.MissingCostCents = -1
+	}
+
+	return suggestion
+}
+
+// sortableCost orders unknown prices after all known ones
+func sortableCost(cents int) int {
+	if cents < 0 {
+		return int(^uint(0) >> 1)
+	}
+	return cents
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// suggestionNames returns the game names of the suggestions in order
+func suggestionNames(suggestions []models.GameSuggestion) []string {
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func TestRankGameSuggestions(t *testing.T) {
+	players := []models.User{{SteamID: "1"}, {SteamID: "2"}, {SteamID: "3"}, {SteamID: "4"}}
+	candidates := map[int]models.Game{
+		1: {AppID: 1, Name: "Paid, two owners", Owners: []string{"1", "2"}, PriceCents: 999, ReviewScore: 95},
+		2: {AppID: 2, Name: "Free, no owners", IsFree: true, ReviewScore: 70},
+		3: {AppID: 3, Name: "Paid, all owners", Owners: []string{"1", "2", "3", "4"}, ReviewScore: 60},
+		4: {AppID: 4, Name: "Paid, too small", Owners: []string{"1", "2", "3", "4"}, MaxPlayers: 2, ReviewScore: 99},
+		5: {AppID: 5, Name: "Nobody owns it", PriceCents: 499},
+		6: {AppID: 6, Name: "Paid, two owners, cheap", Owners: []string{"3", "4"}, PriceCents: 499, ReviewScore: 50},
+		7: {AppID: 7, Name: "Paid, two owners, no price", Owners: []string{"1", "3"}, ReviewScore: 99},
+	}
+
+	got := suggestionNames(rankGameSuggestions(candidates, players, 10))
+	want := []string{
+		// Free games are playable by everyone, ties are broken by the review score
+		"Free, no owners",
+		"Paid, all owners",
+		"Paid, too small",
+		"Paid, two owners, cheap",
+		"Paid, two owners",
+		"Paid, two owners, no price",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	if limited := rankGameSuggestions(candidates, players, 2); len(limited) != 2 || limited[0].Name != "Free, no owners" {
+		t.Errorf("expected the two best suggestions, got %v", suggestionNames(limited))
+	}
+}
+
+func TestBuildGameSuggestionFreeGame(t *testing.T) {
+	players := []models.User{{SteamID: "1"}, {SteamID: "2"}}
+	suggestion := buildGameSuggestion(models.Game{AppID: 1, IsFree: true, Owners: []string{"1"}}, players)
+
+	if suggestion.Coverage != 1 || suggestion.MissingCostCents != 0 {
+		t.Errorf("expected a free game to be playable by everyone at no cost, got %+v", suggestion)
+	}
+	if len(suggestion.PresentOwners) != 1 || len(suggestion.MissingOwners) != 1 {
+		t.Errorf("expected the library owners to be listed anyway, got %+v", suggestion)
+	}
+}
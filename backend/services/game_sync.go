@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+)
+
+// ErrSyncInProgress is returned when a game sync is started while another one is running
+var ErrSyncInProgress = errors.New("game sync already in progress")
+
+const (
+	// gameSyncJobName is the name of the persisted game library sync job
+	gameSyncJobName = "game_library"
+	// gameSyncSaveEvery is the number of synced games after which the job progress is persisted
+	gameSyncSaveEvery = 10
+)
+
+// GameSyncScheduler syncs stale game data in the background
+// It runs on a fixed interval and on demand, waits out Steam rate limits with exponential
+// backoff and persists its progress so an interrupted sync is resumed after a restart.
+type GameSyncScheduler struct {
+	cfg         *config.Config
+	gameService *GameService
+	syncJobRepo *repository.SyncJobRepository
+	onProgress  SyncProgressCallback
+	wake        chan struct{}
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewGameSyncScheduler creates a new game sync scheduler and attaches it to the game service
+// onProgress receives the progress of every sync and may be nil.
+func NewGameSyncScheduler(cfg *config.Config, gameService *GameService, syncJobRepo *repository.SyncJobRepository, onProgress SyncProgressCallback) *GameSyncScheduler {
+	sch := &GameSyncScheduler{
+		cfg:         cfg,
+		gameService: gameService,
+		syncJobRepo: syncJobRepo,
+		onProgress:  onProgress,
+		wake:        make(chan struct{}, 1),
+	}
+	gameService.syncScheduler = sch
+	return sch
+}
+
+// Start begins syncing in the background until ctx is cancelled or Stop is called
+// A sync that was interrupted by a shutdown is resumed right away.
+func (sch *GameSyncScheduler) Start(ctx context.Context) {
+	ctx, sch.cancel = context.WithCancel(ctx)
+
+	job, err := sch.syncJobRepo.Get(gameSyncJobName)
+	if err != nil {
+		log.Printf("[GameSync] Failed to load sync job: %v", err)
+	}
+	if job != nil && job.Status != models.SyncJobIdle {
+		log.Printf("[GameSync] Resuming interrupted sync (%d/%d games done)", job.Processed, job.Total)
+		if job.PausedUntil != nil {
+			sch.gameService.restoreRateLimit(*job.PausedUntil, job.Failures)
+		}
+		sch.Trigger()
+	} else if sch.cfg.GameSyncInterval > 0 {
+		sch.Trigger()
+	}
+
+	sch.wg.Add(1)
+	go sch.run(ctx)
+	log.Printf("[GameSync] Scheduler started (interval: %v, workers: %d)", sch.cfg.GameSyncInterval, sch.workers())
+}
+
+// Stop cancels a running sync and waits for the scheduler to exit
+// The progress of the cancelled sync stays persisted and is resumed on the next start.
+func (sch *GameSyncScheduler) Stop() {
+	if sch.cancel == nil {
+		return
+	}
+	sch.cancel()
+	sch.wg.Wait()
+	log.Println("[GameSync] Scheduler stopped")
+}
+
+// Trigger requests a sync without waiting for it
+// Requests while a sync is already pending are merged into one.
+func (sch *GameSyncScheduler) Trigger() {
+	select {
+	case sch.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run syncs on every tick and every trigger
+func (sch *GameSyncScheduler) run(ctx context.Context) {
+	defer sch.wg.Done()
+
+	var tick <-chan time.Time
+	if sch.cfg.GameSyncInterval > 0 {
+		ticker := time.NewTicker(sch.cfg.GameSyncInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		announce := false
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-sch.wake:
+			announce = true
+		}
+
+		if err := sch.sync(ctx, announce); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("[GameSync] Sync failed: %v", err)
+		}
+	}
+}
+
+// RunOnce syncs all stale games and returns when done, waiting out rate limits
+func (sch *GameSyncScheduler) RunOnce(ctx context.Context) error {
+	return sch.sync(ctx, true)
+}
+
+// sync works through all games needing a sync in batches until none are left
+// announce reports completion even if there was nothing to sync, for syncs somebody asked for.
+func (sch *GameSyncScheduler) sync(ctx context.Context, announce bool) error {
+	s := sch.gameService
+	if !s.beginSync() {
+		return ErrSyncInProgress
+	}
+	defer s.setSyncProgress(false, "", "", 0, 0)
+
+	job, err := sch.syncJobRepo.Get(gameSyncJobName)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if job == nil || job.Status == models.SyncJobIdle {
+		job = &models.SyncJob{Name: gameSyncJobName, StartedAt: &now}
+	}
+
+	// Games that were tried in this run are not retried until the next run,
+	// otherwise games failing with network errors would be fetched forever
+	attempted := make(map[int]bool)
+	multiplayerCount := 0
+
+	for {
+		if err := sch.waitForRateLimit(ctx, job); err != nil {
+			sch.save(job)
+			return err
+		}
+
+		pending, err := s.gameCacheRepo.GetGamesNeedingSync(gameCacheMaxAge, failedFetchRetryDelay)
+		if err != nil {
+			return fmt.Errorf("failed to get games needing sync: %w", err)
+		}
+		var games []*models.Game
+		for _, g := range pending {
+			if !attempted[g.AppID] {
+				games = append(games, &models.Game{AppID: g.AppID, Name: g.Name})
+			}
+		}
+		if len(games) == 0 {
+			break
+		}
+
+		job.Status = models.SyncJobRunning
+		job.Total = job.Processed + len(games)
+		job.PausedUntil = nil
+		sch.save(job)
+		log.Printf("[GameSync] Syncing %d games", len(games))
+		sch.report("fetching_categories", "", job.Processed, job.Total)
+
+		err = s.syncGamesParallel(ctx, games, func(game *models.Game, err error) {
+			attempted[game.AppID] = true
+			job.Processed++
+			if err == nil && game.HasMultiplayerCategory() {
+				multiplayerCount++
+			}
+			sch.report("fetching_categories", game.Name, job.Processed, job.Total)
+			if job.Processed%gameSyncSaveEvery == 0 {
+				sch.save(job)
+			}
+		})
+
+		var rateLimitErr *steam.RateLimitError
+		switch {
+		case errors.As(err, &rateLimitErr):
+			job.LastError = err.Error()
+		case err != nil:
+			sch.save(job)
+			return err
+		}
+		sch.save(job)
+	}
+
+	finished := time.Now().UTC().Truncate(time.Second)
+	processed := job.Processed
+	job.Status = models.SyncJobIdle
+	job.FinishedAt = &finished
+	job.PausedUntil = nil
+	job.Processed, job.Total = 0, 0
+	sch.save(job)
+
+	s.InvalidateCache()
+
+	if processed > 0 || announce {
+		log.Printf("[GameSync] All games synced (%d games, %d multiplayer)", processed, multiplayerCount)
+		if sch.onProgress != nil {
+			sch.onProgress("complete", "", multiplayerCount, processed)
+		}
+	}
+	return nil
+}
+
+// waitForRateLimit blocks until a rate limit pause is over, persisting the paused state
+func (sch *GameSyncScheduler) waitForRateLimit(ctx context.Context, job *models.SyncJob) error {
+	until := sch.gameService.RateLimitedUntil()
+	if until.IsZero() {
+		return nil
+	}
+
+	job.Status = models.SyncJobPaused
+	job.PausedUntil = &until
+	sch.save(job)
+	log.Printf("[GameSync] Rate limited - pausing sync until %v", until.Format(time.RFC3339))
+	sch.report("paused", "", job.Processed, job.Total)
+
+	timer := time.NewTimer(time.Until(until))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// report updates the sync status and forwards it to the progress callback
+func (sch *GameSyncScheduler) report(phase, currentGame string, processed, total int) {
+	sch.gameService.setSyncProgress(true, phase, currentGame, processed, total)
+	if sch.onProgress != nil {
+		sch.onProgress(phase, currentGame, processed, total)
+	}
+}
+
+// save persists the job together with the current backoff state
+// Failures are only logged - a lost progress update is not worth aborting the sync.
+func (sch *GameSyncScheduler) save(job *models.SyncJob) {
+	pausedUntil, failures := sch.gameService.rateLimitState()
+	job.Failures = failures
+	if job.Status == models.SyncJobPaused {
+		job.PausedUntil = &pausedUntil
+	}
+	job.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	if err := sch.syncJobRepo.Save(job); err != nil {
+		log.Printf("[GameSync] Failed to save sync job: %v", err)
+	}
+}
+
+// workers returns the configured number of parallel store lookups
+func (sch *GameSyncScheduler) workers() int {
+	return max(sch.cfg.GameSyncWorkers, 1)
+}
+
+// syncGamesParallel syncs games with a bounded pool of workers
+// onDone is called once per synced or failed game, never concurrently. Games that hit a rate
+// limit are not reported so they stay pending; the remaining games are skipped and the
+// RateLimitError is returned. Cancelling ctx stops the workers after their current game.
+func (s *GameService) syncGamesParallel(ctx context.Context, games []*models.Game, onDone func(game *models.Game, err error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu           sync.Mutex
+		rateLimitErr error
+		wg           sync.WaitGroup
+	)
+	jobs := make(chan *models.Game)
+
+	for i := 0; i < max(s.cfg.GameSyncWorkers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for game := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				err := s.syncGame(game)
+
+				var limited *steam.RateLimitError
+				if errors.As(err, &limited) {
+					mu.Lock()
+					if rateLimitErr == nil {
+						rateLimitErr = err
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+
+				if onDone != nil {
+					mu.Lock()
+					onDone(game, err)
+					mu.Unlock()
+				}
+
+				select {
+				case <-ctx.Done():
+				case <-time.After(storeRequestDelay):
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, game := range games {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- game:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if rateLimitErr != nil {
+		return rateLimitErr
+	}
+	return ctx.Err()
+}
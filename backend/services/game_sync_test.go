@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{"first rate limit", 0, 0, time.Minute},
+		{"doubles", 1, 0, 2 * time.Minute},
+		{"doubles again", 3, 0, 8 * time.Minute},
+		{"capped", 10, 0, time.Hour},
+		{"retry-after is longer", 0, 10 * time.Minute, 10 * time.Minute},
+		{"retry-after is shorter", 2, 30 * time.Second, 4 * time.Minute},
+		{"retry-after beyond cap", 10, 2 * time.Hour, 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoffDelay(time.Minute, time.Hour, tt.failures, tt.retryAfter); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameSyncWithFakeSteam(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		steamClient, err := steam.NewFakeClient("../defaults/steam-fake")
+		if err != nil {
+			t.Fatalf("failed to load Steam fixtures: %v", err)
+		}
+
+		cfg := &config.Config{GameSyncWorkers: 2, GameSyncBackoffMin: time.Minute, GameSyncBackoffMax: time.Hour}
+		userRepo := repository.NewUserRepository()
+		gameCacheRepo := repository.NewGameCacheRepository()
+		gameService := NewGameService(cfg, steamClient, userRepo, gameCacheRepo, repository.NewGameOwnerRepository(),
+			newImageCacheService(t.TempDir(), 0, 0, &http.Client{Transport: offlineTransport{}}), NewGameMetadataService(&config.Config{}, repository.NewGameMetadataRepository()))
+
+		for _, steamID := range []string{"76561190000000001", "76561190000000002"} {
+			user := &models.User{SteamID: steamID, Username: steamID, LastCreditAt: time.Now().UTC()}
+			if err := userRepo.Create(user); err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+		}
+
+		if _, _, err := gameService.RefreshAllLibraries(); err != nil {
+			t.Fatalf("RefreshAllLibraries failed: %v", err)
+		}
+
+		var completed []int
+		scheduler := NewGameSyncScheduler(cfg, gameService, repository.NewSyncJobRepository(), func(phase string, _ string, processed, total int) {
+			if phase == "complete" {
+				completed = []int{processed, total}
+			}
+		})
+		if err := scheduler.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce failed: %v", err)
+		}
+
+		// 6 games in both libraries, the delisted one is not multiplayer
+		if len(completed) != 2 || completed[0] != 4 || completed[1] != 6 {
+			t.Errorf("complete reported %v, want [4 6] (multiplayer, synced)", completed)
+		}
+
+		game, err := gameCacheRepo.GetByAppID(1509960)
+		if err != nil || game == nil {
+			t.Fatalf("expected PICO PARK in the cache, got %v (%v)", game, err)
+		}
+		if game.DiscountPercent != 50 || game.ReviewScore != 97 || len(game.GetCategories()) != 3 {
+			t.Errorf("unexpected cached game: %+v", game)
+		}
+
+		delisted, err := gameCacheRepo.GetByAppID(99999990)
+		if err != nil || delisted == nil || !delisted.FetchFailed {
+			t.Errorf("expected the delisted game to be cached as failed, got %+v (%v)", delisted, err)
+		}
+
+		remaining, err := gameCacheRepo.CountGamesNeedingSync(gameCacheMaxAge, failedFetchRetryDelay)
+		if err != nil || remaining != 0 {
+			t.Errorf("expected no games left to sync, got %d (%v)", remaining, err)
+		}
+
+		job, err := repository.NewSyncJobRepository().Get(gameSyncJobName)
+		if err != nil || job == nil || job.Status != models.SyncJobIdle || job.FinishedAt == nil {
+			t.Errorf("expected a finished idle sync job, got %+v (%v)", job, err)
+		}
+	})
+}
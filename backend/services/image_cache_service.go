@@ -1,58 +1,109 @@
 package services
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/gif" // Register GIF decoder for uploaded cover images
+	"image/jpeg"
+	_ "image/png" // Register PNG decoder for uploaded cover images
 	"io"
 	"log"
 	"net/http"
-	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	_ "golang.org/x/image/webp" // Register WebP decoder for uploaded cover images
 )
 
 const (
 	gameImagesDir = "data/game_images"
 	steamCDNURL   = "https://steamcdn-a.akamaihd.net/steam/apps"
+	// maxGameImageSize limits downloaded header images, Steam's are well below 1 MB
+	maxGameImageSize = 5 << 20
+)
+
+// ErrInvalidImage is returned when an uploaded image cannot be decoded
+var ErrInvalidImage = errors.New("invalid image")
+
+// GameImageSizes are the widths header images can be resized to, matching Steam's capsule sizes
+var GameImageSizes = []int{120, 184, 231}
+
+// Limits for uploaded cover images
+const (
+	maxUploadedImagePixels = 4096 * 4096
+	uploadedImageQuality   = 90
 )
 
 // ImageCacheService handles caching of game images locally
+// Header images are downloaded from the Steam CDN on demand and evicted when the disk quota
+// is exceeded. Uploaded covers of custom games cannot be downloaded again and are never evicted.
 type ImageCacheService struct {
-	httpClient *http.Client
-	baseDir    string
+	cache   *fileCache
+	baseDir string
 }
 
 // NewImageCacheService creates a new image cache service
-func NewImageCacheService() *ImageCacheService {
-	svc := &ImageCacheService{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		baseDir: gameImagesDir,
-	}
+func NewImageCacheService(cfg *config.Config) *ImageCacheService {
+	return newImageCacheService(gameImagesDir, int64(cfg.ImageCacheMaxMB)<<20, cfg.ImageCacheMaxAge,
+		&http.Client{Timeout: 30 * time.Second})
+}
 
-	// Ensure the images directory exists at startup
-	if err := svc.ensureDir(); err != nil {
-		log.Printf("Warning: Could not create game images directory: %v", err)
+// newImageCacheService creates an image cache service for the given directory
+func newImageCacheService(baseDir string, maxBytes int64, maxAge time.Duration, httpClient *http.Client) *ImageCacheService {
+	return &ImageCacheService{
+		cache:   newFileCache("game image", baseDir, maxBytes, maxAge, maxGameImageSize, httpClient, isUploadedImage),
+		baseDir: baseDir,
 	}
+}
 
-	return svc
+// isUploadedImage reports whether a cached file is the uploaded cover of a custom game
+func isUploadedImage(filename string) bool {
+	appID, err := strconv.Atoi(strings.TrimSuffix(filename, filepath.Ext(filename)))
+	return err == nil && models.IsCustomAppID(appID)
 }
 
-// ensureDir creates the game images directory if it doesn't exist
-func (s *ImageCacheService) ensureDir() error {
-	return os.MkdirAll(s.baseDir, 0755)
+// imageFilename returns the cache filename of a game's header image
+func imageFilename(appID int) string {
+	return fmt.Sprintf("%d.jpg", appID)
 }
 
 // GetImagePath returns the local file path for a game's header image
 func (s *ImageCacheService) GetImagePath(appID int) string {
-	return filepath.Join(s.baseDir, fmt.Sprintf("%d.jpg", appID))
+	return s.cache.path(imageFilename(appID))
 }
 
 // HasImage checks if an image is already cached locally
 func (s *ImageCacheService) HasImage(appID int) bool {
-	path := s.GetImagePath(appID)
-	_, err := os.Stat(path)
-	return err == nil
+	return s.cache.has(imageFilename(appID))
+}
+
+// GetImage returns the path of a game's header image for serving it, downloading it if needed
+// Returns false if the image is neither cached nor available from Steam.
+func (s *ImageCacheService) GetImage(appID int) (string, bool) {
+	if s.cache.get(imageFilename(appID)) {
+		return s.GetImagePath(appID), true
+	}
+	// Uploaded covers only exist locally
+	if models.IsCustomAppID(appID) || !s.CacheImage(appID) {
+		return "", false
+	}
+	return s.GetImagePath(appID), true
+}
+
+// GetImageVariant returns the path of a game's header image resized to one of GameImageSizes
+// The original must be cached already, see GetImage.
+func (s *ImageCacheService) GetImageVariant(appID int, width int) (string, error) {
+	if !slices.Contains(GameImageSizes, width) {
+		return "", fmt.Errorf("%w: %d", ErrUnsupportedSize, width)
+	}
+	return s.cache.variant(imageFilename(appID), width)
 }
 
 // GetLocalImageURL returns the URL path for serving the cached image
@@ -69,50 +120,7 @@ func (s *ImageCacheService) GetSteamImageURL(appID int) string {
 // CacheImage downloads and caches a game's header image
 // Returns true if the image was successfully cached, false otherwise
 func (s *ImageCacheService) CacheImage(appID int) bool {
-	// Skip if already cached
-	if s.HasImage(appID) {
-		return true
-	}
-
-	// Ensure directory exists
-	if err := s.ensureDir(); err != nil {
-		log.Printf("Failed to create image cache directory: %v", err)
-		return false
-	}
-
-	// Download from Steam CDN
-	imageURL := s.GetSteamImageURL(appID)
-	resp, err := s.httpClient.Get(imageURL)
-	if err != nil {
-		log.Printf("Failed to download image for game %d from %s: %v", appID, imageURL, err)
-		return false
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Failed to download image for game %d from %s: HTTP %d", appID, imageURL, resp.StatusCode)
-		return false
-	}
-
-	// Create the local file
-	localPath := s.GetImagePath(appID)
-	file, err := os.Create(localPath)
-	if err != nil {
-		log.Printf("Failed to create image file for game %d: %v", appID, err)
-		return false
-	}
-	defer file.Close()
-
-	// Copy the image data
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		log.Printf("Failed to save image for game %d: %v", appID, err)
-		// Clean up partial file
-		os.Remove(localPath)
-		return false
-	}
-
-	return true
+	return s.CacheImageFromURL(appID, s.GetSteamImageURL(appID))
 }
 
 // CacheImageAsync downloads and caches a game's header image asynchronously
@@ -125,56 +133,64 @@ func (s *ImageCacheService) CacheImageAsync(appID int) {
 // CacheImageFromURL downloads and caches a game's header image from a specific URL
 // This is useful for newer Steam games that use the new CDN with hash-based URLs
 func (s *ImageCacheService) CacheImageFromURL(appID int, imageURL string) bool {
-	// Skip if already cached
-	if s.HasImage(appID) {
-		return true
-	}
-
-	// Ensure directory exists
-	if err := s.ensureDir(); err != nil {
-		log.Printf("Failed to create image cache directory: %v", err)
+	if err := s.cache.fetch(imageFilename(appID), imageURL); err != nil {
+		log.Printf("Failed to cache image for game %d from %s: %v", appID, imageURL, err)
 		return false
 	}
+	return true
+}
 
-	// Download from the provided URL
-	resp, err := s.httpClient.Get(imageURL)
+// CacheImageFromURLAsync downloads and caches a game's header image from a specific URL asynchronously
+func (s *ImageCacheService) CacheImageFromURLAsync(appID int, imageURL string) {
+	go func() {
+		s.CacheImageFromURL(appID, imageURL)
+	}()
+}
+
+// SaveImage stores an uploaded image as a game's header image, replacing any existing one
+// PNG, GIF, JPEG and WebP are accepted and re-encoded as JPEG, so every image is served the same
+// way. A WebP upload is kept as well and served to clients that accept WebP.
+func (s *ImageCacheService) SaveImage(appID int, r io.Reader) error {
+	data, err := io.ReadAll(r)
 	if err != nil {
-		log.Printf("Failed to download image for game %d from %s: %v", appID, imageURL, err)
-		return false
+		return fmt.Errorf("failed to read image: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Failed to download image for game %d from %s: HTTP %d", appID, imageURL, resp.StatusCode)
-		return false
+	// Check the dimensions before decoding, so a tiny file cannot claim a huge canvas
+	bounds, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidImage, err)
 	}
-
-	// Create the local file
-	localPath := s.GetImagePath(appID)
-	file, err := os.Create(localPath)
+	if bounds.Width <= 0 || bounds.Height <= 0 || bounds.Width*bounds.Height > maxUploadedImagePixels {
+		return fmt.Errorf("%w: unsupported dimensions %dx%d", ErrInvalidImage, bounds.Width, bounds.Height)
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
-		log.Printf("Failed to create image file for game %d: %v", appID, err)
-		return false
+		return fmt.Errorf("%w: %v", ErrInvalidImage, err)
 	}
-	defer file.Close()
 
-	// Copy the image data
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		log.Printf("Failed to save image for game %d: %v", appID, err)
-		// Clean up partial file
-		os.Remove(localPath)
-		return false
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: uploadedImageQuality}); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	// Replacing the JPEG removes the WebP version of a previous upload
+	if err := s.cache.store(imageFilename(appID), encoded.Bytes()); err != nil {
+		return err
 	}
+	if format == "webp" {
+		return s.cache.store(webpFilename(imageFilename(appID)), data)
+	}
+	return nil
+}
 
-	return true
+// DeleteImage removes a game's cached header image
+func (s *ImageCacheService) DeleteImage(appID int) error {
+	return s.cache.remove(imageFilename(appID))
 }
 
-// CacheImageFromURLAsync downloads and caches a game's header image from a specific URL asynchronously
-func (s *ImageCacheService) CacheImageFromURLAsync(appID int, imageURL string) {
-	go func() {
-		s.CacheImageFromURL(appID, imageURL)
-	}()
+// Stats returns size and hit rate of the image cache
+func (s *ImageCacheService) Stats() CacheStats {
+	return s.cache.stats()
 }
 
 // GetBaseDir returns the base directory for cached images
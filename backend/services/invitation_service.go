@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/auth"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// Errors returned by the invitation service
+var (
+	ErrInvalidInvitation = errors.New("invalid invitation")
+	ErrSteamUnavailable  = errors.New("Steam request failed")
+)
+
+const (
+	// maxInvitesPerImport limits how many players one friend list or group import may invite
+	maxInvitesPerImport = 500
+	// playerSummariesBatchSize is the maximum number of Steam IDs per GetPlayerSummaries call
+	playerSummariesBatchSize = 100
+)
+
+// InvitationService imports Steam friends and group members as invited placeholder users
+// Invited users show up in the user picker and their games in the games list before they
+// log in for the first time. The first Steam login claims the placeholder (see UserRepository.FindOrCreate).
+type InvitationService struct {
+	steamClient steam.Client
+	userRepo    *repository.UserRepository
+	gameService *GameService
+	wsHub       *websocket.Hub
+	// mu serializes imports, so two imports of overlapping lists do not invite a player twice
+	mu sync.Mutex
+}
+
+// NewInvitationService creates a new invitation service
+func NewInvitationService(steamClient steam.Client, userRepo *repository.UserRepository, gameService *GameService, wsHub *websocket.Hub) *InvitationService {
+	return &InvitationService{
+		steamClient: steamClient,
+		userRepo:    userRepo,
+		gameService: gameService,
+		wsHub:       wsHub,
+	}
+}
+
+// ImportFriends invites all friends of a player
+// The player's friend list has to be public.
+func (s *InvitationService) ImportFriends(steamID string) (*models.InviteResult, error) {
+	steamID, err := auth.ParseSteamID64(steamID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInvitation, err)
+	}
+
+	friends, err := s.steamClient.GetFriendList(steamID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch friend list: %v", ErrSteamUnavailable, err)
+	}
+
+	log.Printf("[Invitations] Importing %d friends of %s", len(friends), steamID)
+	return s.invite(friends)
+}
+
+// ImportGroup invites all members of a Steam group
+func (s *InvitationService) ImportGroup(group string) (*models.InviteResult, error) {
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return nil, fmt.Errorf("%w: group is required", ErrInvalidInvitation)
+	}
+
+	members, err := s.steamClient.GetGroupMembers(group)
+	if errors.Is(err, steam.ErrGroupNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch group members: %v", ErrSteamUnavailable, err)
+	}
+
+	log.Printf("[Invitations] Importing %d members of group %s", len(members), group)
+	return s.invite(members)
+}
+
+// invite creates placeholder users for all unknown players and syncs their game libraries
+// Players that are registered, invited or banned already are skipped, as are Steam IDs
+// without a profile.
+func (s *InvitationService) invite(steamIDs []string) (*models.InviteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &models.InviteResult{Invited: []models.PublicUser{}}
+
+	seen := make(map[string]bool, len(steamIDs))
+	var candidates []string
+	for _, id := range steamIDs {
+		id, err := auth.ParseSteamID64(id)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		banned, err := s.userRepo.IsBanned(id)
+		if err != nil {
+			return nil, err
+		}
+		if banned {
+			result.Banned++
+			continue
+		}
+
+		existing, err := s.userRepo.GetBySteamID(id)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			result.AlreadyRegistered++
+			continue
+		}
+		candidates = append(candidates, id)
+	}
+
+	if len(candidates) > maxInvitesPerImport {
+		return nil, fmt.Errorf("%w: %d players found, at most %d can be invited at once", ErrInvalidInvitation, len(candidates), maxInvitesPerImport)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var invitedIDs []string
+	for start := 0; start < len(candidates); start += playerSummariesBatchSize {
+		batch := candidates[start:min(start+playerSummariesBatchSize, len(candidates))]
+		players, err := s.steamClient.GetPlayerSummaries(batch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to fetch player profiles: %v", ErrSteamUnavailable, err)
+		}
+		result.NotFound += len(batch) - len(players)
+
+		for _, player := range players {
+			avatar := auth.GetAvatarOrFallback(player.AvatarFull, player.PersonaName)
+			user := &models.User{
+				SteamID:      player.SteamID,
+				Username:     player.PersonaName,
+				AvatarURL:    avatar,
+				AvatarSmall:  avatar,
+				ProfileURL:   player.ProfileURL,
+				LastCreditAt: now,
+				InvitedAt:    &now,
+			}
+			if err := s.userRepo.Create(user); err != nil {
+				return nil, err
+			}
+			invitedIDs = append(invitedIDs, user.SteamID)
+			result.Invited = append(result.Invited, user.ToPublic())
+		}
+	}
+
+	log.Printf("[Invitations] Invited %d players (%d already registered, %d banned, %d not found)",
+		len(result.Invited), result.AlreadyRegistered, result.Banned, result.NotFound)
+
+	if len(invitedIDs) > 0 {
+		s.gameService.RegisterUserGames(invitedIDs...)
+		if s.wsHub != nil {
+			s.wsHub.BroadcastUsersInvited(result.Invited)
+		}
+	}
+	return result, nil
+}
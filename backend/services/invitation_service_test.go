@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+)
+
+func TestInvitationImportGroup(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		steamClient, err := steam.NewFakeClient("../defaults/steam-fake")
+		if err != nil {
+			t.Fatalf("failed to load Steam fixtures: %v", err)
+		}
+
+		userRepo := repository.NewUserRepository()
+		gameService := NewGameService(&config.Config{}, steamClient, userRepo, repository.NewGameCacheRepository(), repository.NewGameOwnerRepository(),
+			newImageCacheService(t.TempDir(), 0, 0, &http.Client{Transport: offlineTransport{}}), NewGameMetadataService(&config.Config{}, repository.NewGameMetadataRepository()))
+		service := NewInvitationService(steamClient, userRepo, gameService, nil)
+		// Invited libraries are registered in the background, finish before the database is closed
+		defer gameService.registrations.Wait()
+
+		registered := &models.User{SteamID: "76561190000000001", Username: "Fragmaster", LastCreditAt: time.Now().UTC()}
+		if err := userRepo.Create(registered); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		if err := userRepo.BanUser("76561190000000003", "LanLegend", "griefing", "admin"); err != nil {
+			t.Fatalf("failed to ban user: %v", err)
+		}
+
+		result, err := service.ImportGroup("lan-crew")
+		if err != nil {
+			t.Fatalf("ImportGroup failed: %v", err)
+		}
+		if len(result.Invited) != 2 || result.AlreadyRegistered != 1 || result.Banned != 1 || result.NotFound != 1 {
+			t.Errorf("unexpected import result: %+v", result)
+		}
+		for _, user := range result.Invited {
+			if !user.Invited || user.AvatarURL == "" {
+				t.Errorf("expected an invited user with a fallback avatar, got %+v", user)
+			}
+		}
+
+		// A second import finds everybody registered
+		result, err = service.ImportFriends("76561190000000001")
+		if err != nil {
+			t.Fatalf("ImportFriends failed: %v", err)
+		}
+		if len(result.Invited) != 0 || result.AlreadyRegistered != 2 || result.Banned != 1 {
+			t.Errorf("unexpected second import result: %+v", result)
+		}
+
+		if _, err := service.ImportGroup("unknown"); !errors.Is(err, steam.ErrGroupNotFound) {
+			t.Errorf("expected ErrGroupNotFound, got %v", err)
+		}
+		if _, err := service.ImportFriends("not-a-steam-id"); !errors.Is(err, ErrInvalidInvitation) {
+			t.Errorf("expected ErrInvalidInvitation, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/auth"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// ErrPrefetchInProgress is returned when a media prefetch is started while another one is running
+var ErrPrefetchInProgress = errors.New("media prefetch already in progress")
+
+const (
+	// mediaPrefetchWorkers is the number of parallel downloads of a media prefetch
+	mediaPrefetchWorkers = 4
+	// mediaPrefetchReportEvery is the number of processed items after which progress is broadcast
+	mediaPrefetchReportEvery = 10
+)
+
+// Phases of a media prefetch
+const (
+	MediaPrefetchPhaseGameImages = "game_images"
+	MediaPrefetchPhaseAvatars    = "avatars"
+	MediaPrefetchPhaseComplete   = "complete"
+)
+
+// MediaPrefetchStatus describes the progress of the last media prefetch
+type MediaPrefetchStatus struct {
+	Running    bool       `json:"running"`
+	Phase      string     `json:"phase"`   // "game_images", "avatars", "complete"
+	Current    string     `json:"current"` // Game or player processed last
+	Processed  int        `json:"processed"`
+	Total      int        `json:"total"` // Multiplayer games plus players
+	Failed     int        `json:"failed"`
+	Percentage int        `json:"percentage"` // 0-100
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// mediaPrefetchItem is a game or player whose images are downloaded
+type mediaPrefetchItem struct {
+	name  string
+	fetch func() error
+}
+
+// MediaPrefetchService downloads all images the frontend needs into the local caches
+// Before a LAN party the uplink is still free: the prefetch walks every multiplayer game and
+// every player, caches header images, capsule thumbnails and avatars, and rewrites avatar URLs
+// still pointing to Steam to the local cache, so the event can run without external CDNs.
+type MediaPrefetchService struct {
+	steamClient        steam.Client
+	userRepo           *repository.UserRepository
+	gameCacheRepo      *repository.GameCacheRepository
+	customGameRepo     *repository.CustomGameRepository
+	imageCacheService  *ImageCacheService
+	avatarCacheService *AvatarCacheService
+	wsHub              *websocket.Hub
+	mu                 sync.Mutex
+	status             MediaPrefetchStatus
+	cancel             context.CancelFunc
+	wg                 sync.WaitGroup
+}
+
+// NewMediaPrefetchService creates a new media prefetch service
+func NewMediaPrefetchService(steamClient steam.Client, userRepo *repository.UserRepository, gameCacheRepo *repository.GameCacheRepository, customGameRepo *repository.CustomGameRepository, imageCacheService *ImageCacheService, avatarCacheService *AvatarCacheService, wsHub *websocket.Hub) *MediaPrefetchService {
+	return &MediaPrefetchService{
+		steamClient:        steamClient,
+		userRepo:           userRepo,
+		gameCacheRepo:      gameCacheRepo,
+		customGameRepo:     customGameRepo,
+		imageCacheService:  imageCacheService,
+		avatarCacheService: avatarCacheService,
+		wsHub:              wsHub,
+	}
+}
+
+// Start begins a prefetch in the background and returns its initial status
+func (s *MediaPrefetchService) Start() (MediaPrefetchStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Running {
+		return s.status, ErrPrefetchInProgress
+	}
+
+	now := time.Now().UTC()
+	s.status = MediaPrefetchStatus{Running: true, Phase: MediaPrefetchPhaseGameImages, StartedAt: &now}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		if err := s.run(ctx); err != nil {
+			log.Printf("[MediaPrefetch] Prefetch failed: %v", err)
+		}
+	}()
+	return s.status, nil
+}
+
+// Status returns the progress of the running or last prefetch
+func (s *MediaPrefetchService) Status() MediaPrefetchStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Stop cancels a running prefetch and waits for it to exit
+func (s *MediaPrefetchService) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// Run prefetches all media and returns when done
+func (s *MediaPrefetchService) Run(ctx context.Context) error {
+	if _, err := s.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Stop()
+		return ctx.Err()
+	}
+	if status := s.Status(); status.Failed > 0 {
+		return fmt.Errorf("%d of %d items could not be cached", status.Failed, status.Total)
+	}
+	return nil
+}
+
+// run works through all games, then all players
+func (s *MediaPrefetchService) run(ctx context.Context) error {
+	defer s.finish()
+
+	games, err := s.gameItems()
+	if err != nil {
+		return err
+	}
+	players, err := s.playerItems()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.status.Total = len(games) + len(players)
+	s.mu.Unlock()
+	log.Printf("[MediaPrefetch] Caching images of %d games and %d players", len(games), len(players))
+	s.report()
+
+	if err := s.process(ctx, MediaPrefetchPhaseGameImages, games); err != nil {
+		return err
+	}
+	return s.process(ctx, MediaPrefetchPhaseAvatars, players)
+}
+
+// finish marks the prefetch as complete and reports the result
+func (s *MediaPrefetchService) finish() {
+	now := time.Now().UTC()
+	s.mu.Lock()
+	s.status.Running = false
+	s.status.Phase = MediaPrefetchPhaseComplete
+	s.status.Current = ""
+	s.status.FinishedAt = &now
+	status := s.status
+	s.mu.Unlock()
+
+	log.Printf("[MediaPrefetch] Finished: %d of %d items processed, %d failed", status.Processed, status.Total, status.Failed)
+	s.report()
+}
+
+// process fetches the items of a phase in parallel
+func (s *MediaPrefetchService) process(ctx context.Context, phase string, items []mediaPrefetchItem) error {
+	s.mu.Lock()
+	s.status.Phase = phase
+	s.mu.Unlock()
+
+	queue := make(chan mediaPrefetchItem)
+	var wg sync.WaitGroup
+	for i := 0; i < mediaPrefetchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				err := item.fetch()
+				if err != nil {
+					log.Printf("[MediaPrefetch] Failed to cache %s: %v", item.name, err)
+				}
+				s.itemDone(item.name, err)
+			}
+		}()
+	}
+
+	defer wg.Wait()
+	defer close(queue)
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case queue <- item:
+		}
+	}
+	return nil
+}
+
+// itemDone counts a processed item and broadcasts the progress every few items
+func (s *MediaPrefetchService) itemDone(name string, err error) {
+	s.mu.Lock()
+	s.status.Processed++
+	s.status.Current = name
+	if err != nil {
+		s.status.Failed++
+	}
+	report := s.status.Processed%mediaPrefetchReportEvery == 0
+	s.mu.Unlock()
+
+	if report {
+		s.report()
+	}
+}
+
+// report broadcasts the current progress
+func (s *MediaPrefetchService) report() {
+	s.mu.Lock()
+	if s.status.Total > 0 {
+		s.status.Percentage = s.status.Processed * 100 / s.status.Total
+	}
+	status := s.status
+	s.mu.Unlock()
+
+	if s.wsHub != nil {
+		s.wsHub.BroadcastMediaPrefetchProgress(status)
+	}
+}
+
+// gameItems returns the multiplayer games of the game cache and custom games with a cover
+func (s *MediaPrefetchService) gameItems() ([]mediaPrefetchItem, error) {
+	cached, err := s.gameCacheRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []mediaPrefetchItem
+	for _, entry := range cached {
+		game := models.Game{AppID: entry.AppID}
+		if entry.FetchFailed || json.Unmarshal([]byte(entry.Categories), &game.Categories) != nil || !game.HasMultiplayerCategory() {
+			continue
+		}
+		appID := entry.AppID
+		items = append(items, mediaPrefetchItem{
+			name:  entry.Name,
+			fetch: func() error { return s.fetchGameImages(appID) },
+		})
+	}
+
+	customGames, err := s.customGameRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, custom := range customGames {
+		if !custom.HasImage {
+			continue
+		}
+		appID := custom.AppID
+		items = append(items, mediaPrefetchItem{
+			name:  custom.Name,
+			fetch: func() error { return s.fetchGameImages(appID) },
+		})
+	}
+	return items, nil
+}
+
+// fetchGameImages caches the header image of a game and its capsule thumbnails
+// Newer games only have their header image on the hash-based CDN, its URL is looked up in the store.
+func (s *MediaPrefetchService) fetchGameImages(appID int) error {
+	if !s.imageCacheService.HasImage(appID) {
+		if models.IsCustomAppID(appID) {
+			return fmt.Errorf("cover of custom game %d is missing", appID)
+		}
+		if !s.imageCacheService.CacheImage(appID) {
+			details, err := s.steamClient.GetAppDetails(appID)
+			if err != nil {
+				return fmt.Errorf("failed to look up header image: %w", err)
+			}
+			if details.HeaderImage == "" || !s.imageCacheService.CacheImageFromURL(appID, details.HeaderImage) {
+				return fmt.Errorf("header image of game %d is not available", appID)
+			}
+		}
+	}
+
+	for _, size := range GameImageSizes {
+		if _, err := s.imageCacheService.GetImageVariant(appID, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// playerItems returns all players
+func (s *MediaPrefetchService) playerItems() ([]mediaPrefetchItem, error) {
+	users, err := s.userRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]mediaPrefetchItem, 0, len(users))
+	for i := range users {
+		user := &users[i]
+		items = append(items, mediaPrefetchItem{
+			name:  user.Username,
+			fetch: func() error { return s.fetchAvatar(user) },
+		})
+	}
+	return items, nil
+}
+
+// fetchAvatar caches a player's avatar and its thumbnails
+// Avatar URLs still pointing to Steam (e.g. of invited players) are replaced by the local URL.
+// A local avatar that was evicted and whose source is unknown is looked up on Steam again.
+func (s *MediaPrefetchService) fetchAvatar(user *models.User) error {
+	if user.AvatarURL == "" {
+		return nil
+	}
+
+	filename, local := s.avatarCacheService.GetLocalFilename(user.AvatarURL)
+	if local {
+		if _, ok := s.avatarCacheService.GetAvatarFile(filename); !ok {
+			player, err := steam.GetPlayerSummary(s.steamClient, user.SteamID)
+			if err != nil {
+				return fmt.Errorf("avatar is missing and the profile could not be loaded: %w", err)
+			}
+			if err := s.localizeAvatar(user, auth.GetAvatarOrFallback(player.AvatarFull, player.PersonaName)); err != nil {
+				return err
+			}
+		}
+	} else if err := s.localizeAvatar(user, user.AvatarURL); err != nil {
+		return err
+	}
+
+	filename, _ = s.avatarCacheService.GetLocalFilename(user.AvatarURL)
+	if strings.HasSuffix(filename, ".svg") {
+		return nil
+	}
+	for _, size := range AvatarSizes {
+		if _, err := s.avatarCacheService.GetAvatarVariant(filename, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localizeAvatar caches an avatar and points the player's avatar URLs to the cached copy
+func (s *MediaPrefetchService) localizeAvatar(user *models.User, sourceURL string) error {
+	filename := s.avatarCacheService.GetAvatarFilename(user.SteamID, sourceURL)
+	localURL := s.avatarCacheService.CacheAvatar(user.SteamID, sourceURL)
+	if !s.avatarCacheService.HasAvatarFile(filename) {
+		return fmt.Errorf("failed to download avatar from %s", sourceURL)
+	}
+
+	s.avatarCacheService.CleanupOldAvatars(user.SteamID, filename)
+	if user.AvatarURL == localURL {
+		return nil
+	}
+	user.AvatarURL = localURL
+	user.AvatarSmall = s.avatarCacheService.GetSmallAvatarURL(localURL)
+	return s.userRepo.Update(user)
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/steam"
+)
+
+// imageTransport answers every request with a PNG, except for URLs containing a missing path
+type imageTransport struct {
+	png     []byte
+	missing string
+}
+
+func (tr imageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.Path, tr.missing) {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"image/png"}},
+		Body:       io.NopCloser(bytes.NewReader(tr.png)),
+		Request:    req,
+	}, nil
+}
+
+func TestMediaPrefetch(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		steamClient, err := steam.NewFakeClient("../defaults/steam-fake")
+		if err != nil {
+			t.Fatalf("failed to load Steam fixtures: %v", err)
+		}
+
+		httpClient := &http.Client{Transport: imageTransport{png: testPNG(t), missing: "/945360/"}}
+		imageCacheService := newImageCacheService(t.TempDir(), 0, 0, httpClient)
+		avatarCacheService := newAvatarCacheService(t.TempDir(), "http://localhost:8080", 0, 0, httpClient)
+
+		gameCacheRepo := repository.NewGameCacheRepository()
+		for appID, categories := range map[int][]string{
+			730:    {"Multi-player", "Online PvP"},
+			945360: {"Multi-player", "LAN PvP"}, // No header image anywhere
+			1:      {"Single-player"},           // Skipped
+		} {
+			if err := gameCacheRepo.Upsert(appID, "Game", categories, nil); err != nil {
+				t.Fatalf("failed to cache game: %v", err)
+			}
+		}
+
+		userRepo := repository.NewUserRepository()
+		invited := &models.User{SteamID: "76561190000000002", Username: "PixelPirate", AvatarURL: "https://avatars.example/pixel.jpg",
+			AvatarSmall: "https://avatars.example/pixel.jpg", LastCreditAt: time.Now().UTC()}
+		for _, user := range []*models.User{invited, {SteamID: "76561190000000001", Username: "Fragmaster", LastCreditAt: time.Now().UTC()}} {
+			if err := userRepo.Create(user); err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+		}
+
+		service := NewMediaPrefetchService(steamClient, userRepo, gameCacheRepo, repository.NewCustomGameRepository(),
+			imageCacheService, avatarCacheService, nil)
+		if err := service.Run(context.Background()); err == nil {
+			t.Errorf("expected an error for the missing header image")
+		}
+
+		status := service.Status()
+		if status.Running || status.Phase != MediaPrefetchPhaseComplete || status.Total != 4 || status.Processed != 4 || status.Failed != 1 {
+			t.Errorf("unexpected status: %+v", status)
+		}
+
+		// Header image and capsules are cached
+		if !imageCacheService.HasImage(730) || imageCacheService.HasImage(945360) || imageCacheService.HasImage(1) {
+			t.Errorf("expected only the header image of game 730 to be cached")
+		}
+		for _, size := range GameImageSizes {
+			if !imageCacheService.cache.has(variantFilename(imageFilename(730), size)) {
+				t.Errorf("expected the %dpx capsule to be cached", size)
+			}
+		}
+
+		// The Steam avatar URL is replaced by the cached copy
+		user, err := userRepo.GetBySteamID(invited.SteamID)
+		if err != nil {
+			t.Fatalf("failed to get user: %v", err)
+		}
+		filename, local := avatarCacheService.GetLocalFilename(user.AvatarURL)
+		if !local || !avatarCacheService.HasAvatarFile(filename) {
+			t.Fatalf("expected a cached local avatar, got %q", user.AvatarURL)
+		}
+		if user.AvatarSmall != user.AvatarURL+"?size=64" {
+			t.Errorf("unexpected small avatar URL %q", user.AvatarSmall)
+		}
+		for _, size := range AvatarSizes {
+			if !avatarCacheService.cache.has(variantFilename(filename, size)) {
+				t.Errorf("expected the %dpx avatar to be cached", size)
+			}
+		}
+
+		// Only one prefetch runs at a time
+		if _, err := service.Start(); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		if _, err := service.Start(); err != ErrPrefetchInProgress {
+			t.Errorf("expected ErrPrefetchInProgress, got %v", err)
+		}
+		service.Stop()
+	})
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// Errors returned by the poll service
+var (
+	ErrPollNotFound  = errors.New("poll not found")
+	ErrPollClosed    = errors.New("poll is closed")
+	ErrInvalidPoll   = errors.New("invalid poll")
+	ErrInvalidBallot = errors.New("invalid ballot")
+)
+
+// pollListLimit is the maximum number of polls returned by ListPolls
+const pollListLimit = 20
+
+// PollService handles game polls and closes them when their timer runs out
+type PollService struct {
+	pollRepo      *repository.PollRepository
+	userRepo      *repository.UserRepository
+	gameCacheRepo *repository.GameCacheRepository
+	gameOwnerRepo *repository.GameOwnerRepository
+	wsHub         *websocket.Hub
+	ticker        *time.Ticker
+	done          chan bool
+}
+
+// NewPollService creates a new poll service
+func NewPollService(pollRepo *repository.PollRepository, userRepo *repository.UserRepository, gameCacheRepo *repository.GameCacheRepository, gameOwnerRepo *repository.GameOwnerRepository, wsHub *websocket.Hub) *PollService {
+	return &PollService{
+		pollRepo:      pollRepo,
+		userRepo:      userRepo,
+		gameCacheRepo: gameCacheRepo,
+		gameOwnerRepo: gameOwnerRepo,
+		wsHub:         wsHub,
+		done:          make(chan bool),
+	}
+}
+
+// Start begins closing polls whose timer ran out
+func (s *PollService) Start() {
+	// Check every second, so the winner is announced right when the timer ends
+	s.ticker = time.NewTicker(1 * time.Second)
+	go s.watch()
+	log.Println("Poll service started")
+}
+
+// Stop stops the poll watcher
+func (s *PollService) Stop() {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	s.done <- true
+	log.Println("Poll service stopped")
+}
+
+// watch continuously closes polls that are due
+func (s *PollService) watch() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.ticker.C:
+			s.closeDuePolls()
+		}
+	}
+}
+
+// closeDuePolls closes all open polls whose timer ran out
+func (s *PollService) closeDuePolls() {
+	ids, err := s.pollRepo.GetDueForClosing(time.Now().UTC())
+	if err != nil {
+		log.Printf("Failed to check polls for closing: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		if _, _, err := s.ClosePoll(id); err != nil && !errors.Is(err, ErrPollClosed) {
+			log.Printf("Failed to close poll %d: %v", id, err)
+		}
+	}
+}
+
+// CreatePoll opens a new poll with candidate games from the games cache
+func (s *PollService) CreatePoll(creatorID uint64, req *models.CreatePollRequest) (*models.PollWithResults, error) {
+	method := req.VotingMethod
+	if method == "" {
+		method = models.PollVotingSingle
+	}
+	if !method.IsValid() {
+		return nil, fmt.Errorf("%w: unknown voting method %q", ErrInvalidPoll, method)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	poll := &models.Poll{
+		Title:        req.Title,
+		VotingMethod: method,
+		CreatedBy:    models.PublicUser{ID: creatorID},
+		CreatedAt:    now,
+	}
+	if req.DurationMinutes > 0 {
+		closesAt := now.Add(time.Duration(req.DurationMinutes) * time.Minute)
+		poll.ClosesAt = &closesAt
+	}
+
+	seen := make(map[int]bool, len(req.AppIDs))
+	for i, appID := range req.AppIDs {
+		if seen[appID] {
+			return nil, fmt.Errorf("%w: game %d is listed twice", ErrInvalidPoll, appID)
+		}
+		seen[appID] = true
+
+		game, err := s.gameCacheRepo.GetByAppID(appID)
+		if err != nil {
+			return nil, err
+		}
+		if game == nil {
+			return nil, fmt.Errorf("%w: game %d is not in the games cache", ErrInvalidPoll, appID)
+		}
+		poll.Options = append(poll.Options, models.PollOption{AppID: appID, Name: game.Name, Position: i})
+	}
+
+	if err := s.pollRepo.Create(poll); err != nil {
+		return nil, err
+	}
+
+	result, err := s.GetPoll(poll.ID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wsHub.BroadcastPollCreated(s.withoutBallot(result))
+	return result, nil
+}
+
+// GetPoll returns a poll with its live results and the ballot of the given user
+func (s *PollService) GetPoll(pollID, userID uint64) (*models.PollWithResults, error) {
+	poll, err := s.pollRepo.GetByID(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	return s.withResults(poll, userID)
+}
+
+// ListPolls returns open polls and the most recently closed ones
+func (s *PollService) ListPolls(userID uint64) ([]models.PollWithResults, error) {
+	polls, err := s.pollRepo.GetRecent(pollListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.PollWithResults, 0, len(polls))
+	for i := range polls {
+		result, err := s.withResults(&polls[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// Vote replaces the ballot of a user and broadcasts the new results
+// appIDs are in order of preference; single choice polls accept exactly one game.
+func (s *PollService) Vote(pollID, userID uint64, appIDs []int) (*models.PollWithResults, error) {
+	poll, err := s.pollRepo.GetByID(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	if !poll.IsOpen() || (poll.ClosesAt != nil && !time.Now().Before(*poll.ClosesAt)) {
+		return nil, ErrPollClosed
+	}
+
+	if poll.VotingMethod == models.PollVotingSingle && len(appIDs) != 1 {
+		return nil, fmt.Errorf("%w: pick exactly one game", ErrInvalidBallot)
+	}
+
+	options := make(map[int]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		options[option.AppID] = true
+	}
+	seen := make(map[int]bool, len(appIDs))
+	for _, appID := range appIDs {
+		if !options[appID] {
+			return nil, fmt.Errorf("%w: game %d is not part of this poll", ErrInvalidBallot, appID)
+		}
+		if seen[appID] {
+			return nil, fmt.Errorf("%w: game %d is listed twice", ErrInvalidBallot, appID)
+		}
+		seen[appID] = true
+	}
+
+	if err := s.pollRepo.SaveBallot(pollID, userID, appIDs); err != nil {
+		return nil, err
+	}
+
+	result, err := s.withResults(poll, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wsHub.BroadcastPollUpdated(s.withoutBallot(result))
+	return result, nil
+}
+
+// ClosePoll closes a poll, stores the winner and announces it
+// Returns ErrPollClosed if the poll was already closed.
+func (s *PollService) ClosePoll(pollID uint64) (*models.PollWithResults, *models.PollWinner, error) {
+	poll, err := s.pollRepo.GetByID(pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if poll == nil {
+		return nil, nil, ErrPollNotFound
+	}
+	if !poll.IsOpen() {
+		return nil, nil, ErrPollClosed
+	}
+
+	ballots, err := s.pollRepo.GetBallots(pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := TallyPoll(poll, ballots)
+
+	closed, err := s.pollRepo.Close(pollID, results.WinnerAppID, time.Now().UTC())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !closed {
+		// Closed concurrently (timer and manual close at the same time)
+		return nil, nil, ErrPollClosed
+	}
+
+	result, err := s.GetPoll(pollID, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var winner *models.PollWinner
+	if results.WinnerAppID != nil {
+		if winner, err = s.buildWinner(poll, *results.WinnerAppID, ballots); err != nil {
+			// The poll is closed already, so still announce it without the install list
+			log.Printf("Failed to determine who needs to install the winner of poll %d: %v", pollID, err)
+			winner = &models.PollWinner{PollID: pollID, AppID: *results.WinnerAppID, NeedsInstall: []models.PublicUser{}}
+		}
+		for _, option := range poll.Options {
+			if option.AppID == winner.AppID {
+				winner.Name = option.Name
+			}
+		}
+		log.Printf("Poll %d closed, winner: %s (%d players still need to install it)", pollID, winner.Name, len(winner.NeedsInstall))
+	} else {
+		log.Printf("Poll %d closed without ballots", pollID)
+	}
+
+	s.wsHub.BroadcastPollClosed(result, winner)
+	return result, winner, nil
+}
+
+// buildWinner lists voters and online players who don't own the winning game
+func (s *PollService) buildWinner(poll *models.Poll, appID int, ballots []models.PollBallot) (*models.PollWinner, error) {
+	owners, err := s.gameOwnerRepo.GetSteamIDsByAppID(appID)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]bool, len(owners))
+	for _, steamID := range owners {
+		owned[steamID] = true
+	}
+
+	players := make(map[uint64]bool)
+	for _, ballot := range ballots {
+		players[ballot.UserID] = true
+	}
+	for _, userID := range s.wsHub.GetConnectedUserIDs() {
+		players[userID] = true
+	}
+
+	winner := &models.PollWinner{PollID: poll.ID, AppID: appID, NeedsInstall: []models.PublicUser{}}
+	for userID := range players {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && !owned[user.SteamID] {
+			winner.NeedsInstall = append(winner.NeedsInstall, user.ToPublic())
+		}
+	}
+	sort.Slice(winner.NeedsInstall, func(i, j int) bool {
+		return winner.NeedsInstall[i].Username < winner.NeedsInstall[j].Username
+	})
+	return winner, nil
+}
+
+// withResults tallies the ballots of a poll and picks out the ballot of the given user
+func (s *PollService) withResults(poll *models.Poll, userID uint64) (*models.PollWithResults, error) {
+	ballots, err := s.pollRepo.GetBallots(poll.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.PollWithResults{
+		Poll:     *poll,
+		Results:  TallyPoll(poll, ballots),
+		MyBallot: []int{},
+	}
+	for _, ballot := range ballots {
+		if ballot.UserID == userID {
+			result.MyBallot = ballot.AppIDs
+		}
+	}
+	return result, nil
+}
+
+// withoutBallot returns a copy of the poll for broadcasts, which must not contain a personal ballot
+func (s *PollService) withoutBallot(poll *models.PollWithResults) *models.PollWithResults {
+	broadcast := *poll
+	broadcast.MyBallot = nil
+	return &broadcast
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// TallyPoll counts the ballots of a poll according to its voting method
+// Ties are broken in favour of the option listed first in the poll.
+func TallyPoll(poll *models.Poll, ballots []models.PollBallot) models.PollResults {
+	results := models.PollResults{TotalBallots: len(ballots)}
+
+	if poll.VotingMethod == models.PollVotingRanked {
+		results.Rounds, results.WinnerAppID = tallyInstantRunoff(poll.Options, ballots)
+		if len(results.Rounds) > 0 {
+			results.Results = results.Rounds[len(results.Rounds)-1].Results
+		}
+		return results
+	}
+
+	votes := make(map[int]int, len(poll.Options))
+	for _, ballot := range ballots {
+		if len(ballot.AppIDs) == 0 {
+			continue
+		}
+		if poll.VotingMethod == models.PollVotingApproval {
+			for _, appID := range ballot.AppIDs {
+				votes[appID]++
+			}
+		} else {
+			votes[ballot.AppIDs[0]]++
+		}
+	}
+
+	results.Results = countOptions(poll.Options, votes, nil)
+	if len(results.Results) > 0 && results.Results[0].Votes > 0 {
+		winner := results.Results[0].AppID
+		results.WinnerAppID = &winner
+	}
+	return results
+}
+
+// tallyInstantRunoff counts ranked ballots round by round
+// Every round, each ballot counts for its highest ranked option still in the race.
+// An option with more than half of these votes wins; otherwise the options with the
+// fewest votes are dropped. If all remaining options are tied, the first listed wins.
+func tallyInstantRunoff(options []models.PollOption, ballots []models.PollBallot) ([]models.PollRound, *int) {
+	if len(ballots) == 0 {
+		return nil, nil
+	}
+
+	eliminated := make(map[int]bool)
+	var rounds []models.PollRound
+	for round := 1; ; round++ {
+		votes := make(map[int]int, len(options))
+		continuing := 0
+		for _, ballot := range ballots {
+			for _, appID := range ballot.AppIDs {
+				if !eliminated[appID] {
+					votes[appID]++
+					continuing++
+					break
+				}
+			}
+		}
+
+		results := countOptions(options, votes, eliminated)
+		current := models.PollRound{Round: round, Results: results, Eliminated: []int{}}
+		if len(results) == 0 || continuing == 0 {
+			return append(rounds, current), nil
+		}
+
+		leader := results[0]
+		lowest := results[len(results)-1].Votes
+		if leader.Votes*2 > continuing || len(results) == 1 || leader.Votes == lowest {
+			return append(rounds, current), &leader.AppID
+		}
+
+		for _, result := range results {
+			if result.Votes == lowest {
+				eliminated[result.AppID] = true
+				current.Eliminated = append(current.Eliminated, result.AppID)
+			}
+		}
+		rounds = append(rounds, current)
+	}
+}
+
+// countOptions builds the results of all options that are not excluded
+// Sorted by votes, then by the order of the options in the poll.
+func countOptions(options []models.PollOption, votes map[int]int, excluded map[int]bool) []models.PollOptionResult {
+	sorted := make([]models.PollOption, len(options))
+	copy(sorted, options)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+
+	results := make([]models.PollOptionResult, 0, len(sorted))
+	for _, option := range sorted {
+		if excluded[option.AppID] {
+			continue
+		}
+		results = append(results, models.PollOptionResult{
+			AppID: option.AppID,
+			Name:  option.Name,
+			Votes: votes[option.AppID],
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Votes > results[j].Votes })
+	return results
+}
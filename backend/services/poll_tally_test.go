@@ -0,0 +1,118 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestTallyPoll(t *testing.T) {
+	// Counter-Strike 2, Dota 2 and Team Fortress 2, listed in that order
+	options := []models.PollOption{
+		{AppID: 730, Name: "Counter-Strike 2", Position: 0},
+		{AppID: 570, Name: "Dota 2", Position: 1},
+		{AppID: 440, Name: "Team Fortress 2", Position: 2},
+	}
+
+	tests := []struct {
+		name       string
+		method     models.PollVotingMethod
+		ballots    [][]int
+		wantWinner int // 0 = no winner
+		wantVotes  map[int]int
+		wantRounds int
+	}{
+		{
+			name:       "no ballots",
+			method:     models.PollVotingSingle,
+			wantVotes:  map[int]int{730: 0, 570: 0, 440: 0},
+			wantRounds: 0,
+		},
+		{
+			name:       "single choice counts the first game only",
+			method:     models.PollVotingSingle,
+			ballots:    [][]int{{570, 730}, {570}, {730}},
+			wantWinner: 570,
+			wantVotes:  map[int]int{730: 1, 570: 2, 440: 0},
+		},
+		{
+			name:       "single choice tie goes to the first listed game",
+			method:     models.PollVotingSingle,
+			ballots:    [][]int{{440}, {570}},
+			wantWinner: 570,
+			wantVotes:  map[int]int{730: 0, 570: 1, 440: 1},
+		},
+		{
+			name:       "approval counts every game",
+			method:     models.PollVotingApproval,
+			ballots:    [][]int{{730, 440}, {570, 440}, {440}},
+			wantWinner: 440,
+			wantVotes:  map[int]int{730: 1, 570: 1, 440: 3},
+		},
+		{
+			name:       "ranked majority in the first round",
+			method:     models.PollVotingRanked,
+			ballots:    [][]int{{730, 570}, {730}, {570, 730}},
+			wantWinner: 730,
+			wantVotes:  map[int]int{730: 2, 570: 1, 440: 0},
+			wantRounds: 1,
+		},
+		{
+			name:   "ranked transfers votes of the eliminated game",
+			method: models.PollVotingRanked,
+			ballots: [][]int{
+				{730}, {730},
+				{570}, {570},
+				{440, 570},
+			},
+			wantWinner: 570,
+			wantVotes:  map[int]int{730: 2, 570: 3},
+			wantRounds: 2,
+		},
+		{
+			name:       "ranked exhausted ballots are ignored",
+			method:     models.PollVotingRanked,
+			ballots:    [][]int{{730}, {730}, {570}, {570}, {440}},
+			wantWinner: 730,
+			wantVotes:  map[int]int{730: 2, 570: 2},
+			wantRounds: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := &models.Poll{VotingMethod: tt.method, Options: options}
+			ballots := make([]models.PollBallot, 0, len(tt.ballots))
+			for i, appIDs := range tt.ballots {
+				ballots = append(ballots, models.PollBallot{UserID: uint64(i + 1), AppIDs: appIDs})
+			}
+
+			results := TallyPoll(poll, ballots)
+
+			var winner int
+			if results.WinnerAppID != nil {
+				winner = *results.WinnerAppID
+			}
+			if winner != tt.wantWinner {
+				t.Errorf("winner = %d, want %d", winner, tt.wantWinner)
+			}
+			if results.TotalBallots != len(tt.ballots) {
+				t.Errorf("total ballots = %d, want %d", results.TotalBallots, len(tt.ballots))
+			}
+			if len(results.Rounds) != tt.wantRounds {
+				t.Errorf("got %d rounds, want %d", len(results.Rounds), tt.wantRounds)
+			}
+
+			gotVotes := make(map[int]int)
+			for _, result := range results.Results {
+				gotVotes[result.AppID] = result.Votes
+			}
+			if tt.method != models.PollVotingRanked || len(tt.ballots) > 0 {
+				if !reflect.DeepEqual(gotVotes, tt.wantVotes) {
+					t.Errorf("votes = %v, want %v", gotVotes, tt.wantVotes)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// Errors returned by the rating service
+var (
+	ErrInvalidMatch   = errors.New("invalid match")
+	ErrNotMatchPlayer = errors.New("only players of the match can record it")
+)
+
+// Limits for the match history
+const (
+	DefaultMatchListLimit = 20
+	maxMatchListLimit     = 100
+)
+
+// RatingService records match outcomes and keeps a per-game Elo rating for every player
+type RatingService struct {
+	ratingRepo    *repository.RatingRepository
+	userRepo      *repository.UserRepository
+	gameCacheRepo *repository.GameCacheRepository
+	wsHub         *websocket.Hub
+	// mu serializes recording, so two matches of the same players are rated one after the other
+	mu sync.Mutex
+}
+
+// NewRatingService creates a new rating service
+func NewRatingService(ratingRepo *repository.RatingRepository, userRepo *repository.UserRepository, gameCacheRepo *repository.GameCacheRepository, wsHub *websocket.Hub) *RatingService {
+	return &RatingService{
+		ratingRepo:    ratingRepo,
+		userRepo:      userRepo,
+		gameCacheRepo: gameCacheRepo,
+		wsHub:         wsHub,
+	}
+}
+
+// RecordMatch stores a match outcome, updates the ratings of all players and broadcasts the changes
+// The recorder has to be one of the players unless they are an admin.
+func (s *RatingService) RecordMatch(recorderID uint64, isAdmin bool, req *models.RecordMatchRequest) (*models.GameMatch, error) {
+	game, err := s.gameCacheRepo.GetByAppID(req.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, fmt.Errorf("%w: game %d", ErrUnknownGame, req.AppID)
+	}
+
+	winner := -1
+	if req.WinnerTeam != nil {
+		if *req.WinnerTeam < 1 || *req.WinnerTeam > len(req.Teams) {
+			return nil, fmt.Errorf("%w: winner_team must be between 1 and %d", ErrInvalidMatch, len(req.Teams))
+		}
+		winner = *req.WinnerTeam - 1
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	playedAt := now
+	if req.PlayedAt != nil {
+		if req.PlayedAt.After(now.Add(time.Minute)) {
+			return nil, fmt.Errorf("%w: played_at is in the future", ErrInvalidMatch)
+		}
+		playedAt = req.PlayedAt.UTC().Truncate(time.Second)
+	}
+
+	match := &models.GameMatch{
+		AppID:      req.AppID,
+		GameName:   game.Name,
+		WinnerTeam: req.WinnerTeam,
+		RecordedBy: recorderID,
+		PlayedAt:   playedAt,
+		CreatedAt:  now,
+	}
+
+	seen := make(map[uint64]bool)
+	var userIDs []uint64
+	for t, team := range req.Teams {
+		if len(team) == 0 {
+			return nil, fmt.Errorf("%w: team %d has no players", ErrInvalidMatch, t+1)
+		}
+		for _, userID := range team {
+			if seen[userID] {
+				return nil, fmt.Errorf("%w: user %d is listed twice", ErrInvalidMatch, userID)
+			}
+			seen[userID] = true
+
+			user, err := s.userRepo.GetByID(userID)
+			if err != nil {
+				return nil, err
+			}
+			if user == nil {
+				return nil, fmt.Errorf("%w: unknown user ID %d", ErrInvalidMatch, userID)
+			}
+			match.Players = append(match.Players, models.MatchPlayer{User: user.ToPublic(), Team: t + 1})
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if !isAdmin && !seen[recorderID] {
+		return nil, ErrNotMatchPlayer
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.ratingRepo.GetRatings(req.AppID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	teamRatings := make([][]float64, len(req.Teams))
+	for i := range match.Players {
+		p := &match.Players[i]
+		p.RatingBefore = InitialRating
+		if rating, ok := current[p.User.ID]; ok {
+			p.RatingBefore = rating.Rating
+		}
+		teamRatings[p.Team-1] = append(teamRatings[p.Team-1], p.RatingBefore)
+	}
+	averages := make([]float64, len(teamRatings))
+	for i, ratings := range teamRatings {
+		averages[i] = averageRating(ratings)
+	}
+	changes := EloChanges(averages, winner)
+
+	ratings := make([]models.PlayerRating, 0, len(match.Players))
+	for i := range match.Players {
+		p := &match.Players[i]
+		p.RatingAfter = roundRating(p.RatingBefore + changes[p.Team-1])
+
+		rating := current[p.User.ID]
+		rating.User = p.User
+		rating.AppID = req.AppID
+		rating.Rating = p.RatingAfter
+		rating.Matches++
+		rating.UpdatedAt = now
+		switch {
+		case winner < 0:
+			rating.Draws++
+		case winner == p.Team-1:
+			rating.Wins++
+		default:
+			rating.Losses++
+		}
+		ratings = append(ratings, rating)
+	}
+
+	if err := s.ratingRepo.RecordMatch(match, ratings); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Recorded match %d of %s with %d players", match.ID, game.Name, len(match.Players))
+	s.wsHub.BroadcastRatingsUpdated(match)
+	return match, nil
+}
+
+// GetLadder returns the ranked ratings of a game
+func (s *RatingService) GetLadder(appID int) ([]models.PlayerRating, error) {
+	ladder, err := s.ratingRepo.GetLadder(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Players with the same rating share the same rank
+	for i := range ladder {
+		ladder[i].Rank = i + 1
+		if i > 0 && ladder[i].Rating == ladder[i-1].Rating {
+			ladder[i].Rank = ladder[i-1].Rank
+		}
+	}
+	return ladder, nil
+}
+
+// GetPlayerRatings returns the ratings of a player in all games
+func (s *RatingService) GetPlayerRatings(userID uint64) ([]models.PlayerRating, error) {
+	return s.ratingRepo.GetByUserID(userID)
+}
+
+// GetHistory returns the rating of a player after each match of a game
+func (s *RatingService) GetHistory(userID uint64, appID int) ([]models.RatingHistoryEntry, error) {
+	return s.ratingRepo.GetHistory(userID, appID)
+}
+
+// GetMatches returns the most recent matches, optionally of one game (appID 0 = all)
+func (s *RatingService) GetMatches(appID int, limit int) ([]models.GameMatch, error) {
+	if limit <= 0 {
+		limit = DefaultMatchListLimit
+	}
+	limit = min(limit, maxMatchListLimit)
+
+	matches, err := s.ratingRepo.GetMatches(appID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string)
+	for i := range matches {
+		name, ok := names[matches[i].AppID]
+		if !ok {
+			if game, err := s.gameCacheRepo.GetByAppID(matches[i].AppID); err == nil && game != nil {
+				name = game.Name
+			}
+			names[matches[i].AppID] = name
+		}
+		matches[i].GameName = name
+	}
+	return matches, nil
+}
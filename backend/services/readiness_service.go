@@ -0,0 +1,339 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// Errors returned by the readiness service
+var (
+	ErrSessionNotFound = errors.New("game session not found")
+	ErrInvalidSession  = errors.New("invalid game session")
+	ErrUnknownGame     = errors.New("game is not in the games cache")
+	ErrInvalidStatus   = errors.New("invalid install status")
+)
+
+// sessionListLimit is the maximum number of sessions returned by ListSessions
+const sessionListLimit = 20
+
+// installStatusOrder sorts rosters from ready to not installed
+var installStatusOrder = map[models.InstallStatus]int{
+	models.InstallStatusReady:       0,
+	models.InstallStatusInstalled:   1,
+	models.InstallStatusDownloading: 2,
+	models.InstallStatusNone:        3,
+}
+
+// ReadinessService tracks which players have a game installed and ready,
+// and announces when everyone in a planned session is ready
+type ReadinessService struct {
+	installRepo   *repository.InstallStatusRepository
+	sessionRepo   *repository.GameSessionRepository
+	userRepo      *repository.UserRepository
+	gameCacheRepo *repository.GameCacheRepository
+	gameOwnerRepo *repository.GameOwnerRepository
+	wsHub         *websocket.Hub
+}
+
+// NewReadinessService creates a new readiness service
+func NewReadinessService(installRepo *repository.InstallStatusRepository, sessionRepo *repository.GameSessionRepository, userRepo *repository.UserRepository, gameCacheRepo *repository.GameCacheRepository, gameOwnerRepo *repository.GameOwnerRepository, wsHub *websocket.Hub) *ReadinessService {
+	return &ReadinessService{
+		installRepo:   installRepo,
+		sessionRepo:   sessionRepo,
+		userRepo:      userRepo,
+		gameCacheRepo: gameCacheRepo,
+		gameOwnerRepo: gameOwnerRepo,
+		wsHub:         wsHub,
+	}
+}
+
+// SetStatus updates the install status of a game for a player
+// Broadcasts the change and checks all sessions of the game the player is part of.
+func (s *ReadinessService) SetStatus(user *models.User, appID int, status models.InstallStatus, progress int) error {
+	if !status.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+	if status != models.InstallStatusDownloading {
+		progress = 0
+	}
+
+	if status == models.InstallStatusNone {
+		if err := s.installRepo.Delete(user.ID, appID); err != nil {
+			return err
+		}
+	} else if err := s.installRepo.Set(user.ID, appID, status, progress); err != nil {
+		return err
+	}
+
+	s.wsHub.BroadcastInstallStatus(&websocket.InstallStatusPayload{
+		UserID:   user.ID,
+		Username: user.Username,
+		AppID:    appID,
+		Status:   string(status),
+		Progress: progress,
+	})
+
+	sessionIDs, err := s.sessionRepo.GetIDsByPlayer(appID, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, sessionID := range sessionIDs {
+		if _, err := s.checkSession(sessionID); err != nil {
+			log.Printf("Failed to check readiness of game session %d: %v", sessionID, err)
+		}
+	}
+	return nil
+}
+
+// GetStatuses returns all install statuses a player reported
+func (s *ReadinessService) GetStatuses(userID uint64) ([]models.GameInstallStatus, error) {
+	return s.installRepo.GetByUserID(userID)
+}
+
+// GetRoster returns everyone who owns a game or reported an install status for it
+func (s *ReadinessService) GetRoster(appID int) (*models.GameRoster, error) {
+	game, err := s.gameCacheRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, ErrUnknownGame
+	}
+
+	users, err := s.userRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.buildEntries(appID, users, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GameRoster{
+		AppID:   appID,
+		Name:    game.Name,
+		Players: entries,
+		Summary: summarize(entries),
+	}, nil
+}
+
+// CreateSession plans a session of a game for a group of players
+// Without user IDs, all currently connected players take part. The creator always does.
+func (s *ReadinessService) CreateSession(creator *models.User, req *models.CreateGameSessionRequest) (*models.GameSession, error) {
+	game, err := s.gameCacheRepo.GetByAppID(req.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, ErrUnknownGame
+	}
+
+	userIDs := req.UserIDs
+	if len(userIDs) == 0 {
+		userIDs = s.wsHub.GetConnectedUserIDs()
+	}
+	players := map[uint64]bool{creator.ID: true}
+	for _, userID := range userIDs {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, fmt.Errorf("%w: unknown user ID %d", ErrInvalidSession, userID)
+		}
+		players[userID] = true
+	}
+
+	playerIDs := make([]uint64, 0, len(players))
+	for userID := range players {
+		playerIDs = append(playerIDs, userID)
+	}
+	sort.Slice(playerIDs, func(i, j int) bool { return playerIDs[i] < playerIDs[j] })
+
+	session := &models.GameSession{
+		AppID:     req.AppID,
+		Title:     req.Title,
+		CreatedBy: creator.ToPublic(),
+		StartsAt:  req.StartsAt,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := s.sessionRepo.Create(session, playerIDs); err != nil {
+		return nil, err
+	}
+
+	result, err := s.checkSession(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.wsHub.BroadcastSessionCreated(result)
+	return result, nil
+}
+
+// ListSessions returns the most recently planned sessions with their readiness
+func (s *ReadinessService) ListSessions() ([]models.GameSession, error) {
+	sessions, err := s.sessionRepo.GetRecent(sessionListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.GameSession, 0, len(sessions))
+	for _, session := range sessions {
+		result, err := s.GetSession(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// GetSession returns a session with the readiness of its players
+func (s *ReadinessService) GetSession(sessionID uint64) (*models.GameSession, error) {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if game, err := s.gameCacheRepo.GetByAppID(session.AppID); err != nil {
+		return nil, err
+	} else if game != nil {
+		session.Name = game.Name
+	}
+
+	playerIDs, err := s.sessionRepo.GetPlayerIDs(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]models.User, 0, len(playerIDs))
+	for _, userID := range playerIDs {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			users = append(users, *user)
+		}
+	}
+
+	if session.Players, err = s.buildEntries(session.AppID, users, true); err != nil {
+		return nil, err
+	}
+	session.Summary = summarize(session.Players)
+	return session, nil
+}
+
+// checkSession returns a session like GetSession and records whether all its players are ready
+// Sends session_ready the first time all players are ready. Only called after changes to the
+// session or an install status, so reading sessions has no side effects.
+func (s *ReadinessService) checkSession(sessionID uint64) (*models.GameSession, error) {
+	session, err := s.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	allReady := len(session.Players) > 0 && session.Summary.Ready == len(session.Players)
+	switch {
+	case allReady && session.ReadyAt == nil:
+		readyAt := time.Now().UTC().Truncate(time.Second)
+		changed, err := s.sessionRepo.SetReadyAt(sessionID, &readyAt)
+		if err != nil {
+			return nil, err
+		}
+		session.ReadyAt = &readyAt
+		if changed {
+			log.Printf("Game session %d (%s): all %d players are ready", sessionID, session.Name, len(session.Players))
+			s.wsHub.BroadcastSessionReady(session)
+		}
+	case !allReady && session.ReadyAt != nil:
+		// Someone dropped out (e.g. an update started), so the next ready state is announced again
+		if _, err := s.sessionRepo.SetReadyAt(sessionID, nil); err != nil {
+			return nil, err
+		}
+		session.ReadyAt = nil
+	}
+
+	return session, nil
+}
+
+// DeleteSession deletes a planned session
+func (s *ReadinessService) DeleteSession(sessionID uint64) error {
+	return s.sessionRepo.Delete(sessionID)
+}
+
+// buildEntries combines ownership, install status and online state of players for a game
+// Unless includeAll is set, players who neither own the game nor reported a status are skipped.
+func (s *ReadinessService) buildEntries(appID int, users []models.User, includeAll bool) ([]models.ReadinessEntry, error) {
+	owners, err := s.gameOwnerRepo.GetSteamIDsByAppID(appID)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]bool, len(owners))
+	for _, steamID := range owners {
+		owned[steamID] = true
+	}
+
+	statuses, err := s.installRepo.GetByAppID(appID)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint64]models.GameInstallStatus, len(statuses))
+	for _, status := range statuses {
+		byUser[status.UserID] = status
+	}
+
+	entries := []models.ReadinessEntry{}
+	for i := range users {
+		user := &users[i]
+		entry := models.ReadinessEntry{
+			User:   user.ToPublic(),
+			Owns:   owned[user.SteamID],
+			Online: s.wsHub.IsUserConnected(user.ID),
+			Status: models.InstallStatusNone,
+		}
+		if status, ok := byUser[user.ID]; ok {
+			updatedAt := status.UpdatedAt
+			entry.Status = status.Status
+			entry.Progress = status.Progress
+			entry.UpdatedAt = &updatedAt
+		} else if !includeAll && !entry.Owns {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if a, b := installStatusOrder[entries[i].Status], installStatusOrder[entries[j].Status]; a != b {
+			return a < b
+		}
+		return entries[i].User.Username < entries[j].User.Username
+	})
+	return entries, nil
+}
+
+// summarize counts the players of a roster per install status
+func summarize(entries []models.ReadinessEntry) models.ReadinessSummary {
+	var summary models.ReadinessSummary
+	for _, entry := range entries {
+		switch entry.Status {
+		case models.InstallStatusReady:
+			summary.Ready++
+		case models.InstallStatusInstalled:
+			summary.Installed++
+		case models.InstallStatusDownloading:
+			summary.Downloading++
+		default:
+			summary.NotInstalled++
+		}
+	}
+	return summary
+}
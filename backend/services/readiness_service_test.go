@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+func TestSessionReadyOnlyOnWrites(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		hub := websocket.NewHub()
+		go hub.Run()
+
+		installRepo := repository.NewInstallStatusRepository()
+		sessionRepo := repository.NewGameSessionRepository()
+		gameCacheRepo := repository.NewGameCacheRepository()
+		service := NewReadinessService(installRepo, sessionRepo, repository.NewUserRepository(), gameCacheRepo,
+			repository.NewGameOwnerRepository(), hub)
+		users := createChatUsers(t, "alice", "bob")
+		alice, bob := users["alice"], users["bob"]
+
+		if err := gameCacheRepo.InsertIfNotExists(730, "Counter-Strike 2"); err != nil {
+			t.Fatalf("failed to cache game: %v", err)
+		}
+		if err := service.SetStatus(alice, 730, models.InstallStatusReady, 0); err != nil {
+			t.Fatalf("SetStatus failed: %v", err)
+		}
+		session, err := service.CreateSession(alice, &models.CreateGameSessionRequest{AppID: 730, UserIDs: []uint64{bob.ID}})
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		if session.ReadyAt != nil || session.Summary.Ready != 1 {
+			t.Fatalf("expected a session with one ready player, got %+v", session)
+		}
+
+		// Bob's status changes behind the service's back, reading the session must not record it
+		if err := installRepo.Set(bob.ID, 730, models.InstallStatusReady, 0); err != nil {
+			t.Fatalf("failed to set install status: %v", err)
+		}
+		if loaded, err := service.GetSession(session.ID); err != nil || loaded.Summary.Ready != 2 || loaded.ReadyAt != nil {
+			t.Fatalf("expected both players to be ready without a ready time, got %+v, %v", loaded, err)
+		}
+		if _, err := service.ListSessions(); err != nil {
+			t.Fatalf("ListSessions failed: %v", err)
+		}
+		if stored, err := sessionRepo.GetByID(session.ID); err != nil || stored.ReadyAt != nil {
+			t.Fatalf("expected reads to leave the ready time alone, got %+v, %v", stored, err)
+		}
+
+		// Bob reporting through the service completes the session
+		if err := service.SetStatus(bob, 730, models.InstallStatusReady, 0); err != nil {
+			t.Fatalf("SetStatus failed: %v", err)
+		}
+		if stored, err := sessionRepo.GetByID(session.ID); err != nil || stored.ReadyAt == nil {
+			t.Errorf("expected the session to be ready, got %+v, %v", stored, err)
+		}
+	})
+}
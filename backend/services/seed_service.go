@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+)
+
+// seedTargetPoints is the net number of vote points the seeder distributes
+const seedTargetPoints = 300
+
+// fakePlayer describes a fake player created by the seeder
+type fakePlayer struct {
+	SteamID   string
+	Username  string
+	AvatarURL string
+}
+
+// fakePlayers are the players created by the seeder
+// The Steam IDs are prefixed with FAKE_ so no Steam API requests are made for them
+var fakePlayers = []fakePlayer{
+	{"FAKE_76561198000000001", "xXDarkLord420Xx", "https://api.dicebear.com/7.x/pixel-art/svg?seed=darklord"},
+	{"FAKE_76561198000000002", "NoobSlayer9000", "https://api.dicebear.com/7.x/pixel-art/svg?seed=noobslayer"},
+	{"FAKE_76561198000000003", "FragMaster2000", "https://api.dicebear.com/7.x/pixel-art/svg?seed=fragmaster"},
+	{"FAKE_76561198000000004", "CampingKing", "https://api.dicebear.com/7.x/pixel-art/svg?seed=campking"},
+	{"FAKE_76561198000000005", "ToxicAvenger", "https://api.dicebear.com/7.x/pixel-art/svg?seed=toxic"},
+	{"FAKE_76561198000000006", "SupportMain", "https://api.dicebear.com/7.x/pixel-art/svg?seed=support"},
+	{"FAKE_76561198000000007", "HeadshotHero", "https://api.dicebear.com/7.x/pixel-art/svg?seed=headshot"},
+	{"FAKE_76561198000000008", "AFKAndy", "https://api.dicebear.com/7.x/pixel-art/svg?seed=afkandy"},
+	{"FAKE_76561198000000009", "ClutchQueen", "https://api.dicebear.com/7.x/pixel-art/svg?seed=clutch"},
+	{"FAKE_76561198000000010", "RageQuitRudi", "https://api.dicebear.com/7.x/pixel-art/svg?seed=ragequit"},
+	{"FAKE_76561198000000011", "TeamKillerTom", "https://api.dicebear.com/7.x/pixel-art/svg?seed=teamkiller"},
+	{"FAKE_76561198000000012", "ProGamerPete", "https://api.dicebear.com/7.x/pixel-art/svg?seed=progamer"},
+	{"FAKE_76561198000000013", "CasualCarl", "https://api.dicebear.com/7.x/pixel-art/svg?seed=casual"},
+	{"FAKE_76561198000000014", "StrategieStefan", "https://api.dicebear.com/7.x/pixel-art/svg?seed=strategie"},
+	{"FAKE_76561198000000015", "FriendlyFranz", "https://api.dicebear.com/7.x/pixel-art/svg?seed=friendly"},
+}
+
+// Indexes into fakePlayers that get a scripted vote distribution
+const (
+	seedToxicIdx      = 4  // ToxicAvenger: many negative votes
+	seedSupportIdx    = 5  // SupportMain: many positive votes
+	seedClutchIdx     = 8  // ClutchQueen: many positive votes
+	seedTeamKillerIdx = 10 // TeamKillerTom: some negative votes
+	seedProGamerIdx   = 11 // ProGamerPete: the leader
+)
+
+// SeedService fills the database with fake players and votes for demos and local development
+type SeedService struct {
+	userRepo   *repository.UserRepository
+	exportRepo *repository.ExportRepository
+	rng        *rand.Rand
+}
+
+// NewSeedService creates a new seed service
+func NewSeedService(userRepo *repository.UserRepository, exportRepo *repository.ExportRepository) *SeedService {
+	return &SeedService{
+		userRepo:   userRepo,
+		exportRepo: exportRepo,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SeedFakeData replaces all existing fake players with 15 new ones and distributes
+// 300 net vote points between them. One player ends up deep in the negative range.
+func (s *SeedService) SeedFakeData() (*models.ImportResult, error) {
+	deleted, err := s.userRepo.DeleteFakeUsers()
+	if err != nil {
+		return nil, err
+	}
+	if deleted > 0 {
+		log.Printf("Seed: Deleted %d existing fake players", deleted)
+	}
+
+	now := time.Now().UTC()
+	archive := &models.ExportArchive{
+		FormatVersion: models.ExportFormatVersion,
+		ExportedAt:    now,
+		SourceDB:      "seed",
+	}
+
+	// Archive user IDs are only used to link votes, real IDs are assigned on restore
+	for i, player := range fakePlayers {
+		archive.Users = append(archive.Users, models.User{
+			ID:           uint64(i + 1),
+			SteamID:      player.SteamID,
+			Username:     player.Username,
+			AvatarURL:    player.AvatarURL,
+			AvatarSmall:  player.AvatarURL,
+			ProfileURL:   "https://steamcommunity.com/id/" + player.Username,
+			Credits:      5,
+			LastCreditAt: now,
+			CreatedAt:    now.Add(-time.Duration(s.rng.Intn(24)) * time.Hour),
+			UpdatedAt:    now,
+		})
+	}
+
+	archive.Votes = s.generateVotes(now)
+
+	result, err := s.exportRepo.Restore(archive, models.ImportModeMerge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store fake data: %w", err)
+	}
+
+	log.Printf("Seed: Created %d fake players with %d votes", result.UsersCreated, result.VotesImported)
+	return result, nil
+}
+
+// generateVotes builds the scripted vote distribution
+// Points are always 1 - whether a vote counts negative depends on the achievement
+func (s *SeedService) generateVotes(now time.Time) []models.Vote {
+	var votes []models.Vote
+	netPoints := 0
+	positiveAchievements := seedAchievementIDs(true)
+	negativeAchievements := seedAchievementIDs(false)
+
+	addVote := func(fromIdx, toIdx int, positive bool, maxHoursAgo int) {
+		var achievementID string
+		if positive {
+			achievementID = positiveAchievements[s.rng.Intn(len(positiveAchievements))]
+			netPoints++
+		} else {
+			achievementID = negativeAchievements[s.rng.Intn(len(negativeAchievements))]
+			netPoints--
+		}
+
+		ago := time.Duration(s.rng.Intn(maxHoursAgo))*time.Hour + time.Duration(s.rng.Intn(3600))*time.Second
+		votes = append(votes, models.Vote{
+			ID:            uint64(len(votes) + 1),
+			FromUserID:    uint64(fromIdx + 1),
+			ToUserID:      uint64(toIdx + 1),
+			AchievementID: achievementID,
+			Points:        1,
+			IsSecret:      s.rng.Intn(100) < 30, // 30% of the votes are secret
+			CreatedAt:     now.Add(-ago),
+		})
+	}
+
+	// randomVoter picks a random player that is not the target (no self-votes)
+	randomVoter := func(targetIdx int) int {
+		for {
+			idx := s.rng.Intn(len(fakePlayers))
+			if idx != targetIdx {
+				return idx
+			}
+		}
+	}
+
+	scripted := []struct {
+		targetIdx int
+		count     int
+		positive  bool
+	}{
+		{seedToxicIdx, 25, false},
+		{seedTeamKillerIdx, 15, false},
+		{seedProGamerIdx, 30, true},
+		{seedClutchIdx, 20, true},
+		{seedSupportIdx, 20, true},
+	}
+	for _, target := range scripted {
+		for i := 0; i < target.count; i++ {
+			addVote(randomVoter(target.targetIdx), target.targetIdx, target.positive, 48)
+		}
+	}
+
+	// Distribute the rest randomly (80% positive) until the target is reached
+	for netPoints < seedTargetPoints {
+		toIdx := s.rng.Intn(len(fakePlayers))
+		addVote(randomVoter(toIdx), toIdx, s.rng.Intn(100) < 80, 72)
+	}
+
+	return votes
+}
+
+// seedAchievementIDs returns the sorted IDs of all positive or negative achievements
+func seedAchievementIDs(positive bool) []string {
+	var ids []string
+	for id, achievement := range models.Achievements {
+		if achievement.IsPositive == positive {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
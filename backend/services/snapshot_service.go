@@ -0,0 +1,229 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+)
+
+// SnapshotFilePrefix is the file name prefix of scheduled and manual snapshots
+const SnapshotFilePrefix = "snapshot-"
+
+// Snapshot kinds
+const (
+	SnapshotKindScheduled    = "snapshot"
+	SnapshotKindPreMigration = "pre-migration"
+)
+
+// SnapshotInfo describes a backup file in the backup directory
+type SnapshotInfo struct {
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SnapshotService writes periodic SQLite snapshots and prunes old ones
+type SnapshotService struct {
+	dir       string
+	interval  time.Duration
+	retention int
+	ticker    *time.Ticker
+	done      chan bool
+	mu        sync.Mutex // Serializes snapshots, so two of them never pick the same file name
+}
+
+// NewSnapshotService creates a new snapshot service
+func NewSnapshotService(cfg *config.Config) *SnapshotService {
+	return &SnapshotService{
+		dir:       cfg.BackupDir,
+		interval:  cfg.SnapshotInterval,
+		retention: cfg.SnapshotRetention,
+		done:      make(chan bool),
+	}
+}
+
+// Enabled returns true if snapshots are supported by the configured database
+func (s *SnapshotService) Enabled() bool {
+	return database.IsSQLite()
+}
+
+// Start begins taking snapshots at the configured interval
+func (s *SnapshotService) Start() {
+	if s.interval <= 0 || !s.Enabled() {
+		return
+	}
+
+	s.ticker = time.NewTicker(s.interval)
+	go s.run()
+	log.Printf("Snapshot service started (interval: %v, retention: %d)", s.interval, s.retention)
+}
+
+// Stop stops the snapshot scheduler
+func (s *SnapshotService) Stop() {
+	if s.ticker == nil {
+		return
+	}
+	s.ticker.Stop()
+	s.done <- true
+	log.Println("Snapshot service stopped")
+}
+
+// run takes a snapshot on every tick
+func (s *SnapshotService) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.ticker.C:
+			if _, err := s.CreateSnapshot(); err != nil {
+				log.Printf("Scheduled snapshot failed: %v", err)
+			}
+		}
+	}
+}
+
+// CreateSnapshot writes a new snapshot and removes snapshots beyond the retention count
+func (s *SnapshotService) CreateSnapshot() (*SnapshotInfo, error) {
+	if !s.Enabled() {
+		return nil, fmt.Errorf("snapshots are only supported for SQLite databases")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := s.nextName(time.Now())
+	path := filepath.Join(s.dir, name)
+	if err := database.BackupSQLite(path); err != nil {
+		return nil, err
+	}
+
+	if err := s.prune(); err != nil {
+		log.Printf("Failed to prune old snapshots: %v", err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat snapshot: %w", err)
+	}
+	return &SnapshotInfo{
+		Name:      name,
+		Kind:      SnapshotKindScheduled,
+		Size:      stat.Size(),
+		CreatedAt: stat.ModTime(),
+	}, nil
+}
+
+// nextName returns a snapshot file name for the given time that is not taken yet
+// Snapshots within the same second (manual and scheduled) get a counter suffix.
+func (s *SnapshotService) nextName(now time.Time) string {
+	base := SnapshotFilePrefix + now.Format(database.BackupFileTimeFormat)
+	name := base + ".db"
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(s.dir, name)); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d.db", base, i)
+	}
+}
+
+// prune deletes the oldest snapshots beyond the retention count
+// Pre-migration backups are never deleted automatically.
+func (s *SnapshotService) prune() error {
+	if s.retention <= 0 {
+		return nil
+	}
+
+	snapshots, err := s.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, snapshot := range snapshots {
+		if snapshot.Kind != SnapshotKindScheduled {
+			continue
+		}
+		kept++
+		if kept <= s.retention {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, snapshot.Name)); err != nil {
+			return fmt.Errorf("failed to delete snapshot %s: %w", snapshot.Name, err)
+		}
+		log.Printf("Deleted old snapshot %s", snapshot.Name)
+	}
+	return nil
+}
+
+// ListSnapshots returns all snapshots and pre-migration backups, newest first
+func (s *SnapshotService) ListSnapshots() ([]SnapshotInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []SnapshotInfo{}, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	snapshots := []SnapshotInfo{}
+	for _, entry := range entries {
+		kind := snapshotKind(entry.Name())
+		if entry.IsDir() || kind == "" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, SnapshotInfo{
+			Name:      entry.Name(),
+			Kind:      kind,
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+
+	// File names carry the timestamp, but pre-migration backups have a version prefix
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// SnapshotPath returns the path of a snapshot by name
+// Only plain file names of existing backups are accepted to prevent path traversal.
+func (s *SnapshotService) SnapshotPath(name string) (string, bool) {
+	if name != filepath.Base(name) || snapshotKind(name) == "" {
+		return "", false
+	}
+
+	path := filepath.Join(s.dir, name)
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// snapshotKind returns the kind of a backup file, or "" if it is not one
+func snapshotKind(name string) string {
+	if !strings.HasSuffix(name, ".db") {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(name, SnapshotFilePrefix):
+		return SnapshotKindScheduled
+	case strings.HasPrefix(name, database.PreMigrationBackupPrefix):
+		return SnapshotKindPreMigration
+	default:
+		return ""
+	}
+}
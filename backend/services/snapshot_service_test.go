@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+	"github.com/guided-traffic/rate-your-mate/backend/database"
+	"github.com/guided-traffic/rate-your-mate/backend/database/dbtest"
+)
+
+func TestCreateSnapshotTwiceInOneSecond(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) {
+		if !database.IsSQLite() {
+			t.Skip("snapshots are only supported for SQLite")
+		}
+		service := NewSnapshotService(&config.Config{BackupDir: t.TempDir()})
+
+		first, err := service.CreateSnapshot()
+		if err != nil {
+			t.Fatalf("first snapshot failed: %v", err)
+		}
+		second, err := service.CreateSnapshot()
+		if err != nil {
+			t.Fatalf("second snapshot failed: %v", err)
+		}
+		if first.Name == second.Name {
+			t.Errorf("expected different file names, got %s twice", first.Name)
+		}
+
+		snapshots, err := service.ListSnapshots()
+		if err != nil || len(snapshots) != 2 {
+			t.Errorf("expected two snapshots, got %+v, %v", snapshots, err)
+		}
+	})
+}
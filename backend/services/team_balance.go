@@ -0,0 +1,374 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// Weights of the skill components when a target game is given
+const (
+	playtimeSkillWeight = 0.6
+	rankingSkillWeight  = 0.4
+)
+
+// teamSplitAttempts is the number of randomized splits tried before picking the best ones
+const teamSplitAttempts = 200
+
+// RateTeamPlayers sets the skill of each player relative to the other participants
+// Playtime counts on a logarithmic scale, so 2000 hours are not worth ten times 200 hours.
+// Without playtime (no target game) the skill is based on the ranking score only.
+func RateTeamPlayers(players []models.TeamPlayer, withPlaytime bool) {
+	if len(players) == 0 {
+		return
+	}
+
+	minScore, maxScore := players[0].RankingScore, players[0].RankingScore
+	maxPlaytime := 0.0
+	for _, p := range players {
+		minScore = min(minScore, p.RankingScore)
+		maxScore = max(maxScore, p.RankingScore)
+		maxPlaytime = math.Max(maxPlaytime, math.Log1p(float64(p.PlaytimeMinutes)/60))
+	}
+
+	for i := range players {
+		ranking := 0.5
+		if maxScore > minScore {
+			ranking = float64(players[i].RankingScore-minScore) / float64(maxScore-minScore)
+		}
+		if !withPlaytime {
+			players[i].Skill = roundSkill(100 * ranking)
+			continue
+		}
+
+		playtime := 0.0
+		if maxPlaytime > 0 {
+			playtime = math.Log1p(float64(players[i].PlaytimeMinutes)/60) / maxPlaytime
+		}
+		players[i].Skill = roundSkill(100 * (playtimeSkillWeight*playtime + rankingSkillWeight*ranking))
+	}
+}
+
+// teamUnit is a group of players that must end up in the same team
+type teamUnit struct {
+	players []int // Indices into the player list
+	skill   float64
+}
+
+// teamBalancer splits units into teams of even size while respecting keep apart constraints
+type teamBalancer struct {
+	players   []models.TeamPlayer
+	units     []teamUnit
+	conflicts map[[2]int]bool // Pairs of unit indices that must not share a team
+	teamCount int
+	maxSize   int
+	fullTeams int // Number of teams that may reach maxSize, the others stay one smaller
+}
+
+// GenerateTeamSplits returns up to candidates distinct splits of the players into teamCount teams, best first
+// together and apart contain groups of user IDs; every user ID must belong to one of the players.
+func GenerateTeamSplits(players []models.TeamPlayer, teamCount int, together, apart [][]uint64, candidates int, rng *rand.Rand) ([]models.TeamSplit, error) {
+	if teamCount < 2 || teamCount > len(players) {
+		return nil, fmt.Errorf("%w: cannot split %d players into %d teams", ErrInvalidTeams, len(players), teamCount)
+	}
+
+	b, err := newTeamBalancer(players, teamCount, together, apart)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var splits []models.TeamSplit
+	for attempt := 0; attempt < teamSplitAttempts; attempt++ {
+		teams, ok := b.assign(rng)
+		if !ok {
+			continue
+		}
+		b.improve(teams)
+
+		split := b.buildSplit(teams)
+		key := splitKey(split)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		splits = append(splits, split)
+	}
+	if len(splits) == 0 {
+		return nil, fmt.Errorf("%w: the keep together and keep apart rules cannot be satisfied with %d teams", ErrInvalidTeams, teamCount)
+	}
+
+	sort.SliceStable(splits, func(i, j int) bool {
+		if splits[i].SkillSpread != splits[j].SkillSpread {
+			return splits[i].SkillSpread < splits[j].SkillSpread
+		}
+		return splitKey(splits[i]) < splitKey(splits[j])
+	})
+	if len(splits) > candidates {
+		splits = splits[:candidates]
+	}
+	return splits, nil
+}
+
+// newTeamBalancer merges keep together groups into units and validates the constraints
+func newTeamBalancer(players []models.TeamPlayer, teamCount int, together, apart [][]uint64) (*teamBalancer, error) {
+	index := make(map[uint64]int, len(players))
+	for i, p := range players {
+		index[p.User.ID] = i
+	}
+	lookup := func(group []uint64) ([]int, error) {
+		indices := make([]int, 0, len(group))
+		for _, id := range group {
+			i, ok := index[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: user %d is not a participant", ErrInvalidTeams, id)
+			}
+			indices = append(indices, i)
+		}
+		return indices, nil
+	}
+
+	// Union-find over players; every keep together group becomes one set
+	parent := make([]int, len(players))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, group := range together {
+		indices, err := lookup(group)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range indices[min(1, len(indices)):] {
+			parent[find(i)] = find(indices[0])
+		}
+	}
+
+	b := &teamBalancer{
+		players:   players,
+		conflicts: make(map[[2]int]bool),
+		teamCount: teamCount,
+		maxSize:   (len(players) + teamCount - 1) / teamCount,
+		fullTeams: len(players) % teamCount,
+	}
+	if b.fullTeams == 0 {
+		b.fullTeams = teamCount
+	}
+
+	unitOf := make([]int, len(players))
+	roots := make(map[int]int)
+	for i := range players {
+		root := find(i)
+		u, ok := roots[root]
+		if !ok {
+			u = len(b.units)
+			roots[root] = u
+			b.units = append(b.units, teamUnit{})
+		}
+		unitOf[i] = u
+		b.units[u].players = append(b.units[u].players, i)
+		b.units[u].skill += players[i].Skill
+	}
+	for _, unit := range b.units {
+		if len(unit.players) > b.maxSize {
+			return nil, fmt.Errorf("%w: a keep together group of %d players does not fit into teams of %d", ErrInvalidTeams, len(unit.players), b.maxSize)
+		}
+	}
+
+	for _, group := range apart {
+		indices, err := lookup(group)
+		if err != nil {
+			return nil, err
+		}
+		if len(indices) > teamCount {
+			return nil, fmt.Errorf("%w: %d players cannot be kept apart with %d teams", ErrInvalidTeams, len(indices), teamCount)
+		}
+		for x := range indices {
+			for y := x + 1; y < len(indices); y++ {
+				a, c := unitOf[indices[x]], unitOf[indices[y]]
+				if a == c {
+					return nil, fmt.Errorf("%w: %s and %s must be kept together and apart", ErrInvalidTeams,
+						players[indices[x]].User.Username, players[indices[y]].User.Username)
+				}
+				b.conflicts[[2]int{a, c}] = true
+				b.conflicts[[2]int{c, a}] = true
+			}
+		}
+	}
+	return b, nil
+}
+
+// assign places the units greedily, largest first in random order, into the weakest team that fits
+func (b *teamBalancer) assign(rng *rand.Rand) ([][]int, bool) {
+	order := rng.Perm(len(b.units))
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(b.units[order[i]].players) > len(b.units[order[j]].players)
+	})
+
+	teams := make([][]int, b.teamCount)
+	for _, u := range order {
+		best := -1
+		for t := range teams {
+			if !b.fits(teams, t, u, -1) {
+				continue
+			}
+			if best == -1 || b.teamSkill(teams[t]) < b.teamSkill(teams[best]) ||
+				(b.teamSkill(teams[t]) == b.teamSkill(teams[best]) && b.teamSize(teams[t]) < b.teamSize(teams[best])) {
+				best = t
+			}
+		}
+		if best == -1 {
+			return nil, false
+		}
+		teams[best] = append(teams[best], u)
+	}
+	return teams, true
+}
+
+// fits checks if unit u can join team t, optionally in exchange for unit out (-1 for none)
+func (b *teamBalancer) fits(teams [][]int, t, u, out int) bool {
+	size := b.teamSize(teams[t]) + len(b.units[u].players)
+	if out >= 0 {
+		size -= len(b.units[out].players)
+	}
+	if size > b.maxSize {
+		return false
+	}
+	if out < 0 && size == b.maxSize && b.teamSize(teams[t]) < b.maxSize {
+		full := 0
+		for _, team := range teams {
+			if b.teamSize(team) == b.maxSize {
+				full++
+			}
+		}
+		if full >= b.fullTeams {
+			return false
+		}
+	}
+
+	for _, other := range teams[t] {
+		if other != out && b.conflicts[[2]int{u, other}] {
+			return false
+		}
+	}
+	return true
+}
+
+// improve swaps units of equal size between teams as long as that evens out the skill
+func (b *teamBalancer) improve(teams [][]int) {
+	for improved := true; improved; {
+		improved = false
+		for t1 := range teams {
+			for t2 := t1 + 1; t2 < len(teams); t2++ {
+				for i, u1 := range teams[t1] {
+					for j, u2 := range teams[t2] {
+						if len(b.units[u1].players) != len(b.units[u2].players) {
+							continue
+						}
+						s1, s2 := b.teamSkill(teams[t1]), b.teamSkill(teams[t2])
+						delta := b.units[u2].skill - b.units[u1].skill
+						// Swapping only helps if the two teams end up closer together
+						if math.Abs((s1+delta)-(s2-delta)) >= math.Abs(s1-s2)-1e-9 {
+							continue
+						}
+						if !b.fits(teams, t1, u2, u1) || !b.fits(teams, t2, u1, u2) {
+							continue
+						}
+						teams[t1][i], teams[t2][j] = u2, u1
+						u1 = u2
+						improved = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// teamSize returns the number of players in a team
+func (b *teamBalancer) teamSize(team []int) int {
+	size := 0
+	for _, u := range team {
+		size += len(b.units[u].players)
+	}
+	return size
+}
+
+// teamSkill returns the summed skill of a team
+func (b *teamBalancer) teamSkill(team []int) float64 {
+	skill := 0.0
+	for _, u := range team {
+		skill += b.units[u].skill
+	}
+	return skill
+}
+
+// buildSplit turns unit assignments into teams of players and rates the balance
+func (b *teamBalancer) buildSplit(teams [][]int) models.TeamSplit {
+	split := models.TeamSplit{Teams: make([]models.GeneratedTeam, 0, len(teams))}
+	total := 0.0
+	for _, team := range teams {
+		generated := models.GeneratedTeam{Players: []models.TeamPlayer{}}
+		for _, u := range team {
+			for _, i := range b.units[u].players {
+				generated.Players = append(generated.Players, b.players[i])
+			}
+		}
+		sort.Slice(generated.Players, func(i, j int) bool {
+			if generated.Players[i].Skill != generated.Players[j].Skill {
+				return generated.Players[i].Skill > generated.Players[j].Skill
+			}
+			return generated.Players[i].User.ID < generated.Players[j].User.ID
+		})
+		generated.Skill = roundSkill(b.teamSkill(team))
+		total += generated.Skill
+		split.Teams = append(split.Teams, generated)
+	}
+
+	// Strongest team first
+	sort.SliceStable(split.Teams, func(i, j int) bool {
+		return split.Teams[i].Skill > split.Teams[j].Skill
+	})
+
+	split.SkillSpread = roundSkill(split.Teams[0].Skill - split.Teams[len(split.Teams)-1].Skill)
+	split.BalanceScore = 100
+	if average := total / float64(len(split.Teams)); average > 0 {
+		split.BalanceScore = roundSkill(math.Max(0, 100*(1-split.SkillSpread/average)))
+	}
+	return split
+}
+
+// splitKey identifies a split independent of the order of teams and players
+func splitKey(split models.TeamSplit) string {
+	teams := make([]string, 0, len(split.Teams))
+	for _, team := range split.Teams {
+		ids := make([]uint64, 0, len(team.Players))
+		for _, p := range team.Players {
+			ids = append(ids, p.User.ID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			parts[i] = strconv.FormatUint(id, 10)
+		}
+		teams = append(teams, strings.Join(parts, ","))
+	}
+	sort.Strings(teams)
+	return strings.Join(teams, "|")
+}
+
+// roundSkill rounds a skill value to one decimal
+func roundSkill(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// teamPlayers creates one player per skill with the IDs 1..n
+func teamPlayers(skills ...float64) []models.TeamPlayer {
+	players := make([]models.TeamPlayer, len(skills))
+	for i, skill := range skills {
+		players[i] = models.TeamPlayer{
+			User:  models.PublicUser{ID: uint64(i + 1), Username: string(rune('a' + i))},
+			Skill: skill,
+		}
+	}
+	return players
+}
+
+// teamOf returns the index of the team a user is in, or -1
+func teamOf(split models.TeamSplit, userID uint64) int {
+	for t, team := range split.Teams {
+		for _, p := range team.Players {
+			if p.User.ID == userID {
+				return t
+			}
+		}
+	}
+	return -1
+}
+
+func TestGenerateTeamSplits(t *testing.T) {
+	players := teamPlayers(90, 80, 70, 60, 50, 40, 30, 20)
+	splits, err := GenerateTeamSplits(players, 2, nil, nil, 3, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("GenerateTeamSplits failed: %v", err)
+	}
+	if len(splits) != 3 {
+		t.Fatalf("got %d splits, want 3", len(splits))
+	}
+
+	// 440 in total can be split into 220 and 220
+	best := splits[0]
+	if best.SkillSpread != 0 || best.BalanceScore != 100 {
+		t.Errorf("best split is not perfectly even: spread %.1f, balance %.1f", best.SkillSpread, best.BalanceScore)
+	}
+	for i, split := range splits {
+		if i > 0 && split.SkillSpread < splits[i-1].SkillSpread {
+			t.Errorf("splits are not sorted by spread: %.1f after %.1f", split.SkillSpread, splits[i-1].SkillSpread)
+		}
+		for _, team := range split.Teams {
+			if len(team.Players) != 4 {
+				t.Errorf("split %d has a team of %d players", i, len(team.Players))
+			}
+		}
+	}
+	if splitKey(splits[0]) == splitKey(splits[1]) {
+		t.Error("candidate splits are not distinct")
+	}
+}
+
+func TestGenerateTeamSplitsUnevenSizes(t *testing.T) {
+	players := teamPlayers(50, 50, 50, 50, 50, 50, 50)
+	splits, err := GenerateTeamSplits(players, 3, nil, nil, 1, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("GenerateTeamSplits failed: %v", err)
+	}
+
+	sizes := map[int]int{}
+	for _, team := range splits[0].Teams {
+		sizes[len(team.Players)]++
+	}
+	if sizes[3] != 1 || sizes[2] != 2 {
+		t.Errorf("7 players in 3 teams should be 3+2+2, got %v", sizes)
+	}
+}
+
+func TestGenerateTeamSplitsConstraints(t *testing.T) {
+	// The two strongest players want to play together, 1 and 3 must not meet in the same team
+	players := teamPlayers(90, 85, 70, 60, 50, 40)
+	together := [][]uint64{{1, 2}}
+	apart := [][]uint64{{1, 3}, {5, 6}}
+
+	splits, err := GenerateTeamSplits(players, 2, together, apart, 5, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("GenerateTeamSplits failed: %v", err)
+	}
+	for i, split := range splits {
+		if teamOf(split, 1) != teamOf(split, 2) {
+			t.Errorf("split %d: players 1 and 2 are not together", i)
+		}
+		if teamOf(split, 1) == teamOf(split, 3) || teamOf(split, 5) == teamOf(split, 6) {
+			t.Errorf("split %d: a keep apart rule is broken", i)
+		}
+	}
+}
+
+func TestGenerateTeamSplitsInvalid(t *testing.T) {
+	players := teamPlayers(50, 50, 50, 50)
+
+	tests := []struct {
+		name      string
+		teamCount int
+		together  [][]uint64
+		apart     [][]uint64
+	}{
+		{"more teams than players", 5, nil, nil},
+		{"unknown participant", 2, [][]uint64{{1, 9}}, nil},
+		{"together and apart", 2, [][]uint64{{1, 2}}, [][]uint64{{2, 1}}},
+		{"group larger than a team", 2, [][]uint64{{1, 2, 3}}, nil},
+		{"more players apart than teams", 2, nil, [][]uint64{{1, 2, 3}}},
+		{"no split satisfies all rules", 2, nil, [][]uint64{{1, 2}, {2, 3}, {1, 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateTeamSplits(players, tt.teamCount, tt.together, tt.apart, 3, rand.New(rand.NewSource(1)))
+			if !errors.Is(err, ErrInvalidTeams) {
+				t.Errorf("expected ErrInvalidTeams, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRateTeamPlayers(t *testing.T) {
+	players := []models.TeamPlayer{
+		{RankingScore: 10, PlaytimeMinutes: 0},
+		{RankingScore: 0, PlaytimeMinutes: 600 * 60},
+		{RankingScore: 5, PlaytimeMinutes: 60 * 60},
+	}
+
+	RateTeamPlayers(players, true)
+	if players[0].Skill != 40 || players[1].Skill != 60 {
+		t.Errorf("unexpected skills with playtime: %.1f, %.1f", players[0].Skill, players[1].Skill)
+	}
+	if players[2].Skill <= 20 || players[2].Skill >= 60 {
+		t.Errorf("skill %.1f of the middle player is out of range", players[2].Skill)
+	}
+
+	RateTeamPlayers(players, false)
+	if players[0].Skill != 100 || players[1].Skill != 0 || players[2].Skill != 50 {
+		t.Errorf("unexpected skills by ranking only: %.1f, %.1f, %.1f", players[0].Skill, players[1].Skill, players[2].Skill)
+	}
+}
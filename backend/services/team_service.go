@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+)
+
+// ErrInvalidTeams is returned when participants or constraints do not allow a split
+var ErrInvalidTeams = errors.New("invalid team request")
+
+// DefaultTeamSplitCandidates is the number of splits returned when the request does not ask for a number
+const DefaultTeamSplitCandidates = 3
+
+// TeamService generates balanced teams from playtime and ranking score
+type TeamService struct {
+	userRepo      *repository.UserRepository
+	voteRepo      *repository.VoteRepository
+	gameCacheRepo *repository.GameCacheRepository
+	gameOwnerRepo *repository.GameOwnerRepository
+}
+
+// NewTeamService creates a new team service
+func NewTeamService(userRepo *repository.UserRepository, voteRepo *repository.VoteRepository, gameCacheRepo *repository.GameCacheRepository, gameOwnerRepo *repository.GameOwnerRepository) *TeamService {
+	return &TeamService{
+		userRepo:      userRepo,
+		voteRepo:      voteRepo,
+		gameCacheRepo: gameCacheRepo,
+		gameOwnerRepo: gameOwnerRepo,
+	}
+}
+
+// GenerateTeams splits the participants into balanced teams and returns the best candidate splits
+func (s *TeamService) GenerateTeams(req *models.TeamGenerateRequest) (*models.TeamGenerateResponse, error) {
+	response := &models.TeamGenerateResponse{AppID: req.AppID}
+
+	playtime := make(map[string]int)
+	if req.AppID != 0 {
+		game, err := s.gameCacheRepo.GetByAppID(req.AppID)
+		if err != nil {
+			return nil, err
+		}
+		if game == nil {
+			return nil, fmt.Errorf("%w: game %d", ErrUnknownGame, req.AppID)
+		}
+		response.GameName = game.Name
+
+		owners, err := s.gameOwnerRepo.GetOwnersByAppID(req.AppID)
+		if err != nil {
+			return nil, err
+		}
+		for _, owner := range owners {
+			playtime[owner.SteamID] = owner.PlaytimeForever
+		}
+	}
+
+	rankings, err := s.voteRepo.GetGlobalRanking()
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[uint64]int, len(rankings))
+	for _, ranking := range rankings {
+		scores[ranking.User.ID] = ranking.TotalScore
+	}
+
+	players := make([]models.TeamPlayer, 0, len(req.UserIDs))
+	seen := make(map[uint64]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, fmt.Errorf("%w: unknown user ID %d", ErrInvalidTeams, userID)
+		}
+		players = append(players, models.TeamPlayer{
+			User:            user.ToPublic(),
+			PlaytimeMinutes: playtime[user.SteamID],
+			RankingScore:    scores[user.ID],
+		})
+	}
+
+	RateTeamPlayers(players, req.AppID != 0)
+
+	candidates := req.Candidates
+	if candidates <= 0 {
+		candidates = DefaultTeamSplitCandidates
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	response.Splits, err = GenerateTeamSplits(players, req.TeamCount, req.KeepTogether, req.KeepApart, candidates, rng)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
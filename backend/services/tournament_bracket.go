@@ -0,0 +1,397 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// matchSlot identifies one of the two team slots of a match
+type matchSlot struct {
+	matchID uint64
+	slot    int
+}
+
+// bracketBuilder collects matches and links them with temporary IDs (index + 1)
+type bracketBuilder struct {
+	matches []models.TournamentMatch
+}
+
+// add appends a new pending match and returns its index
+func (b *bracketBuilder) add(bracket string, round, position int) int {
+	b.matches = append(b.matches, models.TournamentMatch{
+		ID:       uint64(len(b.matches) + 1),
+		Bracket:  bracket,
+		Round:    round,
+		Position: position,
+		Status:   models.MatchStatusPending,
+	})
+	return len(b.matches) - 1
+}
+
+// linkWinner sends the winner of match from into a slot of match to
+func (b *bracketBuilder) linkWinner(from, to, slot int) {
+	id := b.matches[to].ID
+	b.matches[from].WinnerNextMatchID = &id
+	b.matches[from].WinnerNextSlot = slot
+}
+
+// linkLoser sends the loser of match from into a slot of match to
+func (b *bracketBuilder) linkLoser(from, to, slot int) {
+	id := b.matches[to].ID
+	b.matches[from].LoserNextMatchID = &id
+	b.matches[from].LoserNextSlot = slot
+}
+
+// BuildBracket creates all matches of a tournament for teams given in seed order
+// Match IDs and links are temporary (1-based positions in the returned slice) until the
+// matches are stored. Byes are settled right away.
+func BuildBracket(format models.TournamentFormat, teamIDs []uint64) []models.TournamentMatch {
+	b := &bracketBuilder{}
+
+	switch format {
+	case models.TournamentRoundRobin:
+		b.roundRobin(teamIDs)
+	case models.TournamentDoubleElimination:
+		b.doubleElimination(teamIDs)
+	default:
+		b.winnersBracket(teamIDs)
+	}
+
+	AdvanceBracket(b.matches)
+	return b.matches
+}
+
+// winnersBracket builds a single elimination bracket and returns the match indices per round
+// The field is padded to a power of two; the top seeds get the byes.
+func (b *bracketBuilder) winnersBracket(teamIDs []uint64) [][]int {
+	size := 2
+	for size < len(teamIDs) {
+		size *= 2
+	}
+	seeds := seedOrder(size)
+
+	var rounds [][]int
+	for round, count := 1, size/2; count >= 1; round, count = round+1, count/2 {
+		indices := make([]int, count)
+		for p := range indices {
+			indices[p] = b.add(models.BracketWinners, round, p)
+			if round == 1 {
+				b.matches[indices[p]].Team1ID = seededTeam(teamIDs, seeds[2*p])
+				b.matches[indices[p]].Team2ID = seededTeam(teamIDs, seeds[2*p+1])
+			} else {
+				previous := rounds[round-2]
+				b.linkWinner(previous[2*p], indices[p], 1)
+				b.linkWinner(previous[2*p+1], indices[p], 2)
+			}
+		}
+		rounds = append(rounds, indices)
+	}
+	return rounds
+}
+
+// doubleElimination builds winners and losers bracket plus a single grand final
+// Losers of winners round k+1 drop into losers round 2k, facing the survivors of the losers bracket.
+func (b *bracketBuilder) doubleElimination(teamIDs []uint64) {
+	wb := b.winnersBracket(teamIDs)
+	wbFinal := wb[len(wb)-1][0]
+
+	grandFinal := func() int {
+		return b.add(models.BracketGrandFinal, 1, 0)
+	}
+
+	// Two teams: the loser of the only winners match gets a second chance in the grand final
+	if len(wb) == 1 {
+		gf := grandFinal()
+		b.linkWinner(wbFinal, gf, 1)
+		b.linkLoser(wbFinal, gf, 2)
+		return
+	}
+
+	size := len(wb[0]) * 2
+	rounds := len(wb)
+	lb := make([][]int, 0, 2*(rounds-1))
+
+	first := make([]int, size/4)
+	for p := range first {
+		first[p] = b.add(models.BracketLosers, 1, p)
+		b.linkLoser(wb[0][2*p], first[p], 1)
+		b.linkLoser(wb[0][2*p+1], first[p], 2)
+	}
+	lb = append(lb, first)
+
+	for j := 1; j <= rounds-1; j++ {
+		// Drop-in round: survivors meet the losers of winners round j+1 (in reverse order to avoid rematches)
+		count := size >> (j + 1)
+		dropIn := make([]int, count)
+		for p := range dropIn {
+			dropIn[p] = b.add(models.BracketLosers, 2*j, p)
+			b.linkWinner(lb[2*j-2][p], dropIn[p], 1)
+			b.linkLoser(wb[j][count-1-p], dropIn[p], 2)
+		}
+		lb = append(lb, dropIn)
+
+		if j == rounds-1 {
+			break
+		}
+
+		// Consolidation round: survivors play each other
+		consolidation := make([]int, size>>(j+2))
+		for p := range consolidation {
+			consolidation[p] = b.add(models.BracketLosers, 2*j+1, p)
+			b.linkWinner(dropIn[2*p], consolidation[p], 1)
+			b.linkWinner(dropIn[2*p+1], consolidation[p], 2)
+		}
+		lb = append(lb, consolidation)
+	}
+
+	gf := grandFinal()
+	b.linkWinner(wbFinal, gf, 1)
+	b.linkWinner(lb[len(lb)-1][0], gf, 2)
+}
+
+// roundRobin pairs every team with every other team using the circle method
+func (b *bracketBuilder) roundRobin(teamIDs []uint64) {
+	circle := make([]*uint64, 0, len(teamIDs)+1)
+	for i := range teamIDs {
+		circle = append(circle, &teamIDs[i])
+	}
+	if len(circle)%2 == 1 {
+		circle = append(circle, nil) // Whoever is paired with nil sits the round out
+	}
+
+	n := len(circle)
+	for round := 1; round < n; round++ {
+		position := 0
+		for i := 0; i < n/2; i++ {
+			team1, team2 := circle[i], circle[n-1-i]
+			if team1 == nil || team2 == nil {
+				continue
+			}
+			index := b.add(models.BracketRoundRobin, round, position)
+			b.matches[index].Team1ID = copyTeam(team1)
+			b.matches[index].Team2ID = copyTeam(team2)
+			position++
+		}
+
+		// Keep the first team fixed and rotate the others
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+}
+
+// seedOrder returns the 1-based seeds of a bracket of the given size in match order,
+// so seed 1 and 2 can only meet in the final
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// seededTeam returns the team with the given 1-based seed, or nil for a bye
+func seededTeam(teamIDs []uint64, seed int) *uint64 {
+	if seed > len(teamIDs) {
+		return nil
+	}
+	return copyTeam(&teamIDs[seed-1])
+}
+
+// AdvanceBracket moves decided teams into their next matches and settles byes
+// A slot is decided once the match feeding it is confirmed. Matches with both slots decided
+// but only one team are confirmed automatically as byes. Returns true if anything changed.
+func AdvanceBracket(matches []models.TournamentMatch) bool {
+	feeders := make(map[matchSlot]*models.TournamentMatch)
+	for i := range matches {
+		m := &matches[i]
+		if m.WinnerNextMatchID != nil {
+			feeders[matchSlot{*m.WinnerNextMatchID, m.WinnerNextSlot}] = m
+		}
+		if m.LoserNextMatchID != nil {
+			feeders[matchSlot{*m.LoserNextMatchID, m.LoserNextSlot}] = m
+		}
+	}
+
+	changedAny := false
+	for changed := true; changed; {
+		changed = false
+		for i := range matches {
+			m := &matches[i]
+			if m.Status == models.MatchStatusConfirmed {
+				continue
+			}
+
+			decided := true
+			for index, team := range []**uint64{&m.Team1ID, &m.Team2ID} {
+				slot := index + 1
+				feeder, ok := feeders[matchSlot{m.ID, slot}]
+				if !ok {
+					continue
+				}
+				if feeder.Status != models.MatchStatusConfirmed {
+					decided = false
+					continue
+				}
+				value := feeder.WinnerTeamID
+				if feeder.LoserNextMatchID != nil && *feeder.LoserNextMatchID == m.ID && feeder.LoserNextSlot == slot {
+					value = matchLoser(feeder)
+				}
+				if !sameTeam(*team, value) {
+					*team = copyTeam(value)
+					changed = true
+				}
+			}
+
+			if decided && (m.Team1ID == nil || m.Team2ID == nil) {
+				m.WinnerTeamID = copyTeam(m.Team1ID)
+				if m.WinnerTeamID == nil {
+					m.WinnerTeamID = copyTeam(m.Team2ID)
+				}
+				m.IsBye = true
+				m.Status = models.MatchStatusConfirmed
+				changed = true
+			}
+		}
+		changedAny = changedAny || changed
+	}
+	return changedAny
+}
+
+// ApplyResult stores the scores of a match and determines the winner (nil for a draw)
+func ApplyResult(m *models.TournamentMatch, score1, score2 int) {
+	m.Score1 = &score1
+	m.Score2 = &score2
+	switch {
+	case score1 > score2:
+		m.WinnerTeamID = copyTeam(m.Team1ID)
+	case score2 > score1:
+		m.WinnerTeamID = copyTeam(m.Team2ID)
+	default:
+		m.WinnerTeamID = nil
+	}
+}
+
+// IsPlayable returns true if both teams of a match are known and the result is not final yet
+func IsPlayable(m *models.TournamentMatch) bool {
+	return m.Status != models.MatchStatusConfirmed && m.Team1ID != nil && m.Team2ID != nil
+}
+
+// BracketWinner returns the winning team once every match is confirmed
+// The second return value is false while matches are still open.
+func BracketWinner(format models.TournamentFormat, teamIDs []uint64, matches []models.TournamentMatch) (*uint64, bool) {
+	for i := range matches {
+		if matches[i].Status != models.MatchStatusConfirmed {
+			return nil, false
+		}
+	}
+
+	if format == models.TournamentRoundRobin {
+		standings := Standings(teamIDs, matches)
+		if len(standings) == 0 {
+			return nil, true
+		}
+		winner := standings[0].TeamID
+		return &winner, true
+	}
+
+	// The final (or grand final) is the only match without a next match for its winner
+	for i := range matches {
+		if matches[i].WinnerNextMatchID == nil && matches[i].Bracket != models.BracketLosers {
+			return matches[i].WinnerTeamID, true
+		}
+	}
+	return nil, true
+}
+
+// Standings builds the round robin table from confirmed matches
+// Sorted by points, score difference, scores and finally seed.
+func Standings(teamIDs []uint64, matches []models.TournamentMatch) []models.TournamentStanding {
+	seed := make(map[uint64]int, len(teamIDs))
+	table := make(map[uint64]*models.TournamentStanding, len(teamIDs))
+	standings := make([]models.TournamentStanding, len(teamIDs))
+	for i, teamID := range teamIDs {
+		seed[teamID] = i
+		standings[i].TeamID = teamID
+		table[teamID] = &standings[i]
+	}
+
+	for i := range matches {
+		m := &matches[i]
+		if m.Status != models.MatchStatusConfirmed || m.IsBye || m.Team1ID == nil || m.Team2ID == nil || m.Score1 == nil || m.Score2 == nil {
+			continue
+		}
+		home, away := table[*m.Team1ID], table[*m.Team2ID]
+		if home == nil || away == nil {
+			continue
+		}
+		home.Played++
+		away.Played++
+		home.ScoreFor += *m.Score1
+		home.ScoreAgainst += *m.Score2
+		away.ScoreFor += *m.Score2
+		away.ScoreAgainst += *m.Score1
+		switch {
+		case *m.Score1 > *m.Score2:
+			home.Wins++
+			away.Losses++
+		case *m.Score2 > *m.Score1:
+			away.Wins++
+			home.Losses++
+		default:
+			home.Draws++
+			away.Draws++
+		}
+	}
+
+	for i := range standings {
+		standings[i].Points = standings[i].Wins*3 + standings[i].Draws
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if diffA, diffB := a.ScoreFor-a.ScoreAgainst, b.ScoreFor-b.ScoreAgainst; diffA != diffB {
+			return diffA > diffB
+		}
+		if a.ScoreFor != b.ScoreFor {
+			return a.ScoreFor > b.ScoreFor
+		}
+		return seed[a.TeamID] < seed[b.TeamID]
+	})
+	return standings
+}
+
+// matchLoser returns the losing team of a confirmed match (nil for a bye)
+func matchLoser(m *models.TournamentMatch) *uint64 {
+	if m.WinnerTeamID == nil {
+		return nil
+	}
+	if sameTeam(m.WinnerTeamID, m.Team1ID) {
+		return m.Team2ID
+	}
+	return m.Team1ID
+}
+
+// sameTeam compares two optional team IDs
+func sameTeam(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// copyTeam returns a copy of an optional team ID, so matches never share pointers
+func copyTeam(teamID *uint64) *uint64 {
+	if teamID == nil {
+		return nil
+	}
+	id := *teamID
+	return &id
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+// playBracket plays all matches until none is left; decide returns true if team1 wins
+func playBracket(t *testing.T, matches []models.TournamentMatch, decide func(team1, team2 uint64) bool) map[uint64]int {
+	t.Helper()
+
+	losses := make(map[uint64]int)
+	for played := 0; ; played++ {
+		if played > len(matches) {
+			t.Fatal("bracket did not finish")
+		}
+
+		var next *models.TournamentMatch
+		for i := range matches {
+			if IsPlayable(&matches[i]) {
+				next = &matches[i]
+				break
+			}
+		}
+		if next == nil {
+			return losses
+		}
+
+		if decide(*next.Team1ID, *next.Team2ID) {
+			ApplyResult(next, 2, 1)
+			losses[*next.Team2ID]++
+		} else {
+			ApplyResult(next, 0, 2)
+			losses[*next.Team1ID]++
+		}
+		next.Status = models.MatchStatusConfirmed
+		AdvanceBracket(matches)
+	}
+}
+
+// teamIDs returns the IDs 1..n, which are also the seeds
+func teamIDs(n int) []uint64 {
+	ids := make([]uint64, n)
+	for i := range ids {
+		ids[i] = uint64(i + 1)
+	}
+	return ids
+}
+
+func TestEliminationBrackets(t *testing.T) {
+	favourite := func(team1, team2 uint64) bool { return team1 < team2 }
+	underdog := func(team1, team2 uint64) bool { return team1 > team2 }
+
+	tests := []struct {
+		name        string
+		format      models.TournamentFormat
+		teams       int
+		decide      func(team1, team2 uint64) bool
+		wantMatches int
+		wantByes    int
+		wantWinner  uint64
+	}{
+		{"single elimination, 8 teams", models.TournamentSingleElimination, 8, favourite, 7, 0, 1},
+		{"single elimination, 5 teams", models.TournamentSingleElimination, 5, favourite, 7, 3, 1},
+		{"single elimination, 2 teams", models.TournamentSingleElimination, 2, underdog, 1, 0, 2},
+		{"double elimination, 2 teams", models.TournamentDoubleElimination, 2, favourite, 2, 0, 1},
+		{"double elimination, 4 teams", models.TournamentDoubleElimination, 4, favourite, 6, 0, 1},
+		{"double elimination, 8 teams", models.TournamentDoubleElimination, 8, underdog, 14, 0, 8},
+		{"double elimination, 6 teams", models.TournamentDoubleElimination, 6, favourite, 14, 4, 1},
+		{"double elimination, 3 teams", models.TournamentDoubleElimination, 3, underdog, 6, 2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := teamIDs(tt.teams)
+			matches := BuildBracket(tt.format, ids)
+			if len(matches) != tt.wantMatches {
+				t.Fatalf("got %d matches, want %d", len(matches), tt.wantMatches)
+			}
+
+			losses := playBracket(t, matches, tt.decide)
+
+			byes := 0
+			for _, m := range matches {
+				if m.IsBye {
+					byes++
+				}
+			}
+			if byes != tt.wantByes {
+				t.Errorf("got %d byes, want %d", byes, tt.wantByes)
+			}
+
+			winner, finished := BracketWinner(tt.format, ids, matches)
+			if !finished || winner == nil || *winner != tt.wantWinner {
+				t.Fatalf("winner = %v (finished %t), want %d", winner, finished, tt.wantWinner)
+			}
+
+			// Everyone but the champion is knocked out by the allowed number of losses
+			maxLosses := 1
+			if tt.format == models.TournamentDoubleElimination {
+				maxLosses = 2
+			}
+			for _, id := range ids {
+				if id == tt.wantWinner {
+					if losses[id] >= maxLosses {
+						t.Errorf("champion %d lost %d times", id, losses[id])
+					}
+					continue
+				}
+				if losses[id] < 1 || losses[id] > maxLosses {
+					t.Errorf("team %d lost %d times, want 1..%d", id, losses[id], maxLosses)
+				}
+			}
+		})
+	}
+}
+
+func TestDoubleEliminationSecondChance(t *testing.T) {
+	ids := teamIDs(4)
+	matches := BuildBracket(models.TournamentDoubleElimination, ids)
+
+	// Seed 1 loses its first match, then wins every other one
+	first := true
+	losses := playBracket(t, matches, func(team1, team2 uint64) bool {
+		if team1 == 1 && first {
+			first = false
+			return false
+		}
+		if team1 == 1 || team2 == 1 {
+			return team1 == 1
+		}
+		return team1 < team2
+	})
+
+	winner, finished := BracketWinner(models.TournamentDoubleElimination, ids, matches)
+	if !finished || winner == nil || *winner != 1 {
+		t.Fatalf("winner = %v (finished %t), want 1 through the losers bracket", winner, finished)
+	}
+	if losses[1] != 1 {
+		t.Errorf("champion lost %d times, want 1", losses[1])
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	tests := []struct {
+		teams       int
+		wantMatches int
+	}{
+		{2, 1},
+		{4, 6},
+		{5, 10},
+	}
+
+	for _, tt := range tests {
+		ids := teamIDs(tt.teams)
+		matches := BuildBracket(models.TournamentRoundRobin, ids)
+		if len(matches) != tt.wantMatches {
+			t.Fatalf("%d teams: got %d matches, want %d", tt.teams, len(matches), tt.wantMatches)
+		}
+
+		pairs := make(map[[2]uint64]bool)
+		for _, m := range matches {
+			a, b := *m.Team1ID, *m.Team2ID
+			if a > b {
+				a, b = b, a
+			}
+			if pairs[[2]uint64{a, b}] {
+				t.Errorf("%d teams: %d vs %d is scheduled twice", tt.teams, a, b)
+			}
+			pairs[[2]uint64{a, b}] = true
+		}
+
+		playBracket(t, matches, func(team1, team2 uint64) bool { return team1 > team2 })
+		standings := Standings(ids, matches)
+		if standings[0].TeamID != uint64(tt.teams) || standings[0].Wins != tt.teams-1 || standings[0].Points != 3*(tt.teams-1) {
+			t.Errorf("%d teams: unexpected leader %+v", tt.teams, standings[0])
+		}
+		winner, finished := BracketWinner(models.TournamentRoundRobin, ids, matches)
+		if !finished || winner == nil || *winner != uint64(tt.teams) {
+			t.Errorf("%d teams: winner = %v, want %d", tt.teams, winner, tt.teams)
+		}
+	}
+}
+
+func TestRoundRobinDraws(t *testing.T) {
+	ids := teamIDs(3)
+	matches := BuildBracket(models.TournamentRoundRobin, ids)
+	for i := range matches {
+		ApplyResult(&matches[i], 1, 1)
+		matches[i].Status = models.MatchStatusConfirmed
+	}
+
+	standings := Standings(ids, matches)
+	for i, standing := range standings {
+		if standing.TeamID != ids[i] || standing.Draws != 2 || standing.Points != 2 {
+			t.Errorf("position %d: unexpected standing %+v", i+1, standing)
+		}
+	}
+}
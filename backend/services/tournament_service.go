@@ -0,0 +1,390 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+	"github.com/guided-traffic/rate-your-mate/backend/repository"
+	"github.com/guided-traffic/rate-your-mate/backend/websocket"
+)
+
+// Errors returned by the tournament service
+var (
+	ErrTournamentNotFound   = errors.New("tournament not found")
+	ErrMatchNotFound        = errors.New("match not found")
+	ErrInvalidTournament    = errors.New("invalid tournament")
+	ErrInvalidMatchResult   = errors.New("invalid match result")
+	ErrRegistrationClosed   = errors.New("registration is closed")
+	ErrTournamentNotRunning = errors.New("tournament is not running")
+	ErrNotTeamMember        = errors.New("only members of the playing teams can report this match")
+)
+
+// TournamentService handles tournaments, from registration and team formation
+// to result reporting and bracket progression
+type TournamentService struct {
+	tournamentRepo *repository.TournamentRepository
+	gameCacheRepo  *repository.GameCacheRepository
+	wsHub          *websocket.Hub
+	// mu serializes changes to brackets, so two confirmations cannot overwrite each other
+	mu sync.Mutex
+}
+
+// NewTournamentService creates a new tournament service
+func NewTournamentService(tournamentRepo *repository.TournamentRepository, gameCacheRepo *repository.GameCacheRepository, wsHub *websocket.Hub) *TournamentService {
+	return &TournamentService{
+		tournamentRepo: tournamentRepo,
+		gameCacheRepo:  gameCacheRepo,
+		wsHub:          wsHub,
+	}
+}
+
+// CreateTournament opens a new tournament for registration
+func (s *TournamentService) CreateTournament(creatorID uint64, req *models.CreateTournamentRequest) (*models.Tournament, error) {
+	if !req.Format.IsValid() {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidTournament, req.Format)
+	}
+
+	game, err := s.gameCacheRepo.GetByAppID(req.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, fmt.Errorf("%w: game %d", ErrUnknownGame, req.AppID)
+	}
+
+	teamSize := req.TeamSize
+	if teamSize == 0 {
+		teamSize = 1
+	}
+
+	tournament := &models.Tournament{
+		Name:        req.Name,
+		AppID:       req.AppID,
+		Format:      req.Format,
+		TeamSize:    teamSize,
+		Status:      models.TournamentStatusRegistration,
+		BonusPoints: req.BonusPoints,
+		CreatedBy:   creatorID,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	if err := s.tournamentRepo.Create(tournament); err != nil {
+		return nil, err
+	}
+
+	return s.broadcastTournament(tournament.ID)
+}
+
+// ListTournaments returns all tournaments with their registered players
+// Teams and matches are only included by GetTournament.
+func (s *TournamentService) ListTournaments() ([]models.Tournament, error) {
+	tournaments, err := s.tournamentRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Tournament, 0, len(tournaments))
+	for i := range tournaments {
+		result = append(result, *s.decorate(&tournaments[i]))
+	}
+	return result, nil
+}
+
+// GetTournament returns a tournament with teams, matches and standings
+func (s *TournamentService) GetTournament(tournamentID uint64) (*models.Tournament, error) {
+	tournament, err := s.tournamentRepo.GetByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if tournament == nil {
+		return nil, ErrTournamentNotFound
+	}
+	return s.decorate(tournament), nil
+}
+
+// Register signs a player up for a tournament; registering twice has no effect
+func (s *TournamentService) Register(tournamentID, userID uint64) (*models.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.openForRegistration(tournamentID); err != nil {
+		return nil, err
+	}
+
+	registered, err := s.tournamentRepo.IsRegistered(tournamentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !registered {
+		if err := s.tournamentRepo.Register(tournamentID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.broadcastTournament(tournamentID)
+}
+
+// Unregister withdraws a player from a tournament before it starts
+func (s *TournamentService) Unregister(tournamentID, userID uint64) (*models.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.openForRegistration(tournamentID); err != nil {
+		return nil, err
+	}
+	if err := s.tournamentRepo.Unregister(tournamentID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.broadcastTournament(tournamentID)
+}
+
+// openForRegistration loads a tournament and checks that players can still sign up
+func (s *TournamentService) openForRegistration(tournamentID uint64) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.Status != models.TournamentStatusRegistration {
+		return nil, ErrRegistrationClosed
+	}
+	return tournament, nil
+}
+
+// StartTournament closes registration, forms random teams and generates the bracket
+func (s *TournamentService) StartTournament(tournamentID uint64) (*models.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tournament, err := s.openForRegistration(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := formTeams(tournament.Players, tournament.TeamSize)
+	if err != nil {
+		return nil, err
+	}
+
+	build := func(teamIDs []uint64) []models.TournamentMatch {
+		return BuildBracket(tournament.Format, teamIDs)
+	}
+	if err := s.tournamentRepo.Start(tournamentID, teams, build, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Tournament %d started with %d teams", tournamentID, len(teams))
+	return s.broadcastTournament(tournamentID)
+}
+
+// formTeams shuffles the registered players into teams of teamSize
+// Leftover players are spread over the first teams, so some teams may have one extra member.
+// The shuffled order is also the seeding.
+func formTeams(players []models.PublicUser, teamSize int) ([]models.TournamentTeam, error) {
+	count := len(players) / teamSize
+	if count < 2 {
+		return nil, fmt.Errorf("%w: %d players are not enough for two teams of %d", ErrInvalidTournament, len(players), teamSize)
+	}
+
+	shuffled := make([]models.PublicUser, len(players))
+	copy(shuffled, players)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	teams := make([]models.TournamentTeam, count)
+	for i := range teams {
+		teams[i].Seed = i + 1
+	}
+	for i, player := range shuffled {
+		teams[i%count].Members = append(teams[i%count].Members, player)
+	}
+
+	for i := range teams {
+		if len(teams[i].Members) == 1 {
+			teams[i].Name = teams[i].Members[0].Username
+		} else {
+			teams[i].Name = "Team " + teams[i].Members[0].Username
+		}
+	}
+	return teams, nil
+}
+
+// ReportResult stores the score of a match reported by one of its players or an admin
+// The result only counts once an admin confirms it.
+func (s *TournamentService) ReportResult(tournamentID, matchID, userID uint64, isAdmin bool, req *models.ReportMatchRequest) (*models.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tournament, match, err := s.playableMatch(tournamentID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && !isTeamMember(tournament, match, userID) {
+		return nil, ErrNotTeamMember
+	}
+	if err := applyScores(tournament, match, req.Score1, req.Score2); err != nil {
+		return nil, err
+	}
+
+	match.Status = models.MatchStatusReported
+	match.ReportedBy = &userID
+	if err := s.tournamentRepo.SaveMatches([]models.TournamentMatch{*match}); err != nil {
+		return nil, err
+	}
+
+	return s.broadcastTournament(tournamentID)
+}
+
+// ConfirmResult makes a match result final and advances the bracket
+// If req is nil the reported result is confirmed, otherwise the given score replaces it.
+// The tournament finishes when its last match is confirmed.
+func (s *TournamentService) ConfirmResult(tournamentID, matchID uint64, req *models.ReportMatchRequest) (*models.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tournament, match, err := s.playableMatch(tournamentID, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req != nil {
+		if err := applyScores(tournament, match, req.Score1, req.Score2); err != nil {
+			return nil, err
+		}
+	} else if match.Status != models.MatchStatusReported {
+		return nil, fmt.Errorf("%w: no result was reported yet", ErrInvalidMatchResult)
+	}
+
+	match.Status = models.MatchStatusConfirmed
+	AdvanceBracket(tournament.Matches)
+	if err := s.tournamentRepo.SaveMatches(tournament.Matches); err != nil {
+		return nil, err
+	}
+
+	teamIDs := make([]uint64, 0, len(tournament.Teams))
+	for _, team := range tournament.Teams {
+		teamIDs = append(teamIDs, team.ID)
+	}
+	winner, finished := BracketWinner(tournament.Format, teamIDs, tournament.Matches)
+	if !finished {
+		return s.broadcastTournament(tournamentID)
+	}
+
+	if err := s.tournamentRepo.Finish(tournamentID, winner, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	result, err := s.GetTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Tournament %d finished", tournamentID)
+	s.wsHub.BroadcastTournamentFinished(result)
+	return result, nil
+}
+
+// playableMatch loads a running tournament and one of its matches that can still be played
+// The returned match points into tournament.Matches.
+func (s *TournamentService) playableMatch(tournamentID, matchID uint64) (*models.Tournament, *models.TournamentMatch, error) {
+	tournament, err := s.GetTournament(tournamentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tournament.Status != models.TournamentStatusRunning {
+		return nil, nil, ErrTournamentNotRunning
+	}
+
+	for i := range tournament.Matches {
+		match := &tournament.Matches[i]
+		if match.ID != matchID {
+			continue
+		}
+		if match.Status == models.MatchStatusConfirmed {
+			return nil, nil, fmt.Errorf("%w: the result is already confirmed", ErrInvalidMatchResult)
+		}
+		if !IsPlayable(match) {
+			return nil, nil, fmt.Errorf("%w: the teams of this match are not known yet", ErrInvalidMatchResult)
+		}
+		return tournament, match, nil
+	}
+	return nil, nil, ErrMatchNotFound
+}
+
+// applyScores sets the score of a match; elimination matches need a winner
+func applyScores(tournament *models.Tournament, match *models.TournamentMatch, score1, score2 int) error {
+	if score1 == score2 && tournament.Format != models.TournamentRoundRobin {
+		return fmt.Errorf("%w: elimination matches cannot end in a draw", ErrInvalidMatchResult)
+	}
+	ApplyResult(match, score1, score2)
+	return nil
+}
+
+// isTeamMember checks if a user plays in one of the two teams of a match
+func isTeamMember(tournament *models.Tournament, match *models.TournamentMatch, userID uint64) bool {
+	for _, team := range tournament.Teams {
+		if !sameTeam(&team.ID, match.Team1ID) && !sameTeam(&team.ID, match.Team2ID) {
+			continue
+		}
+		for _, member := range team.Members {
+			if member.ID == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DeleteTournament deletes a tournament with its teams and matches
+// Deleting a finished tournament also removes its bonus points from the ranking.
+func (s *TournamentService) DeleteTournament(tournamentID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.GetTournament(tournamentID); err != nil {
+		return err
+	}
+	return s.tournamentRepo.Delete(tournamentID)
+}
+
+// broadcastTournament loads a tournament and sends it to all clients
+func (s *TournamentService) broadcastTournament(tournamentID uint64) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	s.wsHub.BroadcastTournamentUpdated(tournament)
+	return tournament, nil
+}
+
+// decorate fills in the game name and the round robin standings
+func (s *TournamentService) decorate(tournament *models.Tournament) *models.Tournament {
+	if game, err := s.gameCacheRepo.GetByAppID(tournament.AppID); err != nil {
+		log.Printf("Failed to get game %d for tournament %d: %v", tournament.AppID, tournament.ID, err)
+	} else if game != nil {
+		tournament.GameName = game.Name
+	}
+
+	if tournament.Players == nil {
+		tournament.Players = []models.PublicUser{}
+	}
+	if tournament.Teams == nil {
+		tournament.Teams = []models.TournamentTeam{}
+	}
+	if tournament.Matches == nil {
+		tournament.Matches = []models.TournamentMatch{}
+	}
+
+	if tournament.Format == models.TournamentRoundRobin && len(tournament.Teams) > 0 {
+		teamIDs := make([]uint64, 0, len(tournament.Teams))
+		for _, team := range tournament.Teams {
+			teamIDs = append(teamIDs, team.ID)
+		}
+		tournament.Standings = Standings(teamIDs, tournament.Matches)
+	}
+	return tournament
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guided-traffic/rate-your-mate/backend/models"
+)
+
+func TestFormTeams(t *testing.T) {
+	players := func(n int) []models.PublicUser {
+		users := make([]models.PublicUser, n)
+		for i := range users {
+			users[i] = models.PublicUser{ID: uint64(i + 1), Username: string(rune('a' + i))}
+		}
+		return users
+	}
+
+	tests := []struct {
+		players   int
+		teamSize  int
+		wantSizes []int
+	}{
+		{2, 1, []int{1, 1}},
+		{8, 2, []int{2, 2, 2, 2}},
+		{7, 3, []int{4, 3}},
+		{11, 5, []int{6, 5}},
+	}
+
+	for _, tt := range tests {
+		teams, err := formTeams(players(tt.players), tt.teamSize)
+		if err != nil {
+			t.Fatalf("%d players in teams of %d: %v", tt.players, tt.teamSize, err)
+		}
+		if len(teams) != len(tt.wantSizes) {
+			t.Fatalf("%d players in teams of %d: got %d teams, want %d", tt.players, tt.teamSize, len(teams), len(tt.wantSizes))
+		}
+
+		seen := make(map[uint64]bool)
+		for i, team := range teams {
+			if len(team.Members) != tt.wantSizes[i] || team.Seed != i+1 || team.Name == "" {
+				t.Errorf("%d players in teams of %d: unexpected team %d: %+v", tt.players, tt.teamSize, i, team)
+			}
+			for _, member := range team.Members {
+				if seen[member.ID] {
+					t.Errorf("player %d is in two teams", member.ID)
+				}
+				seen[member.ID] = true
+			}
+		}
+		if len(seen) != tt.players {
+			t.Errorf("%d players in teams of %d: %d players got a team", tt.players, tt.teamSize, len(seen))
+		}
+	}
+
+	if _, err := formTeams(players(3), 2); !errors.Is(err, ErrInvalidTournament) {
+		t.Errorf("expected ErrInvalidTournament for a single team, got %v", err)
+	}
+}
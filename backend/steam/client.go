@@ -0,0 +1,125 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guided-traffic/rate-your-mate/backend/config"
+)
+
+// Errors returned by Steam clients
+var (
+	ErrNotConfigured = errors.New("Steam API key not configured")
+	ErrAppNotFound   = errors.New("game not found or not accessible")
+	ErrGroupNotFound = errors.New("Steam group not found")
+)
+
+// RateLimitError is returned when Steam answered with 429
+type RateLimitError struct {
+	RetryAfter time.Duration // Pause requested by Steam via Retry-After, 0 if not sent
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited (429), retry after %v", e.RetryAfter)
+	}
+	return "rate limited (429)"
+}
+
+// Client covers the Steam Web API and Steam Store endpoints used by the backend
+type Client interface {
+	// GetPlayerSummaries fetches profile data for up to 100 players
+	GetPlayerSummaries(steamIDs []string) ([]PlayerSummary, error)
+	// GetFriendList fetches the Steam IDs of a player's friends, fails for private friend lists
+	GetFriendList(steamID string) ([]string, error)
+	// GetGroupMembers fetches the Steam IDs of a group's members by group URL name or 64-bit group ID
+	GetGroupMembers(group string) ([]string, error)
+	// GetOwnedGames fetches the game library of a player
+	GetOwnedGames(steamID string) ([]OwnedGame, error)
+	// GetAppDetails fetches the store page data of a game, ErrAppNotFound if it is not in the store
+	GetAppDetails(appID int) (*AppDetails, error)
+	// GetReviewSummary fetches the review counts of a game
+	GetReviewSummary(appID int) (*ReviewSummary, error)
+	// IsConfigured returns true if the client can make Web API calls
+	IsConfigured() bool
+	// CheckConnectivity verifies that Steam is reachable
+	CheckConnectivity() error
+}
+
+// PlayerSummary represents a Steam player's profile data
+type PlayerSummary struct {
+	SteamID                  string `json:"steamid"`
+	PersonaName              string `json:"personaname"`
+	ProfileURL               string `json:"profileurl"`
+	Avatar                   string `json:"avatar"`       // 32x32
+	AvatarMedium             string `json:"avatarmedium"` // 64x64
+	AvatarFull               string `json:"avatarfull"`   // 184x184
+	PersonaState             int    `json:"personastate"` // 0=Offline, 1=Online, etc.
+	CommunityVisibilityState int    `json:"communityvisibilitystate"`
+	ProfileState             int    `json:"profilestate"`
+	LastLogoff               int64  `json:"lastlogoff"`
+	RealName                 string `json:"realname,omitempty"`
+	TimeCreated              int64  `json:"timecreated,omitempty"`
+	LocCountryCode           string `json:"loccountrycode,omitempty"`
+}
+
+// OwnedGame is a game in a player's library
+type OwnedGame struct {
+	AppID           int    `json:"appid"`
+	Name            string `json:"name"`
+	PlaytimeForever int    `json:"playtime_forever"` // Minutes
+	ImgIconURL      string `json:"img_icon_url"`
+}
+
+// AppDetails is the store page data of a game
+type AppDetails struct {
+	Name        string `json:"name"`
+	HeaderImage string `json:"header_image"`
+	IsFree      bool   `json:"is_free"`
+	Categories  []struct {
+		ID          int    `json:"id"`
+		Description string `json:"description"`
+	} `json:"categories"`
+	PriceOverview *struct {
+		Currency         string `json:"currency"`
+		Initial          int    `json:"initial"`
+		Final            int    `json:"final"`
+		DiscountPercent  int    `json:"discount_percent"`
+		InitialFormatted string `json:"initial_formatted"`
+		FinalFormatted   string `json:"final_formatted"`
+	} `json:"price_overview"`
+}
+
+// ReviewSummary contains the review counts of a game
+type ReviewSummary struct {
+	NumReviews      int    `json:"num_reviews"`
+	ReviewScore     int    `json:"review_score"`
+	ReviewScoreDesc string `json:"review_score_desc"`
+	TotalPositive   int    `json:"total_positive"`
+	TotalNegative   int    `json:"total_negative"`
+	TotalReviews    int    `json:"total_reviews"`
+}
+
+// New creates the Steam client selected by the configuration
+// With STEAM_FAKE_DIR set all calls are answered from local fixtures.
+func New(cfg *config.Config) (Client, error) {
+	if cfg.SteamFakeDir != "" {
+		return NewFakeClient(cfg.SteamFakeDir)
+	}
+	return NewHTTPClient(cfg.SteamAPIKey, cfg.SteamAPIBaseURL, cfg.SteamStoreBaseURL, cfg.SteamCommunityBaseURL), nil
+}
+
+// GetPlayerSummary fetches a single player's profile data
+func GetPlayerSummary(c Client, steamID string) (*PlayerSummary, error) {
+	players, err := c.GetPlayerSummaries([]string{steamID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(players) == 0 {
+		return nil, fmt.Errorf("player not found: %s", steamID)
+	}
+
+	return &players[0], nil
+}
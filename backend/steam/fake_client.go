@@ -0,0 +1,169 @@
+package steam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Fixture files read by FakeClient, all optional
+const (
+	fakePlayersFile    = "players.json"     // []PlayerSummary
+	fakeOwnedGamesFile = "owned_games.json" // Steam ID -> []OwnedGame
+	fakeAppsFile       = "apps.json"        // App ID -> AppDetails
+	fakeReviewsFile    = "reviews.json"     // App ID -> ReviewSummary
+	fakeFriendsFile    = "friends.json"     // Steam ID -> []Steam ID
+	fakeGroupsFile     = "groups.json"      // Group name or ID -> []Steam ID
+)
+
+// FakeClient answers all Steam calls from JSON fixtures in a directory
+// It is used by tests and offline demos. Games missing from apps.json are reported as
+// removed from the store, players missing from owned_games.json own no games.
+type FakeClient struct {
+	players    map[string]PlayerSummary
+	ownedGames map[string][]OwnedGame
+	apps       map[int]AppDetails
+	reviews    map[int]ReviewSummary
+	friends    map[string][]string
+	groups     map[string][]string
+}
+
+// NewFakeClient loads the fixtures from dir
+func NewFakeClient(dir string) (*FakeClient, error) {
+	c := &FakeClient{
+		players:    make(map[string]PlayerSummary),
+		ownedGames: make(map[string][]OwnedGame),
+		apps:       make(map[int]AppDetails),
+		reviews:    make(map[int]ReviewSummary),
+		friends:    make(map[string][]string),
+		groups:     make(map[string][]string),
+	}
+
+	var players []PlayerSummary
+	if err := readFixture(dir, fakePlayersFile, &players); err != nil {
+		return nil, err
+	}
+	for _, p := range players {
+		c.players[p.SteamID] = p
+	}
+
+	if err := readFixture(dir, fakeOwnedGamesFile, &c.ownedGames); err != nil {
+		return nil, err
+	}
+
+	var apps map[string]AppDetails
+	if err := readFixture(dir, fakeAppsFile, &apps); err != nil {
+		return nil, err
+	}
+	for id, app := range apps {
+		appID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid app ID %q in %s: %w", id, fakeAppsFile, err)
+		}
+		c.apps[appID] = app
+	}
+
+	var reviews map[string]ReviewSummary
+	if err := readFixture(dir, fakeReviewsFile, &reviews); err != nil {
+		return nil, err
+	}
+	for id, review := range reviews {
+		appID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid app ID %q in %s: %w", id, fakeReviewsFile, err)
+		}
+		c.reviews[appID] = review
+	}
+
+	if err := readFixture(dir, fakeFriendsFile, &c.friends); err != nil {
+		return nil, err
+	}
+	if err := readFixture(dir, fakeGroupsFile, &c.groups); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[STEAM FAKE] Loaded fixtures from %s: %d players, %d libraries, %d games", dir, len(c.players), len(c.ownedGames), len(c.apps))
+	return c, nil
+}
+
+// readFixture decodes a fixture file into target, a missing file is left empty
+func readFixture(dir, name string, target interface{}) error {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read Steam fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to parse Steam fixture %s: %w", name, err)
+	}
+	return nil
+}
+
+// IsConfigured always returns true, the fake needs no API key
+func (c *FakeClient) IsConfigured() bool {
+	return true
+}
+
+// CheckConnectivity always succeeds
+func (c *FakeClient) CheckConnectivity() error {
+	log.Printf("[STEAM FAKE] Using local Steam fixtures, skipping connectivity check")
+	return nil
+}
+
+// GetPlayerSummaries returns the fixture profiles of the known players
+func (c *FakeClient) GetPlayerSummaries(steamIDs []string) ([]PlayerSummary, error) {
+	if len(steamIDs) == 0 {
+		return nil, fmt.Errorf("no Steam IDs provided")
+	}
+
+	players := []PlayerSummary{}
+	for _, id := range steamIDs {
+		if p, ok := c.players[id]; ok {
+			players = append(players, p)
+		}
+	}
+	return players, nil
+}
+
+// GetOwnedGames returns the fixture library of a player
+func (c *FakeClient) GetOwnedGames(steamID string) ([]OwnedGame, error) {
+	return c.ownedGames[steamID], nil
+}
+
+// GetFriendList returns the fixture friends of a player
+func (c *FakeClient) GetFriendList(steamID string) ([]string, error) {
+	return c.friends[steamID], nil
+}
+
+// GetGroupMembers returns the fixture members of a group
+func (c *FakeClient) GetGroupMembers(group string) ([]string, error) {
+	members, ok := c.groups[group]
+	if !ok {
+		return nil, ErrGroupNotFound
+	}
+	return members, nil
+}
+
+// GetAppDetails returns the fixture store data of a game
+func (c *FakeClient) GetAppDetails(appID int) (*AppDetails, error) {
+	app, ok := c.apps[appID]
+	if !ok {
+		return nil, ErrAppNotFound
+	}
+	return &app, nil
+}
+
+// GetReviewSummary returns the fixture review counts of a game
+func (c *FakeClient) GetReviewSummary(appID int) (*ReviewSummary, error) {
+	review, ok := c.reviews[appID]
+	if !ok {
+		return nil, fmt.Errorf("no reviews for game %d", appID)
+	}
+	return &review, nil
+}
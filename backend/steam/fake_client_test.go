@@ -0,0 +1,58 @@
+package steam
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFakeClient(t *testing.T) {
+	client, err := NewFakeClient("../defaults/steam-fake")
+	if err != nil {
+		t.Fatalf("NewFakeClient failed: %v", err)
+	}
+
+	players, err := client.GetPlayerSummaries([]string{"76561190000000001", "76561190000000099"})
+	if err != nil {
+		t.Fatalf("GetPlayerSummaries failed: %v", err)
+	}
+	if len(players) != 1 || players[0].PersonaName != "Fragmaster" {
+		t.Errorf("expected only the known player, got %+v", players)
+	}
+
+	games, err := client.GetOwnedGames("76561190000000003")
+	if err != nil || len(games) != 3 {
+		t.Errorf("expected 3 owned games, got %d (%v)", len(games), err)
+	}
+
+	app, err := client.GetAppDetails(945360)
+	if err != nil {
+		t.Fatalf("GetAppDetails failed: %v", err)
+	}
+	if app.Name != "Among Us" || app.PriceOverview == nil || app.PriceOverview.Final != 399 {
+		t.Errorf("unexpected app details: %+v", app)
+	}
+
+	if _, err := client.GetAppDetails(99999990); !errors.Is(err, ErrAppNotFound) {
+		t.Errorf("expected ErrAppNotFound for a delisted game, got %v", err)
+	}
+
+	friends, err := client.GetFriendList("76561190000000001")
+	if err != nil || len(friends) != 3 {
+		t.Errorf("expected 3 friends, got %v (%v)", friends, err)
+	}
+
+	if _, err := client.GetGroupMembers("unknown"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("expected ErrGroupNotFound, got %v", err)
+	}
+}
+
+func TestFakeClientMissingDir(t *testing.T) {
+	// Missing fixture files leave the fake empty instead of failing
+	client, err := NewFakeClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFakeClient failed: %v", err)
+	}
+	if games, _ := client.GetOwnedGames("76561190000000001"); len(games) != 0 {
+		t.Errorf("expected no games, got %v", games)
+	}
+}
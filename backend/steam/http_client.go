@@ -0,0 +1,384 @@
+package steam
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxGroupPages limits how many member list pages (1000 members each) are fetched per group
+const maxGroupPages = 10
+
+// groupIDPattern matches 64-bit Steam group IDs, everything else is treated as group URL name
+var groupIDPattern = regexp.MustCompile(`^1035\d{14}$`)
+
+// HTTPClient talks to the real Steam Web API, Steam Store and Steam Community
+type HTTPClient struct {
+	apiKey           string
+	apiBaseURL       string
+	storeBaseURL     string
+	communityBaseURL string
+	httpClient       *http.Client
+}
+
+// NewHTTPClient creates a new Steam client for the given base URLs
+func NewHTTPClient(apiKey, apiBaseURL, storeBaseURL, communityBaseURL string) *HTTPClient {
+	return &HTTPClient{
+		apiKey:           apiKey,
+		apiBaseURL:       strings.TrimSuffix(apiBaseURL, "/"),
+		storeBaseURL:     strings.TrimSuffix(storeBaseURL, "/"),
+		communityBaseURL: strings.TrimSuffix(communityBaseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// playerSummariesResponse represents the GetPlayerSummaries response structure
+type playerSummariesResponse struct {
+	Response struct {
+		Players []PlayerSummary `json:"players"`
+	} `json:"response"`
+}
+
+// ownedGamesResponse represents the GetOwnedGames response structure
+type ownedGamesResponse struct {
+	Response struct {
+		GameCount int         `json:"game_count"`
+		Games     []OwnedGame `json:"games"`
+	} `json:"response"`
+}
+
+// friendListResponse represents the GetFriendList response structure
+type friendListResponse struct {
+	FriendsList struct {
+		Friends []struct {
+			SteamID      string `json:"steamid"`
+			Relationship string `json:"relationship"`
+		} `json:"friends"`
+	} `json:"friendslist"`
+}
+
+// groupMembersResponse represents a page of the Steam Community group member list XML
+type groupMembersResponse struct {
+	XMLName     xml.Name `xml:"memberList"`
+	GroupID64   string   `xml:"groupID64"`
+	TotalPages  int      `xml:"totalPages"`
+	CurrentPage int      `xml:"currentPage"`
+	Members     []string `xml:"members>steamID64"`
+}
+
+// appDetailsResponse represents the Steam Store appdetails response
+type appDetailsResponse map[string]struct {
+	Success bool       `json:"success"`
+	Data    AppDetails `json:"data"`
+}
+
+// reviewsResponse represents the Steam Store appreviews response
+type reviewsResponse struct {
+	Success      int           `json:"success"`
+	QuerySummary ReviewSummary `json:"query_summary"`
+}
+
+// IsConfigured returns true if the client has an API key
+func (c *HTTPClient) IsConfigured() bool {
+	return c.apiKey != ""
+}
+
+// GetPlayerSummaries fetches profile data for multiple players (max 100)
+func (c *HTTPClient) GetPlayerSummaries(steamIDs []string) ([]PlayerSummary, error) {
+	if len(steamIDs) == 0 {
+		return nil, fmt.Errorf("no Steam IDs provided")
+	}
+
+	// Filter out fake users (used for development/testing)
+	realSteamIDs := make([]string, 0, len(steamIDs))
+	for _, id := range steamIDs {
+		if !strings.HasPrefix(id, "FAKE_") {
+			realSteamIDs = append(realSteamIDs, id)
+		}
+	}
+
+	if len(realSteamIDs) == 0 {
+		return []PlayerSummary{}, nil
+	}
+
+	if len(realSteamIDs) > 100 {
+		return nil, fmt.Errorf("maximum 100 Steam IDs allowed per request")
+	}
+
+	if c.apiKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	url := fmt.Sprintf(
+		"%s/ISteamUser/GetPlayerSummaries/v2/?key=%s&steamids=%s",
+		c.apiBaseURL,
+		c.apiKey,
+		strings.Join(realSteamIDs, ","),
+	)
+
+	log.Printf("[STEAM API] GET /ISteamUser/GetPlayerSummaries/v2 - Fetching %d player(s): %s", len(realSteamIDs), strings.Join(realSteamIDs, ", "))
+	var apiResp playerSummariesResponse
+	if err := c.getJSON("[STEAM API]", "GetPlayerSummaries", url, &apiResp); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[STEAM API] OK - GetPlayerSummaries returned %d player(s)", len(apiResp.Response.Players))
+	return apiResp.Response.Players, nil
+}
+
+// GetOwnedGames fetches all games owned by a player, including free games
+func (c *HTTPClient) GetOwnedGames(steamID string) ([]OwnedGame, error) {
+	if c.apiKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	url := fmt.Sprintf(
+		"%s/IPlayerService/GetOwnedGames/v1/?key=%s&steamid=%s&include_appinfo=true&include_played_free_games=true",
+		c.apiBaseURL,
+		c.apiKey,
+		steamID,
+	)
+
+	log.Printf("[STEAM API] GET /IPlayerService/GetOwnedGames/v1 - Fetching games for user: %s", steamID)
+	var apiResp ownedGamesResponse
+	if err := c.getJSON("[STEAM API]", "GetOwnedGames", url, &apiResp); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[STEAM API] OK - GetOwnedGames returned %d games for user %s", len(apiResp.Response.Games), steamID)
+	return apiResp.Response.Games, nil
+}
+
+// GetFriendList fetches the Steam IDs of all friends of a player
+// Steam answers 401 for players whose friend list is not public.
+func (c *HTTPClient) GetFriendList(steamID string) ([]string, error) {
+	if c.apiKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	url := fmt.Sprintf(
+		"%s/ISteamUser/GetFriendList/v1/?key=%s&steamid=%s&relationship=friend",
+		c.apiBaseURL,
+		c.apiKey,
+		steamID,
+	)
+
+	log.Printf("[STEAM API] GET /ISteamUser/GetFriendList/v1 - Fetching friends of user: %s", steamID)
+	var apiResp friendListResponse
+	if err := c.getJSON("[STEAM API]", "GetFriendList", url, &apiResp); err != nil {
+		return nil, err
+	}
+
+	friends := make([]string, 0, len(apiResp.FriendsList.Friends))
+	for _, f := range apiResp.FriendsList.Friends {
+		friends = append(friends, f.SteamID)
+	}
+	log.Printf("[STEAM API] OK - GetFriendList returned %d friends for user %s", len(friends), steamID)
+	return friends, nil
+}
+
+// GetGroupMembers fetches the Steam IDs of all members of a Steam group
+// The Web API has no group endpoint, so the public member list XML of the Steam Community is used.
+func (c *HTTPClient) GetGroupMembers(group string) ([]string, error) {
+	group = strings.TrimSpace(group)
+	if group == "" {
+		return nil, ErrGroupNotFound
+	}
+
+	base := fmt.Sprintf("%s/groups/%s", c.communityBaseURL, url.PathEscape(group))
+	if groupIDPattern.MatchString(group) {
+		base = fmt.Sprintf("%s/gid/%s", c.communityBaseURL, group)
+	}
+
+	var members []string
+	for page := 1; page <= maxGroupPages; page++ {
+		log.Printf("[STEAM COMMUNITY] GET /memberslistxml - Fetching page %d of group %s", page, group)
+		var pageResp groupMembersResponse
+		if err := c.getXML("[STEAM COMMUNITY]", "memberslistxml", fmt.Sprintf("%s/memberslistxml/?xml=1&p=%d", base, page), &pageResp); err != nil {
+			return nil, err
+		}
+		members = append(members, pageResp.Members...)
+		if page >= pageResp.TotalPages {
+			break
+		}
+	}
+
+	log.Printf("[STEAM COMMUNITY] OK - memberslistxml returned %d members for group %s", len(members), group)
+	return members, nil
+}
+
+// GetAppDetails fetches categories and price of a game from the Steam Store
+func (c *HTTPClient) GetAppDetails(appID int) (*AppDetails, error) {
+	url := fmt.Sprintf("%s/api/appdetails?appids=%d&cc=de", c.storeBaseURL, appID)
+
+	log.Printf("[STEAM STORE API] GET /appdetails - Fetching details for game %d", appID)
+	var apiResp appDetailsResponse
+	if err := c.getJSON("[STEAM STORE API]", "appdetails", url, &apiResp); err != nil {
+		return nil, err
+	}
+
+	appData, ok := apiResp[strconv.Itoa(appID)]
+	if !ok || !appData.Success {
+		log.Printf("[STEAM STORE API] WARN - Game %d not found or not accessible", appID)
+		return nil, ErrAppNotFound
+	}
+
+	log.Printf("[STEAM STORE API] OK - appdetails returned data for game %d (%s)", appID, appData.Data.Name)
+	return &appData.Data, nil
+}
+
+// GetReviewSummary fetches the review counts of a game from the Steam Store
+func (c *HTTPClient) GetReviewSummary(appID int) (*ReviewSummary, error) {
+	url := fmt.Sprintf("%s/appreviews/%d?json=1&purchase_type=all&language=all", c.storeBaseURL, appID)
+
+	log.Printf("[STEAM STORE API] GET /appreviews - Fetching reviews for game %d", appID)
+	var reviewResp reviewsResponse
+	if err := c.getJSON("[STEAM STORE API]", "appreviews", url, &reviewResp); err != nil {
+		return nil, err
+	}
+
+	if reviewResp.Success != 1 {
+		log.Printf("[STEAM STORE API] WARN - appreviews returned unsuccessful for game %d", appID)
+		return nil, fmt.Errorf("Steam Store API returned no reviews for game %d", appID)
+	}
+	return &reviewResp.QuerySummary, nil
+}
+
+// getJSON performs a GET request and decodes the JSON response into target
+// A 429 response is returned as RateLimitError.
+func (c *HTTPClient) getJSON(prefix, endpoint, url string, target interface{}) error {
+	start := time.Now()
+	resp, err := c.httpClient.Get(url)
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("%s ERROR - %s failed after %v: %v", prefix, endpoint, duration, err)
+		return fmt.Errorf("failed to call Steam API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		log.Printf("%s WARN - Rate limited (429) on %s after %v", prefix, endpoint, duration)
+		return &RateLimitError{RetryAfter: retryAfter}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("%s ERROR - %s returned status %d after %v", prefix, endpoint, resp.StatusCode, duration)
+		return fmt.Errorf("Steam API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		log.Printf("%s ERROR - Failed to parse %s response after %v: %v", prefix, endpoint, duration, err)
+		return fmt.Errorf("failed to parse Steam API response: %w", err)
+	}
+	return nil
+}
+
+// getXML performs a GET request against the Steam Community and decodes the XML response into target
+// Unknown groups are answered with an HTML page or an XML error document, both are reported as ErrGroupNotFound.
+func (c *HTTPClient) getXML(prefix, endpoint, url string, target interface{}) error {
+	start := time.Now()
+	resp, err := c.httpClient.Get(url)
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("%s ERROR - %s failed after %v: %v", prefix, endpoint, duration, err)
+		return fmt.Errorf("failed to call Steam Community: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		log.Printf("%s WARN - Rate limited (429) on %s after %v", prefix, endpoint, duration)
+		return &RateLimitError{RetryAfter: retryAfter}
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrGroupNotFound
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("%s ERROR - %s returned status %d after %v", prefix, endpoint, resp.StatusCode, duration)
+		return fmt.Errorf("Steam Community returned status %d", resp.StatusCode)
+	}
+
+	if err := xml.NewDecoder(resp.Body).Decode(target); err != nil {
+		log.Printf("%s WARN - %s returned no member list after %v: %v", prefix, endpoint, duration, err)
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+// CheckConnectivity verifies that the Steam API endpoints are reachable
+// Returns nil if all checks pass, otherwise returns an error describing the issue
+func (c *HTTPClient) CheckConnectivity() error {
+	log.Printf("[STEAM API] Checking connectivity to Steam services...")
+
+	// Check Steam Community (OpenID endpoint)
+	steamCommunityURL := c.communityBaseURL + "/openid"
+	log.Printf("[STEAM API] HEAD %s", steamCommunityURL)
+	start := time.Now()
+	resp, err := c.httpClient.Head(steamCommunityURL)
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("[STEAM API] ERROR - Steam Community unreachable after %v: %v", duration, err)
+		return fmt.Errorf("cannot reach Steam Community (%s): %w", steamCommunityURL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		log.Printf("[STEAM API] ERROR - Steam Community returned status %d after %v", resp.StatusCode, duration)
+		return fmt.Errorf("Steam Community returned status %d", resp.StatusCode)
+	}
+	log.Printf("[STEAM API] OK - Steam Community reachable (status %d, %v)", resp.StatusCode, duration)
+
+	// Check Steam Web API (only if API key is configured)
+	if c.apiKey != "" {
+		// Use a simple API call to verify connectivity and API key validity
+		// We use GetPlayerSummaries with Valve's test Steam ID
+		testURL := fmt.Sprintf("%s/ISteamUser/GetPlayerSummaries/v2/?key=%s&steamids=76561197960435530", c.apiBaseURL, c.apiKey)
+		log.Printf("[STEAM API] GET /ISteamUser/GetPlayerSummaries/v2 - Testing API key validity")
+		start = time.Now()
+		resp, err := c.httpClient.Get(testURL)
+		duration = time.Since(start)
+		if err != nil {
+			log.Printf("[STEAM API] ERROR - Steam Web API unreachable after %v: %v", duration, err)
+			return fmt.Errorf("cannot reach Steam Web API (%s): %w", c.apiBaseURL, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == 401 || resp.StatusCode == 403 {
+			log.Printf("[STEAM API] ERROR - API key invalid/unauthorized (status %d, %v)", resp.StatusCode, duration)
+			return fmt.Errorf("Steam API key is invalid or unauthorized (status %d)", resp.StatusCode)
+		}
+		if resp.StatusCode >= 400 {
+			log.Printf("[STEAM API] ERROR - Steam Web API returned status %d after %v", resp.StatusCode, duration)
+			return fmt.Errorf("Steam Web API returned status %d", resp.StatusCode)
+		}
+		log.Printf("[STEAM API] OK - Steam Web API reachable, API key valid (status %d, %v)", resp.StatusCode, duration)
+	} else {
+		log.Printf("[STEAM API] WARN - No API key configured, skipping Web API check")
+	}
+
+	log.Printf("[STEAM API] Connectivity check completed successfully")
+	return nil
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+// Returns 0 if the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
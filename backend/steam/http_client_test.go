@@ -0,0 +1,121 @@
+package steam
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientAppDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/appdetails" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("appids") {
+		case "730":
+			w.Write([]byte(`{"730": {"success": true, "data": {"name": "Counter-Strike 2", "is_free": true, "categories": [{"id": 1, "description": "Multi-player"}]}}}`))
+		case "1":
+			w.Write([]byte(`{"1": {"success": false}}`))
+		default:
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient("key", server.URL, server.URL+"/", server.URL)
+
+	app, err := client.GetAppDetails(730)
+	if err != nil {
+		t.Fatalf("GetAppDetails failed: %v", err)
+	}
+	if app.Name != "Counter-Strike 2" || !app.IsFree || len(app.Categories) != 1 {
+		t.Errorf("unexpected app details: %+v", app)
+	}
+
+	if _, err := client.GetAppDetails(1); !errors.Is(err, ErrAppNotFound) {
+		t.Errorf("expected ErrAppNotFound, got %v", err)
+	}
+
+	_, err = client.GetAppDetails(2)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 2*time.Minute {
+		t.Errorf("expected a rate limit error with a 2m Retry-After, got %v", err)
+	}
+}
+
+func TestHTTPClientNotConfigured(t *testing.T) {
+	client := NewHTTPClient("", "http://127.0.0.1:1", "http://127.0.0.1:1", "http://127.0.0.1:1")
+
+	if _, err := client.GetOwnedGames("76561190000000001"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+
+	// Fake users are filtered out before the key is needed
+	players, err := client.GetPlayerSummaries([]string{"FAKE_1"})
+	if err != nil || len(players) != 0 {
+		t.Errorf("expected no players for fake users, got %v (%v)", players, err)
+	}
+}
+
+func TestHTTPClientGroupMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/groups/lan-crew/memberslistxml/":
+			page := r.URL.Query().Get("p")
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<memberList><groupID64>103582791429521408</groupID64><totalPages>2</totalPages><currentPage>` + page + `</currentPage>
+<members><steamID64>7656119000000000` + page + `</steamID64></members></memberList>`))
+		case "/groups/unknown/memberslistxml/":
+			w.Write([]byte(`<!DOCTYPE html><html><body>No group could be retrieved for the given URL.</body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient("key", server.URL, server.URL, server.URL)
+
+	members, err := client.GetGroupMembers("lan-crew")
+	if err != nil {
+		t.Fatalf("GetGroupMembers failed: %v", err)
+	}
+	if len(members) != 2 || members[0] != "76561190000000001" || members[1] != "76561190000000002" {
+		t.Errorf("expected the members of both pages, got %v", members)
+	}
+
+	if _, err := client.GetGroupMembers("unknown"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("expected ErrGroupNotFound for an HTML page, got %v", err)
+	}
+	if _, err := client.GetGroupMembers("103582791429521409"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("expected ErrGroupNotFound for an unknown group ID, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"missing", "", 0},
+		{"seconds", "120", 2 * time.Minute},
+		{"negative seconds", "-5", 0},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"http date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"garbage", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
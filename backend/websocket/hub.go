@@ -28,6 +28,22 @@ const (
 	MessageTypeVotesReset MessageType = "votes_reset"
 	// MessageTypeChatMessage is sent when a new chat message is posted
 	MessageTypeChatMessage MessageType = "chat_message"
+	// MessageTypeChatMessageUpdated is sent when the author edited a chat message
+	MessageTypeChatMessageUpdated MessageType = "chat_message_updated"
+	// MessageTypeChatMessageDeleted is sent when a chat message was deleted by its author or a moderator
+	MessageTypeChatMessageDeleted MessageType = "chat_message_deleted"
+	// MessageTypeChatReactionsUpdated is sent when a player adds or removes a reaction on a chat message
+	MessageTypeChatReactionsUpdated MessageType = "chat_reactions_updated"
+	// MessageTypeChatMention is sent to a player who was mentioned with @username in a chat message
+	MessageTypeChatMention MessageType = "chat_mention"
+	// MessageTypeChatSlowModeUpdated is sent when a moderator changes the chat slow mode
+	MessageTypeChatSlowModeUpdated MessageType = "chat_slow_mode_updated"
+	// MessageTypeChatMuteUpdated is sent to a player when a moderator mutes or unmutes them
+	MessageTypeChatMuteUpdated MessageType = "chat_mute_updated"
+	// MessageTypeDirectMessage is sent to sender and recipient of a direct message, never to other players
+	MessageTypeDirectMessage MessageType = "direct_message"
+	// MessageTypeDirectMessagesRead is sent to the sender when the recipient read their direct messages
+	MessageTypeDirectMessagesRead MessageType = "direct_messages_read"
 	// MessageTypeNewKing is sent when the king changes
 	MessageTypeNewKing MessageType = "new_king"
 	// MessageTypeGamesSyncProgress is sent during background game library sync
@@ -40,6 +56,32 @@ const (
 	MessageTypeUserBanned MessageType = "user_banned"
 	// MessageTypeVoteInvalidation is sent when a vote's invalidation status changes
 	MessageTypeVoteInvalidation MessageType = "vote_invalidation"
+	// MessageTypeDataImported is sent when an admin imported an event archive
+	MessageTypeDataImported MessageType = "data_imported"
+	// MessageTypePollCreated is sent when a game poll is opened
+	MessageTypePollCreated MessageType = "poll_created"
+	// MessageTypePollUpdated is sent with the live results whenever a ballot changes
+	MessageTypePollUpdated MessageType = "poll_updated"
+	// MessageTypePollClosed is sent when a poll is closed, including the winner
+	MessageTypePollClosed MessageType = "poll_closed"
+	// MessageTypeInstallStatus is sent when a player changes the install status of a game
+	MessageTypeInstallStatus MessageType = "install_status"
+	// MessageTypeSessionCreated is sent when a game session is planned
+	MessageTypeSessionCreated MessageType = "session_created"
+	// MessageTypeSessionReady is sent when all players of a planned session are ready
+	MessageTypeSessionReady MessageType = "session_ready"
+	// MessageTypeTournamentUpdated is sent when registrations, teams or matches of a tournament change
+	MessageTypeTournamentUpdated MessageType = "tournament_updated"
+	// MessageTypeTournamentFinished is sent when the last match of a tournament is confirmed
+	MessageTypeTournamentFinished MessageType = "tournament_finished"
+	// MessageTypeRatingsUpdated is sent when a recorded match changed player ratings
+	MessageTypeRatingsUpdated MessageType = "ratings_updated"
+	// MessageTypeUsersInvited is sent when an admin imported Steam players as invited users
+	MessageTypeUsersInvited MessageType = "users_invited"
+	// MessageTypeGamesUpdated is sent when custom games or their declared owners change
+	MessageTypeGamesUpdated MessageType = "games_updated"
+	// MessageTypeMediaPrefetchProgress is sent while game images and avatars are downloaded for offline use
+	MessageTypeMediaPrefetchProgress MessageType = "media_prefetch_progress"
 	// MessageTypeError is sent when an error occurs
 	MessageTypeError MessageType = "error"
 )
@@ -80,6 +122,7 @@ type SettingsPayload struct {
 // ChatMessagePayload contains chat message information for broadcasts
 type ChatMessagePayload struct {
 	ID           uint64        `json:"id"`
+	ChannelID    uint64        `json:"channel_id"`
 	UserID       uint64        `json:"user_id"`
 	Username     string        `json:"username"`
 	SteamID      string        `json:"steam_id"`
@@ -87,6 +130,10 @@ type ChatMessagePayload struct {
 	Message      string        `json:"message"`
 	Achievements interface{}   `json:"achievements"` // Achievement badges at time of message
 	CreatedAt    string        `json:"created_at"`
+	EditedAt     string        `json:"edited_at,omitempty"`
+	ParentID     uint64        `json:"parent_id,omitempty"` // Message this one replies to
+	Parent       interface{}   `json:"parent,omitempty"`    // Preview of the message this one replies to
+	Reactions    interface{}   `json:"reactions"`           // Aggregated reactions per emoji
 }
 
 // Client represents a connected WebSocket client
@@ -244,6 +291,18 @@ func (h *Hub) IsUserConnected(userID uint64) bool {
 	return ok
 }
 
+// GetConnectedUserIDs returns the IDs of all connected users
+func (h *Hub) GetConnectedUserIDs() []uint64 {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	userIDs := make([]uint64, 0, len(h.clients))
+	for userID := range h.clients {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
+
 // BroadcastVoteInvalidation sends vote invalidation update to all clients
 func (h *Hub) BroadcastVoteInvalidation(voteID uint64, isInvalidated bool) {
 	msg := Message{
@@ -332,8 +391,25 @@ func (h *Hub) BroadcastVotesReset() {
 	log.Printf("WebSocket: Broadcasted votes reset to all clients")
 }
 
-// BroadcastChatMessage sends a new chat message to all clients
-func (h *Hub) BroadcastChatMessage(payload *ChatMessagePayload) {
+// BroadcastDataImported notifies all clients that event data was imported and should be reloaded
+func (h *Hub) BroadcastDataImported() {
+	msg := Message{
+		Type:    MessageTypeDataImported,
+		Payload: map[string]string{"message": "Event-Daten wurden importiert"},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal data imported message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+	log.Printf("WebSocket: Broadcasted data imported to all clients")
+}
+
+// BroadcastChatMessage sends a new chat message to the members of its channel, to all clients when members is nil
+func (h *Hub) BroadcastChatMessage(payload *ChatMessagePayload, members []uint64) {
 	msg := Message{
 		Type:    MessageTypeChatMessage,
 		Payload: payload,
@@ -345,10 +421,198 @@ func (h *Hub) BroadcastChatMessage(payload *ChatMessagePayload) {
 		return
 	}
 
-	log.Printf("WebSocket: Broadcasting chat_message to %d clients", h.GetConnectedUserCount())
+	h.sendToChannel(data, members)
+}
+
+// BroadcastChatMessageUpdated sends an edited chat message to the members of its channel, to all clients when members is nil
+func (h *Hub) BroadcastChatMessageUpdated(payload *ChatMessagePayload, members []uint64) {
+	msg := Message{
+		Type:    MessageTypeChatMessageUpdated,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal chat message update: %v", err)
+		return
+	}
+
+	h.sendToChannel(data, members)
+}
+
+// BroadcastChatMessageDeleted notifies the members of a channel that a chat message was deleted, all clients when members is nil
+func (h *Hub) BroadcastChatMessageDeleted(messageID, channelID uint64, members []uint64) {
+	msg := Message{
+		Type: MessageTypeChatMessageDeleted,
+		Payload: map[string]interface{}{
+			"id":         messageID,
+			"channel_id": channelID,
+		},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal chat message deletion: %v", err)
+		return
+	}
+
+	h.sendToChannel(data, members)
+}
+
+// BroadcastChatReactionsUpdated sends the new reactions of a chat message to the members of its channel,
+// to all connected clients when members is nil
+func (h *Hub) BroadcastChatReactionsUpdated(messageID, channelID uint64, reactions interface{}, members []uint64) {
+	msg := Message{
+		Type: MessageTypeChatReactionsUpdated,
+		Payload: map[string]interface{}{
+			"message_id": messageID,
+			"channel_id": channelID,
+			"reactions":  reactions,
+		},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal chat reactions update: %v", err)
+		return
+	}
+
+	h.sendToChannel(data, members)
+}
+
+// sendToChannel sends a chat event to the given channel members, or to all clients when members is nil
+// Global channels have no member list, everybody is in them.
+func (h *Hub) sendToChannel(data []byte, members []uint64) {
+	if members == nil {
+		h.broadcast <- data
+		return
+	}
+	for _, userID := range members {
+		h.sendToUser <- &UserMessage{
+			UserID:  userID,
+			Message: data,
+		}
+	}
+}
+
+// NotifyChatMention sends a chat message to a player mentioned in it
+func (h *Hub) NotifyChatMention(userID uint64, payload *ChatMessagePayload) {
+	msg := Message{
+		Type:    MessageTypeChatMention,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal chat mention: %v", err)
+		return
+	}
+
+	h.sendToUser <- &UserMessage{
+		UserID:  userID,
+		Message: data,
+	}
+}
+
+// BroadcastChatSlowModeUpdated sends the new slow mode to all connected clients
+func (h *Hub) BroadcastChatSlowModeUpdated(seconds int) {
+	msg := Message{
+		Type: MessageTypeChatSlowModeUpdated,
+		Payload: map[string]interface{}{
+			"slow_mode_seconds": seconds,
+		},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal chat slow mode update: %v", err)
+		return
+	}
+
 	h.broadcast <- data
 }
 
+// ChatMutePayload tells a player until when they are muted
+type ChatMutePayload struct {
+	MutedUntil string `json:"muted_until,omitempty"` // RFC3339, empty when the mute was lifted
+	Reason     string `json:"reason,omitempty"`
+}
+
+// NotifyChatMuteUpdated tells a player that they were muted or unmuted
+func (h *Hub) NotifyChatMuteUpdated(userID uint64, payload *ChatMutePayload) {
+	msg := Message{
+		Type:    MessageTypeChatMuteUpdated,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal chat mute update: %v", err)
+		return
+	}
+
+	h.sendToUser <- &UserMessage{
+		UserID:  userID,
+		Message: data,
+	}
+}
+
+// DirectMessagePayload contains a direct message with its sender for notifications
+type DirectMessagePayload struct {
+	ID                uint64 `json:"id"`
+	SenderID          uint64 `json:"sender_id"`
+	SenderUsername    string `json:"sender_username"`
+	SenderAvatarSmall string `json:"sender_avatar_small"`
+	RecipientID       uint64 `json:"recipient_id"`
+	Message           string `json:"message"`
+	CreatedAt         string `json:"created_at"`
+}
+
+// NotifyDirectMessage sends a direct message to one of its two participants
+func (h *Hub) NotifyDirectMessage(userID uint64, payload *DirectMessagePayload) {
+	msg := Message{
+		Type:    MessageTypeDirectMessage,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal direct message: %v", err)
+		return
+	}
+
+	h.sendToUser <- &UserMessage{
+		UserID:  userID,
+		Message: data,
+	}
+}
+
+// DirectMessagesReadPayload is the read receipt for all messages up to LastReadID
+type DirectMessagesReadPayload struct {
+	ReaderID   uint64 `json:"reader_id"`
+	LastReadID uint64 `json:"last_read_id"`
+	ReadAt     string `json:"read_at"`
+}
+
+// NotifyDirectMessagesRead sends a read receipt to the sender of the read messages
+func (h *Hub) NotifyDirectMessagesRead(senderID uint64, payload *DirectMessagesReadPayload) {
+	msg := Message{
+		Type:    MessageTypeDirectMessagesRead,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal direct message read receipt: %v", err)
+		return
+	}
+
+	h.sendToUser <- &UserMessage{
+		UserID:  senderID,
+		Message: data,
+	}
+}
+
 // NewKingPayload contains info about the new king
 type NewKingPayload struct {
 	UserID   uint64 `json:"user_id"`
@@ -422,6 +686,27 @@ func (h *Hub) BroadcastGamesSyncComplete(totalGames int) {
 	log.Printf("WebSocket: Broadcasted games sync complete with %d games", totalGames)
 }
 
+// BroadcastGamesSyncStatus notifies all clients about a game sync progress update
+// The "complete" phase is sent as completion message with the number of multiplayer games.
+func (h *Hub) BroadcastGamesSyncStatus(phase string, currentGame string, processed, total int) {
+	if phase == "complete" {
+		h.BroadcastGamesSyncComplete(processed)
+		return
+	}
+
+	percentage := 0
+	if total > 0 {
+		percentage = (processed * 100) / total
+	}
+	h.BroadcastGamesSyncProgress(&GamesSyncProgressPayload{
+		Phase:          phase,
+		CurrentGame:    currentGame,
+		ProcessedCount: processed,
+		TotalCount:     total,
+		Percentage:     percentage,
+	})
+}
+
 // UserActionPayload contains info about a user kick/ban
 type UserActionPayload struct {
 	UserID   uint64 `json:"user_id"`
@@ -467,3 +752,189 @@ func (h *Hub) BroadcastUserBanned(userID uint64, username string) {
 	h.broadcast <- data
 	log.Printf("WebSocket: Broadcasted user banned notification for %s", username)
 }
+
+// BroadcastPollCreated notifies all clients that a game poll was opened
+func (h *Hub) BroadcastPollCreated(poll interface{}) {
+	h.broadcastPoll(MessageTypePollCreated, poll)
+}
+
+// BroadcastPollUpdated sends the live results of a game poll to all clients
+func (h *Hub) BroadcastPollUpdated(poll interface{}) {
+	h.broadcastPoll(MessageTypePollUpdated, poll)
+}
+
+// BroadcastPollClosed notifies all clients that a game poll was closed
+// winner is nil if nobody voted.
+func (h *Hub) BroadcastPollClosed(poll interface{}, winner interface{}) {
+	h.broadcastPoll(MessageTypePollClosed, map[string]interface{}{
+		"poll":   poll,
+		"winner": winner,
+	})
+}
+
+// broadcastPoll marshals a poll message and sends it to all clients
+func (h *Hub) broadcastPoll(messageType MessageType, payload interface{}) {
+	msg := Message{
+		Type:    messageType,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal %s message: %v", messageType, err)
+		return
+	}
+
+	h.broadcast <- data
+}
+
+// InstallStatusPayload contains a player's install status of a game
+type InstallStatusPayload struct {
+	UserID   uint64 `json:"user_id"`
+	Username string `json:"username"`
+	AppID    int    `json:"app_id"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+}
+
+// BroadcastInstallStatus notifies all clients that a player's install status changed
+func (h *Hub) BroadcastInstallStatus(payload *InstallStatusPayload) {
+	msg := Message{
+		Type:    MessageTypeInstallStatus,
+		Payload: payload,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal install status message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+}
+
+// BroadcastSessionCreated notifies all clients that a game session was planned
+func (h *Hub) BroadcastSessionCreated(session interface{}) {
+	msg := Message{
+		Type:    MessageTypeSessionCreated,
+		Payload: session,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal session created message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+}
+
+// BroadcastSessionReady notifies all clients that everyone in a game session is ready
+func (h *Hub) BroadcastSessionReady(session interface{}) {
+	msg := Message{
+		Type:    MessageTypeSessionReady,
+		Payload: session,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal session ready message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+	log.Printf("WebSocket: Broadcasted session ready")
+}
+
+// BroadcastTournamentUpdated notifies all clients that a tournament changed
+func (h *Hub) BroadcastTournamentUpdated(tournament interface{}) {
+	h.broadcastTournament(MessageTypeTournamentUpdated, tournament)
+}
+
+// BroadcastTournamentFinished notifies all clients that a tournament has a winner
+func (h *Hub) BroadcastTournamentFinished(tournament interface{}) {
+	h.broadcastTournament(MessageTypeTournamentFinished, tournament)
+}
+
+// broadcastTournament marshals a tournament message and sends it to all clients
+func (h *Hub) broadcastTournament(messageType MessageType, tournament interface{}) {
+	msg := Message{
+		Type:    messageType,
+		Payload: tournament,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal %s message: %v", messageType, err)
+		return
+	}
+
+	h.broadcast <- data
+}
+
+// BroadcastRatingsUpdated notifies all clients about a recorded match and the rating changes of its players
+func (h *Hub) BroadcastRatingsUpdated(match interface{}) {
+	msg := Message{
+		Type:    MessageTypeRatingsUpdated,
+		Payload: match,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal ratings updated message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+}
+
+// BroadcastUsersInvited notifies all clients about newly invited users so user lists are reloaded
+func (h *Hub) BroadcastUsersInvited(users interface{}) {
+	msg := Message{
+		Type:    MessageTypeUsersInvited,
+		Payload: users,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal users invited message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+}
+
+// BroadcastGamesUpdated notifies all clients that a game of the games list changed so the list is reloaded
+func (h *Hub) BroadcastGamesUpdated(appID int) {
+	msg := Message{
+		Type: MessageTypeGamesUpdated,
+		Payload: map[string]interface{}{
+			"message": "Spieleliste aktualisiert",
+			"app_id":  appID,
+		},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal games updated message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+}
+
+// BroadcastMediaPrefetchProgress notifies all clients about the progress of a media prefetch
+func (h *Hub) BroadcastMediaPrefetchProgress(status interface{}) {
+	msg := Message{
+		Type:    MessageTypeMediaPrefetchProgress,
+		Payload: status,
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("WebSocket: Failed to marshal media prefetch progress message: %v", err)
+		return
+	}
+
+	h.broadcast <- data
+}
@@ -33,6 +33,14 @@ cleanup() {
 
 trap cleanup SIGINT SIGTERM
 
+# Mit --fake-steam beantwortet das Backend alle Steam-API-Aufrufe aus lokalen Fixtures
+# (backend/defaults/steam-fake) - ideal für Offline-Demos ohne Steam API Key
+if [ "$1" = "--fake-steam" ]; then
+    export STEAM_FAKE_DIR="defaults/steam-fake"
+    echo -e "${YELLOW}🧪 Steam-Fake aktiv: Daten kommen aus backend/$STEAM_FAKE_DIR${NC}"
+    echo ""
+fi
+
 # Prüfe ob .env existiert
 if [ ! -f "$BACKEND_DIR/.env" ]; then
     echo -e "${RED}❌ Fehler: Backend .env Datei nicht gefunden!${NC}"